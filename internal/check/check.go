@@ -0,0 +1,181 @@
+// Package check implements `grit check`: a headless report on the stack
+// that is checked out, for pre-push hooks and CI. A branch fails if it
+// needs a restack, if it has no PR, if its open PR is behind the local head, or if a
+// required check on its PR failed.
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// Problem kinds, as they appear in the report.
+const (
+	NeedsRestack  = "needs-restack"
+	Unsubmitted   = "unsubmitted"
+	ChecksFailing = "checks-failing"
+	NoPR          = "no-pr"
+)
+
+// Problem is one reason a branch fails the check.
+type Problem struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// Branch is the result for one branch in the stack.
+type Branch struct {
+	Name     string    `json:"name"`
+	PR       int       `json:"pr,omitempty"`
+	Problems []Problem `json:"problems"`
+}
+
+// Report is what `grit check` prints.
+type Report struct {
+	OK       bool     `json:"ok"`
+	Current  string   `json:"current"`
+	Branches []Branch `json:"branches"` // bottom of the stack first
+}
+
+// Run checks every branch in the stack of the checked-out branch: its
+// ancestors below trunk and all its descendants. With trunk checked out
+// there is no stack and the report is empty. Unlike the TUI, a failed
+// remote lookup is an error rather than a missing badge, since a gate
+// that can't see the PR can't vouch for it.
+func Run(ctx context.Context, client *gt.Client) (Report, error) {
+	out, err := client.LogShort(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	roots, err := gt.ParseLogShort(out)
+	if err != nil {
+		return Report{}, err
+	}
+	heads, err := client.BranchHeads(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	r := Report{OK: true, Branches: []Branch{}}
+	for _, b := range currentStack(roots) {
+		if b.IsCurrent {
+			r.Current = b.Name
+		}
+		result, err := checkBranch(ctx, client, b, heads[b.Name])
+		if err != nil {
+			return Report{}, fmt.Errorf("%s: %w", b.Name, err)
+		}
+		if len(result.Problems) > 0 {
+			r.OK = false
+		}
+		r.Branches = append(r.Branches, result)
+	}
+	return r, nil
+}
+
+// currentStack returns the checked-out branch's ancestors below trunk, the
+// branch itself and its descendants, bottom first, or nil if no branch
+// outside trunk is checked out.
+func currentStack(roots []*gt.Branch) []*gt.Branch {
+	var path []*gt.Branch
+	var find func(b *gt.Branch) bool
+	find = func(b *gt.Branch) bool {
+		path = append(path, b)
+		if b.IsCurrent {
+			return true
+		}
+		for _, child := range b.Children {
+			if find(child) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	for _, root := range roots {
+		if find(root) {
+			break
+		}
+	}
+	if len(path) < 2 {
+		// Nothing checked out, or trunk is.
+		return nil
+	}
+
+	stack := path[1:]
+	var descend func(b *gt.Branch)
+	descend = func(b *gt.Branch) {
+		for _, child := range b.Children {
+			stack = append(stack, child)
+			descend(child)
+		}
+	}
+	descend(path[len(path)-1])
+	return stack
+}
+
+// checkBranch looks up b's PR and collects its problems.
+func checkBranch(ctx context.Context, client *gt.Client, b *gt.Branch, head string) (Branch, error) {
+	result := Branch{Name: b.Name, Problems: []Problem{}}
+	if strings.Contains(b.Annotation, "restack") {
+		result.Problems = append(result.Problems, Problem{NeedsRestack, "branch is not based on its parent's head"})
+	}
+
+	pr, err := client.BranchPR(ctx, b.Name)
+	if err != nil {
+		return Branch{}, err
+	}
+	if pr.Number == 0 {
+		result.Problems = append(result.Problems, Problem{NoPR, "branch has no PR"})
+		return result, nil
+	}
+	result.PR = pr.Number
+	switch strings.ToUpper(pr.State) {
+	case "OPEN", "DRAFT":
+	default:
+		return result, nil
+	}
+
+	details, err := client.PRDetails(ctx, b.Name)
+	if err != nil {
+		return Branch{}, err
+	}
+	if details.HeadSHA != "" && head != "" && details.HeadSHA != head {
+		result.Problems = append(result.Problems, Problem{Unsubmitted, fmt.Sprintf("PR #%d is at %s, local head is %s", pr.Number, short(details.HeadSHA), short(head))})
+	}
+
+	checks, err := client.RequiredChecks(ctx, b.Name)
+	if err != nil {
+		return Branch{}, err
+	}
+	var failed []string
+	for _, c := range checks {
+		if c.Failed() {
+			failed = append(failed, c.Name)
+		}
+	}
+	if len(failed) > 0 {
+		result.Problems = append(result.Problems, Problem{ChecksFailing, "required checks failed: " + strings.Join(failed, ", ")})
+	}
+	return result, nil
+}
+
+// short abbreviates a commit SHA.
+func short(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// Write prints r to w as indented JSON.
+func Write(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,168 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// fakeExecutor answers gt, git and gh calls from canned data.
+type fakeExecutor struct {
+	log     string
+	heads   string
+	prs     map[string]string // branch → pr-info JSON
+	ghPRs   map[string]string // branch → gh pr list JSON
+	details map[string]string // branch → gh pr view JSON
+	checks  map[string]string // branch → gh pr checks JSON
+	err     error             // returned by gh pr checks
+}
+
+func (f *fakeExecutor) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	switch {
+	case name == "gt" && args[0] == "log":
+		return f.log, nil
+	case name == "gt" && args[0] == "branch":
+		return f.prs[args[3]], nil
+	case name == "git" && args[0] == "for-each-ref":
+		return f.heads, nil
+	case name == "gh" && args[1] == "list":
+		return f.ghPRs[args[3]], nil
+	case name == "gh" && args[1] == "view":
+		return f.details[args[2]], nil
+	case name == "gh" && args[1] == "checks":
+		return f.checks[args[2]], f.err
+	}
+	return "", nil
+}
+
+func newFake() *fakeExecutor {
+	return &fakeExecutor{
+		log:   "◯      other\n│ ◯    api-c\n│ ◉    api-b (needs restack)\n│ ◯    api-a\n◯─┘    main",
+		heads: "main aaaaaaaa\napi-a 1111111111\napi-b 2222222222\napi-c 3333333333\nother 4444444444\n",
+		prs: map[string]string{
+			"api-a": `{"prNumber": 12, "state": "OPEN"}`,
+			"api-b": `{"prNumber": 13, "state": "DRAFT"}`,
+			"other": `{"prNumber": 14, "state": "OPEN"}`,
+		},
+		details: map[string]string{
+			"api-a": `{"headRefOid": "1111111111"}`,
+			"api-b": `{"headRefOid": "9999999999"}`,
+			"other": `{"headRefOid": "0000000000"}`,
+		},
+		checks: map[string]string{
+			"api-a": `[{"name":"build","bucket":"pass"},{"name":"lint","bucket":"fail"}]`,
+			"api-b": `[{"name":"build","bucket":"pending"}]`,
+			"other": `[{"name":"build","bucket":"fail"}]`,
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	r, err := Run(context.Background(), gt.New(newFake()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.OK || r.Current != "api-b" {
+		t.Errorf("OK = %v, Current = %q", r.OK, r.Current)
+	}
+	want := map[string][]string{
+		"api-a": {ChecksFailing},
+		"api-b": {NeedsRestack, Unsubmitted},
+		"api-c": {NoPR},
+	}
+	if len(r.Branches) != len(want) {
+		t.Fatalf("branches = %+v, want only the current stack", r.Branches)
+	}
+	for i, name := range []string{"api-a", "api-b", "api-c"} {
+		b := r.Branches[i]
+		if b.Name != name {
+			t.Errorf("branch %d = %s, want %s", i, b.Name, name)
+			continue
+		}
+		var kinds []string
+		for _, p := range b.Problems {
+			kinds = append(kinds, p.Kind)
+		}
+		if len(kinds) != len(want[name]) {
+			t.Errorf("%s problems = %+v, want %v", name, b.Problems, want[name])
+			continue
+		}
+		for j := range kinds {
+			if kinds[j] != want[name][j] {
+				t.Errorf("%s problems = %+v, want %v", name, b.Problems, want[name])
+			}
+		}
+	}
+	if got := r.Branches[1].Problems[1].Detail; got != "PR #13 is at 9999999, local head is 2222222" {
+		t.Errorf("unsubmitted detail = %q", got)
+	}
+}
+
+func TestRun_TrunkCheckedOut(t *testing.T) {
+	f := newFake()
+	f.log = "│ ◯  api-a\n◉─┘  main"
+	r, err := Run(context.Background(), gt.New(f))
+	if err != nil || !r.OK || len(r.Branches) != 0 {
+		t.Errorf("report = %+v, err = %v, want an empty passing report", r, err)
+	}
+}
+
+func TestRun_LookupFails(t *testing.T) {
+	f := newFake()
+	f.err = errors.New("gh: not logged in")
+	f.checks = nil
+	if _, err := Run(context.Background(), gt.New(f)); err == nil {
+		t.Error("a failed checks lookup should fail the run")
+	}
+}
+
+func TestRun_PRFromGhWhenGtPrintsNothing(t *testing.T) {
+	f := newFake()
+	f.prs = map[string]string{}
+	f.ghPRs = map[string]string{"api-a": `[{"number":12,"state":"OPEN","isDraft":false}]`}
+	r, err := Run(context.Background(), gt.New(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.OK {
+		t.Error("api-a's failing checks should fail the run")
+	}
+	a := r.Branches[0]
+	if a.PR != 12 || len(a.Problems) != 1 || a.Problems[0].Kind != ChecksFailing {
+		t.Errorf("api-a = %+v, want PR #12 from gh with failing checks", a)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	r := Report{Current: "api-a", Branches: []Branch{{Name: "api-a", PR: 12, Problems: []Problem{{NeedsRestack, "x"}}}}}
+	if err := Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	var got Report
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if got.Branches[0].Problems[0].Kind != NeedsRestack {
+		t.Errorf("round trip = %+v", got)
+	}
+}
+
+func TestRun_BranchWithoutPRFails(t *testing.T) {
+	f := newFake()
+	f.log = "│ ◉  api-c\n◯─┘  main"
+	r, err := Run(context.Background(), gt.New(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.OK {
+		t.Error("a branch without a PR should fail the run")
+	}
+	if c := r.Branches[0]; c.PR != 0 || len(c.Problems) != 1 || c.Problems[0].Kind != NoPR {
+		t.Errorf("api-c = %+v, want a single no-pr problem", c)
+	}
+}
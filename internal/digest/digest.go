@@ -0,0 +1,182 @@
+// Package digest builds the Markdown activity summary printed by
+// `grit digest`: branches created and PRs merged over a period, from the
+// activity journal, plus the stacks still outstanding with their ages and
+// PR states.
+package digest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/journal"
+)
+
+// report is everything a digest shows.
+type report struct {
+	since, now time.Time
+	created    []journal.Event
+	merged     []journal.Event
+	stacks     []stack
+}
+
+// stack is one outstanding stack: a trunk child and its descendants that
+// haven't merged, bottom first.
+type stack struct {
+	branches []branch
+	oldest   time.Time // first commit on any branch; zero if unknown
+}
+
+type branch struct {
+	name string
+	pr   gt.PRInfo
+}
+
+// Build gathers the digest for the days before now. Remote PR lookups and
+// commit times are best-effort: failures show as unknown.
+func Build(ctx context.Context, client *gt.Client, events []journal.Event, now time.Time, days int) (string, error) {
+	out, err := client.LogShort(ctx)
+	if err != nil {
+		return "", err
+	}
+	roots, err := gt.ParseLogShort(out)
+	if err != nil {
+		return "", err
+	}
+	r := report{since: now.AddDate(0, 0, -days), now: now}
+	r.created, r.merged = activity(events, r.since)
+	for _, root := range roots {
+		for _, child := range root.Children {
+			if s, ok := collectStack(ctx, client, root.Name, child); ok {
+				r.stacks = append(r.stacks, s)
+			}
+		}
+	}
+	sort.SliceStable(r.stacks, func(i, j int) bool {
+		a, b := r.stacks[i].oldest, r.stacks[j].oldest
+		if a.IsZero() != b.IsZero() {
+			return b.IsZero()
+		}
+		return a.Before(b)
+	})
+	return render(r), nil
+}
+
+// activity returns the branches created and the PRs merged since since,
+// oldest first, keeping only the latest event per branch.
+func activity(events []journal.Event, since time.Time) (created, merged []journal.Event) {
+	latest := func(kind string) []journal.Event {
+		byBranch := make(map[string]journal.Event)
+		for _, e := range events {
+			if e.Kind == kind && !e.Time.Before(since) {
+				byBranch[e.Branch] = e
+			}
+		}
+		list := make([]journal.Event, 0, len(byBranch))
+		for _, e := range byBranch {
+			list = append(list, e)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if !list[i].Time.Equal(list[j].Time) {
+				return list[i].Time.Before(list[j].Time)
+			}
+			return list[i].Branch < list[j].Branch
+		})
+		return list
+	}
+	return latest(journal.Created), latest(journal.Merged)
+}
+
+// collectStack gathers the unmerged branches of the stack rooted at b,
+// reporting false if all of them have merged.
+func collectStack(ctx context.Context, client *gt.Client, parent string, b *gt.Branch) (stack, bool) {
+	var s stack
+	var walk func(parent string, b *gt.Branch)
+	walk = func(parent string, b *gt.Branch) {
+		pr, _ := client.BranchPR(ctx, b.Name)
+		if !strings.EqualFold(pr.State, "MERGED") {
+			s.branches = append(s.branches, branch{name: b.Name, pr: pr})
+			if t, err := client.FirstCommitTime(ctx, parent, b.Name); err == nil && !t.IsZero() {
+				if s.oldest.IsZero() || t.Before(s.oldest) {
+					s.oldest = t
+				}
+			}
+		}
+		for _, child := range b.Children {
+			walk(b.Name, child)
+		}
+	}
+	walk(parent, b)
+	return s, len(s.branches) > 0
+}
+
+// render formats r as Markdown.
+func render(r report) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Stack digest: %s – %s\n", r.since.Format("Jan 2"), r.now.Format("Jan 2, 2006"))
+
+	fmt.Fprintf(&sb, "\n## Branches created (%d)\n\n", len(r.created))
+	if len(r.created) == 0 {
+		sb.WriteString("None recorded.\n")
+	}
+	for _, e := range r.created {
+		fmt.Fprintf(&sb, "- `%s` — %s\n", e.Branch, e.Time.Format("Mon Jan 2"))
+	}
+
+	fmt.Fprintf(&sb, "\n## PRs merged (%d)\n\n", len(r.merged))
+	if len(r.merged) == 0 {
+		sb.WriteString("None recorded.\n")
+	}
+	for _, e := range r.merged {
+		pr := ""
+		if e.PR > 0 {
+			pr = fmt.Sprintf(" (#%d)", e.PR)
+		}
+		fmt.Fprintf(&sb, "- `%s`%s — %s\n", e.Branch, pr, e.Time.Format("Mon Jan 2"))
+	}
+
+	fmt.Fprintf(&sb, "\n## Outstanding stacks (%d)\n\n", len(r.stacks))
+	if len(r.stacks) == 0 {
+		sb.WriteString("None.\n")
+	}
+	for _, s := range r.stacks {
+		names := make([]string, len(s.branches))
+		for i, b := range s.branches {
+			names[i] = "`" + b.name + "`"
+		}
+		fmt.Fprintf(&sb, "- %s — %s, %s\n", strings.Join(names, " → "), plural(len(s.branches), "branch", "branches"), age(s.oldest, r.now))
+		for _, b := range s.branches {
+			fmt.Fprintf(&sb, "  - `%s`: %s\n", b.name, prState(b.pr))
+		}
+	}
+	return sb.String()
+}
+
+// age describes how long ago t was, in days.
+func age(t, now time.Time) string {
+	if t.IsZero() {
+		return "age unknown"
+	}
+	days := int(now.Sub(t).Hours() / 24)
+	if days < 1 {
+		return "started today"
+	}
+	return plural(days, "day", "days") + " old"
+}
+
+func prState(pr gt.PRInfo) string {
+	if pr.Number == 0 {
+		return "no PR"
+	}
+	return fmt.Sprintf("#%d %s", pr.Number, strings.ToLower(pr.State))
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
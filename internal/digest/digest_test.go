@@ -0,0 +1,107 @@
+package digest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/journal"
+)
+
+// fakeExecutor answers gt and git calls from canned data.
+type fakeExecutor struct {
+	log      string
+	prs      map[string]string // branch → pr-info JSON
+	firstCom map[string]string // "parent..branch" → git log output
+}
+
+func (f *fakeExecutor) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	switch {
+	case name == "gt" && args[0] == "log":
+		return f.log, nil
+	case name == "gt" && args[0] == "branch":
+		return f.prs[args[3]], nil
+	case name == "git" && args[0] == "log":
+		return f.firstCom[args[len(args)-1]], nil
+	}
+	return "", nil
+}
+
+var now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func TestBuild(t *testing.T) {
+	exec := &fakeExecutor{
+		log: "│ ◯  api-b\n│ ◯  api-a\n│ │ ◯  old-ui\n│ ◯─┘  done\n◉─┘  main",
+		prs: map[string]string{
+			"api-a":  `{"prNumber": 12, "state": "OPEN"}`,
+			"done":   `{"prNumber": 9, "state": "MERGED"}`,
+			"old-ui": `{"prNumber": 10, "state": "DRAFT"}`,
+		},
+		firstCom: map[string]string{
+			"main..api-a":  "1791676800\n", // Oct 11
+			"api-a..api-b": "1791849600\n",
+			"done..old-ui": "1791072000\n", // Oct 4
+		},
+	}
+	events := []journal.Event{
+		{Time: now.AddDate(0, 0, -30), Kind: journal.Created, Branch: "ancient"},
+		{Time: now.AddDate(0, 0, -3), Kind: journal.Created, Branch: "api-b"},
+		{Time: now.AddDate(0, 0, -5), Kind: journal.Created, Branch: "api-a"},
+		{Time: now.AddDate(0, 0, -1), Kind: journal.Merged, Branch: "done", PR: 9},
+		{Time: now.AddDate(0, 0, -2), Kind: journal.Seen, Branch: "old-ui"},
+	}
+
+	got, err := Build(context.Background(), gt.New(exec), events, now, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Stack digest: Oct 9 – Oct 16, 2026",
+		"## Branches created (2)\n\n- `api-a` — Sun Oct 11\n- `api-b` — Tue Oct 13\n",
+		"## PRs merged (1)\n\n- `done` (#9) — Thu Oct 15\n",
+		"## Outstanding stacks (2)",
+		"- `old-ui` — 1 branch, 12 days old\n  - `old-ui`: #10 draft\n",
+		"- `api-a` → `api-b` — 2 branches, 5 days old\n  - `api-a`: #12 open\n  - `api-b`: no PR\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("digest missing %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "`old-ui` —") > strings.Index(got, "`api-a` →") {
+		t.Error("oldest stack should be listed first")
+	}
+	if strings.Contains(got, "ancient") {
+		t.Error("events before the period should be left out")
+	}
+}
+
+func TestBuild_Empty(t *testing.T) {
+	got, err := Build(context.Background(), gt.New(&fakeExecutor{log: "◉  main"}), nil, now, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## Branches created (0)\n\nNone recorded.", "## Outstanding stacks (0)\n\nNone."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("digest missing %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestAge(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Time{}, "age unknown"},
+		{now.Add(-3 * time.Hour), "started today"},
+		{now.Add(-30 * time.Hour), "1 day old"},
+		{now.AddDate(0, 0, -9), "9 days old"},
+	}
+	for _, tt := range tests {
+		if got := age(tt.t, now); got != tt.want {
+			t.Errorf("age(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
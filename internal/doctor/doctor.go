@@ -0,0 +1,277 @@
+// Package doctor implements `grit doctor`: checks of the tools, auth,
+// repository, file watching, terminal and config grit depends on, each
+// with a fix when it fails.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/muesli/termenv"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	OK Status = iota
+	Warn
+	Fail
+)
+
+// Check is the result of one health check.
+type Check struct {
+	Name   string
+	Status Status
+	Detail string // what was found
+	Fix    string // how to fix a warning or failure, "" if OK
+}
+
+// minGitVersion is the oldest git grit is tested with.
+var minGitVersion = [2]int{2, 20}
+
+// inotifyMinInstances is the max_user_instances below which watcher
+// failures are likely once editors and other tools take their share.
+const inotifyMinInstances = 128
+
+// Env is what the checks inspect. Fields are swappable for tests.
+type Env struct {
+	Client      *gt.Client
+	Home        string                       // user home directory
+	Getenv      func(string) string          // environment lookup
+	ReadFile    func(string) ([]byte, error) // file reads (Graphite config, /proc)
+	Watch       func(gitDir string) error    // tries to watch gitDir like grit does
+	Terminal    bool                         // stdout is a terminal
+	Colors      termenv.Profile              // detected color support
+	CheckConfig func() error                 // loads and validates grit's config
+}
+
+// Run runs every check in order.
+func Run(ctx context.Context, env Env) []Check {
+	checks := []Check{checkGit(ctx, env), checkGt(ctx, env), checkGtAuth(env), checkGhAuth(ctx, env)}
+	gitDir, repo := checkRepo(ctx, env)
+	checks = append(checks, repo)
+	if gitDir != "" {
+		checks = append(checks, checkWatcher(env, gitDir))
+	}
+	checks = append(checks, checkTerminal(env), checkConfig(env))
+	return checks
+}
+
+func checkGit(ctx context.Context, env Env) Check {
+	c := Check{Name: "git"}
+	version, err := env.Client.GitVersion(ctx)
+	if err != nil {
+		c.Status, c.Detail, c.Fix = Fail, "not found: "+err.Error(), "Install git from https://git-scm.com/downloads"
+		return c
+	}
+	c.Detail = version
+	if olderThan(version, minGitVersion) {
+		c.Status = Warn
+		c.Fix = fmt.Sprintf("Upgrade git to %d.%d or later", minGitVersion[0], minGitVersion[1])
+	}
+	return c
+}
+
+// olderThan reports whether a "major.minor[.patch...]" version is older
+// than min. Unparseable versions are not flagged.
+func olderThan(version string, min [2]int) bool {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	return major < min[0] || major == min[0] && minor < min[1]
+}
+
+func checkGt(ctx context.Context, env Env) Check {
+	c := Check{Name: "gt"}
+	version, err := env.Client.GtVersion(ctx)
+	if err != nil {
+		c.Status, c.Detail, c.Fix = Fail, "not found: "+err.Error(), "Install the Graphite CLI: npm install -g @withgraphite/graphite-cli"
+		return c
+	}
+	c.Detail = version
+	return c
+}
+
+// checkGtAuth looks for a Graphite auth token in the user config, which
+// gt keeps in either its XDG location or the older dotfile.
+func checkGtAuth(env Env) Check {
+	c := Check{Name: "gt auth"}
+	configHome := env.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(env.Home, ".config")
+	}
+	for _, path := range []string{filepath.Join(configHome, "graphite", "user_config"), filepath.Join(env.Home, ".graphite_user_config")} {
+		if data, err := env.ReadFile(path); err == nil && strings.Contains(string(data), `"authToken"`) {
+			c.Detail = "token in " + path
+			return c
+		}
+	}
+	c.Status, c.Detail = Fail, "no Graphite auth token found"
+	c.Fix = "Run gt auth --token <token> with a token from https://app.graphite.dev/activate"
+	return c
+}
+
+func checkGhAuth(ctx context.Context, env Env) Check {
+	c := Check{Name: "gh auth", Detail: "logged in"}
+	if err := env.Client.GhAuthStatus(ctx); err != nil {
+		c.Status, c.Detail = Warn, err.Error()
+		c.Fix = "Install gh and run gh auth login; without it the unsubmitted badge, reviewers and GitHub quota are unavailable"
+	}
+	return c
+}
+
+// checkRepo checks that the working directory is in a git repository that
+// gt has been initialized in, returning the git dir if there is one.
+func checkRepo(ctx context.Context, env Env) (string, Check) {
+	c := Check{Name: "repository"}
+	gitDir, err := env.Client.GitDir(ctx)
+	if err != nil {
+		c.Status, c.Detail, c.Fix = Fail, "not in a git repository", "Run grit from inside a git repository"
+		return "", c
+	}
+	if _, err := env.ReadFile(filepath.Join(gitDir, ".graphite_repo_config")); err != nil {
+		c.Status, c.Detail = Fail, "Graphite is not initialized in "+gitDir
+		c.Fix = "Run gt repo init, or press i in grit"
+		return gitDir, c
+	}
+	c.Detail = "Graphite initialized in " + gitDir
+	return gitDir, c
+}
+
+// checkWatcher tries to watch the repo the way grit does and reports the
+// inotify limits where the kernel exposes them.
+func checkWatcher(env Env, gitDir string) Check {
+	c := Check{Name: "file watcher"}
+	instances, hasLimits := readInt(env, "/proc/sys/fs/inotify/max_user_instances")
+	watches, _ := readInt(env, "/proc/sys/fs/inotify/max_user_watches")
+	if hasLimits {
+		c.Detail = fmt.Sprintf("inotify max_user_instances=%d max_user_watches=%d", instances, watches)
+	}
+	if err := env.Watch(gitDir); err != nil {
+		limits := c.Detail
+		c.Status, c.Detail = Fail, "cannot watch "+gitDir+": "+err.Error()
+		if limits != "" {
+			c.Detail += "; " + limits
+		}
+		c.Fix = "grit won't refresh on its own. Raise the limit with sudo sysctl fs.inotify.max_user_instances=512, or close other watching tools"
+		return c
+	}
+	if hasLimits && instances < inotifyMinInstances {
+		c.Status = Warn
+		c.Fix = "Low inotify limit; raise it with sudo sysctl fs.inotify.max_user_instances=512"
+	}
+	if c.Detail == "" {
+		c.Detail = "watching " + gitDir
+	}
+	return c
+}
+
+func readInt(env Env, path string) (int, bool) {
+	data, err := env.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	return n, err == nil
+}
+
+// checkTerminal checks that output is a terminal that can draw the tree:
+// color support and a UTF-8 locale for the ◉/◯ markers.
+func checkTerminal(env Env) Check {
+	c := Check{Name: "terminal"}
+	if !env.Terminal {
+		c.Status, c.Detail, c.Fix = Warn, "output is not a terminal", "Run grit doctor in the terminal you use grit in"
+		return c
+	}
+	term := env.Getenv("TERM")
+	locale := firstSet(env, "LC_ALL", "LC_CTYPE", "LANG")
+	c.Detail = fmt.Sprintf("TERM=%s, %s color, locale %s", term, colorName(env.Colors), locale)
+	switch {
+	case term == "dumb" || env.Colors == termenv.Ascii:
+		c.Status, c.Fix = Warn, "No color support; run grit with --low-bandwidth for a plain layout, or set TERM=xterm-256color"
+	case !strings.Contains(strings.ToUpper(strings.ReplaceAll(locale, "-", "")), "UTF8"):
+		c.Status, c.Fix = Warn, "Tree markers may not render; use a UTF-8 locale, e.g. export LANG=en_US.UTF-8"
+	}
+	return c
+}
+
+func firstSet(env Env, names ...string) string {
+	for _, name := range names {
+		if v := env.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return "unset"
+}
+
+func colorName(p termenv.Profile) string {
+	switch p {
+	case termenv.TrueColor:
+		return "true"
+	case termenv.ANSI256:
+		return "256"
+	case termenv.ANSI:
+		return "16"
+	}
+	return "no"
+}
+
+func checkConfig(env Env) Check {
+	c := Check{Name: "config", Detail: "valid"}
+	if err := env.CheckConfig(); err != nil {
+		c.Status, c.Detail, c.Fix = Fail, err.Error(), "Fix the setting named above in ~/.config/grit/config.json or .grit.json"
+	}
+	return c
+}
+
+// Write prints checks as a report and reports whether any failed.
+func Write(w io.Writer, checks []Check) (failed bool) {
+	for _, c := range checks {
+		mark := "✓"
+		switch c.Status {
+		case Warn:
+			mark = "!"
+		case Fail:
+			mark, failed = "✗", true
+		}
+		fmt.Fprintf(w, "%s %-13s %s\n", mark, c.Name, c.Detail)
+		if c.Fix != "" {
+			fmt.Fprintf(w, "  %-13s → %s\n", "", c.Fix)
+		}
+	}
+	return failed
+}
+
+// DefaultEnv returns the Env for the current process.
+func DefaultEnv(client *gt.Client, watch func(string) error, checkConfig func() error) Env {
+	home, _ := os.UserHomeDir()
+	return Env{
+		Client:      client,
+		Home:        home,
+		Getenv:      os.Getenv,
+		ReadFile:    os.ReadFile,
+		Watch:       watch,
+		Terminal:    isTerminal(os.Stdout),
+		Colors:      termenv.NewOutput(os.Stdout).EnvColorProfile(),
+		CheckConfig: checkConfig,
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,175 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// fakeExecutor answers commands by their command line.
+type fakeExecutor map[string]string
+
+func (f fakeExecutor) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	line := gt.FormatCommand(name, args...)
+	out, ok := f[line]
+	if !ok {
+		return "", errors.New(line + ": not found")
+	}
+	return out, nil
+}
+
+func healthyEnv() Env {
+	files := map[string]string{
+		"/home/u/.config/graphite/user_config":    `{"authToken":"secret"}`,
+		"/repo/.git/.graphite_repo_config":        `{"trunk":"main"}`,
+		"/proc/sys/fs/inotify/max_user_instances": "512\n",
+		"/proc/sys/fs/inotify/max_user_watches":   "65536\n",
+	}
+	vars := map[string]string{"TERM": "xterm-256color", "LANG": "en_US.UTF-8"}
+	return Env{
+		Client: gt.New(fakeExecutor{
+			"git --version":                    "git version 2.43.0\n",
+			"gt --version":                     "1.4.2\n",
+			"gh auth status":                   "",
+			"git rev-parse --absolute-git-dir": "/repo/.git\n",
+		}),
+		Home:   "/home/u",
+		Getenv: func(k string) string { return vars[k] },
+		ReadFile: func(path string) ([]byte, error) {
+			if data, ok := files[path]; ok {
+				return []byte(data), nil
+			}
+			return nil, fs.ErrNotExist
+		},
+		Watch:       func(string) error { return nil },
+		Terminal:    true,
+		Colors:      termenv.ANSI256,
+		CheckConfig: func() error { return nil },
+	}
+}
+
+func find(t *testing.T, checks []Check, name string) Check {
+	t.Helper()
+	for _, c := range checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("no %q check in %+v", name, checks)
+	return Check{}
+}
+
+func TestRun_Healthy(t *testing.T) {
+	checks := Run(context.Background(), healthyEnv())
+	for _, c := range checks {
+		if c.Status != OK {
+			t.Errorf("%s: status %d, %s (fix: %s)", c.Name, c.Status, c.Detail, c.Fix)
+		}
+	}
+	var out bytes.Buffer
+	if Write(&out, checks) {
+		t.Error("healthy report should not fail")
+	}
+	if !strings.Contains(out.String(), "✓ git           2.43.0") {
+		t.Errorf("report:\n%s", out.String())
+	}
+}
+
+func TestRun_Problems(t *testing.T) {
+	env := healthyEnv()
+	env.Client = gt.New(fakeExecutor{
+		"git --version":                    "git version 2.17.1\n",
+		"git rev-parse --absolute-git-dir": "/other/.git\n",
+	})
+	env.Home = "/home/nobody"
+	env.Watch = func(string) error { return errors.New("too many open files") }
+	env.Getenv = func(k string) string {
+		if k == "LANG" {
+			return "C"
+		}
+		return ""
+	}
+	env.CheckConfig = func() error { return errors.New(`unknown key action "sumbit"`) }
+
+	checks := Run(context.Background(), env)
+	for name, want := range map[string]Status{
+		"git":          Warn,
+		"gt":           Fail,
+		"gt auth":      Fail,
+		"gh auth":      Warn,
+		"repository":   Fail,
+		"file watcher": Fail,
+		"terminal":     Warn,
+		"config":       Fail,
+	} {
+		if c := find(t, checks, name); c.Status != want {
+			t.Errorf("%s: status %d, want %d (%s)", name, c.Status, want, c.Detail)
+		} else if c.Fix == "" {
+			t.Errorf("%s: a problem needs a fix", name)
+		}
+	}
+	if c := find(t, checks, "config"); !strings.Contains(c.Detail, "sumbit") {
+		t.Errorf("config detail = %q, want the error", c.Detail)
+	}
+
+	var out bytes.Buffer
+	if !Write(&out, checks) {
+		t.Error("report with failures should fail")
+	}
+	if !strings.Contains(out.String(), "→ Run gt repo init") {
+		t.Errorf("report should print fixes:\n%s", out.String())
+	}
+}
+
+func TestRun_NotARepo(t *testing.T) {
+	env := healthyEnv()
+	env.Client = gt.New(fakeExecutor{"git --version": "git version 2.43.0\n"})
+	checks := Run(context.Background(), env)
+	if c := find(t, checks, "repository"); c.Status != Fail {
+		t.Errorf("repository status = %d, want fail", c.Status)
+	}
+	for _, c := range checks {
+		if c.Name == "file watcher" {
+			t.Error("watcher is not checked outside a repository")
+		}
+	}
+}
+
+func TestCheckWatcher_LowLimit(t *testing.T) {
+	env := healthyEnv()
+	read := env.ReadFile
+	env.ReadFile = func(path string) ([]byte, error) {
+		if path == "/proc/sys/fs/inotify/max_user_instances" {
+			return []byte("64\n"), nil
+		}
+		return read(path)
+	}
+	if c := checkWatcher(env, "/repo/.git"); c.Status != Warn || !strings.Contains(c.Fix, "max_user_instances") {
+		t.Errorf("check = %+v, want a low-limit warning", c)
+	}
+}
+
+func TestOlderThan(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		want    bool
+	}{
+		{"2.43.0", false},
+		{"2.20.1", false},
+		{"2.19.6", true},
+		{"1.9", true},
+		{"2.39.5 (Apple Git-154)", false},
+		{"weird", false},
+	} {
+		if got := olderThan(tc.version, minGitVersion); got != tc.want {
+			t.Errorf("olderThan(%q) = %v, want %v", tc.version, got, tc.want)
+		}
+	}
+}
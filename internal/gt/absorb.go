@@ -0,0 +1,9 @@
+package gt
+
+import "context"
+
+// Absorb runs `gt absorb --force --no-interactive`, amending each staged
+// hunk into the downstack commit it belongs to, and returns gt's report.
+func (c *Client) Absorb(ctx context.Context) (string, error) {
+	return c.executor.Execute(ctx, "gt", "absorb", "--force", "--no-interactive")
+}
@@ -0,0 +1,25 @@
+package gt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAbsorb(t *testing.T) {
+	mock := &mockExecutor{output: "Absorbed 2 hunks\n"}
+	out, err := New(mock).Absorb(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"absorb", "--force", "--no-interactive"})
+	if out != "Absorbed 2 hunks\n" {
+		t.Errorf("Absorb() = %q", out)
+	}
+}
+
+func TestDemo_AbsorbNeedsStagedChanges(t *testing.T) {
+	_, client := newTestDemo()
+	if _, err := client.Absorb(context.Background()); err == nil {
+		t.Error("the demo has nothing staged, so absorb should fail")
+	}
+}
@@ -0,0 +1,43 @@
+package gt
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// CheckRun is one CI check reported on a PR.
+type CheckRun struct {
+	Name   string `json:"name"`
+	Bucket string `json:"bucket"` // "pass", "fail", "pending", "skipping" or "cancel"
+}
+
+// Failed reports whether the check failed or was cancelled.
+func (c CheckRun) Failed() bool {
+	return c.Bucket == "fail" || c.Bucket == "cancel"
+}
+
+// RequiredChecks runs `gh pr checks <branchName> --required --json
+// name,bucket` and returns the required checks on the branch's PR. gh exits
+// non-zero when checks fail or are pending but still prints them; a PR
+// without required checks returns none.
+func (c *Client) RequiredChecks(ctx context.Context, branchName string) ([]CheckRun, error) {
+	out, err := c.executor.Execute(ctx, "gh", "pr", "checks", branchName, "--required", "--json", "name,bucket")
+	if checks, ok := ParseChecks(out); ok {
+		return checks, nil
+	}
+	if err != nil && strings.Contains(err.Error(), "no required checks") {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// ParseChecks parses the JSON output of RequiredChecks, reporting false if
+// it isn't a check list.
+func ParseChecks(output string) ([]CheckRun, bool) {
+	var checks []CheckRun
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &checks); err != nil {
+		return nil, false
+	}
+	return checks, true
+}
@@ -0,0 +1,33 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRequiredChecks(t *testing.T) {
+	mock := &mockExecutor{output: `[{"name":"build","bucket":"pass"},{"name":"lint","bucket":"fail"}]`, err: errors.New("exit status 1")}
+	client := New(mock)
+
+	checks, err := client.RequiredChecks(context.Background(), "feature")
+	if err != nil {
+		t.Fatalf("failing checks should not be an error: %v", err)
+	}
+	assertCommand(t, mock, "gh", []string{"pr", "checks", "feature", "--required", "--json", "name,bucket"})
+	if len(checks) != 2 || checks[0].Failed() || !checks[1].Failed() {
+		t.Errorf("checks = %+v", checks)
+	}
+}
+
+func TestRequiredChecks_None(t *testing.T) {
+	client := New(&mockExecutor{err: errors.New("no required checks reported on the 'feature' branch")})
+	if checks, err := client.RequiredChecks(context.Background(), "feature"); err != nil || len(checks) != 0 {
+		t.Errorf("checks = %+v, err = %v, want none", checks, err)
+	}
+
+	client = New(&mockExecutor{err: errors.New("gh: not logged in")})
+	if _, err := client.RequiredChecks(context.Background(), "feature"); err == nil {
+		t.Error("other gh failures should be returned")
+	}
+}
@@ -0,0 +1,180 @@
+package gt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PRComment is one comment on a PR: in the conversation, a review's
+// summary, or a reply in a review thread.
+type PRComment struct {
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// ReviewThread is a review comment on a line of a file and its replies.
+type ReviewThread struct {
+	Path     string
+	Line     int // 0 when the line is outdated
+	Resolved bool
+	Comments []PRComment
+}
+
+// PRDiscussion is a PR's conversation, oldest first, and its review
+// threads.
+type PRDiscussion struct {
+	Comments []PRComment
+	Threads  []ReviewThread
+}
+
+// Unresolved counts the threads not yet resolved.
+func (d PRDiscussion) Unresolved() int {
+	n := 0
+	for _, t := range d.Threads {
+		if !t.Resolved {
+			n++
+		}
+	}
+	return n
+}
+
+// prDiscussionQuery asks GitHub's GraphQL API for a PR's conversation
+// comments, review summaries and review threads.
+const prDiscussionQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      comments(first: 100) { nodes { author { login } body createdAt } }
+      reviews(first: 50) { nodes { author { login } body createdAt } }
+      reviewThreads(first: 100) {
+        nodes {
+          isResolved path line
+          comments(first: 50) { nodes { author { login } body createdAt } }
+        }
+      }
+    }
+  }
+}`
+
+// unresolvedThreadsQuery asks only whether each review thread is resolved.
+const unresolvedThreadsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100) { nodes { isResolved } }
+    }
+  }
+}`
+
+// commentJSON is a comment node in the GraphQL response.
+type commentJSON struct {
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (c commentJSON) comment() PRComment {
+	return PRComment{Author: c.Author.Login, Body: c.Body, CreatedAt: c.CreatedAt}
+}
+
+// prDiscussionJSON matches the response to prDiscussionQuery and
+// unresolvedThreadsQuery.
+type prDiscussionJSON struct {
+	Data struct {
+		Repository struct {
+			PullRequest *struct {
+				Comments struct {
+					Nodes []commentJSON `json:"nodes"`
+				} `json:"comments"`
+				Reviews struct {
+					Nodes []commentJSON `json:"nodes"`
+				} `json:"reviews"`
+				ReviewThreads struct {
+					Nodes []struct {
+						IsResolved bool   `json:"isResolved"`
+						Path       string `json:"path"`
+						Line       int    `json:"line"`
+						Comments   struct {
+							Nodes []commentJSON `json:"nodes"`
+						} `json:"comments"`
+					} `json:"nodes"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+}
+
+// prGraphQL runs `gh api graphql` with query for PR number in the current
+// repository; gh fills in {owner} and {repo}.
+func (c *Client) prGraphQL(ctx context.Context, query string, number int) (string, error) {
+	return c.executor.Execute(ctx, "gh", "api", "graphql",
+		"-F", "owner={owner}", "-F", "name={repo}", "-F", fmt.Sprintf("number=%d", number),
+		"-f", "query="+query)
+}
+
+// PRDiscussion fetches PR number's conversation and review threads via
+// `gh api graphql`.
+func (c *Client) PRDiscussion(ctx context.Context, number int) (PRDiscussion, error) {
+	out, err := c.prGraphQL(ctx, prDiscussionQuery, number)
+	if err != nil {
+		return PRDiscussion{}, err
+	}
+	return ParsePRDiscussion(out)
+}
+
+// UnresolvedThreads counts PR number's unresolved review threads via `gh
+// api graphql`.
+func (c *Client) UnresolvedThreads(ctx context.Context, number int) (int, error) {
+	out, err := c.prGraphQL(ctx, unresolvedThreadsQuery, number)
+	if err != nil {
+		return 0, err
+	}
+	d, err := ParsePRDiscussion(out)
+	return d.Unresolved(), err
+}
+
+// CommentPR runs `gh pr comment <branchName> --body <body>`, adding body
+// to the conversation on the branch's PR.
+func (c *Client) CommentPR(ctx context.Context, branchName, body string) error {
+	_, err := c.executor.Execute(ctx, "gh", "pr", "comment", branchName, "--body", body)
+	return err
+}
+
+// ParsePRDiscussion parses a response to PRDiscussion's query. Review
+// summaries join the conversation, which is sorted oldest first; empty
+// review summaries, left by reviews made only of line comments, are
+// dropped.
+func ParsePRDiscussion(output string) (PRDiscussion, error) {
+	var raw prDiscussionJSON
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &raw); err != nil {
+		return PRDiscussion{}, fmt.Errorf("parsing PR discussion: %w", err)
+	}
+	pr := raw.Data.Repository.PullRequest
+	if pr == nil {
+		return PRDiscussion{}, errors.New("no pull request in response")
+	}
+	var d PRDiscussion
+	for _, c := range pr.Comments.Nodes {
+		d.Comments = append(d.Comments, c.comment())
+	}
+	for _, c := range pr.Reviews.Nodes {
+		if strings.TrimSpace(c.Body) != "" {
+			d.Comments = append(d.Comments, c.comment())
+		}
+	}
+	slices.SortStableFunc(d.Comments, func(a, b PRComment) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	for _, t := range pr.ReviewThreads.Nodes {
+		thread := ReviewThread{Path: t.Path, Line: t.Line, Resolved: t.IsResolved}
+		for _, c := range t.Comments.Nodes {
+			thread.Comments = append(thread.Comments, c.comment())
+		}
+		d.Threads = append(d.Threads, thread)
+	}
+	return d, nil
+}
@@ -0,0 +1,67 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const discussionOutput = `{"data":{"repository":{"pullRequest":{
+  "comments":{"nodes":[{"author":{"login":"bob"},"body":"Ping?","createdAt":"2026-02-03T10:00:00Z"}]},
+  "reviews":{"nodes":[
+    {"author":{"login":"alice"},"body":"A few nits.","createdAt":"2026-02-02T10:00:00Z"},
+    {"author":{"login":"alice"},"body":"","createdAt":"2026-02-02T11:00:00Z"}
+  ]},
+  "reviewThreads":{"nodes":[
+    {"isResolved":false,"path":"auth/login.go","line":12,"comments":{"nodes":[{"author":{"login":"alice"},"body":"Typo","createdAt":"2026-02-02T10:00:00Z"}]}},
+    {"isResolved":true,"path":"auth/login.go","line":null,"comments":{"nodes":[]}}
+  ]}
+}}}}`
+
+func TestPRDiscussion_Success(t *testing.T) {
+	mock := &mockExecutor{output: discussionOutput}
+	client := New(mock)
+
+	d, err := client.PRDiscussion(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(d.Comments) != 2 || d.Comments[0].Author != "alice" || d.Comments[1].Body != "Ping?" {
+		t.Errorf("comments = %+v, want alice's review summary then bob's comment", d.Comments)
+	}
+	if len(d.Threads) != 2 || d.Threads[0].Line != 12 || d.Threads[0].Comments[0].Body != "Typo" || d.Unresolved() != 1 {
+		t.Errorf("threads = %+v", d.Threads)
+	}
+	if mock.calledName != "gh" || len(mock.calledArgs) != 10 || mock.calledArgs[1] != "graphql" || mock.calledArgs[7] != "number=42" ||
+		!strings.Contains(mock.calledArgs[9], "reviewThreads") {
+		t.Errorf("command = %s %v", mock.calledName, mock.calledArgs)
+	}
+}
+
+func TestUnresolvedThreads(t *testing.T) {
+	client := New(&mockExecutor{output: discussionOutput})
+	if n, err := client.UnresolvedThreads(context.Background(), 42); err != nil || n != 1 {
+		t.Errorf("unresolved = %d, %v, want 1", n, err)
+	}
+}
+
+func TestPRDiscussion_Error(t *testing.T) {
+	client := New(&mockExecutor{err: errors.New("HTTP 401")})
+	if _, err := client.PRDiscussion(context.Background(), 42); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, output := range []string{"not json", `{"data":{"repository":{"pullRequest":null}}}`} {
+		if _, err := ParsePRDiscussion(output); err == nil {
+			t.Errorf("ParsePRDiscussion(%q) should fail", output)
+		}
+	}
+}
+
+func TestCommentPR(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).CommentPR(context.Background(), "feature", "rebased, PTAL"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "gh", []string{"pr", "comment", "feature", "--body", "rebased, PTAL"})
+}
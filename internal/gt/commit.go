@@ -0,0 +1,21 @@
+package gt
+
+import "context"
+
+// CommitCreate runs `gt commit create -m <message> --no-interactive`,
+// committing the staged changes to the current branch and restacking the
+// branches above it. message may span several lines.
+func (c *Client) CommitCreate(ctx context.Context, message string) error {
+	_, err := c.executor.Execute(ctx, "gt", "commit", "create", "-m", message, "--no-interactive")
+	return err
+}
+
+// StagedFiles runs `git diff --cached --name-only` and returns the paths
+// staged for the next commit.
+func (c *Client) StagedFiles(ctx context.Context) ([]string, error) {
+	out, err := c.executor.Execute(ctx, "git", "diff", "--cached", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+	return ParseNameOnly(out), nil
+}
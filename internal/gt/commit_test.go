@@ -0,0 +1,38 @@
+package gt
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCommitCreate(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).CommitCreate(context.Background(), "Add login\n\nWith tests."); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"commit", "create", "-m", "Add login\n\nWith tests.", "--no-interactive"})
+}
+
+func TestStagedFiles(t *testing.T) {
+	mock := &mockExecutor{output: "a.go\npkg/b.go\n"}
+	files, err := New(mock).StagedFiles(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"diff", "--cached", "--name-only"})
+	if want := []string{"a.go", "pkg/b.go"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("StagedFiles() = %v, want %v", files, want)
+	}
+}
+
+func TestDemo_CommitNeedsStagedChanges(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	if files, err := client.StagedFiles(ctx); err != nil || len(files) != 0 {
+		t.Errorf("StagedFiles() = %v, %v; the demo stages nothing", files, err)
+	}
+	if err := client.CommitCreate(ctx, "msg"); err == nil {
+		t.Error("the demo has nothing staged, so commit should fail")
+	}
+}
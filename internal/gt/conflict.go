@@ -0,0 +1,60 @@
+package gt
+
+import (
+	"context"
+	"strings"
+)
+
+// unmergedCodes are the `git status --porcelain` XY codes of paths with
+// unresolved merge conflicts.
+var unmergedCodes = map[string]bool{
+	"DD": true, "AU": true, "UD": true, "UA": true, "DU": true, "AA": true, "UU": true,
+}
+
+// ConflictedFiles runs `git status --porcelain` and returns the paths with
+// unresolved conflicts, relative to the repository root.
+func (c *Client) ConflictedFiles(ctx context.Context) ([]string, error) {
+	out, err := c.StatusPorcelain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConflicts(out), nil
+}
+
+// ParseConflicts extracts the unmerged paths from `git status --porcelain`
+// output, in the order git lists them.
+func ParseConflicts(output string) []string {
+	var paths []string
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 4 || !unmergedCodes[line[:2]] {
+			continue
+		}
+		paths = append(paths, unquotePath(line[3:]))
+	}
+	return paths
+}
+
+// unquotePath undoes git's quoting of paths with unusual characters.
+func unquotePath(path string) string {
+	if len(path) < 2 || path[0] != '"' || path[len(path)-1] != '"' {
+		return path
+	}
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\t`, "\t").Replace(path[1 : len(path)-1])
+}
+
+// Abort runs `gt abort --force`, abandoning the restack or other gt
+// command halted by a conflict. grit asks before calling it.
+func (c *Client) Abort(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "abort", "--force")
+	return err
+}
+
+// TopLevel runs `git rev-parse --show-toplevel` and returns the root of
+// the working tree, which porcelain status paths are relative to.
+func (c *Client) TopLevel(ctx context.Context) (string, error) {
+	out, err := c.executor.Execute(ctx, "git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
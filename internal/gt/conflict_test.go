@@ -0,0 +1,48 @@
+package gt
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseConflicts(t *testing.T) {
+	out := "UU api/server.go\n M README.md\nAA new.go\n?? scratch.txt\nUD \"dir/with \\\"quote\\\".go\"\nDU gone.go\n"
+	want := []string{"api/server.go", "new.go", `dir/with "quote".go`, "gone.go"}
+	if got := ParseConflicts(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseConflicts = %q, want %q", got, want)
+	}
+	if got := ParseConflicts(" M README.md\n"); got != nil {
+		t.Errorf("clean merge should have no conflicts, got %q", got)
+	}
+}
+
+func TestConflictedFiles(t *testing.T) {
+	mock := &mockExecutor{output: "UU a.go\n"}
+	client := New(mock)
+	files, err := client.ConflictedFiles(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCommand(t, mock, "git", []string{"status", "--porcelain"})
+	if !reflect.DeepEqual(files, []string{"a.go"}) {
+		t.Errorf("files = %q", files)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).Abort(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertCommand(t, mock, "gt", []string{"abort", "--force"})
+}
+
+func TestTopLevel(t *testing.T) {
+	mock := &mockExecutor{output: "/home/u/repo\n"}
+	root, err := New(mock).TopLevel(context.Background())
+	if err != nil || root != "/home/u/repo" {
+		t.Errorf("TopLevel = %q, %v", root, err)
+	}
+	assertCommand(t, mock, "git", []string{"rev-parse", "--show-toplevel"})
+}
@@ -0,0 +1,59 @@
+package gt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnonymizeLogShort replaces the branch names in `gt log short` output
+// with placeholders, keeping the graph, markers, spacing and annotations
+// the parser reads, so real output can be attached to a parser bug report.
+// Trunk (the last branch listed) becomes "main" and the others "branch-1",
+// "branch-2" and so on, numbered from trunk upwards. It fails rather than
+// leave a name it can't find in its line, e.g. one containing graph
+// characters the parser strips.
+func AnonymizeLogShort(output string) (string, error) {
+	lines := strings.Split(output, "\n")
+	aliases := make(map[string]string)
+	for i := len(lines) - 1; i >= 0; i-- {
+		pl, ok := parseLine(lines[i])
+		if !ok {
+			continue
+		}
+		if _, seen := aliases[pl.name]; seen {
+			continue
+		}
+		if len(aliases) == 0 {
+			aliases[pl.name] = "main"
+		} else {
+			aliases[pl.name] = fmt.Sprintf("branch-%d", len(aliases))
+		}
+	}
+	for i, line := range lines {
+		pl, ok := parseLine(line)
+		if !ok {
+			continue
+		}
+		// The name follows the marker; searching from there keeps graph
+		// characters that happen to match it intact.
+		marker := strings.IndexAny(line, string(currentMarker)+string(otherMarker))
+		at := strings.Index(line[marker:], pl.name)
+		if at < 0 {
+			return "", fmt.Errorf("cannot anonymize line %d: branch name %q not found in %q", i+1, pl.name, line)
+		}
+		at += marker
+		lines[i] = line[:at] + aliases[pl.name] + line[at+len(pl.name):]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// CorpusFile names and formats a capture of anonymized output recorded
+// with gt version, e.g. "gt-1.4.2-deep-stack.txt". The version
+// goes in a header comment, which the parser skips like any line without a
+// marker.
+func CorpusFile(version, name, anonymized string) (filename, content string) {
+	v := strings.TrimPrefix(strings.Fields(version + " unknown")[0], "v")
+	filename = "gt-" + v + "-" + name + ".txt"
+	content = "# gt " + strings.TrimSpace(version) + "\n" + strings.TrimRight(anonymized, "\n") + "\n"
+	return filename, content
+}
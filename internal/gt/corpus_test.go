@@ -0,0 +1,70 @@
+package gt
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// describeTree renders branches one per line, indented by tree depth, with
+// everything the parser derives from the output.
+func describeTree(branches []*Branch) string {
+	var sb strings.Builder
+	var walk func(b *Branch, level int)
+	walk = func(b *Branch, level int) {
+		fmt.Fprintf(&sb, "%s%s depth=%d order=%d", strings.Repeat("  ", level), b.Name, b.Depth, b.Order)
+		if b.IsCurrent {
+			sb.WriteString(" current")
+		}
+		if b.Annotation != "" {
+			fmt.Fprintf(&sb, " (%s)", b.Annotation)
+		}
+		if b.Stack.Size > 0 {
+			fmt.Fprintf(&sb, " stack=%d/%d", b.Stack.Index, b.Stack.Size)
+		}
+		sb.WriteString("\n")
+		for _, child := range b.Children {
+			walk(child, level+1)
+		}
+	}
+	for _, root := range branches {
+		walk(root, 0)
+	}
+	return sb.String()
+}
+
+func TestAnonymizeLogShort(t *testing.T) {
+	input := "◯      fix-graph\n│ ◉    auth (needs restack)\n│ ◯    auth-base\n◯─┘    master"
+	want := "◯      branch-3\n│ ◉    branch-2 (needs restack)\n│ ◯    branch-1\n◯─┘    main"
+	if got, err := AnonymizeLogShort(input); err != nil || got != want {
+		t.Errorf("AnonymizeLogShort =\n%s\nwant\n%s (err %v)", got, want, err)
+	}
+
+	before, _ := ParseLogShort(input)
+	after, _ := ParseLogShort(want)
+	shape := func(bs []*Branch) string {
+		s := describeTree(bs)
+		for _, name := range []string{"fix-graph", "auth-base", "auth", "master", "branch-3", "branch-2", "branch-1", "main"} {
+			s = strings.ReplaceAll(s, name, "x")
+		}
+		return s
+	}
+	if shape(before) != shape(after) {
+		t.Errorf("anonymizing changed the parsed shape:\n%s\nvs\n%s", describeTree(before), describeTree(after))
+	}
+
+	if _, err := AnonymizeLogShort("│ ◯  odd─┘name\n◯─┘  main"); err == nil {
+		t.Error("a name that can't be found in its line should be an error, not left in place")
+	}
+}
+
+func TestCorpusFile(t *testing.T) {
+	filename, content := CorpusFile("1.4.2\n", "deep-stack", "◉  main\n")
+	if filename != "gt-1.4.2-deep-stack.txt" || content != "# gt 1.4.2\n◉  main\n" {
+		t.Errorf("CorpusFile = %q, %q", filename, content)
+	}
+	branches, err := ParseLogShort(content)
+	if err != nil || len(branches) != 1 || branches[0].Name != "main" {
+		t.Errorf("the header should not parse as a branch: %v, %v", branches, err)
+	}
+}
@@ -0,0 +1,1082 @@
+package gt
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// demoBranch is a branch of the repository simulated by DemoExecutor.
+type demoBranch struct {
+	name      string
+	parent    string // "" for trunk
+	subject   string
+	title     string // PR title if edited, else the subject
+	body      string // PR description
+	files     []demoFile
+	age       time.Duration // since the branch's first commit
+	restack   bool          // needs restack
+	rev       int           // bumped whenever the branch is rewritten
+	pr        int           // PR number, 0 if never submitted
+	state     string        // PR state
+	pushed    int           // rev the PR head points at
+	merged    time.Time     // when the PR merged
+	reviewers []string
+	labels    []string
+	assignees []string
+	comments  []PRComment    // PR conversation
+	threads   []ReviewThread // PR review threads
+}
+
+// demoFile is a file a demo branch adds lines to.
+type demoFile struct {
+	path  string
+	lines []string
+}
+
+// DemoExecutor is a CommandExecutor that answers the gt, git and gh
+// commands grit runs from a simulated repository instead of running them:
+// two stacks and a standalone branch off main with PRs in every state.
+// Actions change the simulated state and every command sleeps for a fixed,
+// realistic latency, so runs are repeatable without touching any repo.
+type DemoExecutor struct {
+	mu       sync.Mutex
+	branches []*demoBranch // trunk first, each branch after its parent
+	current  string
+	nextPR   int
+	now      time.Time
+	scale    float64 // latency multiplier; 0 disables sleeping
+}
+
+// NewDemoExecutor creates a DemoExecutor with the initial demo repository.
+func NewDemoExecutor() *DemoExecutor {
+	day := 24 * time.Hour
+	now := time.Now()
+	return &DemoExecutor{
+		branches: []*demoBranch{
+			{name: "main"},
+			{name: "auth-session-store", parent: "main", subject: "Add session store", age: 9 * day, pr: 412, state: "MERGED", merged: now.Add(-4 * day),
+				files: []demoFile{{"auth/session.go", []string{"type SessionStore struct {", "\tttl time.Duration", "}"}}}},
+			{name: "auth-login-api", parent: "auth-session-store", subject: "Add login endpoint", age: 6 * day, pr: 418, state: "OPEN", reviewers: []string{"alice", "platform-team"},
+				labels: []string{"breaking", "needs-qa"}, assignees: []string{"dana"},
+				comments: []PRComment{
+					{Author: "alice", Body: "Looks good overall. Can we get a test for an expired session?", CreatedAt: now.Add(-2 * day)},
+					{Author: "dana", Body: "Added one in login_test.go.", CreatedAt: now.Add(-day)},
+				},
+				threads: []ReviewThread{
+					{Path: "auth/login.go", Line: 2, Comments: []PRComment{
+						{Author: "alice", Body: "Should this check the session's TTL first?", CreatedAt: now.Add(-2 * day)},
+					}},
+					{Path: "auth/login.go", Line: 3, Resolved: true, Comments: []PRComment{
+						{Author: "platform-team", Body: "Save can fail; handle the error.", CreatedAt: now.Add(-3 * day)},
+						{Author: "dana", Body: "Done.", CreatedAt: now.Add(-2 * day)},
+					}},
+				},
+				files: []demoFile{
+					{"auth/login.go", []string{"func Login(w http.ResponseWriter, r *http.Request) {", "\tsession := store.New(r)", "\tsession.Save(w)", "}"}},
+					{"auth/login_test.go", []string{"func TestLogin(t *testing.T) {}"}},
+				}},
+			{name: "auth-login-ui", parent: "auth-login-api", subject: "Add login form", age: 3 * day, rev: 1, pr: 421, state: "DRAFT", reviewers: []string{"bob"},
+				files: []demoFile{{"web/login.tsx", []string{"export function LoginForm() {", "  return <form method=\"post\" />", "}"}}}},
+			{name: "auth-remember-me", parent: "auth-login-ui", subject: "Remember me checkbox", age: day, restack: true,
+				files: []demoFile{{"web/login.tsx", []string{"  <input type=\"checkbox\" name=\"remember\" />"}}}},
+			{name: "search-index", parent: "main", subject: "Build search index", age: 4 * day, pr: 424, state: "OPEN", labels: []string{"perf"},
+				files: []demoFile{{"search/index.go", []string{"func Build(docs []Doc) *Index {", "\treturn newIndex(docs)", "}"}}}},
+			{name: "search-ranking", parent: "search-index", subject: "Rank by recency", age: 2 * day,
+				files: []demoFile{{"search/rank.go", []string{"func Rank(hits []Hit) {", "\tsort.Slice(hits, byRecency(hits))", "}"}}}},
+			{name: "docs-typos", parent: "main", subject: "Fix typos in README", age: 12 * day, pr: 399, state: "CLOSED",
+				files: []demoFile{{"README.md", []string{"Grit shows your stacks."}}}},
+		},
+		current: "auth-login-ui",
+		nextPR:  430,
+		now:     now,
+		scale:   1,
+	}
+}
+
+// NewDemo creates a Client backed by a new DemoExecutor, with remote
+// metadata calls rate limited and coalesced as in NewDefault.
+func NewDemo() *Client {
+	limiter := NewLimiter(DefaultRemoteRate, DefaultRemoteBurst)
+	return &Client{executor: NewRemoteExecutor(NewDemoExecutor(), limiter)}
+}
+
+// Execute simulates the command. Unknown commands fail.
+func (d *DemoExecutor) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	latency, run := d.command(name, args)
+	if run == nil {
+		return "", fmt.Errorf("demo: unsupported command: %s", FormatCommand(name, args...))
+	}
+	timer := time.NewTimer(time.Duration(float64(latency) * d.scale))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return run()
+}
+
+// command returns how long name args takes and a function producing its
+// result, or a nil function if the command isn't simulated. The function
+// runs with d.mu held.
+func (d *DemoExecutor) command(name string, args []string) (time.Duration, func() (string, error)) {
+	arg := func(i int) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return ""
+	}
+	flag := func(flag string) string {
+		if i := slices.Index(args, flag); i >= 0 {
+			return arg(i + 1)
+		}
+		return ""
+	}
+	ms := time.Millisecond
+
+	switch name + " " + arg(0) {
+	case "gt log":
+		return 80 * ms, func() (string, error) { return d.logShort(), nil }
+	case "gt checkout":
+		return 150 * ms, func() (string, error) { return "", d.checkout(arg(1)) }
+	case "gt up", "gt down", "gt top", "gt bottom":
+		return 150 * ms, func() (string, error) { return "", d.navigate(arg(0)) }
+	case "gt create":
+		if slices.Contains(args, "--insert") {
+			return 300 * ms, func() (string, error) { return "", d.insert(arg(1)) }
+		}
+		return 300 * ms, func() (string, error) { return "", d.create(arg(1)) }
+	case "gt rename":
+		return 250 * ms, func() (string, error) { return "", d.rename(arg(1)) }
+	case "gt delete":
+		return 200 * ms, func() (string, error) { return "", d.delete(arg(1)) }
+	case "gt pop":
+		return 300 * ms, func() (string, error) { return "", d.pop() }
+	case "gt fold":
+		return 700 * ms, func() (string, error) { return "", d.fold(flag("--branch")) }
+	case "gt move":
+		return 800 * ms, func() (string, error) { return "", d.move(flag("--branch"), flag("--onto")) }
+	case "gt stack", "gt downstack":
+		target := flag("--branch")
+		switch arg(1) {
+		case "submit":
+			return 1500 * ms, func() (string, error) { return "", d.submit(target, arg(0) == "stack") }
+		case "restack":
+			return 600 * ms, func() (string, error) { return "", d.restackStack(target) }
+		}
+	case "gt upstack":
+		if arg(1) == "restack" {
+			return 400 * ms, func() (string, error) { return "", d.restackUpstack(flag("--branch")) }
+		}
+	case "gt submit":
+		if slices.Contains(args, "--publish") {
+			return 900 * ms, func() (string, error) { return "", d.publish(flag("--branch")) }
+		}
+		return 900 * ms, func() (string, error) { return "", d.submitBranch(flag("--branch"), slices.Contains(args, "--draft")) }
+	case "gt merge":
+		return 1200 * ms, func() (string, error) { return "", d.merge() }
+	case "gt repo":
+		switch arg(1) {
+		case "sync":
+			return 900 * ms, func() (string, error) { return "", nil }
+		case "init":
+			return 200 * ms, func() (string, error) { return "", nil }
+		}
+	case "gt sync":
+		return 1800 * ms, func() (string, error) { return "", d.sync(slices.Contains(args, "-f")) }
+	case "gt absorb":
+		return 100 * ms, func() (string, error) { return "", fmt.Errorf("no staged changes to absorb") }
+	case "gt commit":
+		return 100 * ms, func() (string, error) { return "", fmt.Errorf("no staged changes to commit") }
+	case "gt modify":
+		return 100 * ms, func() (string, error) { return "", fmt.Errorf("no changes to amend") }
+	case "gt continue", "gt abort":
+		return 100 * ms, func() (string, error) { return "", fmt.Errorf("no rebase in progress") }
+	case "gt pr":
+		return 100 * ms, func() (string, error) { return "", d.need(arg(1)) }
+	case "gt branch":
+		switch arg(1) {
+		case "pr-info":
+			return 350 * ms, func() (string, error) { return d.prInfo(flag("--branch")) }
+		case "restack":
+			return 250 * ms, func() (string, error) { return "", d.restackBranch(flag("--branch")) }
+		}
+	case "gh pr":
+		switch arg(1) {
+		case "merge":
+			return 800 * ms, func() (string, error) { return "", d.mergePR(arg(2)) }
+		case "comment":
+			return 600 * ms, func() (string, error) { return "", d.commentPR(arg(2), flag("--body")) }
+		case "list":
+			return 400 * ms, func() (string, error) { return d.prList(flag("--head")) }
+		case "edit":
+			if flag("--add-label") != "" || flag("--remove-label") != "" {
+				return 500 * ms, func() (string, error) {
+					return "", d.editLabels(arg(2), demoList(flag("--add-label")), demoList(flag("--remove-label")))
+				}
+			}
+			if reviewers := flag("--add-reviewer"); reviewers != "" {
+				return 500 * ms, func() (string, error) { return "", d.requestReviewers(arg(2), strings.Split(reviewers, ",")) }
+			}
+			return 500 * ms, func() (string, error) { return "", d.editPR(arg(2), flag("--title"), flag("--body")) }
+		}
+		switch flag("--json") {
+		case prDetailsFields:
+			return 400 * ms, func() (string, error) { return d.prDetails(arg(2)) }
+		case "title,body":
+			return 300 * ms, func() (string, error) { return d.prText(arg(2)) }
+		case "reviewRequests,reviews":
+			return 400 * ms, func() (string, error) { return d.prReviewers(arg(2)) }
+		case "name,bucket":
+			return 400 * ms, func() (string, error) { return d.prChecks(arg(2)) }
+		case "mergedAt":
+			return 300 * ms, func() (string, error) { return d.prMergedAt(arg(2)) }
+		}
+	case "open " + arg(0), "xdg-open " + arg(0), "rundll32 " + arg(0):
+		// The demo doesn't open a browser.
+		return 50 * ms, func() (string, error) { return "", nil }
+	case "gh label":
+		return 300 * ms, func() (string, error) { return demoRepoLabels, nil }
+	case "gh api":
+		if arg(1) == "graphql" {
+			return 400 * ms, func() (string, error) { return d.prDiscussion(demoGraphQLNumber(args)) }
+		}
+		return 200 * ms, func() (string, error) { return d.rateLimit(), nil }
+	case "git symbolic-ref":
+		return 10 * ms, func() (string, error) { return d.current + "\n", nil }
+	case "git for-each-ref":
+		if args[len(args)-1] == "refs/branch-metadata" {
+			return 20 * ms, func() (string, error) { return d.metadata(), nil }
+		}
+		return 20 * ms, func() (string, error) { return d.heads(), nil }
+	case "git remote":
+		return 10 * ms, func() (string, error) { return "git@github.com:acme/demo.git\n", nil }
+	case "git status":
+		return 20 * ms, func() (string, error) { return "", nil }
+	case "git fetch":
+		return 600 * ms, func() (string, error) { return "", nil }
+	case "git rev-list":
+		// origin/main is a few commits ahead of the local trunk.
+		return 20 * ms, func() (string, error) { return "7\n", nil }
+	case "git push":
+		if slices.Contains(args, "--delete") {
+			return 500 * ms, func() (string, error) { return "", d.deleteRemote(args[len(args)-1]) }
+		}
+	case "git branch":
+		return 20 * ms, func() (string, error) { return d.merged(), nil }
+	case "git merge-tree":
+		return 80 * ms, func() (string, error) { return d.mergeTree(args[len(args)-1]) }
+	case "git commit":
+		return 50 * ms, func() (string, error) { return "", fmt.Errorf("nothing to commit, working tree clean") }
+	case "git diff":
+		if arg(1) == "--cached" {
+			// The demo has no working tree, so nothing is staged.
+			return 20 * ms, func() (string, error) { return "", nil }
+		}
+		return 60 * ms, func() (string, error) { return d.diff(args[1:]) }
+	case "git log":
+		return 40 * ms, func() (string, error) { return d.log(args[1:]) }
+	case "git worktree":
+		return 300 * ms, func() (string, error) { return "", nil }
+	case "sh -c":
+		return 2500 * ms, func() (string, error) { return "ok\n", nil }
+	}
+	return 0, nil
+}
+
+func (d *DemoExecutor) find(name string) *demoBranch {
+	for _, b := range d.branches {
+		if b.name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+func (d *DemoExecutor) need(name string) error {
+	if d.find(name) == nil {
+		return fmt.Errorf("branch %s does not exist", name)
+	}
+	return nil
+}
+
+func (d *DemoExecutor) children(name string) []*demoBranch {
+	var children []*demoBranch
+	for _, b := range d.branches {
+		if b.parent == name {
+			children = append(children, b)
+		}
+	}
+	return children
+}
+
+// stack returns the branches from the trunk-side base of name's stack up
+// to name, then, if upstack is set, the branches above name.
+func (d *DemoExecutor) stack(name string, upstack bool) []*demoBranch {
+	var stack []*demoBranch
+	for b := d.find(name); b != nil && b.parent != ""; b = d.find(b.parent) {
+		stack = append([]*demoBranch{b}, stack...)
+	}
+	for children := d.children(name); upstack && len(children) > 0; children = d.children(children[0].name) {
+		stack = append(stack, children[0])
+	}
+	return stack
+}
+
+// logShort renders the tree the way ParseLogShort reads it: each stack's
+// base at depth 0 and the rest of the stack at depth 1, stacks listed
+// top-of-stack first and trunk last.
+func (d *DemoExecutor) logShort() string {
+	var lines []string
+	for _, base := range d.children(d.branches[0].name) {
+		for i, b := range d.stack(base.name, true) {
+			prefix := "◯    "
+			if i > 0 {
+				prefix = "│ ◯  "
+			}
+			if b.name == d.current {
+				prefix = strings.Replace(prefix, "◯", "◉", 1)
+			}
+			line := prefix + b.name
+			if b.restack {
+				line += " (needs restack)"
+			}
+			lines = append(lines, line)
+		}
+	}
+	trunk := "◯─┘  " + d.branches[0].name
+	if d.current == d.branches[0].name {
+		trunk = "◉─┘  " + d.branches[0].name
+	}
+	lines = append(lines, trunk)
+	slices.Reverse(lines[:len(lines)-1])
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (d *DemoExecutor) checkout(name string) error {
+	if err := d.need(name); err != nil {
+		return err
+	}
+	d.current = name
+	return nil
+}
+
+// navigate checks out the branch gt up, down, top or bottom would move to
+// from the current branch.
+func (d *DemoExecutor) navigate(dir string) error {
+	trunk := d.branches[0].name
+	b := d.find(d.current)
+	switch dir {
+	case "down":
+		if b.parent == "" {
+			return fmt.Errorf("already at trunk")
+		}
+		b = d.find(b.parent)
+	case "bottom":
+		if b.parent == "" {
+			return fmt.Errorf("already at trunk")
+		}
+		for b.parent != trunk {
+			b = d.find(b.parent)
+		}
+	default:
+		for {
+			children := d.children(b.name)
+			if len(children) > 1 {
+				return fmt.Errorf("%s has more than one child", b.name)
+			}
+			if len(children) == 0 {
+				if dir == "up" {
+					return fmt.Errorf("already at the top of the stack")
+				}
+				break
+			}
+			b = children[0]
+			if dir == "up" {
+				break
+			}
+		}
+	}
+	d.current = b.name
+	return nil
+}
+
+// create stacks name on the current branch. Only linear stacks are
+// simulated, so a branch can't be created below another.
+func (d *DemoExecutor) create(name string) error {
+	if d.find(name) != nil {
+		return fmt.Errorf("branch %s already exists", name)
+	}
+	if d.current != d.branches[0].name && len(d.children(d.current)) > 0 {
+		return fmt.Errorf("demo: %s already has a branch stacked on it", d.current)
+	}
+	d.branches = append(d.branches, &demoBranch{
+		name:    name,
+		parent:  d.current,
+		subject: "WIP " + name,
+		files:   []demoFile{{strings.ReplaceAll(name, "-", "_") + ".go", []string{"// TODO"}}},
+	})
+	d.current = name
+	return nil
+}
+
+// insert creates name on the current branch and moves the current
+// branch's children onto it. They need a restack, name having a commit of
+// its own.
+func (d *DemoExecutor) insert(name string) error {
+	if d.find(name) != nil {
+		return fmt.Errorf("branch %s already exists", name)
+	}
+	children := d.children(d.current)
+	if len(children) == 0 {
+		return fmt.Errorf("demo: %s has no branch to insert below", d.current)
+	}
+	for _, child := range children {
+		child.parent = name
+		child.restack = true
+	}
+	d.branches = append(d.branches, &demoBranch{
+		name:    name,
+		parent:  d.current,
+		subject: "WIP " + name,
+		files:   []demoFile{{strings.ReplaceAll(name, "-", "_") + ".go", []string{"// TODO"}}},
+	})
+	d.current = name
+	return nil
+}
+
+func (d *DemoExecutor) rename(name string) error {
+	b := d.find(d.current)
+	switch {
+	case b.parent == "":
+		return fmt.Errorf("cannot rename trunk")
+	case d.find(name) != nil:
+		return fmt.Errorf("branch %s already exists", name)
+	}
+	for _, child := range d.children(b.name) {
+		child.parent = name
+	}
+	b.name, d.current = name, name
+	return nil
+}
+
+// delete removes name, reparenting its children onto its parent.
+func (d *DemoExecutor) delete(name string) error {
+	b := d.find(name)
+	switch {
+	case b == nil:
+		return fmt.Errorf("branch %s does not exist", name)
+	case b.parent == "":
+		return fmt.Errorf("cannot delete trunk")
+	}
+	for _, child := range d.children(name) {
+		child.parent = b.parent
+		child.restack = true
+	}
+	if d.current == name {
+		d.current = b.parent
+	}
+	d.branches = slices.DeleteFunc(d.branches, func(x *demoBranch) bool { return x == b })
+	return nil
+}
+
+// pop deletes the current branch, leaving its changes uncommitted on its
+// parent. The demo has no working tree, so the changes just vanish.
+func (d *DemoExecutor) pop() error {
+	if len(d.children(d.current)) > 0 {
+		return fmt.Errorf("cannot pop %s: it has branches stacked on it", d.current)
+	}
+	return d.delete(d.current)
+}
+
+// fold merges name's changes into its parent and deletes it.
+func (d *DemoExecutor) fold(name string) error {
+	b := d.find(name)
+	switch {
+	case b == nil:
+		return fmt.Errorf("branch %s does not exist", name)
+	case b.parent == "":
+		return fmt.Errorf("cannot fold trunk")
+	case b.parent == d.branches[0].name:
+		return fmt.Errorf("cannot fold into trunk")
+	}
+	parent := d.find(b.parent)
+	parent.files = append(parent.files, b.files...)
+	parent.rev++
+	if err := d.delete(name); err != nil {
+		return err
+	}
+	for _, child := range d.children(parent.name) {
+		child.restack = false
+	}
+	return nil
+}
+
+// move rebases name and the branches above it onto onto. Only linear
+// stacks are simulated, so onto must be trunk or the top of its stack.
+func (d *DemoExecutor) move(name, onto string) error {
+	b, target := d.find(name), d.find(onto)
+	switch {
+	case b == nil:
+		return fmt.Errorf("branch %s does not exist", name)
+	case target == nil:
+		return fmt.Errorf("branch %s does not exist", onto)
+	case b.parent == "":
+		return fmt.Errorf("cannot move trunk")
+	}
+	moved := []*demoBranch{b}
+	for children := d.children(name); len(children) > 0; children = d.children(children[0].name) {
+		moved = append(moved, children[0])
+	}
+	if slices.Contains(moved, target) {
+		return fmt.Errorf("cannot move %s onto itself or a branch above it", name)
+	}
+	if target.parent != "" && len(d.children(onto)) > 0 {
+		return fmt.Errorf("demo: %s already has a branch stacked on it", onto)
+	}
+	b.parent = onto
+	// Keep every branch after its parent.
+	d.branches = slices.DeleteFunc(d.branches, func(x *demoBranch) bool { return slices.Contains(moved, x) })
+	for _, m := range moved {
+		m.restack = false
+		m.rev++
+		d.branches = append(d.branches, m)
+	}
+	return nil
+}
+
+func (d *DemoExecutor) submit(name string, upstack bool) error {
+	if err := d.need(name); err != nil {
+		return err
+	}
+	return d.push(d.stack(name, upstack), false)
+}
+
+// submitBranch submits name alone, leaving the rest of its stack as is. A
+// new PR is opened as a draft if draft is set.
+func (d *DemoExecutor) submitBranch(name string, draft bool) error {
+	if err := d.need(name); err != nil {
+		return err
+	}
+	return d.push([]*demoBranch{d.find(name)}, draft)
+}
+
+// publish submits name and marks its PR ready for review.
+func (d *DemoExecutor) publish(name string) error {
+	if err := d.submitBranch(name, false); err != nil {
+		return err
+	}
+	if b := d.find(name); b.state == "DRAFT" {
+		b.state = "OPEN"
+	}
+	return nil
+}
+
+// deleteRemote fails like git for branches that were never pushed, which
+// in the demo are those without a PR.
+func (d *DemoExecutor) deleteRemote(name string) error {
+	if b := d.find(name); b == nil || b.pr == 0 {
+		return fmt.Errorf("error: unable to delete '%s': remote ref does not exist", name)
+	}
+	return nil
+}
+
+// merge checks the PRs below and including the current branch can be
+// queued. The demo's queue never lands them: they stay open.
+func (d *DemoExecutor) merge() error {
+	for _, b := range d.stack(d.current, false) {
+		switch {
+		case b.state == "MERGED":
+		case b.pr == 0 || b.state == "CLOSED":
+			return fmt.Errorf("%s has no open PR to merge", b.name)
+		case b.state == "DRAFT":
+			return fmt.Errorf("%s's PR is a draft", b.name)
+		case b.restack:
+			return fmt.Errorf("%s needs to be restacked before merging", b.name)
+		}
+	}
+	return nil
+}
+
+// mergePR merges name's open PR on GitHub, as `gh pr merge` does. Like
+// gh it refuses drafts, and like GitHub's branch protection it refuses PRs
+// that need a restack.
+func (d *DemoExecutor) mergePR(name string) error {
+	b := d.find(name)
+	switch {
+	case b == nil || b.pr == 0 || b.state == "CLOSED" || b.state == "MERGED":
+		return fmt.Errorf("no open pull requests found for branch %q", name)
+	case b.state == "DRAFT":
+		return fmt.Errorf("Pull request #%d is still a draft", b.pr)
+	case b.restack:
+		return fmt.Errorf("Pull request #%d is not mergeable: the merge commit cannot be cleanly created", b.pr)
+	}
+	b.state = "MERGED"
+	b.merged = d.now
+	return nil
+}
+
+// push opens or updates a PR for each branch, refusing if any needs a
+// restack. New PRs are opened as drafts if draft is set.
+func (d *DemoExecutor) push(branches []*demoBranch, draft bool) error {
+	for _, b := range branches {
+		if b.restack {
+			return fmt.Errorf("%s needs to be restacked before submitting", b.name)
+		}
+	}
+	for _, b := range branches {
+		if b.pr == 0 || b.state == "CLOSED" {
+			b.pr, b.state = d.nextPR, "OPEN"
+			if draft {
+				b.state = "DRAFT"
+			}
+			d.nextPR++
+		}
+		b.pushed = b.rev
+	}
+	return nil
+}
+
+func (d *DemoExecutor) restackStack(name string) error {
+	if err := d.need(name); err != nil {
+		return err
+	}
+	for _, b := range d.stack(name, true) {
+		if b.restack {
+			b.restack = false
+			b.rev++
+		}
+	}
+	return nil
+}
+
+// restackBranch restacks name alone; branches stacked on it then need a
+// restack of their own.
+func (d *DemoExecutor) restackBranch(name string) error {
+	b := d.find(name)
+	if b == nil {
+		return fmt.Errorf("branch %s does not exist", name)
+	}
+	if !b.restack {
+		return nil
+	}
+	b.restack = false
+	b.rev++
+	for _, child := range d.children(name) {
+		child.restack = true
+	}
+	return nil
+}
+
+// restackUpstack restacks name and every branch above it, leaving the
+// branches below untouched.
+func (d *DemoExecutor) restackUpstack(name string) error {
+	b := d.find(name)
+	if b == nil {
+		return fmt.Errorf("branch %s does not exist", name)
+	}
+	for pending := []*demoBranch{b}; len(pending) > 0; pending = pending[1:] {
+		if p := pending[0]; p.restack {
+			p.restack = false
+			p.rev++
+		}
+		pending = append(pending, d.children(pending[0].name)...)
+	}
+	return nil
+}
+
+// sync deletes branches whose PRs merged if force is set, as `gt sync -f`
+// does. Without it gt keeps them, having no one to ask.
+func (d *DemoExecutor) sync(force bool) error {
+	if !force {
+		return nil
+	}
+	for _, b := range slices.Clone(d.branches) {
+		if b.state == "MERGED" {
+			if err := d.delete(b.name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// merged lists the branches whose PRs merged, as `git branch --merged`
+// prints them.
+func (d *DemoExecutor) merged() string {
+	var sb strings.Builder
+	for _, b := range d.branches {
+		if b.state == "MERGED" {
+			sb.WriteString(b.name + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// mergeTree answers `git merge-tree` for merging name into trunk: branches
+// that need a restack conflict in their first file.
+func (d *DemoExecutor) mergeTree(name string) (string, error) {
+	tree := fmt.Sprintf("%x\n", sha1.Sum([]byte("tree:"+name)))
+	if b := d.find(name); b != nil && b.restack && len(b.files) > 0 {
+		return tree + b.files[0].path + "\n", fmt.Errorf("merge conflict")
+	}
+	return tree, nil
+}
+
+func (d *DemoExecutor) sha(b *demoBranch, rev int) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("%s@%d", b.name, rev))))
+}
+
+func (d *DemoExecutor) heads() string {
+	var sb strings.Builder
+	for _, b := range d.branches {
+		fmt.Fprintf(&sb, "%s %s\n", b.name, d.sha(b, b.rev))
+	}
+	return sb.String()
+}
+
+// metadata renders each tracked branch's Graphite metadata as
+// `git for-each-ref` prints it.
+func (d *DemoExecutor) metadata() string {
+	var sb strings.Builder
+	for _, b := range d.branches {
+		if b.parent == "" {
+			continue
+		}
+		parent := d.find(b.parent)
+		out, _ := json.Marshal(branchMetadata{ParentBranchName: parent.name, ParentBranchRevision: d.sha(parent, parent.rev)})
+		fmt.Fprintf(&sb, "%s %s\n", b.name, out)
+	}
+	return sb.String()
+}
+
+func (d *DemoExecutor) prInfo(name string) (string, error) {
+	b := d.find(name)
+	if b == nil {
+		return "", fmt.Errorf("branch %s does not exist", name)
+	}
+	if b.pr == 0 {
+		return "", nil
+	}
+	out, err := json.Marshal(prInfoJSON{PRNumber: b.pr, State: b.state, URL: demoPRURL(b.pr)})
+	return string(out), err
+}
+
+// demoPRURL returns the web page of the demo's PR number.
+func demoPRURL(number int) string {
+	return fmt.Sprintf("https://github.com/acme/demo/pull/%d", number)
+}
+
+// prList answers `gh pr list --head`, listing the branch's PR if it has
+// one.
+func (d *DemoExecutor) prList(name string) (string, error) {
+	b := d.find(name)
+	if b == nil || b.pr == 0 {
+		return "[]", nil
+	}
+	pr := prListJSON{Number: b.pr, State: b.state, URL: demoPRURL(b.pr)}
+	if b.state == "DRAFT" {
+		pr.State, pr.IsDraft = "OPEN", true
+	}
+	out, err := json.Marshal([]prListJSON{pr})
+	return string(out), err
+}
+
+// prMergedAt answers `gh pr view --json mergedAt`, null until the PR merges.
+func (d *DemoExecutor) prMergedAt(name string) (string, error) {
+	b := d.find(name)
+	if b == nil || b.pr == 0 {
+		return "", fmt.Errorf("no pull requests found for branch %q", name)
+	}
+	if b.merged.IsZero() {
+		return `{"mergedAt":null}`, nil
+	}
+	return fmt.Sprintf(`{"mergedAt":%q}`, b.merged.UTC().Format(time.RFC3339)), nil
+}
+
+func (d *DemoExecutor) prDetails(name string) (string, error) {
+	b := d.find(name)
+	if b == nil || b.pr == 0 {
+		return "", fmt.Errorf("no pull requests found for branch %q", name)
+	}
+	raw := prDetailsJSON{HeadRefOid: d.sha(b, b.pushed)}
+	check := rollupEntry{Typename: "CheckRun", Status: "COMPLETED", Conclusion: "SUCCESS"}
+	switch demoCheckBucket(b) {
+	case "fail":
+		check.Conclusion = "FAILURE"
+	case "pending":
+		check.Status, check.Conclusion = "IN_PROGRESS", ""
+	}
+	raw.StatusCheckRollup = []rollupEntry{check}
+	raw.Mergeable = "MERGEABLE"
+	if b.restack {
+		raw.Mergeable = "CONFLICTING"
+	}
+	for _, l := range b.labels {
+		raw.Labels = append(raw.Labels, struct {
+			Name string `json:"name"`
+		}{l})
+	}
+	for _, a := range b.assignees {
+		raw.Assignees = append(raw.Assignees, struct {
+			Login string `json:"login"`
+		}{a})
+	}
+	out, err := json.Marshal(raw)
+	return string(out), err
+}
+
+func (d *DemoExecutor) prReviewers(name string) (string, error) {
+	b := d.find(name)
+	if b == nil || b.pr == 0 {
+		return "", fmt.Errorf("no pull requests found for branch %q", name)
+	}
+	var raw prReviewersJSON
+	for _, r := range b.reviewers {
+		raw.ReviewRequests = append(raw.ReviewRequests, struct {
+			Login string `json:"login"`
+			Slug  string `json:"slug"`
+			Name  string `json:"name"`
+		}{Login: r})
+	}
+	out, err := json.Marshal(raw)
+	return string(out), err
+}
+
+// prText reports a PR's title, the branch's subject until edited, and
+// description.
+func (d *DemoExecutor) prText(name string) (string, error) {
+	b := d.find(name)
+	if b == nil || b.pr == 0 {
+		return "", fmt.Errorf("no pull requests found for branch %q", name)
+	}
+	text := PRText{Title: b.title, Body: b.body}
+	if text.Title == "" {
+		text.Title = b.subject
+	}
+	out, err := json.Marshal(text)
+	return string(out), err
+}
+
+// editPR sets a PR's title and description, as `gh pr edit` does.
+func (d *DemoExecutor) editPR(name, title, body string) error {
+	b := d.find(name)
+	if b == nil || b.pr == 0 {
+		return fmt.Errorf("no pull requests found for branch %q", name)
+	}
+	b.title, b.body = title, body
+	return nil
+}
+
+// requestReviewers adds reviewers to a PR's review requests, as `gh pr
+// edit --add-reviewer` does.
+func (d *DemoExecutor) requestReviewers(name string, reviewers []string) error {
+	b := d.find(name)
+	if b == nil || b.pr == 0 {
+		return fmt.Errorf("no pull requests found for branch %q", name)
+	}
+	for _, r := range reviewers {
+		if !slices.Contains(b.reviewers, r) {
+			b.reviewers = append(b.reviewers, r)
+		}
+	}
+	return nil
+}
+
+// commentPR adds body to name's PR conversation as the demo user.
+func (d *DemoExecutor) commentPR(name, body string) error {
+	b := d.find(name)
+	if b == nil || b.pr == 0 {
+		return fmt.Errorf("no pull requests found for branch %q", name)
+	}
+	b.comments = append(b.comments, PRComment{Author: "you", Body: body, CreatedAt: time.Now()})
+	return nil
+}
+
+// demoRepoLabels is the demo repository's labels, as `gh label list --json
+// name` prints them.
+const demoRepoLabels = `[{"name":"breaking"},{"name":"needs-qa"},{"name":"perf"},{"name":"docs"},{"name":"good first issue"}]`
+
+// demoList splits a comma separated flag value, empty for none.
+func demoList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// editLabels adds and removes a PR's labels, as `gh pr edit --add-label
+// --remove-label` does.
+func (d *DemoExecutor) editLabels(name string, add, remove []string) error {
+	b := d.find(name)
+	if b == nil || b.pr == 0 {
+		return fmt.Errorf("no pull requests found for branch %q", name)
+	}
+	b.labels = slices.DeleteFunc(b.labels, func(l string) bool { return slices.Contains(remove, l) })
+	for _, l := range add {
+		if !slices.Contains(b.labels, l) {
+			b.labels = append(b.labels, l)
+		}
+	}
+	return nil
+}
+
+// demoGraphQLNumber returns the PR number passed to `gh api graphql` as
+// -F number=N.
+func demoGraphQLNumber(args []string) int {
+	for i, a := range args {
+		if n, ok := strings.CutPrefix(a, "number="); ok && i > 0 && args[i-1] == "-F" {
+			number, _ := strconv.Atoi(n)
+			return number
+		}
+	}
+	return 0
+}
+
+// prDiscussion answers the GraphQL query for PR number's conversation and
+// review threads.
+func (d *DemoExecutor) prDiscussion(number int) (string, error) {
+	var b *demoBranch
+	for _, candidate := range d.branches {
+		if candidate.pr != 0 && candidate.pr == number {
+			b = candidate
+		}
+	}
+	if b == nil {
+		return "", fmt.Errorf("GraphQL: Could not resolve to a PullRequest with the number of %d", number)
+	}
+	type node = map[string]any
+	comment := func(c PRComment) node {
+		return node{"author": node{"login": c.Author}, "body": c.Body, "createdAt": c.CreatedAt}
+	}
+	comments := []node{}
+	for _, c := range b.comments {
+		comments = append(comments, comment(c))
+	}
+	threads := []node{}
+	for _, t := range b.threads {
+		replies := []node{}
+		for _, c := range t.Comments {
+			replies = append(replies, comment(c))
+		}
+		threads = append(threads, node{"isResolved": t.Resolved, "path": t.Path, "line": t.Line, "comments": node{"nodes": replies}})
+	}
+	pr := node{"comments": node{"nodes": comments}, "reviews": node{"nodes": []node{}}, "reviewThreads": node{"nodes": threads}}
+	out, err := json.Marshal(node{"data": node{"repository": node{"pullRequest": pr}}})
+	return string(out), err
+}
+
+// prChecks reports the demo's one required check, which fails on branches
+// that need a restack and is still running on drafts.
+func (d *DemoExecutor) prChecks(name string) (string, error) {
+	b := d.find(name)
+	if b == nil || b.pr == 0 {
+		return "", fmt.Errorf("no pull requests found for branch %q", name)
+	}
+	out, err := json.Marshal([]CheckRun{{Name: "ci", Bucket: demoCheckBucket(b)}})
+	return string(out), err
+}
+
+// demoCheckBucket is the outcome of the demo's one CI check on b: failing
+// when it needs a restack, pending while it is a draft.
+func demoCheckBucket(b *demoBranch) string {
+	switch {
+	case b.restack:
+		return "fail"
+	case b.state == "DRAFT":
+		return "pending"
+	}
+	return "pass"
+}
+
+func (d *DemoExecutor) rateLimit() string {
+	reset := d.now.Add(time.Hour).Unix()
+	return fmt.Sprintf(`{"resources":{"core":{"limit":5000,"remaining":4821,"reset":%d},"graphql":{"limit":5000,"remaining":4968,"reset":%d}}}`, reset, reset)
+}
+
+// rangeBranch returns the branch of a "<parent>..<branch>" or
+// "<parent>...<branch>" revision range.
+func (d *DemoExecutor) rangeBranch(rev string) (*demoBranch, error) {
+	i := strings.LastIndex(rev, "..")
+	if i < 0 {
+		return nil, fmt.Errorf("demo: expected a revision range, got %q", rev)
+	}
+	name := rev[i+2:]
+	b := d.find(name)
+	if b == nil {
+		return nil, fmt.Errorf("unknown revision %q", name)
+	}
+	return b, nil
+}
+
+// diff answers `git diff --stat`, `--name-only` and `--color=always` for
+// a branch against its parent, optionally limited to paths after "--".
+func (d *DemoExecutor) diff(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("demo: unsupported git diff")
+	}
+	b, err := d.rangeBranch(args[1])
+	if err != nil {
+		return "", err
+	}
+	files := b.files
+	if i := slices.Index(args, "--"); i >= 0 {
+		files = slices.DeleteFunc(slices.Clone(files), func(f demoFile) bool { return !slices.Contains(args[i+1:], f.path) })
+	}
+
+	var sb strings.Builder
+	switch args[0] {
+	case "--stat":
+		total := 0
+		for _, f := range files {
+			fmt.Fprintf(&sb, " %s | %d %s\n", f.path, len(f.lines), strings.Repeat("+", len(f.lines)))
+			total += len(f.lines)
+		}
+		fmt.Fprintf(&sb, " %d files changed, %d insertions(+)\n", len(files), total)
+	case "--name-only":
+		for _, f := range files {
+			sb.WriteString(f.path + "\n")
+		}
+	case "--color=always":
+		for _, f := range files {
+			fmt.Fprintf(&sb, "\x1b[1mdiff --git a/%s b/%s\x1b[m\n", f.path, f.path)
+			fmt.Fprintf(&sb, "\x1b[1m--- a/%s\x1b[m\n\x1b[1m+++ b/%s\x1b[m\n", f.path, f.path)
+			fmt.Fprintf(&sb, "\x1b[36m@@ -0,0 +1,%d @@\x1b[m\n", len(f.lines))
+			for _, line := range f.lines {
+				fmt.Fprintf(&sb, "\x1b[32m+%s\x1b[m\n", line)
+			}
+		}
+	default:
+		return "", fmt.Errorf("demo: unsupported git diff %s", args[0])
+	}
+	return sb.String(), nil
+}
+
+// log answers `git log --format=%s` and `git log --reverse --format=%ct`
+// for a revision range; each demo branch has a single commit.
+func (d *DemoExecutor) log(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("demo: unsupported git log")
+	}
+	if strings.Contains(args[len(args)-1], "..origin/") {
+		// The demo's remote branches never have commits of their own.
+		return "", nil
+	}
+	b, err := d.rangeBranch(args[len(args)-1])
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(args, "--format=%ct") {
+		return fmt.Sprintf("%d\n", d.now.Add(-b.age).Unix()), nil
+	}
+	return b.subject + "\n", nil
+}
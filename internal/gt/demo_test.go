@@ -0,0 +1,437 @@
+package gt
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newTestDemo() (*DemoExecutor, *Client) {
+	d := NewDemoExecutor()
+	d.scale = 0
+	return d, New(d)
+}
+
+func TestDemo_LogShortParses(t *testing.T) {
+	_, client := newTestDemo()
+	out, err := client.LogShort(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	branches, err := ParseLogShort(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(branches) != 1 || branches[0].Name != "main" {
+		t.Fatalf("roots = %v, want main", branches)
+	}
+	main := branches[0]
+	if len(main.Children) != 3 {
+		t.Fatalf("main children = %d, want 3 stacks:\n%s", len(main.Children), out)
+	}
+	for _, tc := range []struct{ branch, parent string }{
+		{"auth-session-store", "main"},
+		{"auth-login-api", "auth-session-store"},
+		{"auth-login-ui", "auth-login-api"},
+		{"auth-remember-me", "auth-login-ui"},
+		{"search-index", "main"},
+		{"search-ranking", "search-index"},
+		{"docs-typos", "main"},
+	} {
+		if parent, ok := FindParent(branches, tc.branch); !ok || parent != tc.parent {
+			t.Errorf("parent of %s = %q, want %q", tc.branch, parent, tc.parent)
+		}
+	}
+	if ui := main.Children[0].Children[0].Children[0]; ui.Name != "auth-login-ui" || !ui.IsCurrent {
+		t.Errorf("auth-login-ui should be current:\n%s", out)
+	}
+	if top := main.Children[0].Children[0].Children[0].Children[0]; top.Annotation != "needs restack" {
+		t.Errorf("auth-remember-me annotation = %q", top.Annotation)
+	}
+}
+
+func TestDemo_CreateAndCheckout(t *testing.T) {
+	d, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.Checkout(ctx, "search-ranking"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Create(ctx, "search-facets"); err != nil {
+		t.Fatal(err)
+	}
+	if d.current != "search-facets" {
+		t.Errorf("current = %q, want the new branch", d.current)
+	}
+	out, _ := client.LogShort(ctx)
+	branches, _ := ParseLogShort(out)
+	if parent, _ := FindParent(branches, "search-facets"); parent != "search-ranking" {
+		t.Errorf("parent = %q, want search-ranking", parent)
+	}
+
+	if err := client.Checkout(ctx, "search-index"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Create(ctx, "fork"); err == nil {
+		t.Error("creating below another branch should fail in the demo")
+	}
+	if err := client.Checkout(ctx, "nope"); err == nil {
+		t.Error("checking out a missing branch should fail")
+	}
+}
+
+func TestDemo_CreateInsert(t *testing.T) {
+	d, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.Checkout(ctx, "search-index"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateInsert(ctx, "search-tokenizer"); err != nil {
+		t.Fatal(err)
+	}
+	if b := d.find("search-tokenizer"); b == nil || b.parent != "search-index" || d.current != "search-tokenizer" {
+		t.Errorf("inserted branch = %+v, current = %q", b, d.current)
+	}
+	if b := d.find("search-ranking"); b.parent != "search-tokenizer" || !b.restack {
+		t.Errorf("child = %+v, want it moved onto the new branch and needing a restack", b)
+	}
+	if err := client.Checkout(ctx, "search-ranking"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateInsert(ctx, "nowhere"); err == nil {
+		t.Error("inserting above the top of a stack should fail in the demo")
+	}
+}
+
+func TestDemo_SubmitAndSync(t *testing.T) {
+	d, client := newTestDemo()
+	ctx := context.Background()
+
+	if err := client.StackSubmit(ctx, "auth-login-ui"); err == nil {
+		t.Error("submitting a stack that needs a restack should fail")
+	}
+	if err := client.StackRestack(ctx, "auth-login-ui"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.StackSubmit(ctx, "auth-login-ui"); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := client.BranchPRInfo(ctx, "auth-remember-me")
+	if info := ParsePRInfo(out); info.Number == 0 || info.State != "OPEN" || !strings.HasSuffix(info.URL, fmt.Sprintf("/pull/%d", info.Number)) {
+		t.Errorf("pr info = %+v, want a new open PR with its URL", info)
+	}
+	heads, _ := client.BranchHeads(ctx)
+	details, _ := client.PRDetails(ctx, "auth-login-ui")
+	if details.HeadSHA != heads["auth-login-ui"] {
+		t.Error("submitted branch should match its PR head")
+	}
+	if details.CI != CIPending {
+		t.Errorf("CI = %d, want the demo's check pending on a draft", details.CI)
+	}
+	if details.Conflicts {
+		t.Error("restacked branch should merge cleanly")
+	}
+
+	if err := client.SyncKeep(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if d.find("auth-session-store") == nil {
+		t.Error("sync without -f should keep the merged branch")
+	}
+	if err := client.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if d.find("auth-session-store") != nil {
+		t.Error("sync should delete the merged branch")
+	}
+	if b := d.find("auth-login-api"); b.parent != "main" || !b.restack {
+		t.Errorf("child of merged branch: parent %q restack %v, want main and true", b.parent, b.restack)
+	}
+
+	if err := client.BranchRestack(ctx, "auth-login-api"); err != nil {
+		t.Fatal(err)
+	}
+	if d.find("auth-login-api").restack || !d.find("auth-login-ui").restack {
+		t.Error("branch restack should restack the branch and leave its child needing one")
+	}
+
+	if err := client.UpstackRestack(ctx, "auth-login-ui"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"auth-login-ui", "auth-remember-me"} {
+		if d.find(name).restack {
+			t.Errorf("upstack restack should restack %s", name)
+		}
+	}
+}
+
+func TestDemo_Fold(t *testing.T) {
+	d, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.Fold(ctx, "search-index"); err == nil {
+		t.Error("folding into trunk should fail")
+	}
+	if err := client.Fold(ctx, "auth-login-ui"); err != nil {
+		t.Fatal(err)
+	}
+	if d.find("auth-login-ui") != nil || d.current != "auth-login-api" {
+		t.Errorf("folded branch should be gone and its parent current, current = %q", d.current)
+	}
+	paths, _ := client.DiffNameOnly(ctx, "auth-session-store", "auth-login-api")
+	if len(paths) != 3 {
+		t.Errorf("parent paths = %v, want the folded branch's files added", paths)
+	}
+	if b := d.find("auth-remember-me"); b.parent != "auth-login-api" {
+		t.Errorf("child parent = %q, want auth-login-api", b.parent)
+	}
+}
+
+func TestDemo_Move(t *testing.T) {
+	d, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.Move(ctx, "auth-login-api", "auth-remember-me"); err == nil {
+		t.Error("moving onto a branch above should fail")
+	}
+	if err := client.Move(ctx, "auth-login-ui", "search-ranking"); err != nil {
+		t.Fatal(err)
+	}
+	if b := d.find("auth-login-ui"); b.parent != "search-ranking" {
+		t.Errorf("parent = %q, want search-ranking", b.parent)
+	}
+	if b := d.find("auth-remember-me"); b.parent != "auth-login-ui" || b.restack {
+		t.Errorf("child should move along and be restacked: %+v", b)
+	}
+	out, _ := client.LogShort(ctx)
+	branches, err := ParseLogShort(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent, _ := FindParent(branches, "auth-login-ui"); parent != "search-ranking" {
+		t.Errorf("log parent = %q, want search-ranking:\n%s", parent, out)
+	}
+}
+
+func TestDemo_BranchSubmit(t *testing.T) {
+	d, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.BranchSubmit(ctx, "auth-remember-me"); err == nil {
+		t.Error("submitting a branch that needs a restack should fail")
+	}
+	if err := client.BranchSubmit(ctx, "search-ranking"); err != nil {
+		t.Fatal(err)
+	}
+	if b := d.find("search-ranking"); b.pr == 0 || b.state != "OPEN" {
+		t.Errorf("submitted branch should have an open PR: %+v", b)
+	}
+}
+
+func TestDemo_DraftSubmitAndPublish(t *testing.T) {
+	d, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.DraftSubmit(ctx, "search-ranking"); err != nil {
+		t.Fatal(err)
+	}
+	b := d.find("search-ranking")
+	if b.pr == 0 || b.state != "DRAFT" {
+		t.Errorf("draft submit should open a draft PR: %+v", b)
+	}
+	if err := client.Publish(ctx, "search-ranking"); err != nil {
+		t.Fatal(err)
+	}
+	if b.state != "OPEN" {
+		t.Errorf("publish should mark the PR ready for review, state = %q", b.state)
+	}
+}
+
+func TestDemo_DeleteRemote(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.DeleteRemote(ctx, "docs-typos"); err != nil {
+		t.Errorf("deleting a pushed branch: %v", err)
+	}
+	if err := client.DeleteRemote(ctx, "search-ranking"); err != nil {
+		t.Errorf("a branch never pushed is already gone, not an error: %v", err)
+	}
+}
+
+func TestDemo_Merge(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.Merge(ctx); err == nil {
+		t.Error("merging a stack with a draft PR should fail")
+	}
+	if err := client.Checkout(ctx, "auth-login-api"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Merge(ctx); err != nil {
+		t.Errorf("merging open PRs above a merged one: %v", err)
+	}
+}
+
+func TestDemo_Pop(t *testing.T) {
+	d, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.Pop(ctx); err == nil {
+		t.Error("popping a branch with children should fail")
+	}
+	if err := client.Checkout(ctx, "auth-remember-me"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Pop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if d.find("auth-remember-me") != nil {
+		t.Error("popped branch should be deleted")
+	}
+	if d.current != "auth-login-ui" {
+		t.Errorf("current = %q, want auth-login-ui", d.current)
+	}
+}
+
+func TestDemo_DiffAndLog(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	paths, err := client.DiffNameOnly(ctx, "auth-session-store", "auth-login-api")
+	if err != nil || len(paths) != 2 {
+		t.Fatalf("paths = %v, %v", paths, err)
+	}
+	stat, _ := client.DiffStat(ctx, "auth-session-store", "auth-login-api", "auth/login.go")
+	if !strings.Contains(stat, "auth/login.go |") || strings.Contains(stat, "login_test.go") {
+		t.Errorf("stat = %q", stat)
+	}
+	subjects, _ := client.CommitSubjects(ctx, "main", "search-index")
+	if len(subjects) != 1 || subjects[0] != "Build search index" {
+		t.Errorf("subjects = %v", subjects)
+	}
+	if first, err := client.FirstCommitTime(ctx, "main", "search-index"); err != nil || first.IsZero() {
+		t.Errorf("first commit = %v, %v", first, err)
+	}
+	if remote, err := client.CommitSummaries(ctx, "search-index", "origin/search-index"); err != nil || len(remote) != 0 {
+		t.Errorf("remote-only commits = %v, %v", remote, err)
+	}
+}
+
+func TestDemo_UnsupportedCommand(t *testing.T) {
+	d, _ := newTestDemo()
+	_, err := d.Execute(context.Background(), "git", "push", "--force")
+	if err == nil || !strings.Contains(err.Error(), "git push --force") {
+		t.Errorf("err = %v, want unsupported command", err)
+	}
+}
+
+func TestDemo_MergePR(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.MergePR(ctx, "auth-login-ui", MergeSquash); err == nil {
+		t.Error("merging a draft PR should fail")
+	}
+	if err := client.MergePR(ctx, "search-ranking", MergeSquash); err == nil {
+		t.Error("merging a branch without a PR should fail")
+	}
+	if at, err := client.PRMergedAt(ctx, "search-index"); err != nil || !at.IsZero() {
+		t.Errorf("open PR merged at %v, %v", at, err)
+	}
+	if err := client.MergePR(ctx, "search-index", MergeRebase); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := client.BranchPRInfo(ctx, "search-index")
+	if info := ParsePRInfo(out); info.State != "MERGED" {
+		t.Errorf("state = %q, want MERGED", info.State)
+	}
+	if at, err := client.PRMergedAt(ctx, "search-index"); err != nil || at.IsZero() {
+		t.Errorf("merged PR merged at %v, %v", at, err)
+	}
+}
+
+func TestDemo_EditPR(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	text, err := client.PRText(ctx, "auth-login-api")
+	if err != nil || text.Title != "Add login endpoint" {
+		t.Fatalf("text = %+v, %v, want the subject as title", text, err)
+	}
+	if err := client.EditPR(ctx, "auth-login-api", PRText{Title: "Login API", Body: "Adds /login."}); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := client.PRText(ctx, "auth-login-api"); text.Title != "Login API" || text.Body != "Adds /login." {
+		t.Errorf("text = %+v, want the edit", text)
+	}
+	if _, err := client.PRText(ctx, "search-ranking"); err == nil {
+		t.Error("a branch without a PR has no PR text")
+	}
+}
+
+func TestDemo_RequestReviewers(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.RequestReviewers(ctx, "auth-login-ui", []string{"bob", "carol"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := client.PRReviewers(ctx, "auth-login-ui"); !slices.Equal(got, []string{"bob", "carol"}) {
+		t.Errorf("reviewers = %v, want bob and carol once each", got)
+	}
+	if err := client.RequestReviewers(ctx, "search-ranking", []string{"bob"}); err == nil {
+		t.Error("a branch without a PR can't get reviewers")
+	}
+}
+
+func TestDemo_EditLabels(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	labels, err := client.RepoLabels(ctx)
+	if err != nil || !slices.Contains(labels, "docs") {
+		t.Fatalf("labels = %v, %v", labels, err)
+	}
+	if err := client.EditPRLabels(ctx, "auth-login-api", []string{"docs"}, []string{"breaking"}); err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := client.PRDetails(ctx, "auth-login-api"); !slices.Equal(d.Labels, []string{"needs-qa", "docs"}) {
+		t.Errorf("labels = %v, want breaking swapped for docs", d.Labels)
+	}
+}
+
+func TestDemo_PRDiscussion(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	d, err := client.PRDiscussion(ctx, 418)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Comments) != 2 || len(d.Threads) != 2 || d.Unresolved() != 1 {
+		t.Errorf("discussion = %+v, want 2 comments and 2 threads, 1 unresolved", d)
+	}
+	if n, err := client.UnresolvedThreads(ctx, 424); err != nil || n != 0 {
+		t.Errorf("unresolved = %d, %v, want none", n, err)
+	}
+	if _, err := client.PRDiscussion(ctx, 1); err == nil {
+		t.Error("an unknown PR has no discussion")
+	}
+}
+
+func TestDemo_CommentPR(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.CommentPR(ctx, "search-index", "rebased, PTAL"); err != nil {
+		t.Fatal(err)
+	}
+	d, err := client.PRDiscussion(ctx, 424)
+	if err != nil || len(d.Comments) != 1 || d.Comments[0].Body != "rebased, PTAL" {
+		t.Errorf("discussion = %+v, %v, want the new comment", d, err)
+	}
+	if err := client.CommentPR(ctx, "search-ranking", "hi"); err == nil {
+		t.Error("commenting on a branch without a PR should fail")
+	}
+}
+
+func TestDemo_PRForBranch(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	info, err := client.PRForBranch(ctx, "search-index")
+	if err != nil || info.Number != 424 || info.URL != demoPRURL(424) {
+		t.Errorf("search-index = %+v, %v, want PR #424", info, err)
+	}
+	if info, err := client.PRForBranch(ctx, "search-ranking"); err != nil || info.Number != 0 {
+		t.Errorf("search-ranking = %+v, %v, want no PR", info, err)
+	}
+}
@@ -2,11 +2,18 @@ package gt
 
 import (
 	"context"
+	"strings"
 )
 
 // DiffStat runs `git diff --stat <parent>...<branch>` and returns the raw output.
-func (c *Client) DiffStat(ctx context.Context, parent, branch string) (string, error) {
-	return c.executor.Execute(ctx, "git", "diff", "--stat", parent+"..."+branch)
+// If paths are given, the diff is limited to them (`-- <paths>`).
+func (c *Client) DiffStat(ctx context.Context, parent, branch string, paths ...string) (string, error) {
+	args := []string{"diff", "--stat", parent + "..." + branch}
+	if len(paths) > 0 {
+		args = append(args, "--")
+		args = append(args, paths...)
+	}
+	return c.executor.Execute(ctx, "git", args...)
 }
 
 // DiffFile runs `git diff --color=always <parent>...<branch> -- <file>` and
@@ -14,3 +21,25 @@ func (c *Client) DiffStat(ctx context.Context, parent, branch string) (string, e
 func (c *Client) DiffFile(ctx context.Context, parent, branch, file string) (string, error) {
 	return c.executor.Execute(ctx, "git", "diff", "--color=always", parent+"..."+branch, "--", file)
 }
+
+// DiffNameOnly runs `git diff --name-only <parent>...<branch>` and returns
+// the changed file paths, relative to the repo root.
+func (c *Client) DiffNameOnly(ctx context.Context, parent, branch string) ([]string, error) {
+	out, err := c.executor.Execute(ctx, "git", "diff", "--name-only", parent+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	return ParseNameOnly(out), nil
+}
+
+// ParseNameOnly splits `git diff --name-only` output into paths, skipping
+// blank lines.
+func ParseNameOnly(output string) []string {
+	var paths []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths
+}
@@ -71,6 +71,45 @@ func TestDiffFile_Error(t *testing.T) {
 	}
 }
 
+func TestDiffStat_WithPaths(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if _, err := client.DiffStat(context.Background(), "main", "feature-a", "services/api"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"diff", "--stat", "main...feature-a", "--", "services/api"})
+}
+
+func TestDiffNameOnly_Success(t *testing.T) {
+	mock := &mockExecutor{output: "a.go\nservices/api/b.go\n\n"}
+	client := New(mock)
+
+	got, err := client.DiffNameOnly(context.Background(), "main", "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "a.go" || got[1] != "services/api/b.go" {
+		t.Errorf("got %v, want [a.go services/api/b.go]", got)
+	}
+	assertCommand(t, mock, "git", []string{"diff", "--name-only", "main...feature-a"})
+}
+
+func TestDiffNameOnly_Error(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("diff failed")}
+	client := New(mock)
+
+	if _, err := client.DiffNameOnly(context.Background(), "main", "feature-a"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestParseNameOnly_Empty(t *testing.T) {
+	if got := ParseNameOnly("\n  \n"); len(got) != 0 {
+		t.Errorf("got %v, want empty", got)
+	}
+}
+
 func TestFindParent_DirectChild(t *testing.T) {
 	branches := []*Branch{
 		{Name: "main", Children: []*Branch{
@@ -162,3 +201,21 @@ func TestFindParent_MultipleBranches(t *testing.T) {
 		t.Errorf("got %q, want %q", parent, "feature-a")
 	}
 }
+
+func TestFindBranch(t *testing.T) {
+	branches := []*Branch{
+		{Name: "main", Children: []*Branch{
+			{Name: "feature-a", Children: []*Branch{
+				{Name: "feature-b"},
+			}},
+		}},
+	}
+	for _, name := range []string{"main", "feature-b"} {
+		if b := FindBranch(branches, name); b == nil || b.Name != name {
+			t.Errorf("FindBranch(%q) = %+v", name, b)
+		}
+	}
+	if b := FindBranch(branches, "nonexistent"); b != nil {
+		t.Errorf("nonexistent branch found: %+v", b)
+	}
+}
@@ -0,0 +1,47 @@
+package gt
+
+import (
+	"context"
+	"strings"
+)
+
+// CommandRecorder is a CommandExecutor that records each command line
+// instead of running it, so callers can show what an action would do.
+// Every command succeeds with empty output.
+type CommandRecorder struct {
+	Commands []string
+}
+
+// Execute records the command line.
+func (r *CommandRecorder) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	r.Commands = append(r.Commands, FormatCommand(name, args...))
+	return "", nil
+}
+
+// FormatCommand renders a command as it would be typed into a shell,
+// single-quoting arguments that contain anything but safe characters.
+func FormatCommand(name string, args ...string) string {
+	words := make([]string, 0, len(args)+1)
+	words = append(words, shellQuote(name))
+	for _, arg := range args {
+		words = append(words, shellQuote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := strings.IndexFunc(s, func(r rune) bool {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return false
+		}
+		return !strings.ContainsRune("-_./=:@%+,", r)
+	}) < 0
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,46 @@
+package gt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFormatCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"stack", "submit", "--branch", "feat/a-1"}, "gt stack submit --branch feat/a-1"},
+		{[]string{"-c", "go test ./..."}, "gt -c 'go test ./...'"},
+		{[]string{"it's"}, `gt 'it'\''s'`},
+		{[]string{""}, "gt ''"},
+	}
+	for _, tt := range tests {
+		if got := FormatCommand("gt", tt.args...); got != tt.want {
+			t.Errorf("FormatCommand(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestCommandRecorder(t *testing.T) {
+	rec := &CommandRecorder{}
+	client := New(rec)
+	if err := client.Checkout(context.Background(), "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.StackRestack(context.Background(), "feature"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"gt checkout main --no-interactive",
+		"gt stack restack --no-interactive --branch feature",
+	}
+	if len(rec.Commands) != len(want) {
+		t.Fatalf("recorded %q, want %q", rec.Commands, want)
+	}
+	for i := range want {
+		if rec.Commands[i] != want[i] {
+			t.Errorf("command %d = %q, want %q", i, rec.Commands[i], want[i])
+		}
+	}
+}
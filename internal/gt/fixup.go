@@ -0,0 +1,38 @@
+package gt
+
+import (
+	"context"
+	"fmt"
+)
+
+// FixupCommit runs `git commit --all --fixup <target>`, committing every
+// working-tree change as a fixup of the commit at the head of target.
+func (c *Client) FixupCommit(ctx context.Context, target string) error {
+	_, err := c.executor.Execute(ctx, "git", "commit", "--all", "--fixup", target)
+	return err
+}
+
+// AutosquashRebase runs `git rebase --interactive --autosquash
+// --update-refs <onto>` with a no-op sequence editor, squashing fixup
+// commits into their targets without prompting. --update-refs moves the
+// stacked branches in between along with the rewritten commits. gt can't
+// continue a rebase it didn't start, so one that stops, e.g. on a
+// conflict, is aborted with `git rebase --abort`, leaving the branches as
+// they were.
+func (c *Client) AutosquashRebase(ctx context.Context, onto string) error {
+	_, err := c.executor.Execute(ctx, "git", "-c", "sequence.editor=:", "rebase", "--interactive", "--autosquash", "--update-refs", onto)
+	if err == nil {
+		return nil
+	}
+	if _, abortErr := c.executor.Execute(ctx, "git", "rebase", "--abort"); abortErr != nil {
+		return fmt.Errorf("%w (git rebase --abort also failed, finish the rebase by hand: %v)", err, abortErr)
+	}
+	return err
+}
+
+// UndoCommit runs `git reset --mixed HEAD~1`, dropping the current
+// branch's last commit and leaving its changes in the working tree.
+func (c *Client) UndoCommit(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "git", "reset", "--mixed", "HEAD~1")
+	return err
+}
@@ -0,0 +1,67 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFixupCommit(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).FixupCommit(context.Background(), "feature-a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"commit", "--all", "--fixup", "feature-a"})
+}
+
+func TestAutosquashRebase(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).AutosquashRebase(context.Background(), "main"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"-c", "sequence.editor=:", "rebase", "--interactive", "--autosquash", "--update-refs", "main"})
+}
+
+func TestAutosquashRebase_AbortsOnFailure(t *testing.T) {
+	var calls []string
+	exec := &funcExecutor{fn: func(name string, args ...string) (string, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		if slices.Contains(args, "--autosquash") {
+			return "CONFLICT (content): Merge conflict in a.go", errors.New("exit status 1")
+		}
+		return "", nil
+	}}
+	if err := New(exec).AutosquashRebase(context.Background(), "main"); err == nil {
+		t.Fatal("expected the rebase's error")
+	}
+	if len(calls) != 2 || calls[1] != "git rebase --abort" {
+		t.Errorf("calls = %q, want the rebase then git rebase --abort", calls)
+	}
+}
+
+func TestAutosquashRebase_ReportsFailedAbort(t *testing.T) {
+	exec := &funcExecutor{fn: func(name string, args ...string) (string, error) {
+		return "", errors.New("exit status 1")
+	}}
+	err := New(exec).AutosquashRebase(context.Background(), "main")
+	if err == nil || !strings.Contains(err.Error(), "git rebase --abort also failed") {
+		t.Errorf("err = %v, want the failed abort reported", err)
+	}
+}
+
+func TestUndoCommit(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).UndoCommit(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"reset", "--mixed", "HEAD~1"})
+}
+
+func TestDemo_FixupNeedsChanges(t *testing.T) {
+	_, client := newTestDemo()
+	if err := client.FixupCommit(context.Background(), "auth-login-api"); err == nil {
+		t.Error("the demo has no working-tree changes, so a fixup commit should fail")
+	}
+}
@@ -0,0 +1,72 @@
+package gt
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"runtime"
+	"strings"
+)
+
+// GitHubRepo runs `git remote get-url origin` and returns the "owner/name"
+// of the GitHub repository it points at.
+func (c *Client) GitHubRepo(ctx context.Context) (string, bool) {
+	out, err := c.executor.Execute(ctx, "git", "remote", "get-url", "origin")
+	if err != nil {
+		return "", false
+	}
+	return ParseGitHubRepo(out)
+}
+
+// ParseGitHubRepo extracts "owner/name" from a GitHub remote URL in any of
+// the https, scp-like ssh or ssh:// forms.
+func ParseGitHubRepo(remote string) (string, bool) {
+	remote = strings.TrimSpace(remote)
+	var path string
+	for _, prefix := range []string{"https://github.com/", "http://github.com/", "git@github.com:", "ssh://git@github.com/"} {
+		if rest, ok := strings.CutPrefix(remote, prefix); ok {
+			path = rest
+			break
+		}
+	}
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+	owner, name, ok := strings.Cut(path, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return path, true
+}
+
+// BlobURL returns the GitHub page for file at ref in repo ("owner/name").
+// ref may be a branch name, including one with slashes, or a commit.
+func BlobURL(repo, ref, file string) string {
+	var segments []string
+	for _, s := range strings.Split(ref+"/"+file, "/") {
+		segments = append(segments, url.PathEscape(s))
+	}
+	return "https://github.com/" + repo + "/blob/" + strings.Join(segments, "/")
+}
+
+// GraphitePRURL returns the Graphite web app's page for PR number in repo
+// ("owner/name"), which shows the PR's whole stack.
+func GraphitePRURL(repo string, number int) string {
+	return fmt.Sprintf("https://app.graphite.dev/github/pr/%s/%d", repo, number)
+}
+
+// OpenURL opens link in the default browser with the platform's opener.
+func (c *Client) OpenURL(ctx context.Context, link string) error {
+	name, args := browserCommand(runtime.GOOS, link)
+	_, err := c.executor.Execute(ctx, name, args...)
+	return err
+}
+
+// browserCommand returns the command that opens link on goos.
+func browserCommand(goos, link string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{link}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", link}
+	}
+	return "xdg-open", []string{link}
+}
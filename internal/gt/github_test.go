@@ -0,0 +1,71 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseGitHubRepo(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+		ok   bool
+	}{
+		{"https://github.com/elliotb/grit.git\n", "elliotb/grit", true},
+		{"https://github.com/elliotb/grit", "elliotb/grit", true},
+		{"git@github.com:elliotb/grit.git", "elliotb/grit", true},
+		{"ssh://git@github.com/elliotb/grit.git", "elliotb/grit", true},
+		{"https://gitlab.com/elliotb/grit.git", "", false},
+		{"https://github.com/elliotb", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseGitHubRepo(tt.url)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseGitHubRepo(%q) = %q, %v; want %q, %v", tt.url, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGitHubRepo(t *testing.T) {
+	mock := &mockExecutor{output: "git@github.com:elliotb/grit.git\n"}
+	got, ok := New(mock).GitHubRepo(context.Background())
+	assertCommand(t, mock, "git", []string{"remote", "get-url", "origin"})
+	if !ok || got != "elliotb/grit" {
+		t.Errorf("GitHubRepo() = %q, %v", got, ok)
+	}
+
+	if _, ok := New(&mockExecutor{err: errors.New("no such remote")}).GitHubRepo(context.Background()); ok {
+		t.Error("GitHubRepo() should fail without an origin remote")
+	}
+}
+
+func TestBlobURL(t *testing.T) {
+	got := BlobURL("elliotb/grit", "elliot/login fix", "internal/ui/model.go")
+	if want := "https://github.com/elliotb/grit/blob/elliot/login%20fix/internal/ui/model.go"; got != want {
+		t.Errorf("BlobURL() = %q, want %q", got, want)
+	}
+}
+
+func TestGraphitePRURL(t *testing.T) {
+	if got, want := GraphitePRURL("elliotb/grit", 42), "https://app.graphite.dev/github/pr/elliotb/grit/42"; got != want {
+		t.Errorf("GraphitePRURL() = %q, want %q", got, want)
+	}
+}
+
+func TestBrowserCommand(t *testing.T) {
+	tests := []struct {
+		goos string
+		want string
+	}{
+		{"darwin", "open https://x"},
+		{"linux", "xdg-open https://x"},
+		{"windows", "rundll32 url.dll,FileProtocolHandler https://x"},
+	}
+	for _, tt := range tests {
+		name, args := browserCommand(tt.goos, "https://x")
+		if got := FormatCommand(name, args...); got != tt.want {
+			t.Errorf("browserCommand(%q) = %q, want %q", tt.goos, got, tt.want)
+		}
+	}
+}
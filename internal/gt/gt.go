@@ -16,15 +16,23 @@ type CommandExecutor interface {
 // ExecCommandExecutor is the real implementation that shells out via os/exec.
 type ExecCommandExecutor struct{}
 
+// Execute runs the command. Errors, including stderr text, are scrubbed of
+// credentials with Redact since they end up on screen.
 func (e *ExecCommandExecutor) Execute(ctx context.Context, name string, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, name, args...)
 	out, err := cmd.Output()
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
-			return string(out), fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+			return string(out), fmt.Errorf("%s", Redact(strings.TrimSpace(string(exitErr.Stderr))))
 		}
 	}
-	return string(out), err
+	return string(out), RedactError(err)
+}
+
+// Command returns cmd for running name interactively, attached to the
+// terminal.
+func (e *ExecCommandExecutor) Command(name string, args ...string) *exec.Cmd {
+	return exec.Command(name, args...)
 }
 
 // Client provides methods for running gt CLI commands.
@@ -37,9 +45,17 @@ func New(executor CommandExecutor) *Client {
 	return &Client{executor: executor}
 }
 
-// NewDefault creates a new Client that executes real shell commands.
+// Executor returns the executor c runs commands with, e.g. to wrap it in
+// a new Client.
+func (c *Client) Executor() CommandExecutor {
+	return c.executor
+}
+
+// NewDefault creates a new Client that executes real shell commands, with
+// remote metadata calls rate limited and coalesced.
 func NewDefault() *Client {
-	return &Client{executor: &ExecCommandExecutor{}}
+	limiter := NewLimiter(DefaultRemoteRate, DefaultRemoteBurst)
+	return &Client{executor: NewRemoteExecutor(&ExecCommandExecutor{}, limiter)}
 }
 
 // LogShort runs `gt log short --no-interactive` and returns the raw output.
@@ -65,12 +81,117 @@ func (c *Client) DownstackSubmit(ctx context.Context, branchName string) error {
 	return err
 }
 
+// BranchSubmit runs `gt submit --no-interactive --branch <branchName>`,
+// submitting that branch without the stack-scoped `gt stack submit`.
+func (c *Client) BranchSubmit(ctx context.Context, branchName string) error {
+	_, err := c.executor.Execute(ctx, "gt", "submit", "--no-interactive", "--branch", branchName)
+	return err
+}
+
+// DraftSubmit runs `gt submit --no-interactive --draft --branch <branchName>`,
+// submitting that branch with a new PR opened as a draft.
+func (c *Client) DraftSubmit(ctx context.Context, branchName string) error {
+	_, err := c.executor.Execute(ctx, "gt", "submit", "--no-interactive", "--draft", "--branch", branchName)
+	return err
+}
+
+// SubmitScope is which branches a submit with options covers.
+type SubmitScope int
+
+const (
+	SubmitBranch    SubmitScope = iota // just the branch, `gt submit`
+	SubmitDownstack                    // the branch and those below it, `gt downstack submit`
+	SubmitStack                        // the whole stack, `gt stack submit`
+)
+
+// SubmitOptions are the flags of a submit chosen in the submit options
+// dialog.
+type SubmitOptions struct {
+	Scope      SubmitScope
+	Draft      bool     // --draft: open new PRs as drafts
+	UpdateOnly bool     // --update-only: only update branches that already have PRs
+	Reviewers  []string // --reviewers: requested on the PRs
+}
+
+// Submit runs the submit command for opts.Scope on branchName with the
+// flags opts selects, e.g.
+// `gt stack submit --no-interactive --draft --reviewers a,b --branch <branchName>`.
+func (c *Client) Submit(ctx context.Context, branchName string, opts SubmitOptions) error {
+	var args []string
+	switch opts.Scope {
+	case SubmitDownstack:
+		args = []string{"downstack", "submit"}
+	case SubmitStack:
+		args = []string{"stack", "submit"}
+	default:
+		args = []string{"submit"}
+	}
+	args = append(args, "--no-interactive")
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	if opts.UpdateOnly {
+		args = append(args, "--update-only")
+	}
+	if len(opts.Reviewers) > 0 {
+		args = append(args, "--reviewers", strings.Join(opts.Reviewers, ","))
+	}
+	args = append(args, "--branch", branchName)
+	_, err := c.executor.Execute(ctx, "gt", args...)
+	return err
+}
+
+// Publish runs `gt submit --no-interactive --publish --branch <branchName>`,
+// submitting that branch and marking its draft PR ready for review.
+func (c *Client) Publish(ctx context.Context, branchName string) error {
+	_, err := c.executor.Execute(ctx, "gt", "submit", "--no-interactive", "--publish", "--branch", branchName)
+	return err
+}
+
+// Merge runs `gt merge --no-interactive`, sending the PRs of the branches
+// from trunk up to the checked-out branch to Graphite's merge queue.
+func (c *Client) Merge(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "merge", "--no-interactive")
+	return err
+}
+
+// MergeMethod is how `gh pr merge` lands a PR's commits on its base.
+type MergeMethod string
+
+const (
+	MergeSquash MergeMethod = "squash" // one commit with all the PR's changes
+	MergeCommit MergeMethod = "merge"  // a merge commit
+	MergeRebase MergeMethod = "rebase" // the PR's commits replayed onto the base
+)
+
+// MergePR runs `gh pr merge <branchName> --<method>`, merging the branch's
+// PR into its base on GitHub. Unlike Merge it bypasses Graphite's merge
+// queue.
+func (c *Client) MergePR(ctx context.Context, branchName string, method MergeMethod) error {
+	_, err := c.executor.Execute(ctx, "gh", "pr", "merge", branchName, "--"+string(method))
+	return err
+}
+
 // StackRestack runs `gt stack restack --no-interactive --branch <branchName>`.
 func (c *Client) StackRestack(ctx context.Context, branchName string) error {
 	_, err := c.executor.Execute(ctx, "gt", "stack", "restack", "--no-interactive", "--branch", branchName)
 	return err
 }
 
+// BranchRestack runs `gt branch restack --no-interactive --branch <branchName>`,
+// restacking just that branch onto its parent.
+func (c *Client) BranchRestack(ctx context.Context, branchName string) error {
+	_, err := c.executor.Execute(ctx, "gt", "branch", "restack", "--no-interactive", "--branch", branchName)
+	return err
+}
+
+// UpstackRestack runs `gt upstack restack --no-interactive --branch <branchName>`,
+// restacking that branch and the branches above it but none below.
+func (c *Client) UpstackRestack(ctx context.Context, branchName string) error {
+	_, err := c.executor.Execute(ctx, "gt", "upstack", "restack", "--no-interactive", "--branch", branchName)
+	return err
+}
+
 // RepoSync runs `gt repo sync --no-interactive`.
 func (c *Client) RepoSync(ctx context.Context) error {
 	_, err := c.executor.Execute(ctx, "gt", "repo", "sync", "--no-interactive")
@@ -83,6 +204,89 @@ func (c *Client) Sync(ctx context.Context) error {
 	return err
 }
 
+// SyncKeep runs `gt sync --no-interactive`. Without -f gt skips its
+// prompts to delete branches with merged or closed PRs, so every local
+// branch is kept; grit deletes the ones the user picked beforehand.
+func (c *Client) SyncKeep(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "sync", "--no-interactive")
+	return err
+}
+
+// Fold runs `gt fold --no-interactive --branch <branchName>`, merging the
+// branch's commits into its parent and deleting it. Its children are
+// restacked onto the parent.
+func (c *Client) Fold(ctx context.Context, branchName string) error {
+	_, err := c.executor.Execute(ctx, "gt", "fold", "--no-interactive", "--branch", branchName)
+	return err
+}
+
+// Pop runs `gt pop --no-interactive`, deleting the current branch and
+// checking out its parent while keeping the branch's changes in the
+// working tree.
+func (c *Client) Pop(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "pop", "--no-interactive")
+	return err
+}
+
+// Move runs `gt move --no-interactive --onto <onto> --branch <branchName>`,
+// rebasing the branch and its descendants onto a new parent.
+func (c *Client) Move(ctx context.Context, branchName, onto string) error {
+	_, err := c.executor.Execute(ctx, "gt", "move", "--no-interactive", "--onto", onto, "--branch", branchName)
+	return err
+}
+
+// Create runs `gt create <branchName> --no-interactive`, creating a new
+// branch stacked on the currently checked-out branch.
+func (c *Client) Create(ctx context.Context, branchName string) error {
+	_, err := c.executor.Execute(ctx, "gt", "create", branchName, "--no-interactive")
+	return err
+}
+
+// CreateInsert runs `gt create <branchName> --insert --no-interactive`,
+// creating a new branch on the checked-out branch and moving the branch
+// stacked on it onto the new one.
+func (c *Client) CreateInsert(ctx context.Context, branchName string) error {
+	_, err := c.executor.Execute(ctx, "gt", "create", branchName, "--insert", "--no-interactive")
+	return err
+}
+
+// Rename renames oldName to newName. gt only renames the current branch,
+// so this runs `gt checkout <oldName> --no-interactive` and then
+// `gt rename <newName> --no-interactive`, leaving newName checked out.
+func (c *Client) Rename(ctx context.Context, oldName, newName string) error {
+	if _, err := c.executor.Execute(ctx, "gt", "checkout", oldName, "--no-interactive"); err != nil {
+		return err
+	}
+	_, err := c.executor.Execute(ctx, "gt", "rename", newName, "--no-interactive")
+	return err
+}
+
+// Delete runs `gt delete <branchName> --force --no-interactive`, deleting
+// the local branch even if it is not merged into trunk locally. gt
+// reparents its children onto its parent; they still need a restack.
+func (c *Client) Delete(ctx context.Context, branchName string) error {
+	_, err := c.executor.Execute(ctx, "gt", "delete", branchName, "--force", "--no-interactive")
+	return err
+}
+
+// DeleteRemote runs `git push origin --delete <branchName>`, deleting the
+// branch on the remote. A branch already gone from the remote, e.g. deleted
+// by GitHub when its PR merged, is not an error.
+func (c *Client) DeleteRemote(ctx context.Context, branchName string) error {
+	_, err := c.executor.Execute(ctx, "git", "push", "origin", "--delete", branchName)
+	if err != nil && strings.Contains(err.Error(), "remote ref does not exist") {
+		return nil
+	}
+	return err
+}
+
+// RepoInit runs `gt repo init --no-interactive` to initialize Graphite in
+// the current repository.
+func (c *Client) RepoInit(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "repo", "init", "--no-interactive")
+	return err
+}
+
 // OpenPR runs `gt pr <branchName>` to open the branch's PR in the browser.
 func (c *Client) OpenPR(ctx context.Context, branchName string) error {
 	_, err := c.executor.Execute(ctx, "gt", "pr", branchName)
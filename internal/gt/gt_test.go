@@ -20,6 +20,16 @@ func (m *mockExecutor) Execute(ctx context.Context, name string, args ...string)
 	return m.output, m.err
 }
 
+// funcExecutor dispatches each call to fn, for tests of methods that run
+// several commands with different outputs.
+type funcExecutor struct {
+	fn func(name string, args ...string) (string, error)
+}
+
+func (f *funcExecutor) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	return f.fn(name, args...)
+}
+
 func TestLogShort_Success(t *testing.T) {
 	want := "◉ main\n├── feature-a\n└── feature-b\n"
 	mock := &mockExecutor{output: want}
@@ -134,6 +144,68 @@ func TestDownstackSubmit_Error(t *testing.T) {
 	}
 }
 
+func TestBranchSubmit_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	err := client.BranchSubmit(context.Background(), "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"submit", "--no-interactive", "--branch", "feature-a"})
+}
+
+func TestDraftSubmit_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	err := client.DraftSubmit(context.Background(), "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"submit", "--no-interactive", "--draft", "--branch", "feature-a"})
+}
+
+func TestSubmit_Options(t *testing.T) {
+	tests := []struct {
+		opts SubmitOptions
+		want []string
+	}{
+		{SubmitOptions{}, []string{"submit", "--no-interactive", "--branch", "feature-a"}},
+		{SubmitOptions{Scope: SubmitStack, Draft: true}, []string{"stack", "submit", "--no-interactive", "--draft", "--branch", "feature-a"}},
+		{SubmitOptions{Scope: SubmitDownstack, UpdateOnly: true, Reviewers: []string{"alice", "org/team"}},
+			[]string{"downstack", "submit", "--no-interactive", "--update-only", "--reviewers", "alice,org/team", "--branch", "feature-a"}},
+	}
+	for _, tt := range tests {
+		mock := &mockExecutor{}
+		if err := New(mock).Submit(context.Background(), "feature-a", tt.opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertArgs(t, mock, tt.want)
+	}
+}
+
+func TestMergePR_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.MergePR(context.Background(), "feature-a", MergeSquash); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "gh", []string{"pr", "merge", "feature-a", "--squash"})
+}
+
+func TestPublish_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	err := client.Publish(context.Background(), "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"submit", "--no-interactive", "--publish", "--branch", "feature-a"})
+}
+
 func TestStackRestack_Success(t *testing.T) {
 	mock := &mockExecutor{}
 	client := New(mock)
@@ -145,6 +217,54 @@ func TestStackRestack_Success(t *testing.T) {
 	assertArgs(t, mock, []string{"stack", "restack", "--no-interactive", "--branch", "feature-a"})
 }
 
+func TestDeleteRemote(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+	if err := client.DeleteRemote(context.Background(), "feature-a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"push", "origin", "--delete", "feature-a"})
+
+	mock.err = errors.New("error: unable to delete 'feature-a': remote ref does not exist")
+	if err := client.DeleteRemote(context.Background(), "feature-a"); err != nil {
+		t.Errorf("a branch already gone from the remote should not be an error: %v", err)
+	}
+	mock.err = errors.New("Permission denied (publickey)")
+	if err := client.DeleteRemote(context.Background(), "feature-a"); err == nil {
+		t.Error("expected other push failures to be returned")
+	}
+}
+
+func TestMerge_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.Merge(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"merge", "--no-interactive"})
+}
+
+func TestUpstackRestack_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.UpstackRestack(context.Background(), "feature-a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"upstack", "restack", "--no-interactive", "--branch", "feature-a"})
+}
+
+func TestBranchRestack_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.BranchRestack(context.Background(), "feature-a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"branch", "restack", "--no-interactive", "--branch", "feature-a"})
+}
+
 func TestStackRestack_Error(t *testing.T) {
 	mock := &mockExecutor{err: errors.New("restack failed")}
 	client := New(mock)
@@ -187,6 +307,16 @@ func TestSync_Success(t *testing.T) {
 	assertArgs(t, mock, []string{"sync", "-f", "--no-interactive"})
 }
 
+func TestSyncKeep_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.SyncKeep(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"sync", "--no-interactive"})
+}
+
 func TestSync_Error(t *testing.T) {
 	mock := &mockExecutor{err: errors.New("sync failed")}
 	client := New(mock)
@@ -197,6 +327,130 @@ func TestSync_Error(t *testing.T) {
 	}
 }
 
+func TestFold_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	err := client.Fold(context.Background(), "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"fold", "--no-interactive", "--branch", "feature-a"})
+}
+
+func TestFold_Error(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("merge conflict")}
+	client := New(mock)
+
+	err := client.Fold(context.Background(), "feature-a")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestMove_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.Move(context.Background(), "feature-b", "main"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"move", "--no-interactive", "--onto", "main", "--branch", "feature-b"})
+}
+
+func TestMove_Error(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("merge conflict")}
+	client := New(mock)
+
+	if err := client.Move(context.Background(), "feature-b", "main"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestCreate_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	err := client.Create(context.Background(), "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"create", "feature-a", "--no-interactive"})
+}
+
+func TestCreate_Error(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("branch exists")}
+	client := New(mock)
+
+	err := client.Create(context.Background(), "feature-a")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestCreateInsert_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.CreateInsert(context.Background(), "feature-mid"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"create", "feature-mid", "--insert", "--no-interactive"})
+}
+
+func TestRename_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	err := client.Rename(context.Background(), "feature-a", "feature-b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"rename", "feature-b", "--no-interactive"})
+}
+
+func TestRename_CheckoutError(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("no such branch")}
+	client := New(mock)
+
+	err := client.Rename(context.Background(), "feature-a", "feature-b")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	assertArgs(t, mock, []string{"checkout", "feature-a", "--no-interactive"})
+}
+
+func TestDelete_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	err := client.Delete(context.Background(), "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"delete", "feature-a", "--force", "--no-interactive"})
+}
+
+func TestDelete_Error(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("no such branch")}
+	client := New(mock)
+
+	if err := client.Delete(context.Background(), "feature-a"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestRepoInit_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	err := client.RepoInit(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"repo", "init", "--no-interactive"})
+}
+
 func TestOpenPR_Success(t *testing.T) {
 	mock := &mockExecutor{}
 	client := New(mock)
@@ -273,3 +527,11 @@ func TestExecCommandExecutor_StderrInError(t *testing.T) {
 		t.Errorf("error should contain stderr output, got: %q", err.Error())
 	}
 }
+
+func TestPop(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).Pop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"pop", "--no-interactive"})
+}
@@ -0,0 +1,80 @@
+package gt
+
+import (
+	"context"
+	"strings"
+)
+
+// HeadState describes what HEAD currently points at.
+type HeadState struct {
+	Detached bool
+	Branch   string // checked-out branch name when not detached
+	SHA      string // abbreviated commit SHA when detached
+	Nearest  string // nearest local branch containing the detached commit, "" if none
+}
+
+// HeadState inspects HEAD. When HEAD is a symbolic ref the branch name is
+// returned; otherwise it is detached and the commit SHA plus the nearest
+// local branch containing it (via `git name-rev`) are resolved.
+func (c *Client) HeadState(ctx context.Context) (HeadState, error) {
+	out, err := c.executor.Execute(ctx, "git", "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err == nil {
+		return HeadState{Branch: strings.TrimSpace(out)}, nil
+	}
+
+	sha, err := c.executor.Execute(ctx, "git", "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return HeadState{}, err
+	}
+	state := HeadState{Detached: true, SHA: strings.TrimSpace(sha)}
+
+	nameRev, err := c.executor.Execute(ctx, "git", "name-rev", "--name-only", "--refs=refs/heads/*", "HEAD")
+	if err == nil {
+		state.Nearest = ParseNameRev(nameRev)
+	}
+	return state, nil
+}
+
+// ParseNameRev extracts the branch name from `git name-rev --name-only`
+// output, e.g. "feature-a~2" → "feature-a". Returns "" when git reports
+// the commit as "undefined" (no branch contains it).
+func ParseNameRev(output string) string {
+	name := strings.TrimSpace(output)
+	if name == "" || name == "undefined" {
+		return ""
+	}
+	if idx := strings.IndexAny(name, "~^"); idx != -1 {
+		name = name[:idx]
+	}
+	return name
+}
+
+// Continue runs `gt continue --no-interactive` to resume an interrupted
+// restack or rebase after conflicts have been resolved.
+func (c *Client) Continue(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "continue", "--no-interactive")
+	return err
+}
+
+// BranchHeads runs `git for-each-ref` over local branches and returns the
+// full commit SHA each branch points at, keyed by branch name.
+func (c *Client) BranchHeads(ctx context.Context) (map[string]string, error) {
+	out, err := c.executor.Execute(ctx, "git", "for-each-ref", "--format=%(refname:short) %(objectname)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	return ParseBranchHeads(out), nil
+}
+
+// ParseBranchHeads parses "<branch> <sha>" lines from `git for-each-ref`.
+func ParseBranchHeads(output string) map[string]string {
+	heads := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		heads[fields[0]] = fields[1]
+	}
+	return heads
+}
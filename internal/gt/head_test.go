@@ -0,0 +1,137 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestHeadState_OnBranch(t *testing.T) {
+	client := New(&funcExecutor{fn: func(name string, args ...string) (string, error) {
+		if args[0] == "symbolic-ref" {
+			return "feature-a\n", nil
+		}
+		t.Errorf("unexpected call %s %v", name, args)
+		return "", nil
+	}})
+
+	got, err := client.HeadState(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Detached {
+		t.Error("expected attached HEAD")
+	}
+	if got.Branch != "feature-a" {
+		t.Errorf("Branch = %q, want %q", got.Branch, "feature-a")
+	}
+}
+
+func TestHeadState_Detached(t *testing.T) {
+	client := New(&funcExecutor{fn: func(name string, args ...string) (string, error) {
+		switch args[0] {
+		case "symbolic-ref":
+			return "", errors.New("exit status 1")
+		case "rev-parse":
+			return "abc1234\n", nil
+		case "name-rev":
+			return "feature-a~2\n", nil
+		}
+		return "", nil
+	}})
+
+	got, err := client.HeadState(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := HeadState{Detached: true, SHA: "abc1234", Nearest: "feature-a"}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestHeadState_DetachedNameRevFails(t *testing.T) {
+	client := New(&funcExecutor{fn: func(name string, args ...string) (string, error) {
+		switch args[0] {
+		case "symbolic-ref", "name-rev":
+			return "", errors.New("failed")
+		}
+		return "abc1234\n", nil
+	}})
+
+	got, err := client.HeadState(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Detached || got.Nearest != "" {
+		t.Errorf("got %+v, want detached with no nearest branch", got)
+	}
+}
+
+func TestHeadState_Error(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("not a git repository")}
+	client := New(mock)
+
+	if _, err := client.HeadState(context.Background()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestParseNameRev(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"feature-a\n", "feature-a"},
+		{"feature-a~2\n", "feature-a"},
+		{"feature-a^2~1", "feature-a"},
+		{"undefined\n", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ParseNameRev(tt.input); got != tt.want {
+			t.Errorf("ParseNameRev(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestContinue_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.Continue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"continue", "--no-interactive"})
+}
+
+func TestBranchHeads(t *testing.T) {
+	mock := &mockExecutor{output: "main abc123\nfeature-a def456\n"}
+	client := New(mock)
+
+	got, err := client.BranchHeads(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"for-each-ref", "--format=%(refname:short) %(objectname)", "refs/heads"})
+	want := map[string]string{"main": "abc123", "feature-a": "def456"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BranchHeads() = %v, want %v", got, want)
+	}
+}
+
+func TestBranchHeads_Error(t *testing.T) {
+	client := New(&mockExecutor{err: errors.New("not a git repository")})
+
+	if _, err := client.BranchHeads(context.Background()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestParseBranchHeads_SkipsMalformedLines(t *testing.T) {
+	got := ParseBranchHeads("main abc123\n\ngarbage\n")
+	if len(got) != 1 || got["main"] != "abc123" {
+		t.Errorf("ParseBranchHeads() = %v", got)
+	}
+}
@@ -0,0 +1,22 @@
+package gt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// HookPaths runs `git rev-parse --git-path hooks --git-common-dir` and
+// returns the directory git runs hooks from, which honors core.hooksPath,
+// and the git dir shared by every worktree.
+func (c *Client) HookPaths(ctx context.Context) (hooksDir, commonDir string, err error) {
+	out, err := c.executor.Execute(ctx, "git", "rev-parse", "--git-path", "hooks", "--git-common-dir")
+	if err != nil {
+		return "", "", err
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		return "", "", fmt.Errorf("unexpected git rev-parse output: %q", out)
+	}
+	return strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1]), nil
+}
@@ -0,0 +1,22 @@
+package gt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHookPaths(t *testing.T) {
+	mock := &mockExecutor{output: ".git/hooks\n.git\n"}
+	hooksDir, commonDir, err := New(mock).HookPaths(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCommand(t, mock, "git", []string{"rev-parse", "--git-path", "hooks", "--git-common-dir"})
+	if hooksDir != ".git/hooks" || commonDir != ".git" {
+		t.Errorf("HookPaths = %q, %q", hooksDir, commonDir)
+	}
+
+	if _, _, err := New(&mockExecutor{output: "\n"}).HookPaths(context.Background()); err == nil {
+		t.Error("expected an error for unexpected output")
+	}
+}
@@ -0,0 +1,46 @@
+package gt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// repoLabelsLimit caps how many labels RepoLabels lists; gh lists 30 by
+// default.
+const repoLabelsLimit = 200
+
+// RepoLabels runs `gh label list --json name --limit 200` and returns the
+// names of the repository's labels.
+func (c *Client) RepoLabels(ctx context.Context) ([]string, error) {
+	out, err := c.executor.Execute(ctx, "gh", "label", "list", "--json", "name", "--limit", fmt.Sprint(repoLabelsLimit))
+	if err != nil {
+		return nil, err
+	}
+	var raw []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &raw); err != nil {
+		return nil, fmt.Errorf("parsing labels: %w", err)
+	}
+	names := make([]string, 0, len(raw))
+	for _, l := range raw {
+		names = append(names, l.Name)
+	}
+	return names, nil
+}
+
+// EditPRLabels runs `gh pr edit <branchName> --add-label a,b --remove-label
+// c`, leaving out either flag when it has no labels.
+func (c *Client) EditPRLabels(ctx context.Context, branchName string, add, remove []string) error {
+	args := []string{"pr", "edit", branchName}
+	if len(add) > 0 {
+		args = append(args, "--add-label", strings.Join(add, ","))
+	}
+	if len(remove) > 0 {
+		args = append(args, "--remove-label", strings.Join(remove, ","))
+	}
+	_, err := c.executor.Execute(ctx, "gh", args...)
+	return err
+}
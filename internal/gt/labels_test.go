@@ -0,0 +1,54 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRepoLabels_Success(t *testing.T) {
+	mock := &mockExecutor{output: `[{"name":"breaking"},{"name":"good first issue"}]`}
+	client := New(mock)
+
+	got, err := client.RepoLabels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"breaking", "good first issue"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	assertCommand(t, mock, "gh", []string{"label", "list", "--json", "name", "--limit", "200"})
+}
+
+func TestRepoLabels_Error(t *testing.T) {
+	client := New(&mockExecutor{err: errors.New("not a GitHub repository")})
+	if _, err := client.RepoLabels(context.Background()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	client = New(&mockExecutor{output: "not json"})
+	if _, err := client.RepoLabels(context.Background()); err == nil {
+		t.Fatal("expected a parse error, got nil")
+	}
+}
+
+func TestEditPRLabels(t *testing.T) {
+	tests := []struct {
+		name        string
+		add, remove []string
+		want        []string
+	}{
+		{"both", []string{"perf", "needs-qa"}, []string{"wip"}, []string{"pr", "edit", "feature-a", "--add-label", "perf,needs-qa", "--remove-label", "wip"}},
+		{"add only", []string{"perf"}, nil, []string{"pr", "edit", "feature-a", "--add-label", "perf"}},
+		{"remove only", nil, []string{"wip"}, []string{"pr", "edit", "feature-a", "--remove-label", "wip"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockExecutor{}
+			if err := New(mock).EditPRLabels(context.Background(), "feature-a", tt.add, tt.remove); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertCommand(t, mock, "gh", tt.want)
+		})
+	}
+}
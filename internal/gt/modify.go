@@ -0,0 +1,11 @@
+package gt
+
+import "context"
+
+// Amend runs `gt modify --all --no-interactive`, amending every working-tree
+// change into the current branch's commit and restacking the branches
+// above it.
+func (c *Client) Amend(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "modify", "--all", "--no-interactive")
+	return err
+}
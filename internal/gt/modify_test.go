@@ -0,0 +1,21 @@
+package gt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAmend(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).Amend(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"modify", "--all", "--no-interactive"})
+}
+
+func TestDemo_AmendNeedsChanges(t *testing.T) {
+	_, client := newTestDemo()
+	if err := client.Amend(context.Background()); err == nil {
+		t.Error("the demo has no working-tree changes, so amend should fail")
+	}
+}
@@ -0,0 +1,31 @@
+package gt
+
+import "context"
+
+// Up runs `gt up --no-interactive` to check out the child of the current
+// branch.
+func (c *Client) Up(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "up", "--no-interactive")
+	return err
+}
+
+// Down runs `gt down --no-interactive` to check out the parent of the
+// current branch.
+func (c *Client) Down(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "down", "--no-interactive")
+	return err
+}
+
+// Top runs `gt top --no-interactive` to check out the tip of the current
+// stack.
+func (c *Client) Top(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "top", "--no-interactive")
+	return err
+}
+
+// Bottom runs `gt bottom --no-interactive` to check out the branch closest
+// to trunk in the current stack.
+func (c *Client) Bottom(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gt", "bottom", "--no-interactive")
+	return err
+}
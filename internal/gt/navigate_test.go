@@ -0,0 +1,61 @@
+package gt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStackNavigation_Args(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(c *Client) error
+		want []string
+	}{
+		{"up", func(c *Client) error { return c.Up(context.Background()) }, []string{"up", "--no-interactive"}},
+		{"down", func(c *Client) error { return c.Down(context.Background()) }, []string{"down", "--no-interactive"}},
+		{"top", func(c *Client) error { return c.Top(context.Background()) }, []string{"top", "--no-interactive"}},
+		{"bottom", func(c *Client) error { return c.Bottom(context.Background()) }, []string{"bottom", "--no-interactive"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockExecutor{}
+			if err := tt.run(New(mock)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertArgs(t, mock, tt.want)
+		})
+	}
+}
+
+func TestDemo_StackNavigation(t *testing.T) {
+	d, client := newTestDemo()
+	ctx := context.Background()
+	if err := client.Checkout(ctx, "auth-login-api"); err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+
+	steps := []struct {
+		run  func(context.Context) error
+		want string
+	}{
+		{client.Down, "auth-session-store"},
+		{client.Top, "auth-remember-me"},
+		{client.Bottom, "auth-session-store"},
+		{client.Up, "auth-login-api"},
+	}
+	for i, step := range steps {
+		if err := step.run(ctx); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		if d.current != step.want {
+			t.Errorf("step %d: current = %s, want %s", i, d.current, step.want)
+		}
+	}
+
+	if err := client.Top(ctx); err != nil {
+		t.Fatalf("top: %v", err)
+	}
+	if err := client.Up(ctx); err == nil {
+		t.Error("up from the top of the stack should fail")
+	}
+}
@@ -0,0 +1,74 @@
+package gt
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// MissingCommits runs `git rev-list --no-walk --ignore-missing` over shas
+// and returns those git no longer has, e.g. after a sync pruned them.
+func (c *Client) MissingCommits(ctx context.Context, shas []string) (map[string]bool, error) {
+	if len(shas) == 0 {
+		return nil, nil
+	}
+	args := append([]string{"rev-list", "--no-walk", "--ignore-missing"}, shas...)
+	out, err := c.executor.Execute(ctx, "git", args...)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		found[strings.TrimSpace(line)] = true
+	}
+	missing := make(map[string]bool)
+	for _, sha := range shas {
+		if !found[sha] {
+			missing[sha] = true
+		}
+	}
+	return missing, nil
+}
+
+// ParentRevisions returns, sorted and deduplicated, the parent revisions
+// recorded in parents.
+func ParentRevisions(parents map[string]ParentRecord) []string {
+	seen := make(map[string]bool)
+	var shas []string
+	for _, p := range parents {
+		if p.Revision != "" && !seen[p.Revision] {
+			seen[p.Revision] = true
+			shas = append(shas, p.Revision)
+		}
+	}
+	sort.Strings(shas)
+	return shas
+}
+
+// FindOrphans returns why each orphaned branch is orphaned, keyed by branch
+// name: its recorded parent is no longer a local branch in heads, or the
+// parent commit it was stacked on is in missing. Branches that no longer
+// exist themselves are skipped.
+func FindOrphans(parents map[string]ParentRecord, heads map[string]string, missing map[string]bool) map[string]string {
+	orphans := make(map[string]string)
+	for name, p := range parents {
+		if _, ok := heads[name]; !ok {
+			continue
+		}
+		switch {
+		case heads[p.Name] == "":
+			orphans[name] = "parent " + p.Name + " no longer exists"
+		case missing[p.Revision]:
+			orphans[name] = "base commit " + p.Revision[:min(len(p.Revision), 7)] + " on " + p.Name + " is gone"
+		}
+	}
+	return orphans
+}
+
+// ApplyOrphans sets Orphan on each branch in the tree from orphans.
+func ApplyOrphans(branches []*Branch, orphans map[string]string) {
+	for _, b := range branches {
+		b.Orphan = orphans[b.Name]
+		ApplyOrphans(b.Children, orphans)
+	}
+}
@@ -0,0 +1,74 @@
+package gt
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMissingCommits(t *testing.T) {
+	mock := &mockExecutor{output: "aaa\n"}
+	client := New(mock)
+
+	got, err := client.MissingCommits(context.Background(), []string{"aaa", "bbb"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"rev-list", "--no-walk", "--ignore-missing", "aaa", "bbb"})
+	if !reflect.DeepEqual(got, map[string]bool{"bbb": true}) {
+		t.Errorf("MissingCommits() = %v, want bbb", got)
+	}
+}
+
+func TestMissingCommits_NoneToCheck(t *testing.T) {
+	mock := &mockExecutor{}
+	if got, err := New(mock).MissingCommits(context.Background(), nil); err != nil || got != nil {
+		t.Errorf("MissingCommits(nil) = %v, %v", got, err)
+	}
+	if mock.calledName != "" {
+		t.Errorf("ran %s with nothing to check", mock.calledName)
+	}
+}
+
+func TestParentRevisions(t *testing.T) {
+	parents := map[string]ParentRecord{
+		"a": {Name: "main", Revision: "222"},
+		"b": {Name: "a", Revision: "111"},
+		"c": {Name: "a", Revision: "111"},
+		"d": {Name: "main"},
+	}
+	if got := ParentRevisions(parents); !reflect.DeepEqual(got, []string{"111", "222"}) {
+		t.Errorf("ParentRevisions() = %v, want [111 222]", got)
+	}
+}
+
+func TestFindOrphans(t *testing.T) {
+	parents := map[string]ParentRecord{
+		"ok":       {Name: "main", Revision: "1111111aaa"},
+		"no-base":  {Name: "main", Revision: "2222222bbb"},
+		"no-par":   {Name: "deleted", Revision: "1111111aaa"},
+		"gone-too": {Name: "deleted"},
+	}
+	heads := map[string]string{"main": "m", "ok": "o", "no-base": "n", "no-par": "p"}
+	missing := map[string]bool{"2222222bbb": true}
+
+	want := map[string]string{
+		"no-base": "base commit 2222222 on main is gone",
+		"no-par":  "parent deleted no longer exists",
+	}
+	if got := FindOrphans(parents, heads, missing); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindOrphans() = %v, want %v", got, want)
+	}
+}
+
+func TestApplyOrphans(t *testing.T) {
+	branches, _ := ParseLogShort("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	ApplyOrphans(branches, map[string]string{"feature-top": "parent x no longer exists"})
+
+	if got := FindBranch(branches, "feature-top").Orphan; got != "parent x no longer exists" {
+		t.Errorf("feature-top Orphan = %q", got)
+	}
+	if got := FindBranch(branches, "feature-base").Orphan; got != "" {
+		t.Errorf("feature-base Orphan = %q, want none", got)
+	}
+}
@@ -0,0 +1,84 @@
+package gt
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// branchMetadata is the part of Graphite's per-branch metadata grit reads.
+type branchMetadata struct {
+	ParentBranchName     string `json:"parentBranchName"`
+	ParentBranchRevision string `json:"parentBranchRevision"`
+}
+
+// ParentRecord is the parent Graphite has recorded for a branch.
+type ParentRecord struct {
+	Name     string // parent branch
+	Revision string // parent commit the branch was last restacked onto, "" if unknown
+}
+
+// BranchParents reads the parent Graphite has recorded for each tracked
+// branch from its metadata refs, keyed by branch name.
+func (c *Client) BranchParents(ctx context.Context) (map[string]ParentRecord, error) {
+	out, err := c.executor.Execute(ctx, "git", "for-each-ref", "--format=%(refname:lstrip=2) %(raw)", "refs/branch-metadata")
+	if err != nil {
+		return nil, err
+	}
+	return ParseBranchParents(out), nil
+}
+
+// ParseBranchParents parses "<branch> <metadata JSON>" lines from
+// `git for-each-ref`, skipping lines whose metadata has no parent.
+func ParseBranchParents(output string) map[string]ParentRecord {
+	parents := make(map[string]ParentRecord)
+	for _, line := range strings.Split(output, "\n") {
+		name, raw, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		var meta branchMetadata
+		if err := json.Unmarshal([]byte(raw), &meta); err != nil || meta.ParentBranchName == "" {
+			continue
+		}
+		parents[name] = ParentRecord{Name: meta.ParentBranchName, Revision: meta.ParentBranchRevision}
+	}
+	return parents
+}
+
+// ApplyParents overrides each branch's tree-derived Parent with the one
+// Graphite recorded, where parents has one that is in the tree. Roots are
+// left alone.
+func ApplyParents(branches []*Branch, parents map[string]ParentRecord) {
+	var walk func(b *Branch)
+	walk = func(b *Branch) {
+		for _, child := range b.Children {
+			if parent, ok := parents[child.Name]; ok && FindBranch(branches, parent.Name) != nil {
+				child.Parent = parent.Name
+			}
+			walk(child)
+		}
+	}
+	for _, root := range branches {
+		walk(root)
+	}
+}
+
+// numberStack sets the stack position of b, at index, and of the branches
+// above it, returning the height of the chain from b upwards.
+func numberStack(b *Branch, index int) int {
+	height := 0
+	for _, child := range b.Children {
+		height = max(height, numberStack(child, index+1))
+	}
+	b.Stack = StackPosition{Index: index, Size: index + height}
+	return height + 1
+}
+
+// linkParents sets Parent on every branch below b from the tree shape.
+func linkParents(b *Branch) {
+	for _, child := range b.Children {
+		child.Parent = b.Name
+		linkParents(child)
+	}
+}
@@ -0,0 +1,80 @@
+package gt
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseLogShort_SetsParent(t *testing.T) {
+	branches, err := ParseLogShort("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if branches[0].Parent != "" {
+		t.Errorf("trunk Parent = %q, want none", branches[0].Parent)
+	}
+	if got := FindBranch(branches, "feature-top").Parent; got != "feature-base" {
+		t.Errorf("feature-top Parent = %q, want feature-base", got)
+	}
+}
+
+func TestBranchParents(t *testing.T) {
+	mock := &mockExecutor{output: "feature-a {\"parentBranchName\":\"main\",\"parentBranchRevision\":\"abc\"}\n" +
+		"feature-b {\"parentBranchName\":\"feature-a\"}\n"}
+	client := New(mock)
+
+	got, err := client.BranchParents(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"for-each-ref", "--format=%(refname:lstrip=2) %(raw)", "refs/branch-metadata"})
+	want := map[string]ParentRecord{"feature-a": {Name: "main", Revision: "abc"}, "feature-b": {Name: "feature-a"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BranchParents() = %v, want %v", got, want)
+	}
+}
+
+func TestParseBranchParents_SkipsMalformedLines(t *testing.T) {
+	got := ParseBranchParents("a {\"parentBranchName\":\"main\"}\n\nb not-json\nc {}\n")
+	if !reflect.DeepEqual(got, map[string]ParentRecord{"a": {Name: "main"}}) {
+		t.Errorf("ParseBranchParents() = %v", got)
+	}
+}
+
+func TestApplyParents_OverridesTree(t *testing.T) {
+	branches, _ := ParseLogShort("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	ApplyParents(branches, map[string]ParentRecord{
+		"feature-top":  {Name: "main"},
+		"feature-base": {Name: "deleted"},
+		"main":         {Name: "other"},
+	})
+
+	if got, _ := FindParent(branches, "feature-top"); got != "main" {
+		t.Errorf("feature-top parent = %q, want the recorded main", got)
+	}
+	if got, _ := FindParent(branches, "feature-base"); got != "main" {
+		t.Errorf("feature-base parent = %q, want main from the tree, as the recorded parent is gone", got)
+	}
+	if _, ok := FindParent(branches, "main"); ok {
+		t.Error("trunk should stay a root")
+	}
+}
+
+func TestDemo_BranchParents(t *testing.T) {
+	_, client := newTestDemo()
+	parents, err := client.BranchParents(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, _ := client.LogShort(context.Background())
+	tree, _ := ParseLogShort(out)
+	for name, parent := range parents {
+		if got, _ := FindParent(tree, name); got != parent.Name {
+			t.Errorf("%s: recorded parent %q, tree parent %q", name, parent, got)
+		}
+	}
+	if len(parents) == 0 {
+		t.Error("demo should record parents")
+	}
+}
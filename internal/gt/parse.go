@@ -7,8 +7,57 @@ import (
 
 // PRInfo holds pull request metadata for a branch.
 type PRInfo struct {
-	Number int    // 0 means no PR
-	State  string // "OPEN", "DRAFT", "MERGED", "CLOSED", or "" if no PR
+	Number  int    // 0 means no PR
+	State   string // "OPEN", "DRAFT", "MERGED", "CLOSED", or "" if no PR
+	URL     string // the PR's web page, "" if gt didn't report it
+	HeadSHA string // commit the PR's head branch points at, "" if unknown
+	Queued  bool   // sent to the merge queue by grit and not yet merged
+	CI      CIStatus
+	// Conflicts is set when GitHub reports the PR can't merge cleanly
+	// into its base.
+	Conflicts bool
+	// Unresolved counts the PR's review threads not yet resolved.
+	Unresolved int
+
+	Labels    []string // PR label names
+	Assignees []string // assignee logins
+}
+
+// ChangeInfo summarizes the files a branch changes relative to its parent.
+type ChangeInfo struct {
+	Loaded  bool
+	Files   int      // total files changed
+	InScope int      // files changed inside the configured path scope (== Files when unscoped)
+	Owners  []string // code owners of the changed files, sorted
+	Paths   []string // changed file paths, excluding ignored files
+}
+
+// TestStatus is the outcome of the configured test command on a branch's
+// current head commit.
+type TestStatus int
+
+const (
+	TestUnknown TestStatus = iota // never run on this head
+	TestRunning
+	TestPassed
+	TestFailed
+)
+
+// CIStatus summarizes the CI checks on a PR's head commit.
+type CIStatus int
+
+const (
+	CIUnknown CIStatus = iota // no checks reported, or not fetched
+	CIPending
+	CIPassing
+	CIFailing
+)
+
+// StackPosition is a branch's place in review order within its stack,
+// counted from the branch on trunk.
+type StackPosition struct {
+	Index int // 1 for the branch on trunk, 0 for trunk itself
+	Size  int // Index plus the longest chain of branches stacked above
 }
 
 // Branch represents a single branch in the Graphite stack tree.
@@ -16,9 +65,16 @@ type Branch struct {
 	Name       string
 	IsCurrent  bool
 	Annotation string // e.g. "needs restack", "merging", "" if none
+	Orphan     string // why gt's record of the parent is broken, "" if it isn't
+	Worktree   string // path of another worktree the branch is checked out in, "" if none
+	Parent     string // parent branch name, "" for trunk; Graphite's recorded parent when known
 	Depth      int    // visual depth from gt log short (0 = trunk level)
 	Order      int    // original line position in gt log short output (for display ordering)
 	PR         PRInfo
+	Changes    ChangeInfo
+	Head       string // full commit SHA the branch points at, "" if unknown
+	Tests      TestStatus
+	Stack      StackPosition
 	Children   []*Branch
 }
 
@@ -141,6 +197,10 @@ func ParseLogShort(output string) ([]*Branch, error) {
 		parentAtDepth[p.depth] = b
 	}
 
+	linkParents(root)
+	for _, b := range root.Children {
+		numberStack(b, 1)
+	}
 	return roots, nil
 }
 
@@ -216,10 +276,13 @@ func extractAnnotation(name string) (string, string) {
 	return name, ""
 }
 
-// FindParent searches the branch tree for the parent of the named branch.
-// Returns the parent name and true if found, or ("", false) if the branch
-// is a root or not present in the tree.
+// FindParent returns the parent of the named branch: its Parent if set,
+// otherwise the branch above it in the tree. Returns ("", false) if the
+// branch is a root or not present in the tree.
 func FindParent(branches []*Branch, name string) (string, bool) {
+	if b := FindBranch(branches, name); b != nil && b.Parent != "" {
+		return b.Parent, true
+	}
 	for _, root := range branches {
 		if found, parent := findParentRecursive(root, name); found {
 			return parent, true
@@ -228,6 +291,20 @@ func FindParent(branches []*Branch, name string) (string, bool) {
 	return "", false
 }
 
+// FindBranch returns the named branch from the tree, or nil if it isn't
+// present.
+func FindBranch(branches []*Branch, name string) *Branch {
+	for _, b := range branches {
+		if b.Name == name {
+			return b
+		}
+		if found := FindBranch(b.Children, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
 // findParentRecursive walks the tree rooted at node, returning (true, parentName)
 // if name is found among its descendants.
 func findParentRecursive(node *Branch, name string) (bool, string) {
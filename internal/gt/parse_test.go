@@ -700,3 +700,27 @@ func countCurrent(b *Branch) int {
 	}
 	return count
 }
+
+func TestParseLogShort_StackPositions(t *testing.T) {
+	input := `◯    upgrade_elixir
+│ ◉  credo
+│ ◯  add_deps
+│ ◯  usage_rules
+◯─┘  master`
+	branches, err := ParseLogShort(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]StackPosition{
+		"master":         {},
+		"usage_rules":    {Index: 1, Size: 3},
+		"add_deps":       {Index: 2, Size: 3},
+		"credo":          {Index: 3, Size: 3},
+		"upgrade_elixir": {Index: 1, Size: 1},
+	}
+	for name, pos := range want {
+		if got := FindBranch(branches, name).Stack; got != pos {
+			t.Errorf("%s: Stack = %+v, want %+v", name, got, pos)
+		}
+	}
+}
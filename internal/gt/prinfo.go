@@ -1,14 +1,18 @@
 package gt
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 )
 
 // prInfoJSON matches the JSON output of `gt branch pr-info`.
 type prInfoJSON struct {
 	PRNumber int    `json:"prNumber"`
 	State    string `json:"state"`
+	URL      string `json:"url"`
 }
 
 // ParsePRInfo parses the JSON output of `gt branch pr-info` into a PRInfo.
@@ -27,5 +31,185 @@ func ParsePRInfo(output string) PRInfo {
 	return PRInfo{
 		Number: raw.PRNumber,
 		State:  raw.State,
+		URL:    raw.URL,
 	}
 }
+
+// prListFields are the fields PRForBranch asks gh for.
+const prListFields = "number,state,isDraft,url"
+
+// prListJSON is one PR in the JSON output of `gh pr list`.
+type prListJSON struct {
+	Number  int    `json:"number"`
+	State   string `json:"state"` // OPEN, CLOSED or MERGED
+	IsDraft bool   `json:"isDraft"`
+	URL     string `json:"url"`
+}
+
+// PRForBranch runs `gh pr list --head <branchName> --state all --limit 1
+// --json number,state,isDraft,url` and returns the branch's newest PR,
+// for gt versions whose `gt branch pr-info` prints nothing.
+func (c *Client) PRForBranch(ctx context.Context, branchName string) (PRInfo, error) {
+	out, err := c.executor.Execute(ctx, "gh", "pr", "list", "--head", branchName, "--state", "all", "--limit", "1", "--json", prListFields)
+	if err != nil {
+		return PRInfo{}, err
+	}
+	return ParsePRList(out), nil
+}
+
+// BranchPR returns the PR of the named branch from `gt branch pr-info`,
+// falling back to PRForBranch on gt versions that print nothing there.
+// A branch without a PR has a zero-value PRInfo.
+func (c *Client) BranchPR(ctx context.Context, branchName string) (PRInfo, error) {
+	out, err := c.BranchPRInfo(ctx, branchName)
+	if err != nil {
+		return PRInfo{}, err
+	}
+	if strings.TrimSpace(out) != "" {
+		return ParsePRInfo(out), nil
+	}
+	return c.PRForBranch(ctx, branchName)
+}
+
+// ParsePRList parses the output of PRForBranch into a PRInfo like
+// ParsePRInfo's, reporting an open draft's state as "DRAFT" as gt does.
+// Returns a zero-value PRInfo if no PR is listed or the output is
+// unparseable.
+func ParsePRList(output string) PRInfo {
+	var raw []prListJSON
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &raw); err != nil || len(raw) == 0 {
+		return PRInfo{}
+	}
+	pr := raw[0]
+	state := pr.State
+	if pr.IsDraft && state == "OPEN" {
+		state = "DRAFT"
+	}
+	return PRInfo{Number: pr.Number, State: state, URL: pr.URL}
+}
+
+// PRDetails is what grit shows of an open PR beyond gt's pr-info.
+type PRDetails struct {
+	HeadSHA   string   // commit the PR's head branch points at
+	Labels    []string // label names
+	Assignees []string // assignee logins
+	CI        CIStatus // combined status of the head commit's checks
+	Conflicts bool     // GitHub can't merge the PR cleanly into its base
+}
+
+// prDetailsFields are the fields PRDetails asks `gh pr view` for.
+const prDetailsFields = "headRefOid,labels,assignees,statusCheckRollup,mergeable"
+
+// prDetailsJSON matches `gh pr view --json ` + prDetailsFields.
+type prDetailsJSON struct {
+	HeadRefOid string `json:"headRefOid"`
+	Labels     []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	StatusCheckRollup []rollupEntry `json:"statusCheckRollup"`
+	Mergeable         string        `json:"mergeable"` // MERGEABLE, CONFLICTING or UNKNOWN while GitHub computes it
+}
+
+// rollupEntry is one check in a statusCheckRollup: a check run (status and,
+// once completed, conclusion) or a commit status context (state).
+type rollupEntry struct {
+	Typename   string `json:"__typename"`
+	Status     string `json:"status,omitempty"`
+	Conclusion string `json:"conclusion,omitempty"`
+	State      string `json:"state,omitempty"`
+}
+
+// ciStatus classifies one check.
+func (e rollupEntry) ciStatus() CIStatus {
+	if e.Typename == "StatusContext" {
+		switch e.State {
+		case "SUCCESS":
+			return CIPassing
+		case "FAILURE", "ERROR":
+			return CIFailing
+		}
+		return CIPending
+	}
+	if e.Status != "COMPLETED" {
+		return CIPending
+	}
+	switch e.Conclusion {
+	case "FAILURE", "TIMED_OUT", "CANCELLED", "ACTION_REQUIRED", "STARTUP_FAILURE":
+		return CIFailing
+	}
+	return CIPassing
+}
+
+// rollupStatus combines checks: failing if any failed, else pending if any
+// are still running, else passing. No checks is CIUnknown.
+func rollupStatus(entries []rollupEntry) CIStatus {
+	status := CIUnknown
+	for _, e := range entries {
+		switch e.ciStatus() {
+		case CIFailing:
+			return CIFailing
+		case CIPending:
+			status = CIPending
+		case CIPassing:
+			if status == CIUnknown {
+				status = CIPassing
+			}
+		}
+	}
+	return status
+}
+
+// PRDetails runs `gh pr view <branchName> --json
+// headRefOid,labels,assignees,statusCheckRollup,mergeable` and returns the
+// commit SHA the branch's PR points at on GitHub, with the PR's labels,
+// assignees, CI status and whether it has merge conflicts.
+func (c *Client) PRDetails(ctx context.Context, branchName string) (PRDetails, error) {
+	out, err := c.executor.Execute(ctx, "gh", "pr", "view", branchName, "--json", prDetailsFields)
+	if err != nil {
+		return PRDetails{}, err
+	}
+	return ParsePRDetails(out), nil
+}
+
+// ParsePRDetails parses the JSON output of PRDetails. Returns a zero
+// PRDetails if the output is empty or unparseable.
+func ParsePRDetails(output string) PRDetails {
+	var raw prDetailsJSON
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &raw); err != nil {
+		return PRDetails{}
+	}
+	d := PRDetails{
+		HeadSHA:   raw.HeadRefOid,
+		CI:        rollupStatus(raw.StatusCheckRollup),
+		Conflicts: raw.Mergeable == "CONFLICTING",
+	}
+	for _, l := range raw.Labels {
+		d.Labels = append(d.Labels, l.Name)
+	}
+	for _, a := range raw.Assignees {
+		d.Assignees = append(d.Assignees, a.Login)
+	}
+	return d
+}
+
+// PRMergedAt runs `gh pr view <branchName> --json mergedAt` and returns
+// when the branch's PR merged, or the zero time if it hasn't.
+func (c *Client) PRMergedAt(ctx context.Context, branchName string) (time.Time, error) {
+	out, err := c.executor.Execute(ctx, "gh", "pr", "view", branchName, "--json", "mergedAt")
+	if err != nil {
+		return time.Time{}, err
+	}
+	var raw struct {
+		MergedAt *time.Time `json:"mergedAt"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &raw); err != nil {
+		return time.Time{}, fmt.Errorf("parsing mergedAt: %w", err)
+	}
+	if raw.MergedAt == nil {
+		return time.Time{}, nil
+	}
+	return *raw.MergedAt, nil
+}
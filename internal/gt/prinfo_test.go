@@ -1,6 +1,12 @@
 package gt
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
 
 func TestParsePRInfo_ValidJSON(t *testing.T) {
 	tests := []struct {
@@ -8,6 +14,7 @@ func TestParsePRInfo_ValidJSON(t *testing.T) {
 		input      string
 		wantNumber int
 		wantState  string
+		wantURL    string
 	}{
 		{
 			name:       "open PR",
@@ -15,6 +22,13 @@ func TestParsePRInfo_ValidJSON(t *testing.T) {
 			wantNumber: 142,
 			wantState:  "OPEN",
 		},
+		{
+			name:       "with URL",
+			input:      `{"prNumber": 142, "state": "OPEN", "url": "https://github.com/acme/app/pull/142"}`,
+			wantNumber: 142,
+			wantState:  "OPEN",
+			wantURL:    "https://github.com/acme/app/pull/142",
+		},
 		{
 			name:       "draft PR",
 			input:      `{"prNumber": 143, "state": "DRAFT", "title": "WIP: tests"}`,
@@ -50,6 +64,9 @@ func TestParsePRInfo_ValidJSON(t *testing.T) {
 			if info.State != tt.wantState {
 				t.Errorf("State = %q, want %q", info.State, tt.wantState)
 			}
+			if info.URL != tt.wantURL {
+				t.Errorf("URL = %q, want %q", info.URL, tt.wantURL)
+			}
 		})
 	}
 }
@@ -98,3 +115,154 @@ func TestParsePRInfo_WithWhitespace(t *testing.T) {
 		t.Errorf("Number = %d, want 142", info.Number)
 	}
 }
+
+func TestPRForBranch(t *testing.T) {
+	mock := &mockExecutor{output: `[{"number":42,"state":"OPEN","isDraft":true,"url":"https://github.com/acme/app/pull/42"}]`}
+	client := New(mock)
+
+	info, err := client.PRForBranch(context.Background(), "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := PRInfo{Number: 42, State: "DRAFT", URL: "https://github.com/acme/app/pull/42"}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("PRForBranch() = %+v, want %+v", info, want)
+	}
+	assertCommand(t, mock, "gh", []string{"pr", "list", "--head", "feature-a", "--state", "all", "--limit", "1", "--json", "number,state,isDraft,url"})
+}
+
+func TestBranchPR_FallsBackToGh(t *testing.T) {
+	var calls []string
+	client := New(&funcExecutor{fn: func(name string, args ...string) (string, error) {
+		calls = append(calls, name)
+		if name == "gh" {
+			return `[{"number":7,"state":"OPEN","isDraft":false}]`, nil
+		}
+		return "\n", nil
+	}})
+	info, err := client.BranchPR(context.Background(), "feature-a")
+	if err != nil || info.Number != 7 || info.State != "OPEN" {
+		t.Errorf("BranchPR() = %+v, %v; want gh's PR #7", info, err)
+	}
+	if !reflect.DeepEqual(calls, []string{"gt", "gh"}) {
+		t.Errorf("calls = %v, want gt then gh", calls)
+	}
+}
+
+func TestBranchPR_UsesGt(t *testing.T) {
+	mock := &mockExecutor{output: `{"prNumber": 12, "state": "MERGED"}`}
+	info, err := New(mock).BranchPR(context.Background(), "feature-a")
+	if err != nil || info.Number != 12 || info.State != "MERGED" {
+		t.Errorf("BranchPR() = %+v, %v", info, err)
+	}
+	assertCommand(t, mock, "gt", []string{"branch", "pr-info", "--branch", "feature-a", "--no-interactive"})
+}
+
+func TestBranchPR_FallbackError(t *testing.T) {
+	client := New(&funcExecutor{fn: func(name string, args ...string) (string, error) {
+		if name == "gh" {
+			return "", errors.New("gh: not logged in")
+		}
+		return "", nil
+	}})
+	if _, err := client.BranchPR(context.Background(), "feature-a"); err == nil {
+		t.Error("a failed gh lookup should be an error, not a branch without a PR")
+	}
+}
+
+func TestParsePRList(t *testing.T) {
+	if got := ParsePRList(`[{"number":7,"state":"MERGED","isDraft":false}]`); got.Number != 7 || got.State != "MERGED" {
+		t.Errorf("merged PR = %+v", got)
+	}
+	for _, input := range []string{"", "[]", "not json"} {
+		if got := ParsePRList(input); !reflect.DeepEqual(got, PRInfo{}) {
+			t.Errorf("ParsePRList(%q) = %+v, want zero", input, got)
+		}
+	}
+}
+
+func TestPRDetails(t *testing.T) {
+	mock := &mockExecutor{output: `{"headRefOid":"abc123def","labels":[{"name":"breaking"},{"name":"needs-qa"}],"assignees":[{"login":"alice"}]}`}
+	client := New(mock)
+
+	d, err := client.PRDetails(context.Background(), "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := PRDetails{HeadSHA: "abc123def", Labels: []string{"breaking", "needs-qa"}, Assignees: []string{"alice"}}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("PRDetails() = %+v, want %+v", d, want)
+	}
+	assertCommand(t, mock, "gh", []string{"pr", "view", "feature-a", "--json", "headRefOid,labels,assignees,statusCheckRollup,mergeable"})
+}
+
+func TestPRDetails_Error(t *testing.T) {
+	client := New(&mockExecutor{err: errors.New("no pull requests found")})
+	if _, err := client.PRDetails(context.Background(), "feature-a"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestParsePRDetails_Invalid(t *testing.T) {
+	for _, input := range []string{"", "not json", "{}"} {
+		if got := ParsePRDetails(input); !reflect.DeepEqual(got, PRDetails{}) {
+			t.Errorf("ParsePRDetails(%q) = %+v, want zero", input, got)
+		}
+	}
+}
+
+func TestParsePRDetails_CIStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		rollup string
+		want   CIStatus
+	}{
+		{"no checks", `[]`, CIUnknown},
+		{"passing", `[{"__typename":"CheckRun","status":"COMPLETED","conclusion":"SUCCESS"},{"__typename":"CheckRun","status":"COMPLETED","conclusion":"SKIPPED"}]`, CIPassing},
+		{"running", `[{"__typename":"CheckRun","status":"COMPLETED","conclusion":"SUCCESS"},{"__typename":"CheckRun","status":"IN_PROGRESS","conclusion":""}]`, CIPending},
+		{"failing wins", `[{"__typename":"CheckRun","status":"IN_PROGRESS"},{"__typename":"CheckRun","status":"COMPLETED","conclusion":"TIMED_OUT"}]`, CIFailing},
+		{"status context", `[{"__typename":"StatusContext","state":"PENDING"}]`, CIPending},
+		{"status context error", `[{"__typename":"StatusContext","state":"ERROR"}]`, CIFailing},
+	}
+	for _, tt := range tests {
+		d := ParsePRDetails(`{"headRefOid":"abc","statusCheckRollup":` + tt.rollup + `}`)
+		if d.CI != tt.want {
+			t.Errorf("%s: CI = %d, want %d", tt.name, d.CI, tt.want)
+		}
+	}
+}
+
+func TestParsePRDetails_Mergeable(t *testing.T) {
+	for mergeable, want := range map[string]bool{"MERGEABLE": false, "CONFLICTING": true, "UNKNOWN": false} {
+		if d := ParsePRDetails(`{"headRefOid":"abc","mergeable":"` + mergeable + `"}`); d.Conflicts != want {
+			t.Errorf("mergeable %s: Conflicts = %v, want %v", mergeable, d.Conflicts, want)
+		}
+	}
+}
+
+func TestPRMergedAt(t *testing.T) {
+	mock := &mockExecutor{output: `{"mergedAt":"2026-03-04T10:00:00Z"}`}
+	got, err := New(mock).PRMergedAt(context.Background(), "feature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("PRMergedAt() = %v, want %v", got, want)
+	}
+	assertCommand(t, mock, "gh", []string{"pr", "view", "feature", "--json", "mergedAt"})
+}
+
+func TestPRMergedAt_NotMerged(t *testing.T) {
+	mock := &mockExecutor{output: `{"mergedAt":null}`}
+	got, err := New(mock).PRMergedAt(context.Background(), "feature")
+	if err != nil || !got.IsZero() {
+		t.Errorf("PRMergedAt() = %v, %v, want zero time", got, err)
+	}
+}
+
+func TestPRMergedAt_Error(t *testing.T) {
+	mock := &mockExecutor{output: "not json"}
+	if _, err := New(mock).PRMergedAt(context.Background(), "feature"); err == nil {
+		t.Error("expected a parse error")
+	}
+}
@@ -0,0 +1,47 @@
+package gt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PRText is a PR's title and description.
+type PRText struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// PRText runs `gh pr view <branchName> --json title,body`.
+func (c *Client) PRText(ctx context.Context, branchName string) (PRText, error) {
+	out, err := c.executor.Execute(ctx, "gh", "pr", "view", branchName, "--json", "title,body")
+	if err != nil {
+		return PRText{}, err
+	}
+	var text PRText
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &text); err != nil {
+		return PRText{}, fmt.Errorf("parsing PR title and body: %w", err)
+	}
+	return text, nil
+}
+
+// EditPR runs `gh pr edit <branchName> --title <title> --body <body>`.
+func (c *Client) EditPR(ctx context.Context, branchName string, text PRText) error {
+	_, err := c.executor.Execute(ctx, "gh", "pr", "edit", branchName, "--title", text.Title, "--body", text.Body)
+	return err
+}
+
+// FormatPRText renders text for editing: the title on the first line, a
+// blank line, then the body.
+func FormatPRText(text PRText) string {
+	return text.Title + "\n\n" + text.Body
+}
+
+// ParsePRText reads text edited in FormatPRText's layout back: the first
+// non-blank line is the title and the rest, trimmed, the body.
+func ParsePRText(edited string) PRText {
+	edited = strings.TrimLeft(strings.ReplaceAll(edited, "\r\n", "\n"), "\n \t")
+	title, body, _ := strings.Cut(edited, "\n")
+	return PRText{Title: strings.TrimSpace(title), Body: strings.TrimSpace(body)}
+}
@@ -0,0 +1,59 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPRText_Success(t *testing.T) {
+	mock := &mockExecutor{output: `{"body":"Adds login.\n\nFixes #3","title":"Add login"}`}
+	client := New(mock)
+
+	got, err := client.PRText(context.Background(), "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Title != "Add login" || got.Body != "Adds login.\n\nFixes #3" {
+		t.Errorf("got %+v", got)
+	}
+	assertCommand(t, mock, "gh", []string{"pr", "view", "feature-a", "--json", "title,body"})
+}
+
+func TestPRText_Error(t *testing.T) {
+	client := New(&mockExecutor{err: errors.New("no pull requests found")})
+	if _, err := client.PRText(context.Background(), "feature-a"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	client = New(&mockExecutor{output: "not json"})
+	if _, err := client.PRText(context.Background(), "feature-a"); err == nil {
+		t.Fatal("expected a parse error, got nil")
+	}
+}
+
+func TestEditPR_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.EditPR(context.Background(), "feature-a", PRText{Title: "New title", Body: "New body"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "gh", []string{"pr", "edit", "feature-a", "--title", "New title", "--body", "New body"})
+}
+
+func TestParsePRText(t *testing.T) {
+	tests := []struct {
+		edited string
+		want   PRText
+	}{
+		{FormatPRText(PRText{Title: "Add login", Body: "## Why\n\nUsers asked."}), PRText{Title: "Add login", Body: "## Why\n\nUsers asked."}},
+		{"\n  Title only  \n", PRText{Title: "Title only"}},
+		{"Title\r\n\r\nBody\r\n", PRText{Title: "Title", Body: "Body"}},
+		{"", PRText{}},
+	}
+	for _, tt := range tests {
+		if got := ParsePRText(tt.edited); got != tt.want {
+			t.Errorf("ParsePRText(%q) = %+v, want %+v", tt.edited, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package gt
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRemoteRate is the sustained rate, in calls per second, at which
+	// NewDefault lets remote metadata calls through.
+	DefaultRemoteRate = 5
+	// DefaultRemoteBurst is how many remote calls may be made back to back
+	// before DefaultRemoteRate applies.
+	DefaultRemoteBurst = 10
+)
+
+// IsRemoteMetadata reports whether a command reads metadata from GitHub or
+// Graphite (PR info, reviewers, rate limits) and so counts against remote
+// rate limits. User-initiated mutations like submit and sync are not
+// included; they are rare and must never be delayed.
+func IsRemoteMetadata(name string, args []string) bool {
+	switch name {
+	case "gh":
+		return true
+	case "gt":
+		return len(args) >= 2 && args[0] == "branch" && args[1] == "pr-info"
+	}
+	return false
+}
+
+// Limiter is a token bucket: it allows bursts of up to burst calls and
+// refills at rate calls per second.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewLimiter creates a full token bucket.
+func NewLimiter(rate float64, burst int) *Limiter {
+	return &Limiter{rate: rate, burst: float64(burst), tokens: float64(burst), now: time.Now}
+}
+
+// Wait blocks until a call may proceed or ctx is done. It reports whether
+// the caller had to wait.
+func (l *Limiter) Wait(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		l.mu.Unlock()
+		return false, nil
+	}
+	// Reserve the token now so concurrent callers queue up behind us.
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return true, ctx.Err()
+	}
+}
+
+// RemoteStats counts remote metadata calls seen by a RemoteExecutor.
+type RemoteStats struct {
+	Calls     int // calls passed through to the underlying executor
+	Coalesced int // calls answered by an identical call already in flight
+	Throttled int // calls delayed by the rate limiter
+}
+
+// remoteCall is an in-flight remote call that identical calls can wait on.
+type remoteCall struct {
+	done chan struct{}
+	out  string
+	err  error
+}
+
+// RemoteExecutor wraps a CommandExecutor, rate limiting remote metadata
+// calls and coalescing identical concurrent ones into a single call. Other
+// commands pass straight through.
+type RemoteExecutor struct {
+	next     CommandExecutor
+	limiter  *Limiter
+	mu       sync.Mutex
+	inflight map[string]*remoteCall
+	stats    RemoteStats
+}
+
+// NewRemoteExecutor wraps next with limiter.
+func NewRemoteExecutor(next CommandExecutor, limiter *Limiter) *RemoteExecutor {
+	return &RemoteExecutor{next: next, limiter: limiter, inflight: make(map[string]*remoteCall)}
+}
+
+func (e *RemoteExecutor) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	if !IsRemoteMetadata(name, args) {
+		return e.next.Execute(ctx, name, args...)
+	}
+
+	key := name + "\x00" + strings.Join(args, "\x00")
+	e.mu.Lock()
+	if call, ok := e.inflight[key]; ok {
+		e.stats.Coalesced++
+		e.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.out, call.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	call := &remoteCall{done: make(chan struct{})}
+	e.inflight[key] = call
+	e.stats.Calls++
+	e.mu.Unlock()
+
+	waited, err := e.limiter.Wait(ctx)
+	if err == nil {
+		call.out, call.err = e.next.Execute(ctx, name, args...)
+	} else {
+		call.err = err
+	}
+
+	e.mu.Lock()
+	if waited {
+		e.stats.Throttled++
+	}
+	delete(e.inflight, key)
+	e.mu.Unlock()
+	close(call.done)
+	return call.out, call.err
+}
+
+// Unwrap returns the wrapped executor.
+func (e *RemoteExecutor) Unwrap() CommandExecutor {
+	return e.next
+}
+
+// Stats returns a snapshot of the call counters.
+func (e *RemoteExecutor) Stats() RemoteStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.stats
+}
+
+// RemoteStats returns remote call counters when the client's executor is a
+// RemoteExecutor, or wraps one via an Unwrap() CommandExecutor method; ok
+// is false otherwise.
+func (c *Client) RemoteStats() (stats RemoteStats, ok bool) {
+	executor := c.executor
+	for {
+		switch e := executor.(type) {
+		case *RemoteExecutor:
+			return e.Stats(), true
+		case interface{ Unwrap() CommandExecutor }:
+			executor = e.Unwrap()
+		default:
+			return RemoteStats{}, false
+		}
+	}
+}
+
+// Quota is the GitHub API quota for one resource, e.g. "core" or "graphql".
+type Quota struct {
+	Resource  string
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// RateLimit runs `gh api rate_limit` and returns the quotas relevant to
+// grit. The rate_limit endpoint itself does not count against the quota.
+func (c *Client) RateLimit(ctx context.Context) ([]Quota, error) {
+	out, err := c.executor.Execute(ctx, "gh", "api", "rate_limit")
+	if err != nil {
+		return nil, err
+	}
+	return ParseRateLimit(out)
+}
+
+// ParseRateLimit parses `gh api rate_limit` JSON into the core (REST) and
+// graphql quotas, in that order. Missing resources are skipped.
+func ParseRateLimit(output string) ([]Quota, error) {
+	var data struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		return nil, err
+	}
+	var quotas []Quota
+	for _, name := range []string{"core", "graphql"} {
+		r, ok := data.Resources[name]
+		if !ok {
+			continue
+		}
+		quotas = append(quotas, Quota{Resource: name, Limit: r.Limit, Remaining: r.Remaining, Reset: time.Unix(r.Reset, 0)})
+	}
+	return quotas, nil
+}
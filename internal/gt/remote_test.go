@@ -0,0 +1,184 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsRemoteMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{"gh", []string{"pr", "view", "a"}, true},
+		{"gt", []string{"branch", "pr-info", "--branch", "a"}, true},
+		{"gt", []string{"log", "short"}, false},
+		{"gt", []string{"stack", "submit"}, false},
+		{"git", []string{"diff"}, false},
+	}
+	for _, tt := range tests {
+		if got := IsRemoteMetadata(tt.name, tt.args); got != tt.want {
+			t.Errorf("IsRemoteMetadata(%s %v) = %v, want %v", tt.name, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestLimiter_BurstThenWait(t *testing.T) {
+	l := NewLimiter(1000, 2)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if waited, err := l.Wait(ctx); err != nil || waited {
+			t.Fatalf("call %d: waited=%v err=%v, want immediate", i, waited, err)
+		}
+	}
+	if waited, err := l.Wait(ctx); err != nil || !waited {
+		t.Errorf("third call: waited=%v err=%v, want throttled", waited, err)
+	}
+}
+
+func TestLimiter_Refills(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(1, 1)
+	l.now = func() time.Time { return now }
+
+	if waited, _ := l.Wait(context.Background()); waited {
+		t.Fatal("first call should not wait")
+	}
+	now = now.Add(time.Second)
+	if waited, _ := l.Wait(context.Background()); waited {
+		t.Error("call after refill should not wait")
+	}
+}
+
+func TestLimiter_ContextCancelled(t *testing.T) {
+	l := NewLimiter(0.001, 1)
+	l.Wait(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestRemoteExecutor_PassesThroughLocalCommands(t *testing.T) {
+	mock := &mockExecutor{output: "ok"}
+	e := NewRemoteExecutor(mock, NewLimiter(1, 1))
+
+	for i := 0; i < 3; i++ {
+		if _, err := e.Execute(context.Background(), "git", "status"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if stats := e.Stats(); stats != (RemoteStats{}) {
+		t.Errorf("stats = %+v, want zero for local commands", stats)
+	}
+}
+
+func TestRemoteExecutor_CoalescesConcurrentCalls(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	next := &funcExecutor{fn: func(name string, args ...string) (string, error) {
+		calls.Add(1)
+		<-release
+		return `{"number":1}`, nil
+	}}
+	e := NewRemoteExecutor(next, NewLimiter(1000, 10))
+
+	var wg sync.WaitGroup
+	results := make([]string, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = e.Execute(context.Background(), "gh", "pr", "view", "a")
+		}(i)
+	}
+	// Wait until the followers have joined the in-flight call.
+	for e.Stats().Coalesced < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("underlying calls = %d, want 1", n)
+	}
+	for i, r := range results {
+		if r != `{"number":1}` {
+			t.Errorf("result %d = %q", i, r)
+		}
+	}
+	if stats := e.Stats(); stats.Calls != 1 || stats.Coalesced != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestRemoteExecutor_CountsThrottledCalls(t *testing.T) {
+	e := NewRemoteExecutor(&mockExecutor{}, NewLimiter(1000, 1))
+	for i := 0; i < 3; i++ {
+		e.Execute(context.Background(), "gh", "pr", "view", "a")
+	}
+	if stats := e.Stats(); stats.Calls != 3 || stats.Throttled != 2 {
+		t.Errorf("stats = %+v, want 3 calls, 2 throttled", stats)
+	}
+}
+
+func TestClientRemoteStats(t *testing.T) {
+	if _, ok := New(&mockExecutor{}).RemoteStats(); ok {
+		t.Error("plain executor should report no stats")
+	}
+	if _, ok := NewDefault().RemoteStats(); !ok {
+		t.Error("default client should report stats")
+	}
+	if _, ok := New(unwrapper{NewDefault().Executor()}).RemoteStats(); !ok {
+		t.Error("a wrapped remote executor should report stats")
+	}
+}
+
+// unwrapper wraps an executor the way recording does.
+type unwrapper struct{ next CommandExecutor }
+
+func (u unwrapper) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	return u.next.Execute(ctx, name, args...)
+}
+
+func (u unwrapper) Unwrap() CommandExecutor { return u.next }
+
+func TestRateLimit(t *testing.T) {
+	mock := &mockExecutor{output: `{"resources":{
+		"core":{"limit":5000,"remaining":4990,"reset":1700000000},
+		"graphql":{"limit":5000,"remaining":120,"reset":1700000100},
+		"search":{"limit":30,"remaining":30,"reset":1700000000}}}`}
+	client := New(mock)
+
+	got, err := client.RateLimit(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "gh", []string{"api", "rate_limit"})
+	if len(got) != 2 {
+		t.Fatalf("got %d quotas, want 2: %+v", len(got), got)
+	}
+	if got[0].Resource != "core" || got[0].Remaining != 4990 || got[0].Limit != 5000 {
+		t.Errorf("core = %+v", got[0])
+	}
+	if got[1].Resource != "graphql" || got[1].Remaining != 120 || !got[1].Reset.Equal(time.Unix(1700000100, 0)) {
+		t.Errorf("graphql = %+v", got[1])
+	}
+}
+
+func TestRateLimit_Errors(t *testing.T) {
+	if _, err := New(&mockExecutor{err: errors.New("gh: not logged in")}).RateLimit(context.Background()); err == nil {
+		t.Error("expected command error")
+	}
+	if _, err := ParseRateLimit("not json"); err == nil {
+		t.Error("expected parse error")
+	}
+}
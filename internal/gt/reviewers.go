@@ -0,0 +1,77 @@
+package gt
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// prReviewersJSON matches `gh pr view --json reviewRequests,reviews`.
+type prReviewersJSON struct {
+	ReviewRequests []struct {
+		Login string `json:"login"`
+		Slug  string `json:"slug"`
+		Name  string `json:"name"`
+	} `json:"reviewRequests"`
+	Reviews []struct {
+		Author struct {
+			Login string `json:"login"`
+		} `json:"author"`
+	} `json:"reviews"`
+}
+
+// PRReviewers runs `gh pr view <branchName> --json reviewRequests,reviews`
+// and returns everyone requested or who has reviewed: user logins and team
+// slugs.
+func (c *Client) PRReviewers(ctx context.Context, branchName string) ([]string, error) {
+	out, err := c.executor.Execute(ctx, "gh", "pr", "view", branchName, "--json", "reviewRequests,reviews")
+	if err != nil {
+		return nil, err
+	}
+	return ParsePRReviewers(out), nil
+}
+
+// RequestReviewers runs `gh pr edit <branchName> --add-reviewer a,b`,
+// requesting review on the branch's PR from user logins or org/team
+// slugs.
+func (c *Client) RequestReviewers(ctx context.Context, branchName string, reviewers []string) error {
+	_, err := c.executor.Execute(ctx, "gh", "pr", "edit", branchName, "--add-reviewer", strings.Join(reviewers, ","))
+	return err
+}
+
+// ParsePRReviewers parses the JSON output of PRReviewers into a
+// de-duplicated list of logins and team slugs. Returns nil if the output
+// is empty or unparseable.
+func ParsePRReviewers(output string) []string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil
+	}
+	var raw prReviewersJSON
+	if err := json.Unmarshal([]byte(output), &raw); err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var reviewers []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			reviewers = append(reviewers, name)
+		}
+	}
+	for _, r := range raw.ReviewRequests {
+		switch {
+		case r.Login != "":
+			add(r.Login)
+		case r.Slug != "":
+			add(r.Slug)
+		default:
+			add(r.Name)
+		}
+	}
+	for _, r := range raw.Reviews {
+		add(r.Author.Login)
+	}
+	return reviewers
+}
@@ -0,0 +1,50 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPRReviewers_Success(t *testing.T) {
+	mock := &mockExecutor{output: `{"reviewRequests":[{"__typename":"User","login":"alice"},{"__typename":"Team","name":"API","slug":"team-api"}],"reviews":[{"author":{"login":"bob"},"state":"APPROVED"},{"author":{"login":"alice"},"state":"COMMENTED"}]}`}
+	client := New(mock)
+
+	got, err := client.PRReviewers(context.Background(), "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"alice", "team-api", "bob"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	assertCommand(t, mock, "gh", []string{"pr", "view", "feature-a", "--json", "reviewRequests,reviews"})
+}
+
+func TestRequestReviewers_Success(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.RequestReviewers(context.Background(), "feature-a", []string{"alice", "org/team-api"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "gh", []string{"pr", "edit", "feature-a", "--add-reviewer", "alice,org/team-api"})
+}
+
+func TestPRReviewers_Error(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("no pull requests found")}
+	client := New(mock)
+
+	if _, err := client.PRReviewers(context.Background(), "feature-a"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestParsePRReviewers_Invalid(t *testing.T) {
+	for _, input := range []string{"", "not json", "{}"} {
+		if got := ParsePRReviewers(input); got != nil {
+			t.Errorf("ParsePRReviewers(%q) = %v, want nil", input, got)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package gt
+
+import "os/exec"
+
+// SplitMode is how `gt split` divides a branch.
+type SplitMode string
+
+const (
+	// SplitByCommit asks which commits start each new branch.
+	SplitByCommit SplitMode = "--by-commit"
+	// SplitByHunk stages hunks interactively into each new branch.
+	SplitByHunk SplitMode = "--by-hunk"
+)
+
+// InteractiveExecutor is implemented by executors that can hand the
+// terminal to a command, for gt commands that prompt the user.
+type InteractiveExecutor interface {
+	Command(name string, args ...string) *exec.Cmd
+}
+
+// SplitCommand returns `gt split <mode>` for the checked-out branch, to be
+// run attached to the terminal. ok is false when the client's executor, or
+// any executor it wraps via Unwrap() CommandExecutor, can't run
+// interactive commands, as in the demo and tests.
+func (c *Client) SplitCommand(mode SplitMode) (cmd *exec.Cmd, ok bool) {
+	executor := c.executor
+	for {
+		switch e := executor.(type) {
+		case InteractiveExecutor:
+			return e.Command("gt", "split", string(mode)), true
+		case interface{ Unwrap() CommandExecutor }:
+			executor = e.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
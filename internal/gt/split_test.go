@@ -0,0 +1,24 @@
+package gt
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	cmd, ok := New(unwrapper{NewDefault().Executor()}).SplitCommand(SplitByHunk)
+	if !ok {
+		t.Fatal("the default executor should run interactive commands")
+	}
+	if want := []string{"gt", "split", "--by-hunk"}; !slices.Equal(cmd.Args, want) {
+		t.Errorf("args = %v, want %v", cmd.Args, want)
+	}
+}
+
+func TestSplitCommand_NotInteractive(t *testing.T) {
+	for name, client := range map[string]*Client{"mock": New(&mockExecutor{}), "demo": NewDemo()} {
+		if _, ok := client.SplitCommand(SplitByCommit); ok {
+			t.Errorf("%s: split should be unavailable", name)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package gt
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatusPorcelain runs `git status --porcelain` and returns the raw output.
+// Empty output means the working tree is clean.
+func (c *Client) StatusPorcelain(ctx context.Context) (string, error) {
+	return c.executor.Execute(ctx, "git", "status", "--porcelain")
+}
+
+// CommitSubjects runs `git log --format=%s <parent>..<branch>` and returns
+// the subject line of each commit on branch that isn't on parent, newest first.
+func (c *Client) CommitSubjects(ctx context.Context, parent, branch string) ([]string, error) {
+	out, err := c.executor.Execute(ctx, "git", "log", "--format=%s", parent+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+	var subjects []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects, nil
+}
+
+// FirstCommitTime runs `git log --reverse --format=%ct <parent>..<branch>`
+// and returns when the oldest commit on branch that isn't on parent was
+// committed, or the zero time if there are none.
+func (c *Client) FirstCommitTime(ctx context.Context, parent, branch string) (time.Time, error) {
+	out, err := c.executor.Execute(ctx, "git", "log", "--reverse", "--format=%ct", parent+".."+branch)
+	if err != nil {
+		return time.Time{}, err
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(out), "\n")
+	if first == "" {
+		return time.Time{}, nil
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// RunShell runs a user-configured command via `sh -c` and returns its output.
+func (c *Client) RunShell(ctx context.Context, command string) (string, error) {
+	return c.executor.Execute(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,78 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStatusPorcelain(t *testing.T) {
+	mock := &mockExecutor{output: " M main.go\n"}
+	client := New(mock)
+
+	got, err := client.StatusPorcelain(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != " M main.go\n" {
+		t.Errorf("got %q", got)
+	}
+	assertCommand(t, mock, "git", []string{"status", "--porcelain"})
+}
+
+func TestCommitSubjects(t *testing.T) {
+	mock := &mockExecutor{output: "fix: second\nfeat: first\n\n"}
+	client := New(mock)
+
+	got, err := client.CommitSubjects(context.Background(), "main", "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"fix: second", "feat: first"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	assertCommand(t, mock, "git", []string{"log", "--format=%s", "main..feature-a"})
+}
+
+func TestCommitSubjects_Error(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("bad revision")}
+	client := New(mock)
+
+	if _, err := client.CommitSubjects(context.Background(), "main", "feature-a"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestFirstCommitTime(t *testing.T) {
+	mock := &mockExecutor{output: "1760000000\n1760086400\n"}
+	client := New(mock)
+
+	got, err := client.FirstCommitTime(context.Background(), "main", "feature-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equal(time.Unix(1760000000, 0)) {
+		t.Errorf("got %v, want the first (oldest) commit", got)
+	}
+	assertCommand(t, mock, "git", []string{"log", "--reverse", "--format=%ct", "main..feature-a"})
+}
+
+func TestFirstCommitTime_NoCommits(t *testing.T) {
+	client := New(&mockExecutor{output: ""})
+	got, err := client.FirstCommitTime(context.Background(), "main", "feature-a")
+	if err != nil || !got.IsZero() {
+		t.Errorf("got %v, %v; want zero time", got, err)
+	}
+}
+
+func TestRunShell(t *testing.T) {
+	mock := &mockExecutor{output: "ok\n"}
+	client := New(mock)
+
+	if _, err := client.RunShell(context.Background(), "go test ./..."); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "sh", []string{"-c", "go test ./..."})
+}
@@ -0,0 +1,107 @@
+package gt
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// FetchTrunk runs `git fetch origin <trunk>`, updating origin/<trunk>
+// without touching any local branch.
+func (c *Client) FetchTrunk(ctx context.Context, trunk string) error {
+	_, err := c.executor.Execute(ctx, "git", "fetch", "origin", trunk)
+	return err
+}
+
+// CommitsBetween runs `git rev-list --count <from>..<to>` and returns the
+// number of commits on to that from lacks.
+func (c *Client) CommitsBetween(ctx context.Context, from, to string) (int, error) {
+	out, err := c.executor.Execute(ctx, "git", "rev-list", "--count", from+".."+to)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(out))
+}
+
+// MergedInto runs `git branch --merged <ref>` and returns the local
+// branches whose heads ref already contains.
+func (c *Client) MergedInto(ctx context.Context, ref string) (map[string]bool, error) {
+	out, err := c.executor.Execute(ctx, "git", "branch", "--merged", ref, "--format=%(refname:short)")
+	if err != nil {
+		return nil, err
+	}
+	merged := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			merged[name] = true
+		}
+	}
+	return merged, nil
+}
+
+// MergeConflicts runs `git merge-tree --write-tree` to merge branch into
+// onto without touching the working tree, and returns the files the merge
+// would conflict in. It approximates what restacking branch onto onto
+// will hit.
+func (c *Client) MergeConflicts(ctx context.Context, onto, branch string) ([]string, error) {
+	out, err := c.executor.Execute(ctx, "git", "merge-tree", "--write-tree", "--name-only", "--no-messages", onto, branch)
+	if err != nil {
+		// merge-tree exits 1 on conflicts, still listing them.
+		if files := ParseMergeTree(out); len(files) > 0 {
+			return files, nil
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
+// ParseMergeTree extracts the conflicted files from `git merge-tree
+// --write-tree --name-only --no-messages` output: the tree ID, then one
+// line per conflicted file.
+func ParseMergeTree(output string) []string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	var files []string
+	seen := make(map[string]bool)
+	for _, line := range lines[1:] {
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		files = append(files, unquotePath(line))
+	}
+	return files
+}
+
+// SimulateSync returns a copy of the tree as `gt sync -f` is expected to
+// leave it: the branches in deleted are gone and their children are
+// restacked onto the nearest surviving ancestor. The copy shares nothing
+// with branches, so it can be rendered alongside the original.
+func SimulateSync(branches []*Branch, deleted map[string]bool) []*Branch {
+	var graft func(b *Branch, parent string) []*Branch
+	graft = func(b *Branch, parent string) []*Branch {
+		if deleted[b.Name] {
+			var lifted []*Branch
+			for _, child := range b.Children {
+				lifted = append(lifted, graft(child, parent)...)
+			}
+			return lifted
+		}
+		c := *b
+		c.Parent = parent
+		c.Children = nil
+		for _, child := range b.Children {
+			c.Children = append(c.Children, graft(child, b.Name)...)
+		}
+		return []*Branch{&c}
+	}
+	var roots []*Branch
+	for _, root := range branches {
+		c := *root
+		c.Children = nil
+		for _, child := range root.Children {
+			c.Children = append(c.Children, graft(child, root.Name)...)
+		}
+		roots = append(roots, &c)
+	}
+	return roots
+}
@@ -0,0 +1,105 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFetchTrunk(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).FetchTrunk(context.Background(), "main"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"fetch", "origin", "main"})
+}
+
+func TestCommitsBetween(t *testing.T) {
+	mock := &mockExecutor{output: "12\n"}
+	n, err := New(mock).CommitsBetween(context.Background(), "main", "origin/main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 12 {
+		t.Errorf("CommitsBetween() = %d, want 12", n)
+	}
+	assertCommand(t, mock, "git", []string{"rev-list", "--count", "main..origin/main"})
+}
+
+func TestMergedInto(t *testing.T) {
+	mock := &mockExecutor{output: "main\nfix-login\n"}
+	merged, err := New(mock).MergedInto(context.Background(), "origin/main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(merged, map[string]bool{"main": true, "fix-login": true}) {
+		t.Errorf("MergedInto() = %v", merged)
+	}
+	assertCommand(t, mock, "git", []string{"branch", "--merged", "origin/main", "--format=%(refname:short)"})
+}
+
+func TestMergeConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		want    []string
+		wantErr bool
+	}{
+		{"clean", "4b825dc\n", nil, nil, false},
+		{"conflicts", "4b825dc\na.go\n\"sp ace.go\"\na.go\n", errors.New("exit status 1"), []string{"a.go", "sp ace.go"}, false},
+		{"failure", "", errors.New("unknown option"), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockExecutor{output: tt.output, err: tt.err}
+			files, err := New(mock).MergeConflicts(context.Background(), "origin/main", "feature")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(files, tt.want) {
+				t.Errorf("files = %q, want %q", files, tt.want)
+			}
+			assertCommand(t, mock, "git", []string{"merge-tree", "--write-tree", "--name-only", "--no-messages", "origin/main", "feature"})
+		})
+	}
+}
+
+func TestSimulateSync(t *testing.T) {
+	branches, err := ParseLogShort("│ ◯  feature-top\n│ ◯  feature-mid\n│ ◯  feature-base\n◯─┘  main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := SimulateSync(branches, map[string]bool{"feature-base": true})
+
+	if FindBranch(after, "feature-base") != nil {
+		t.Error("deleted branch should be gone")
+	}
+	mid := FindBranch(after, "feature-mid")
+	if mid == nil || mid.Parent != "main" {
+		t.Fatalf("feature-mid should move onto main: %+v", mid)
+	}
+	if top := FindBranch(after, "feature-top"); top == nil || top.Parent != "feature-mid" {
+		t.Errorf("feature-top should stay on feature-mid: %+v", top)
+	}
+	if FindBranch(branches, "feature-base") == nil || len(branches[0].Children) != 1 || branches[0].Children[0].Name != "feature-base" {
+		t.Error("the original tree should be unchanged")
+	}
+}
+
+func TestDemo_SyncPreview(t *testing.T) {
+	_, client := newTestDemo()
+	ctx := context.Background()
+	merged, err := client.MergedInto(ctx, "origin/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !merged["auth-session-store"] {
+		t.Errorf("the merged demo PR's branch should be merged: %v", merged)
+	}
+	files, err := client.MergeConflicts(ctx, "origin/main", "auth-remember-me")
+	if err != nil || len(files) == 0 {
+		t.Errorf("a demo branch needing a restack should conflict: %v, %v", files, err)
+	}
+}
@@ -0,0 +1,33 @@
+package gt
+
+import (
+	"context"
+	"sort"
+)
+
+// Track runs `gt track <branch> --parent <parent> --no-interactive` to start
+// tracking an existing git branch on top of parent.
+func (c *Client) Track(ctx context.Context, branch, parent string) error {
+	_, err := c.executor.Execute(ctx, "gt", "track", branch, "--parent", parent, "--no-interactive")
+	return err
+}
+
+// Untrack runs `gt untrack <branch> --force --no-interactive` to stop
+// tracking branch, and the branches stacked on it, keeping them in git.
+func (c *Client) Untrack(ctx context.Context, branch string) error {
+	_, err := c.executor.Execute(ctx, "gt", "untrack", branch, "--force", "--no-interactive")
+	return err
+}
+
+// Untracked returns, sorted, the local branches in heads that aren't in
+// the tree, i.e. branches git has but Graphite doesn't track.
+func Untracked(branches []*Branch, heads map[string]string) []string {
+	var names []string
+	for name := range heads {
+		if FindBranch(branches, name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
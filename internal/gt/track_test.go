@@ -0,0 +1,34 @@
+package gt
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTrack(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.Track(context.Background(), "hotfix", "main"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"track", "hotfix", "--parent", "main", "--no-interactive"})
+}
+
+func TestUntrack(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).Untrack(context.Background(), "feature-a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertArgs(t, mock, []string{"untrack", "feature-a", "--force", "--no-interactive"})
+}
+
+func TestUntracked(t *testing.T) {
+	branches, _ := ParseLogShort("│ ◉  feature-a\n◯─┘  main")
+	heads := map[string]string{"main": "a", "feature-a": "b", "wip": "c", "hotfix": "d"}
+
+	if got := Untracked(branches, heads); !reflect.DeepEqual(got, []string{"hotfix", "wip"}) {
+		t.Errorf("Untracked() = %v, want [hotfix wip]", got)
+	}
+}
@@ -0,0 +1,29 @@
+package gt
+
+import (
+	"context"
+	"strings"
+)
+
+// FetchBranch runs `git fetch origin <branch>`, updating origin/<branch>
+// without touching the local branch.
+func (c *Client) FetchBranch(ctx context.Context, branch string) error {
+	_, err := c.executor.Execute(ctx, "git", "fetch", "origin", branch)
+	return err
+}
+
+// CommitSummaries runs `git log --format=%h %an: %s <from>..<to>` and
+// returns one line per commit on to that from lacks, newest first.
+func (c *Client) CommitSummaries(ctx context.Context, from, to string) ([]string, error) {
+	out, err := c.executor.Execute(ctx, "git", "log", "--format=%h %an: %s", from+".."+to)
+	if err != nil {
+		return nil, err
+	}
+	var commits []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commits = append(commits, line)
+		}
+	}
+	return commits, nil
+}
@@ -0,0 +1,36 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFetchBranch(t *testing.T) {
+	mock := &mockExecutor{}
+	if err := New(mock).FetchBranch(context.Background(), "feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"fetch", "origin", "feature"})
+}
+
+func TestCommitSummaries(t *testing.T) {
+	mock := &mockExecutor{output: "abc1234 alice: fix typo\n\ndef5678 bob: address review\n"}
+	commits, err := New(mock).CommitSummaries(context.Background(), "feature", "origin/feature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"abc1234 alice: fix typo", "def5678 bob: address review"}
+	if !reflect.DeepEqual(commits, want) {
+		t.Errorf("CommitSummaries() = %q, want %q", commits, want)
+	}
+	assertCommand(t, mock, "git", []string{"log", "--format=%h %an: %s", "feature..origin/feature"})
+}
+
+func TestCommitSummaries_Error(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("unknown revision")}
+	if _, err := New(mock).CommitSummaries(context.Background(), "feature", "origin/feature"); err == nil {
+		t.Fatal("expected error")
+	}
+}
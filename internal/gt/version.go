@@ -0,0 +1,42 @@
+package gt
+
+import (
+	"context"
+	"strings"
+)
+
+// GitVersion runs `git --version` and returns the version number, e.g.
+// "2.43.0" from "git version 2.43.0".
+func (c *Client) GitVersion(ctx context.Context) (string, error) {
+	out, err := c.executor.Execute(ctx, "git", "--version")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(strings.TrimSpace(out), "git version "), nil
+}
+
+// GtVersion runs `gt --version` and returns the version it prints.
+func (c *Client) GtVersion(ctx context.Context) (string, error) {
+	out, err := c.executor.Execute(ctx, "gt", "--version")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// GhAuthStatus runs `gh auth status`, which fails when gh is not logged
+// in to GitHub.
+func (c *Client) GhAuthStatus(ctx context.Context) error {
+	_, err := c.executor.Execute(ctx, "gh", "auth", "status")
+	return err
+}
+
+// GitDir runs `git rev-parse --absolute-git-dir` and returns the git dir
+// of the repository containing the working directory.
+func (c *Client) GitDir(ctx context.Context) (string, error) {
+	out, err := c.executor.Execute(ctx, "git", "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
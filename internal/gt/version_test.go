@@ -0,0 +1,42 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGitVersion(t *testing.T) {
+	mock := &mockExecutor{output: "git version 2.43.0\n"}
+	got, err := New(mock).GitVersion(context.Background())
+	if err != nil || got != "2.43.0" {
+		t.Errorf("GitVersion = %q, %v", got, err)
+	}
+	assertCommand(t, mock, "git", []string{"--version"})
+}
+
+func TestGtVersion(t *testing.T) {
+	mock := &mockExecutor{output: "1.4.2\n"}
+	got, err := New(mock).GtVersion(context.Background())
+	if err != nil || got != "1.4.2" {
+		t.Errorf("GtVersion = %q, %v", got, err)
+	}
+	assertCommand(t, mock, "gt", []string{"--version"})
+}
+
+func TestGhAuthStatus(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("You are not logged into any GitHub hosts")}
+	if err := New(mock).GhAuthStatus(context.Background()); err == nil {
+		t.Error("expected error")
+	}
+	assertCommand(t, mock, "gh", []string{"auth", "status"})
+}
+
+func TestGitDir(t *testing.T) {
+	mock := &mockExecutor{output: "/src/grit/.git\n"}
+	got, err := New(mock).GitDir(context.Background())
+	if err != nil || got != "/src/grit/.git" {
+		t.Errorf("GitDir = %q, %v", got, err)
+	}
+	assertCommand(t, mock, "git", []string{"rev-parse", "--absolute-git-dir"})
+}
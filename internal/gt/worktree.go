@@ -0,0 +1,80 @@
+package gt
+
+import (
+	"context"
+	"strings"
+)
+
+// WorktreeAdd runs `git worktree add --detach <dir> <ref>`, checking out ref
+// into a new worktree without touching the current one.
+func (c *Client) WorktreeAdd(ctx context.Context, dir, ref string) error {
+	_, err := c.executor.Execute(ctx, "git", "worktree", "add", "--detach", dir, ref)
+	return err
+}
+
+// WorktreeRemove runs `git worktree remove --force <dir>`.
+func (c *Client) WorktreeRemove(ctx context.Context, dir string) error {
+	_, err := c.executor.Execute(ctx, "git", "worktree", "remove", "--force", dir)
+	return err
+}
+
+// RunShellIn runs a user-configured command via `sh -c` inside dir. The
+// directory and command are passed as positional parameters rather than
+// spliced into the script, so neither needs quoting.
+func (c *Client) RunShellIn(ctx context.Context, dir, command string) (string, error) {
+	return c.executor.Execute(ctx, "sh", "-c", `cd "$1" && eval "$2"`, "sh", dir, command)
+}
+
+// Worktree is a working tree of the repository, from `git worktree list`.
+type Worktree struct {
+	Path   string
+	Branch string // checked-out branch, "" when HEAD is detached or the worktree is bare
+}
+
+// Worktrees runs `git worktree list --porcelain` and returns every working
+// tree of the repository, the main one first.
+func (c *Client) Worktrees(ctx context.Context) ([]Worktree, error) {
+	out, err := c.executor.Execute(ctx, "git", "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	return ParseWorktrees(out), nil
+}
+
+// ParseWorktrees parses the output of `git worktree list --porcelain`: one
+// block of "worktree <path>", "HEAD <sha>" and "branch refs/heads/<name>"
+// (or "detached") lines per working tree, separated by blank lines.
+func ParseWorktrees(output string) []Worktree {
+	var worktrees []Worktree
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if path, ok := strings.CutPrefix(line, "worktree "); ok {
+			worktrees = append(worktrees, Worktree{Path: path})
+			continue
+		}
+		if ref, ok := strings.CutPrefix(line, "branch "); ok && len(worktrees) > 0 {
+			worktrees[len(worktrees)-1].Branch = strings.TrimPrefix(ref, "refs/heads/")
+		}
+	}
+	return worktrees
+}
+
+// CheckedOutElsewhere maps each branch checked out in a worktree to that
+// worktree's path, leaving out current, the branch checked out here.
+func CheckedOutElsewhere(worktrees []Worktree, current string) map[string]string {
+	elsewhere := make(map[string]string)
+	for _, w := range worktrees {
+		if w.Branch != "" && w.Branch != current {
+			elsewhere[w.Branch] = w.Path
+		}
+	}
+	return elsewhere
+}
+
+// ApplyWorktrees sets Worktree on each branch in the tree from elsewhere.
+func ApplyWorktrees(branches []*Branch, elsewhere map[string]string) {
+	for _, b := range branches {
+		b.Worktree = elsewhere[b.Name]
+		ApplyWorktrees(b.Children, elsewhere)
+	}
+}
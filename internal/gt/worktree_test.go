@@ -0,0 +1,91 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWorktreeAdd(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.WorktreeAdd(context.Background(), "/tmp/wt", "feature-a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"worktree", "add", "--detach", "/tmp/wt", "feature-a"})
+}
+
+func TestWorktreeAdd_Error(t *testing.T) {
+	mock := &mockExecutor{err: errors.New("already exists")}
+	client := New(mock)
+
+	if err := client.WorktreeAdd(context.Background(), "/tmp/wt", "feature-a"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestWorktreeRemove(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if err := client.WorktreeRemove(context.Background(), "/tmp/wt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"worktree", "remove", "--force", "/tmp/wt"})
+}
+
+func TestRunShellIn(t *testing.T) {
+	mock := &mockExecutor{}
+	client := New(mock)
+
+	if _, err := client.RunShellIn(context.Background(), "/tmp/wt", "go test ./..."); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "sh", []string{"-c", `cd "$1" && eval "$2"`, "sh", "/tmp/wt", "go test ./..."})
+}
+
+const worktreePorcelain = `worktree /src/app
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/feature-a
+
+worktree /src/app-review
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feature-b
+
+worktree /src/app-bisect
+HEAD 3333333333333333333333333333333333333333
+detached
+`
+
+func TestWorktrees(t *testing.T) {
+	mock := &mockExecutor{output: worktreePorcelain}
+	client := New(mock)
+
+	got, err := client.Worktrees(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommand(t, mock, "git", []string{"worktree", "list", "--porcelain"})
+	want := []Worktree{{Path: "/src/app", Branch: "feature-a"}, {Path: "/src/app-review", Branch: "feature-b"}, {Path: "/src/app-bisect"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Worktrees() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckedOutElsewhere(t *testing.T) {
+	got := CheckedOutElsewhere(ParseWorktrees(worktreePorcelain), "feature-a")
+	if want := map[string]string{"feature-b": "/src/app-review"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckedOutElsewhere() = %v, want %v", got, want)
+	}
+}
+
+func TestApplyWorktrees(t *testing.T) {
+	child := &Branch{Name: "feature-b"}
+	root := &Branch{Name: "main", Children: []*Branch{child}}
+	ApplyWorktrees([]*Branch{root}, map[string]string{"feature-b": "/src/app-review"})
+	if child.Worktree != "/src/app-review" || root.Worktree != "" {
+		t.Errorf("Worktree = %q on child, %q on root", child.Worktree, root.Worktree)
+	}
+}
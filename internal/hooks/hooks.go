@@ -0,0 +1,123 @@
+// Package hooks implements `grit hooks`: git hooks that write a sentinel
+// file under the git dir after checkouts, commits and rewrites. grit
+// watches the sentinel, so it refreshes reliably even on filesystems where
+// watching refs/ misses changes (network mounts, some container setups).
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SentinelFile is the file the hooks write, relative to the git dir.
+const SentinelFile = "grit/refresh"
+
+// Names are the hooks grit installs.
+var Names = []string{"post-checkout", "post-commit", "post-rewrite"}
+
+// marker identifies a hook grit installed.
+const marker = "# Installed by grit hooks: tells a running grit to refresh."
+
+// Line is the command a hook runs. It never fails the hook, since a failing
+// post-checkout hook makes the checkout report failure. The common git dir
+// is where grit looks from any worktree.
+const Line = `d=$(git rev-parse --git-common-dir 2>/dev/null) && mkdir -p "$d/grit" && date +%s >"$d/grit/refresh" 2>/dev/null || true`
+
+// script is the content of a hook grit installs.
+const script = "#!/bin/sh\n" + marker + "\n" + Line + "\n"
+
+// Result is what was done to one hook.
+type Result struct {
+	Hook   string
+	OK     bool // false if the hook was left alone and needs attention
+	Detail string
+}
+
+// Install writes each hook into hooksDir, updating ones grit installed
+// before. Hooks the user already has are left alone, with the line to add
+// to them by hand. It creates the sentinel in gitDir so grit can watch it
+// before any hook has run.
+func Install(hooksDir, gitDir string) ([]Result, error) {
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, name := range Names {
+		path := filepath.Join(hooksDir, name)
+		existing, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return nil, err
+		case !strings.Contains(string(existing), marker):
+			results = append(results, Result{name, false, "existing hook left alone; add this line to it: " + Line})
+			continue
+		}
+		if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+			return nil, err
+		}
+		// WriteFile keeps the mode of an existing file.
+		if err := os.Chmod(path, 0o755); err != nil {
+			return nil, err
+		}
+		detail := "installed"
+		if existing != nil {
+			detail = "updated"
+		}
+		results = append(results, Result{name, true, detail})
+	}
+	return results, touch(filepath.Join(gitDir, SentinelFile))
+}
+
+// Uninstall removes the hooks grit installed from hooksDir, leaving any
+// others.
+func Uninstall(hooksDir string) ([]Result, error) {
+	var results []Result
+	for _, name := range Names {
+		path := filepath.Join(hooksDir, name)
+		existing, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			results = append(results, Result{name, true, "not installed"})
+			continue
+		case err != nil:
+			return nil, err
+		case !strings.Contains(string(existing), marker):
+			results = append(results, Result{name, true, "not grit's hook, left alone"})
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+		results = append(results, Result{name, true, "removed"})
+	}
+	return results, nil
+}
+
+// touch creates path if it doesn't exist.
+func touch(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Write prints results to w, one hook per line.
+func Write(w io.Writer, results []Result) {
+	for _, r := range results {
+		mark := "✓"
+		if !r.OK {
+			mark = "!"
+		}
+		fmt.Fprintf(w, "%s %-13s %s\n", mark, r.Hook, r.Detail)
+	}
+}
@@ -0,0 +1,94 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallAndUninstall(t *testing.T) {
+	gitDir := t.TempDir()
+	hooksDir := filepath.Join(gitDir, "hooks")
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mine := "#!/bin/sh\nnpm test\n"
+	if err := os.WriteFile(filepath.Join(hooksDir, "post-commit"), []byte(mine), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := Install(hooksDir, gitDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"post-checkout": "installed", "post-rewrite": "installed"}
+	for _, r := range results {
+		if r.Hook == "post-commit" {
+			if r.OK || !strings.Contains(r.Detail, Line) {
+				t.Errorf("post-commit = %+v, want it left alone with the line to add", r)
+			}
+			continue
+		}
+		if !r.OK || r.Detail != want[r.Hook] {
+			t.Errorf("%s = %+v", r.Hook, r)
+		}
+		info, err := os.Stat(filepath.Join(hooksDir, r.Hook))
+		if err != nil || info.Mode()&0o111 == 0 {
+			t.Errorf("%s should be executable: %v", r.Hook, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(hooksDir, "post-commit")); string(data) != mine {
+		t.Errorf("post-commit was changed:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, SentinelFile)); err != nil {
+		t.Errorf("sentinel should exist after install: %v", err)
+	}
+
+	results, _ = Install(hooksDir, gitDir)
+	if results[0].Detail != "updated" {
+		t.Errorf("reinstall = %+v, want updated", results[0])
+	}
+
+	results, err = Uninstall(hooksDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		_, err := os.Stat(filepath.Join(hooksDir, r.Hook))
+		if r.Hook == "post-commit" {
+			if err != nil {
+				t.Error("uninstall should leave the user's hook")
+			}
+		} else if r.Detail != "removed" || err == nil {
+			t.Errorf("%s = %+v, want removed", r.Hook, r)
+		}
+	}
+}
+
+func TestScript_WritesSentinel(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Dir = repo
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("hook failed: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".git", SentinelFile)); err != nil {
+		t.Errorf("hook should write the sentinel: %v", err)
+	}
+
+	// Outside a repository the hook still succeeds.
+	cmd = exec.Command("sh", "-c", script)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+filepath.Dir(cmd.Dir))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("hook should never fail: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,241 @@
+// Package journal records stack activity grit observes — branches
+// appearing, PRs merging, branches going away, actions run on them — in an
+// append-only file under the git dir, for reports such as `grit digest`
+// and the detail panel's history.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// File is where events are stored, relative to the git dir, one JSON
+// object per line.
+const File = "grit/journal.jsonl"
+
+// Event kinds.
+const (
+	// Seen records a branch that existed when the journal was started, so
+	// it isn't later reported as created.
+	Seen    = "seen"
+	Created = "created"
+	Merged  = "merged"
+	Deleted = "deleted"
+	// Action records a grit action (submit, restack, ...) that changed a
+	// branch, named in the event's Action field.
+	Action = "action"
+)
+
+// historyLen is how many actions State keeps per branch.
+const historyLen = 5
+
+// Event is one journal entry.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Branch string    `json:"branch"`
+	PR     int       `json:"pr,omitempty"`
+	Action string    `json:"action,omitempty"`
+}
+
+// State is what the journal knows, replayed from its events.
+type State struct {
+	Started  bool               // any events have been recorded
+	Branches map[string]bool    // branches seen or created and not since deleted
+	Merged   map[string]bool    // branches with a recorded merge
+	Actions  map[string][]Event // each branch's latest actions, oldest first
+}
+
+// Load reads all events from gitDir. A missing journal has no events;
+// malformed lines are skipped.
+func Load(gitDir string) ([]Event, error) {
+	f, err := os.Open(filepath.Join(gitDir, File))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if json.Unmarshal(scanner.Bytes(), &e) == nil && e.Kind != "" {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// Append adds events to the journal in gitDir.
+func Append(gitDir string, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	path := filepath.Join(gitDir, File)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// Replay builds the state the events describe.
+func Replay(events []Event) State {
+	s := State{Branches: make(map[string]bool), Merged: make(map[string]bool), Actions: make(map[string][]Event)}
+	for _, e := range events {
+		s.apply(e)
+	}
+	return s
+}
+
+func (s *State) apply(e Event) {
+	s.Started = true
+	switch e.Kind {
+	case Seen, Created:
+		s.Branches[e.Branch] = true
+	case Deleted:
+		delete(s.Branches, e.Branch)
+		delete(s.Actions, e.Branch)
+	case Merged:
+		s.Merged[e.Branch] = true
+	case Action:
+		actions := append(s.Actions[e.Branch], e)
+		if len(actions) > historyLen {
+			actions = actions[len(actions)-historyLen:]
+		}
+		s.Actions[e.Branch] = actions
+	}
+}
+
+// History returns branch's latest actions, newest first.
+func (s State) History(branch string) []Event {
+	actions := s.Actions[branch]
+	history := make([]Event, len(actions))
+	for i, e := range actions {
+		history[len(actions)-1-i] = e
+	}
+	return history
+}
+
+// RecordAction returns an action event for each of branches and updates s.
+func (s *State) RecordAction(action string, branches []string, now time.Time) []Event {
+	events := make([]Event, 0, len(branches))
+	for _, name := range branches {
+		e := Event{Time: now, Kind: Action, Branch: name, Action: action}
+		s.apply(e)
+		events = append(events, e)
+	}
+	return events
+}
+
+// Observe compares the branches present after a reload with s, updates s,
+// and returns the events to record. The first observation of a new journal
+// records existing branches as seen rather than created.
+func (s *State) Observe(branches []string, now time.Time) []Event {
+	kind := Created
+	if !s.Started {
+		kind = Seen
+	}
+	present := make(map[string]bool, len(branches))
+	var events []Event
+	for _, name := range branches {
+		present[name] = true
+		if !s.Branches[name] {
+			events = append(events, Event{Time: now, Kind: kind, Branch: name})
+		}
+	}
+	var gone []string
+	for name := range s.Branches {
+		if !present[name] {
+			gone = append(gone, name)
+		}
+	}
+	sort.Strings(gone)
+	for _, name := range gone {
+		events = append(events, Event{Time: now, Kind: Deleted, Branch: name})
+	}
+	for _, e := range events {
+		s.apply(e)
+	}
+	s.Started = true
+	return events
+}
+
+// ObserveMerged returns merge events for branches whose PR is merged and
+// that have no recorded merge yet, and updates s. prs maps branch names to
+// PR numbers.
+func (s *State) ObserveMerged(prs map[string]int, now time.Time) []Event {
+	names := make([]string, 0, len(prs))
+	for name := range prs {
+		if !s.Merged[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	events := make([]Event, 0, len(names))
+	for _, name := range names {
+		e := Event{Time: now, Kind: Merged, Branch: name, PR: prs[name]}
+		s.apply(e)
+		events = append(events, e)
+	}
+	return events
+}
+
+// Cycle is a branch's trip through review, replayed from the journal.
+type Cycle struct {
+	FirstSubmit time.Time // first submit through grit, zero if none
+	Merged      time.Time // when the merge was first seen, zero if not yet
+	Submits     int
+	Restacks    int
+}
+
+// Cycles replays each branch's review cycle from events, counting the
+// submit and restack actions grit recorded. A branch created again under
+// an old name starts a new cycle.
+func Cycles(events []Event) map[string]Cycle {
+	cycles := make(map[string]Cycle)
+	for _, e := range events {
+		c := cycles[e.Branch]
+		switch e.Kind {
+		case Created:
+			c = Cycle{}
+		case Merged:
+			if c.Merged.IsZero() {
+				c.Merged = e.Time
+			}
+		case Action:
+			switch e.Action {
+			case "submit":
+				if c.FirstSubmit.IsZero() {
+					c.FirstSubmit = e.Time
+				}
+				c.Submits++
+			case "restack":
+				c.Restacks++
+			}
+		default:
+			continue
+		}
+		cycles[e.Branch] = c
+	}
+	return cycles
+}
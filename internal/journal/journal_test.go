@@ -0,0 +1,158 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func kinds(events []Event) []string {
+	var out []string
+	for _, e := range events {
+		out = append(out, e.Kind+" "+e.Branch)
+	}
+	return out
+}
+
+func TestObserve_FirstRunRecordsSeen(t *testing.T) {
+	s := Replay(nil)
+	got := kinds(s.Observe([]string{"a", "b"}, time.Now()))
+	want := []string{"seen a", "seen b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+func TestObserve_CreatedAndDeleted(t *testing.T) {
+	s := Replay([]Event{{Kind: Seen, Branch: "a"}, {Kind: Seen, Branch: "b"}})
+	got := kinds(s.Observe([]string{"a", "c"}, time.Now()))
+	want := []string{"created c", "deleted b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	if events := s.Observe([]string{"a", "c"}, time.Now()); len(events) != 0 {
+		t.Errorf("no change should record nothing, got %v", kinds(events))
+	}
+}
+
+func TestObserve_EmptyTreeStartsJournal(t *testing.T) {
+	s := Replay(nil)
+	s.Observe(nil, time.Now())
+	got := kinds(s.Observe([]string{"a"}, time.Now()))
+	if !reflect.DeepEqual(got, []string{"created a"}) {
+		t.Errorf("events = %v, want created a", got)
+	}
+}
+
+func TestObserveMerged_Once(t *testing.T) {
+	s := Replay(nil)
+	events := s.ObserveMerged(map[string]int{"b": 2, "a": 1}, time.Now())
+	if got := kinds(events); !reflect.DeepEqual(got, []string{"merged a", "merged b"}) {
+		t.Errorf("events = %v", got)
+	}
+	if events[0].PR != 1 {
+		t.Errorf("PR = %d, want 1", events[0].PR)
+	}
+	if events := s.ObserveMerged(map[string]int{"a": 1}, time.Now()); len(events) != 0 {
+		t.Errorf("merge should be recorded once, got %v", kinds(events))
+	}
+}
+
+func TestAppendLoad_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if events, err := Load(dir); err != nil || events != nil {
+		t.Fatalf("missing journal: events=%v err=%v", events, err)
+	}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	first := []Event{{Time: now, Kind: Created, Branch: "a"}}
+	second := []Event{{Time: now, Kind: Merged, Branch: "a", PR: 7}}
+	if err := Append(dir, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(dir, second); err != nil {
+		t.Fatal(err)
+	}
+	// A corrupt line is skipped.
+	f, err := os.OpenFile(filepath.Join(dir, File), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{not json\n")
+	f.Close()
+
+	got, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(first, second...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load = %+v, want %+v", got, want)
+	}
+}
+
+func TestRecordAction_History(t *testing.T) {
+	s := Replay(nil)
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	s.Observe([]string{"a", "b"}, start)
+	s.RecordAction("submit", []string{"a", "b"}, start)
+	for i := range historyLen {
+		s.RecordAction("restack", []string{"a"}, start.Add(time.Duration(i+1)*time.Hour))
+	}
+
+	history := s.History("a")
+	if len(history) != historyLen {
+		t.Fatalf("history = %d events, want %d", len(history), historyLen)
+	}
+	if history[0].Time != start.Add(historyLen*time.Hour) || history[0].Action != "restack" {
+		t.Errorf("newest = %+v, want the last restack", history[0])
+	}
+	if got := s.History("b"); len(got) != 1 || got[0].Action != "submit" {
+		t.Errorf("b history = %+v", got)
+	}
+
+	s.Observe([]string{"b"}, start)
+	if got := s.History("a"); len(got) != 0 {
+		t.Errorf("deleted branch should lose its history, got %+v", got)
+	}
+}
+
+func TestRecordAction_Replayed(t *testing.T) {
+	dir := t.TempDir()
+	s := Replay(nil)
+	if err := Append(dir, s.RecordAction("fold", []string{"a"}, time.Now())); err != nil {
+		t.Fatal(err)
+	}
+	events, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Replay(events).History("a"); len(got) != 1 || got[0].Action != "fold" {
+		t.Errorf("replayed history = %+v", got)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2026, 3, n, 0, 0, 0, 0, time.UTC) }
+	events := []Event{
+		{Time: day(1), Kind: Seen, Branch: "a"},
+		{Time: day(2), Kind: Action, Branch: "a", Action: "submit"},
+		{Time: day(3), Kind: Action, Branch: "a", Action: "restack"},
+		{Time: day(3), Kind: Action, Branch: "a", Action: "rename"},
+		{Time: day(4), Kind: Action, Branch: "a", Action: "submit"},
+		{Time: day(6), Kind: Merged, Branch: "a", PR: 1},
+		{Time: day(7), Kind: Merged, Branch: "a", PR: 1},
+		{Time: day(2), Kind: Created, Branch: "b"},
+		{Time: day(3), Kind: Action, Branch: "b", Action: "restack"},
+		{Time: day(5), Kind: Created, Branch: "b"},
+	}
+	got := Cycles(events)
+	want := map[string]Cycle{
+		"a": {FirstSubmit: day(2), Merged: day(6), Submits: 2, Restacks: 1},
+		"b": {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles() = %+v, want %+v", got, want)
+	}
+}
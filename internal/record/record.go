@@ -0,0 +1,223 @@
+// Package record captures a grit session — rendered frames, bubbletea
+// messages and the commands grit runs with their output — to a file that
+// `grit replay` plays back, so rendering bugs that depend on exact gt
+// output and timing can be reproduced from a user's recording.
+package record
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// Event kinds.
+const (
+	Frame = "frame" // a rendered view, recorded when it changes
+	Msg   = "msg"   // a message passed to Update
+	Exec  = "exec"  // a command grit ran, with its output or error
+)
+
+// maxMsgLen caps how much of a message's printed form is recorded.
+const maxMsgLen = 500
+
+// Event is one line of a recording.
+type Event struct {
+	T    int64  `json:"t"` // milliseconds since the recording started
+	Kind string `json:"kind"`
+	Data string `json:"data"`
+	Err  string `json:"err,omitempty"`  // Exec: the command's error
+	Dur  int64  `json:"dur,omitempty"`  // Exec: milliseconds the command took
+	Type string `json:"type,omitempty"` // Msg: the message's Go type
+}
+
+// Recorder appends events to a recording file. It is safe for concurrent
+// use, since commands run on their own goroutines. Write errors are
+// dropped: a broken recording must not break the session.
+type Recorder struct {
+	mu    sync.Mutex
+	f     *os.File
+	w     *bufio.Writer
+	start time.Time
+	now   func() time.Time
+}
+
+// Create starts a recording at path, truncating any existing file.
+func Create(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{f: f, w: bufio.NewWriter(f), start: time.Now(), now: time.Now}, nil
+}
+
+// Close flushes and closes the recording.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.w.Flush(); err != nil {
+		r.f.Close()
+		return err
+	}
+	return r.f.Close()
+}
+
+// add writes e, stamped with the time since the recording started.
+// Everything but frames is redacted, since command output and error
+// messages can carry credentials.
+func (r *Recorder) add(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.T = r.now().Sub(r.start).Milliseconds()
+	if e.Kind != Frame {
+		e.Data, e.Err = gt.Redact(e.Data), gt.Redact(e.Err)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	r.w.Write(data)
+	r.w.WriteByte('\n')
+}
+
+// Executor wraps next, recording each command with its output, error and
+// duration.
+func (r *Recorder) Executor(next gt.CommandExecutor) gt.CommandExecutor {
+	return &executor{next: next, r: r}
+}
+
+type executor struct {
+	next gt.CommandExecutor
+	r    *Recorder
+}
+
+func (e *executor) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	start := e.r.now()
+	out, err := e.next.Execute(ctx, name, args...)
+	ev := Event{Kind: Exec, Data: gt.FormatCommand(name, args...) + "\n" + out, Dur: e.r.now().Sub(start).Milliseconds()}
+	if err != nil {
+		ev.Err = err.Error()
+	}
+	e.r.add(ev)
+	return out, err
+}
+
+// Unwrap returns the wrapped executor.
+func (e *executor) Unwrap() gt.CommandExecutor {
+	return e.next
+}
+
+// Model wraps m, recording every message it is sent and every distinct
+// frame it renders.
+func (r *Recorder) Model(m tea.Model) tea.Model {
+	return model{inner: m, r: r, last: new(string)}
+}
+
+type model struct {
+	inner tea.Model
+	r     *Recorder
+	last  *string // previous frame, shared across copies of the model
+}
+
+func (m model) Init() tea.Cmd {
+	return m.inner.Init()
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	text := fmt.Sprintf("%+v", msg)
+	if len(text) > maxMsgLen {
+		text = text[:maxMsgLen] + "…"
+	}
+	m.r.add(Event{Kind: Msg, Type: fmt.Sprintf("%T", msg), Data: text})
+	var cmd tea.Cmd
+	m.inner, cmd = m.inner.Update(msg)
+	return m, cmd
+}
+
+func (m model) View() string {
+	view := m.inner.View()
+	if view != *m.last {
+		*m.last = view
+		m.r.add(Event{Kind: Frame, Data: view})
+	}
+	return view
+}
+
+// Load reads a recording. Malformed lines are an error, since a replay
+// that silently skips frames would mislead.
+func Load(rd io.Reader) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+// Replay writes each frame to w as a full-screen redraw, sleeping between
+// frames to match the recording divided by speed. Pauses longer than
+// maxGap are shortened to it.
+func Replay(ctx context.Context, events []Event, w io.Writer, speed float64, maxGap time.Duration) error {
+	var last int64
+	for _, e := range events {
+		if e.Kind != Frame {
+			continue
+		}
+		gap := min(time.Duration(float64(e.T-last)*float64(time.Millisecond)/speed), maxGap)
+		last = e.T
+		select {
+		case <-time.After(gap):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if _, err := io.WriteString(w, "\x1b[H\x1b[2J"+strings.ReplaceAll(e.Data, "\n", "\r\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteLog writes the messages and commands of a recording to w as a
+// timeline, one entry per line, for reading alongside the replay.
+func WriteLog(events []Event, w io.Writer) error {
+	for _, e := range events {
+		var line string
+		switch e.Kind {
+		case Msg:
+			line = fmt.Sprintf("%8d  msg   %s %s", e.T, e.Type, e.Data)
+		case Exec:
+			cmd, out, _ := strings.Cut(e.Data, "\n")
+			line = fmt.Sprintf("%8d  exec  %s (%dms)", e.T, cmd, e.Dur)
+			if e.Err != "" {
+				line += " error: " + e.Err
+			}
+			if out = strings.TrimRight(out, "\n"); out != "" {
+				line += "\n" + indent(out)
+			}
+		default:
+			continue
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func indent(s string) string {
+	return "          │ " + strings.ReplaceAll(s, "\n", "\n          │ ")
+}
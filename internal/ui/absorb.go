@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// absorbSummary condenses gt absorb's report into a status message: its
+// last line, which says where the hunks went.
+func absorbSummary(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	if last == "" {
+		return "Absorbed staged changes"
+	}
+	return "Absorbed staged changes — " + truncateToWidth(last, 80)
+}
+
+// startAbsorb runs `gt absorb`, which amends commits across the stack,
+// once confirmed, and reports gt's summary in the status bar.
+func (m *Model) startAbsorb() []tea.Cmd {
+	const label = "Absorbing staged changes..."
+	return m.confirmAction("absorb", pendingAction{
+		desc:    label,
+		warning: "Each staged hunk is amended into the commit below it that it belongs to, and the branches above are restacked.",
+		commands: previewCommands(func(ctx context.Context, client *gt.Client) error {
+			_, err := client.Absorb(ctx)
+			return err
+		}),
+		run: func(m *Model) []tea.Cmd {
+			m.running = true
+			client := m.gtClient
+			spinnerCmd := m.statusBar.startSpinner(label)
+			return []tea.Cmd{spinnerCmd, func() tea.Msg {
+				ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+				defer cancel()
+				out, err := client.Absorb(ctx)
+				return actionResultMsg{action: "absorb", err: err, message: absorbSummary(out)}
+			}}
+		},
+	})
+}
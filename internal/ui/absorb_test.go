@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestAbsorbSummary(t *testing.T) {
+	tests := []struct{ output, want string }{
+		{"", "Absorbed staged changes"},
+		{"Found 2 hunks\nAbsorbed 2 hunks into feature-base\n", "Absorbed staged changes — Absorbed 2 hunks into feature-base"},
+	}
+	for _, tt := range tests {
+		if got := absorbSummary(tt.output); got != tt.want {
+			t.Errorf("absorbSummary(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestAbsorbKey_ConfirmsThenReportsSummary(t *testing.T) {
+	var args []string
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, a ...string) (string, error) {
+		if name == "gt" && a[0] == "absorb" {
+			args = a
+			return "Absorbed 1 hunk into feature-base\n", nil
+		}
+		return "", nil
+	}}
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	m = sendKey(m, 'a')
+	if m.mode != modeConfirm || !containsString(m.View(), "gt absorb --force") {
+		t.Fatalf("absorb should confirm first, mode = %d:\n%s", m.mode, m.View())
+	}
+	if args != nil {
+		t.Fatal("absorb ran before confirming")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	for _, msg := range batchMsgs(cmd) {
+		if _, ok := msg.(actionResultMsg); ok {
+			updated, _ = m.Update(msg)
+			m = updated.(Model)
+		}
+	}
+	if len(args) == 0 {
+		t.Fatal("gt absorb should run after confirming")
+	}
+	if m.statusBar.message != "Absorbed staged changes — Absorbed 1 hunk into feature-base" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
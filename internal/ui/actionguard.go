@@ -0,0 +1,51 @@
+package ui
+
+import "time"
+
+// actionRepeatWindow is how long after an action starts or finishes the
+// same action on the same branch is ignored. It covers the gap between key
+// repeats, so a held key fires once.
+const actionRepeatWindow = time.Second
+
+// actionGuard drops repeats of the most recent mutating action. Input is
+// blocked while an action runs, but key repeats still queued when it
+// finishes would otherwise run it again, e.g. submitting a stack twice.
+type actionGuard struct {
+	key string // action and branch of the last allowed action
+	at  time.Time
+}
+
+// repeated reports whether action on branch at now repeats the last
+// started action within the window. branch is "" for repo-wide actions.
+func (g *actionGuard) repeated(action, branch string, now time.Time) bool {
+	return g.key == action+"\x00"+branch && now.Sub(g.at) < actionRepeatWindow
+}
+
+// started records that action on branch started at now. Actions refused
+// before starting (e.g. on trunk) are not recorded, so they can be retried.
+func (g *actionGuard) started(action, branch string, now time.Time) {
+	g.key, g.at = action+"\x00"+branch, now
+}
+
+// finished restarts the repeat window when the last action completes, since
+// that is when queued key repeats are let through.
+func (g *actionGuard) finished(now time.Time) {
+	if g.key != "" {
+		g.at = now
+	}
+}
+
+// specTarget returns the action s mutates and the branch it would run on,
+// or "" if s doesn't change repo or PR state.
+func (m Model) specTarget(s *actionSpec) (action, branch string) {
+	if s == nil || s.mutates == "" {
+		return "", ""
+	}
+	if s.repoWide {
+		return s.mutates, ""
+	}
+	if b := m.selectedBranch(); b != nil {
+		return s.mutates, b.Name
+	}
+	return s.mutates, ""
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestActionGuard_Window(t *testing.T) {
+	var g actionGuard
+	now := time.Now()
+	if g.repeated("submit", "a", now) {
+		t.Error("first action is not a repeat")
+	}
+	g.started("submit", "a", now)
+	if !g.repeated("submit", "a", now.Add(100*time.Millisecond)) {
+		t.Error("same action and branch within the window should repeat")
+	}
+	if g.repeated("submit", "b", now) || g.repeated("restack", "a", now) {
+		t.Error("a different action or branch is not a repeat")
+	}
+	if g.repeated("submit", "a", now.Add(actionRepeatWindow)) {
+		t.Error("the window should expire")
+	}
+
+	g.finished(now.Add(5 * time.Second))
+	if !g.repeated("submit", "a", now.Add(5*time.Second+100*time.Millisecond)) {
+		t.Error("finishing should restart the window")
+	}
+}
+
+func TestKeyRepeat_DoesNotResubmit(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.cursor = 1 // feature-base
+
+	m = sendKey(m, 's')
+	if !m.running {
+		t.Fatal("first s should start the submit")
+	}
+	updated, _ := m.Update(actionResultMsg{action: "submit", message: "Stack submitted"})
+	m = updated.(Model)
+
+	// A key repeat queued behind the first submit arrives right after it.
+	m = sendKey(m, 's')
+	if m.running {
+		t.Error("repeated s right after the submit finished should be ignored")
+	}
+
+	// A different branch is a different action.
+	m.cursor = 0
+	m = sendKey(m, 's')
+	if !m.running {
+		t.Error("submit of another branch should start")
+	}
+}
+
+func TestKeyRepeat_AllowedAfterWindow(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.cursor = 1
+	m = sendKey(m, 'r')
+	updated, _ := m.Update(actionResultMsg{action: "restack", message: "Restacked"})
+	m = updated.(Model)
+	m.actionGuard.at = m.actionGuard.at.Add(-actionRepeatWindow)
+
+	m = sendKey(m, 'r')
+	if !m.running {
+		t.Error("restack should run again once the window has passed")
+	}
+}
+
+func TestKeyRepeat_RefusedActionNotRecorded(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.cursor = 2 // trunk
+	m = sendKey(m, 's')
+	if m.actionGuard.key != "" {
+		t.Errorf("refused submit should not be recorded, got %q", m.actionGuard.key)
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if !m.running {
+		t.Error("checkout should start")
+	}
+}
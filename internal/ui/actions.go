@@ -0,0 +1,440 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// actionSpec describes one keyboard action. The default keymap, the names
+// config and profiles rebind keys by, the help screen, the tree legend,
+// the command palette and history, the repeat guard, the confirmation
+// policy and the untracked-branch check are all derived from actionSpecs,
+// and the tree, palette and history all run an action through its execute
+// func, so a new action is added in one place (plus its handler in
+// actionHandlers).
+type actionSpec struct {
+	name    string                       // config and profile name, e.g. "stackSubmit"
+	binding func(k *keyMap) *key.Binding // the action's field in keyMap
+	keys    []string                     // default keys
+	helpKey string                       // key shown in help when not just the keys, e.g. "q" for q/ctrl+c
+	help    string                       // short description in key hints
+
+	section   string             // help screen section, "" to leave the action off it
+	desc      string             // help screen and command palette description
+	legend    string             // label in the tree legend, "" if not shown there
+	mutates   string             // action the repeat guard tracks, "" if it changes nothing
+	repoWide  bool               // mutates the repo as a whole rather than the selected branch
+	tracked   bool               // only applies to branches Graphite tracks
+	when      func(m Model) bool // whether the action applies right now, nil for always
+	noPalette bool               // left out of the command palette, e.g. cursor movement
+	noHistory bool               // left out of the command history, e.g. checkouts
+
+	execute func(m *Model) []tea.Cmd // runs the action, from actionHandlers; nil for keys only another view handles
+	confirm confirmPolicy            // when the commands of the action it mutates are confirmed first
+}
+
+// Help screen sections, in order.
+var helpSections = []string{"Navigation", "Actions", "Detached HEAD / Rebase", "Views", "Diff View"}
+
+// Applicability predicates shared by several actions.
+func rebasing(m Model) bool { return m.repo.rebasing }
+func detached(m Model) bool { return m.repo.head.Detached }
+func noStacks(m Model) bool { return !hasStacks(m.displayEntries) }
+func trackable(m Model) bool {
+	b := m.selectedBranch()
+	return m.selectedUntracked() != "" || b != nil && b.Orphan != ""
+}
+
+// actionSpecs lists every action, in help screen order within each
+// section. The legend lists its actions in this order too.
+var actionSpecs = []actionSpec{
+
+	// Navigation
+	{
+		name: "up", binding: func(k *keyMap) *key.Binding { return &k.Up }, keys: []string{"up", "k"}, help: "up",
+		section: "Navigation", desc: "Move cursor up", noPalette: true,
+	},
+	{
+		name: "down", binding: func(k *keyMap) *key.Binding { return &k.Down }, keys: []string{"down", "j"}, help: "down",
+		section: "Navigation", desc: "Move cursor down", noPalette: true,
+	},
+	{
+		name: "checkout", binding: func(k *keyMap) *key.Binding { return &k.Checkout }, keys: []string{"enter"}, help: "checkout",
+		section: "Navigation", desc: "Check out selected branch, or collapse/expand the group under the cursor", legend: "checkout", mutates: "checkout", noHistory: true,
+	},
+	{
+		name: "finder", binding: func(k *keyMap) *key.Binding { return &k.Finder }, keys: []string{"/"}, help: "find branch",
+		section: "Navigation", desc: "Find a branch by fuzzy name and check it out",
+	},
+	{
+		name: "trunk", binding: func(k *keyMap) *key.Binding { return &k.Trunk }, keys: []string{"m"}, help: "trunk",
+		section: "Navigation", desc: "Check out trunk (main/master)", legend: "trunk", mutates: "trunk", repoWide: true, noHistory: true,
+	},
+	{
+		name: "stackDown", binding: func(k *keyMap) *key.Binding { return &k.StackDown }, keys: []string{"["}, help: "down stack",
+		section: "Navigation", desc: "Check out the current branch's parent (gt down)",
+	},
+	{
+		name: "stackUp", binding: func(k *keyMap) *key.Binding { return &k.StackUp }, keys: []string{"]"}, help: "up stack",
+		section: "Navigation", desc: "Check out the current branch's child (gt up)",
+	},
+	{
+		name: "stackBottom", binding: func(k *keyMap) *key.Binding { return &k.StackBottom }, keys: []string{"{"}, help: "stack bottom",
+		section: "Navigation", desc: "Check out the bottom of the current stack (gt bottom)",
+	},
+	{
+		name: "stackTop", binding: func(k *keyMap) *key.Binding { return &k.StackTop }, keys: []string{"}"}, help: "stack top",
+		section: "Navigation", desc: "Check out the top of the current stack (gt top)",
+	},
+
+	// Actions
+	{
+		name: "stackSubmit", binding: func(k *keyMap) *key.Binding { return &k.StackSubmit }, keys: []string{"s"}, help: "submit stack",
+		section: "Actions", desc: "Submit stack", legend: "submit", mutates: "submit", tracked: true,
+	},
+	{
+		name: "downstackSubmit", binding: func(k *keyMap) *key.Binding { return &k.DownstackSubmit }, keys: []string{"S"}, help: "submit downstack",
+		section: "Actions", desc: "Submit downstack", legend: "downstack", mutates: "downstack-submit", tracked: true,
+	},
+	{
+		name: "branchSubmit", binding: func(k *keyMap) *key.Binding { return &k.BranchSubmit }, keys: []string{"b"}, help: "submit branch",
+		section: "Actions", desc: "Submit selected branch only", legend: "branch", mutates: "branch-submit", tracked: true,
+	},
+	{
+		name: "draftSubmit", binding: func(k *keyMap) *key.Binding { return &k.DraftSubmit }, keys: []string{"ctrl+d"}, help: "submit as draft",
+		section: "Actions", desc: "Submit selected branch, opening its PR as a draft", mutates: "draft-submit", tracked: true,
+	},
+	{
+		name: "submitOptions", binding: func(k *keyMap) *key.Binding { return &k.SubmitOptions }, keys: []string{"N"}, help: "submit with options",
+		section: "Actions", desc: "Submit with options: scope, draft, update only, reviewers",
+	},
+	{
+		name: "publish", binding: func(k *keyMap) *key.Binding { return &k.Publish }, keys: []string{"ctrl+p"}, help: "publish PR",
+		section: "Actions", desc: "Publish selected branch's draft PR (ready for review)", mutates: "publish", tracked: true,
+	},
+	{
+		name: "editPR", binding: func(k *keyMap) *key.Binding { return &k.EditPR }, keys: []string{"ctrl+e"}, help: "edit PR",
+		section: "Actions", desc: "Edit selected branch's PR title and description in $EDITOR (gh pr edit)", mutates: "edit-pr", tracked: true,
+	},
+	{
+		name: "requestReview", binding: func(k *keyMap) *key.Binding { return &k.RequestReview }, keys: []string{"V"}, help: "request review",
+		section: "Actions", desc: "Request review on selected branch's PR, completing recent reviewers (gh pr edit --add-reviewer)", mutates: "request-review", tracked: true,
+	},
+	{
+		name: "postComment", binding: func(k *keyMap) *key.Binding { return &k.PostComment }, keys: []string{"ctrl+o"}, help: "comment",
+		section: "Actions", desc: "Post a comment on selected branch's PR (gh pr comment)", mutates: "comment", tracked: true,
+	},
+	{
+		name: "editLabels", binding: func(k *keyMap) *key.Binding { return &k.EditLabels }, keys: []string{"l"}, help: "labels",
+		section: "Actions", desc: "Tick the labels on selected branch's PR (gh pr edit --add-label/--remove-label)", mutates: "edit-labels", tracked: true,
+	},
+	{
+		name: "mergeQueue", binding: func(k *keyMap) *key.Binding { return &k.MergeQueue }, keys: []string{"Q"}, help: "merge queue",
+		section: "Actions", desc: "Send the PRs up to the selected branch to the Graphite merge queue (gt merge)", mutates: "merge", tracked: true, confirm: confirmAlways,
+	},
+	{
+		name: "mergePR", binding: func(k *keyMap) *key.Binding { return &k.MergePR }, keys: []string{"G"}, help: "merge PR",
+		section: "Actions", desc: "Merge selected branch's PR on GitHub, picking squash, merge or rebase", mutates: "merge-pr", tracked: true, confirm: confirmAlways,
+	},
+	{
+		name: "resubmit", binding: func(k *keyMap) *key.Binding { return &k.Resubmit }, keys: []string{"U"}, help: "resubmit",
+		section: "Actions", desc: "Resubmit branch whose PR is behind local (⇡ unsubmitted)", mutates: "resubmit", tracked: true,
+	},
+	{
+		name: "cleanup", binding: func(k *keyMap) *key.Binding { return &k.Cleanup }, keys: []string{"X"}, help: "clean up merged",
+		section: "Actions", desc: "Clean up merged branch: sync, delete, restack children", mutates: "cleanup", tracked: true,
+	},
+	{
+		name: "cleanupAll", binding: func(k *keyMap) *key.Binding { return &k.CleanupAll }, keys: []string{"ctrl+x"}, help: "clean up all merged",
+		section: "Actions", desc: "Delete branches with merged or closed PRs (pick which), restack the rest",
+	},
+	{
+		name: "deleteRemote", binding: func(k *keyMap) *key.Binding { return &k.DeleteRemote }, keys: []string{"K"}, help: "delete here and on remote",
+		section: "Actions", desc: "Delete selected branch locally and on the remote, confirming twice", mutates: "delete-remote", tracked: true, confirm: confirmAlways,
+	},
+	{
+		name: "restack", binding: func(k *keyMap) *key.Binding { return &k.Restack }, keys: []string{"r"}, help: "restack",
+		section: "Actions", desc: "Restack stack", legend: "restack", mutates: "restack", tracked: true,
+	},
+	{
+		name: "branchRestack", binding: func(k *keyMap) *key.Binding { return &k.BranchRestack }, keys: []string{"ctrl+r"}, help: "restack branch",
+		section: "Actions", desc: "Restack only the selected branch onto its parent", mutates: "branch-restack", tracked: true,
+	},
+	{
+		name: "upstackRestack", binding: func(k *keyMap) *key.Binding { return &k.UpstackRestack }, keys: []string{"u"}, help: "restack upstack",
+		section: "Actions", desc: "Restack the selected branch and those above it, leaving those below alone", mutates: "upstack-restack", tracked: true,
+	},
+	{
+		name: "fetch", binding: func(k *keyMap) *key.Binding { return &k.Fetch }, keys: []string{"f"}, help: "fetch",
+		section: "Actions", desc: "Fetch (repo sync)", legend: "fetch", mutates: "fetch", repoWide: true,
+	},
+	{
+		name: "sync", binding: func(k *keyMap) *key.Binding { return &k.Sync }, keys: []string{"y"}, help: "sync",
+		section: "Actions", desc: "Sync, picking which merged and closed branches to delete first", legend: "sync", mutates: "sync", repoWide: true,
+	},
+	{
+		name: "syncPreview", binding: func(k *keyMap) *key.Binding { return &k.SyncPreview }, keys: []string{"g"}, help: "preview sync",
+		section: "Actions", desc: "Preview the tree after a sync next to the current one, then sync or cancel",
+	},
+	{
+		name: "openPR", binding: func(k *keyMap) *key.Binding { return &k.OpenPR }, keys: []string{"o"}, help: "open PR",
+		section: "Actions", desc: "Open PR in browser", legend: "open PR", mutates: "openpr", tracked: true, noHistory: true,
+	},
+	{
+		name: "openPRGraphite", binding: func(k *keyMap) *key.Binding { return &k.OpenPRGraphite }, keys: []string{"ctrl+w"}, help: "open in Graphite",
+		section: "Actions", desc: "Open PR in the Graphite web app (on GitHub when openPRIn is graphite)", tracked: true,
+	},
+	{
+		name: "test", binding: func(k *keyMap) *key.Binding { return &k.Test }, keys: []string{"t"}, help: "run tests",
+		section: "Actions", desc: "Run test command on selected branch", tracked: true,
+	},
+	{
+		name: "create", binding: func(k *keyMap) *key.Binding { return &k.Create }, keys: []string{"c"}, help: "create branch",
+		section: "Actions", desc: "Create branch stacked on selected branch", tracked: true,
+	},
+	{
+		name: "insert", binding: func(k *keyMap) *key.Binding { return &k.Insert }, keys: []string{"I"}, help: "insert branch",
+		section: "Actions", desc: "Insert a branch between the selected branch and the one stacked on it", tracked: true,
+	},
+	{
+		name: "rename", binding: func(k *keyMap) *key.Binding { return &k.Rename }, keys: []string{"R"}, help: "rename branch",
+		section: "Actions", desc: "Rename selected branch", tracked: true,
+	},
+	{
+		name: "fold", binding: func(k *keyMap) *key.Binding { return &k.Fold }, keys: []string{"F"}, help: "fold into parent",
+		section: "Actions", desc: "Fold selected branch into its parent (asks first)", mutates: "fold", tracked: true, confirm: confirmAlways,
+	},
+	{
+		name: "pop", binding: func(k *keyMap) *key.Binding { return &k.Pop }, keys: []string{"p"}, help: "pop branch",
+		section: "Actions", desc: "Pop selected branch, keeping its changes uncommitted (asks first)", mutates: "pop", tracked: true, confirm: confirmAlways,
+	},
+	{
+		name: "commit", binding: func(k *keyMap) *key.Binding { return &k.Commit }, keys: []string{"w"}, help: "commit",
+		section: "Actions", desc: "Commit staged changes to the current branch with a multi-line message",
+	},
+	{
+		name: "amend", binding: func(k *keyMap) *key.Binding { return &k.Amend }, keys: []string{"ctrl+a"}, help: "amend",
+		section: "Actions", desc: "Amend all working-tree changes into the current branch and restack above (asks first)", mutates: "amend", repoWide: true, confirm: confirmAlways,
+	},
+	{
+		name: "fixup", binding: func(k *keyMap) *key.Binding { return &k.Fixup }, keys: []string{"z"}, help: "fixup into branch",
+		section: "Actions", desc: "Fixup all working-tree changes into the selected downstack branch and autosquash (asks first)", mutates: "fixup", tracked: true, confirm: confirmAlways,
+	},
+	{
+		name: "absorb", binding: func(k *keyMap) *key.Binding { return &k.Absorb }, keys: []string{"a"}, help: "absorb staged",
+		section: "Actions", desc: "Absorb staged hunks into the downstack commits they belong to (asks first)", mutates: "absorb", confirm: confirmAlways,
+	},
+	{
+		name: "move", binding: func(k *keyMap) *key.Binding { return &k.Move }, keys: []string{"M"}, help: "move onto",
+		section: "Actions", desc: "Move selected branch (and those above it) onto a parent picked with the cursor", tracked: true,
+	},
+	{
+		name: "track", binding: func(k *keyMap) *key.Binding { return &k.Track }, keys: []string{"T"}, help: "track",
+		section: "Actions", desc: "Track an untracked (?) or orphaned branch on a parent picked with the cursor", when: trackable,
+	},
+	{
+		name: "untrack", binding: func(k *keyMap) *key.Binding { return &k.Untrack }, keys: []string{"T"}, help: "untrack",
+		section: "Actions", desc: "Untrack selected branch and those above it, keeping the git branches (asks first)", mutates: "untrack", tracked: true, confirm: confirmAlways,
+	},
+	{
+		name: "split", binding: func(k *keyMap) *key.Binding { return &k.Split }, keys: []string{"B"}, help: "split",
+		section: "Actions", desc: "Split selected branch by commit or by hunk (gt split)", tracked: true,
+	},
+	{
+		name: "repoInit", binding: func(k *keyMap) *key.Binding { return &k.RepoInit }, keys: []string{"i"}, help: "init repo",
+		section: "Actions", desc: "Initialize Graphite (no stacks yet)", mutates: "init", repoWide: true, when: noStacks,
+	},
+
+	// Detached HEAD / Rebase
+	{
+		name: "checkoutNearest", binding: func(k *keyMap) *key.Binding { return &k.CheckoutNearest }, keys: []string{"n"}, help: "checkout nearest branch",
+		section: "Detached HEAD / Rebase", desc: "Check out nearest branch", mutates: "checkout-nearest", repoWide: true, when: detached, noHistory: true,
+	},
+	{
+		name: "continue", binding: func(k *keyMap) *key.Binding { return &k.Continue }, keys: []string{"C"}, help: "continue rebase",
+		section: "Detached HEAD / Rebase", desc: "Continue rebase (gt continue)", mutates: "continue", repoWide: true, when: rebasing,
+	},
+	{
+		name: "abort", binding: func(k *keyMap) *key.Binding { return &k.Abort }, keys: []string{"A"}, help: "abort rebase",
+		section: "Detached HEAD / Rebase", desc: "Abort rebase (gt abort), after confirming", mutates: "abort", repoWide: true, when: rebasing, confirm: confirmAlways,
+	},
+
+	// Views
+	{
+		name: "diff", binding: func(k *keyMap) *key.Binding { return &k.Diff }, keys: []string{"d"}, help: "diff",
+		section: "Views", desc: "Open diff view for selected branch (or marked branches)", legend: "diff", tracked: true,
+	},
+	{
+		name: "mark", binding: func(k *keyMap) *key.Binding { return &k.Mark }, keys: []string{" "}, help: "mark for diff",
+		section: "Views", desc: "Mark/unmark branch for a combined diff (esc clears)", tracked: true,
+	},
+	{
+		name: "toggleDetail", binding: func(k *keyMap) *key.Binding { return &k.ToggleDetail }, keys: []string{"v"}, help: "details",
+		section: "Views", desc: "Toggle detail panel (wide terminals)",
+	},
+	{
+		name: "pin", binding: func(k *keyMap) *key.Binding { return &k.Pin }, keys: []string{"P"}, help: "pin",
+		section: "Views", desc: "Pin/unpin branch at top of tree", tracked: true,
+	},
+	{
+		name: "hideMerged", binding: func(k *keyMap) *key.Binding { return &k.HideMerged }, keys: []string{"H"}, help: "hide merged",
+		section: "Views", desc: "Hide/show branches with merged PRs",
+	},
+	{
+		name: "prFilter", binding: func(k *keyMap) *key.Binding { return &k.PRFilter }, keys: []string{"W"}, help: "filter PR state",
+		section: "Views", desc: "Cycle PR-state filter: open, draft, no PR, all",
+	},
+	{
+		name: "filter", binding: func(k *keyMap) *key.Binding { return &k.Filter }, keys: []string{"ctrl+f"}, help: "filter by name",
+		section: "Views", desc: "Filter branches by name (empty clears)",
+	},
+	{
+		name: "clearFilters", binding: func(k *keyMap) *key.Binding { return &k.ClearFilters }, keys: []string{"ctrl+g"}, help: "clear filters",
+		section: "Views", desc: "Clear all filters (filters are remembered per repo)",
+	},
+	{
+		name: "jobs", binding: func(k *keyMap) *key.Binding { return &k.Jobs }, keys: []string{"J"}, help: "jobs",
+		section: "Views", desc: "Jobs view",
+	},
+	{
+		name: "cancelJob", binding: func(k *keyMap) *key.Binding { return &k.CancelJob }, keys: []string{"x"}, help: "cancel job",
+		section: "Views", desc: "Cancel the selected job (jobs view)", noPalette: true,
+	},
+	{
+		name: "debug", binding: func(k *keyMap) *key.Binding { return &k.Debug }, keys: []string{"D"}, help: "debug",
+		section: "Views", desc: "Debug view (remote calls, GitHub quota)",
+	},
+	{
+		name: "errorDetails", binding: func(k *keyMap) *key.Binding { return &k.ErrorDetails }, keys: []string{"E"}, help: "last error",
+		section: "Views", desc: "Show the last error in full",
+	},
+	{
+		name: "overlaps", binding: func(k *keyMap) *key.Binding { return &k.Overlaps }, keys: []string{"O"}, help: "overlaps",
+		section: "Views", desc: "Overlaps view (branches changing the same files)",
+	},
+	{
+		name: "comments", binding: func(k *keyMap) *key.Binding { return &k.Comments }, keys: []string{"ctrl+t"}, help: "comments",
+		section: "Views", desc: "Comments view: selected branch's PR conversation and review threads",
+	},
+	{
+		name: "stats", binding: func(k *keyMap) *key.Binding { return &k.Stats }, keys: []string{"%"}, help: "stats",
+		section: "Views", desc: "Stats view: each stack's time in review, submits and restacks",
+	},
+	{
+		name: "lastView", binding: func(k *keyMap) *key.Binding { return &k.LastView }, keys: []string{"`"}, help: "last view",
+		section: "Views", desc: "Switch back to the previous view (e.g. tree ⇄ diff), as it was left",
+	},
+	{
+		name: "yank", binding: func(k *keyMap) *key.Binding { return &k.Yank }, keys: []string{"Y"}, help: "copy",
+		section: "Views", desc: "Copy branch name, file path, diff or job under the cursor",
+	},
+	{
+		name: "yankRef", binding: func(k *keyMap) *key.Binding { return &k.YankRef }, keys: []string{"ctrl+y"}, help: "copy remote ref",
+		section: "Views", desc: "Copy the selected branch's remote ref (origin/<branch>)",
+	},
+	{
+		name: "yankPR", binding: func(k *keyMap) *key.Binding { return &k.YankPR }, keys: []string{"ctrl+u"}, help: "copy PR URL",
+		section: "Views", desc: "Copy the selected branch's PR URL",
+	},
+	{
+		name: "share", binding: func(k *keyMap) *key.Binding { return &k.Share }, keys: []string{"L"}, help: "share stack",
+		section: "Views", desc: "Copy the selected stack's PR links in review order, with a Graphite stack link", tracked: true,
+	},
+	{
+		name: "palette", binding: func(k *keyMap) *key.Binding { return &k.Palette }, keys: []string{":"}, help: "command palette",
+		section: "Views", desc: "Command palette: run any action by name", noPalette: true,
+	},
+	{
+		name: "history", binding: func(k *keyMap) *key.Binding { return &k.History }, keys: []string{"h"}, help: "command history",
+		section: "Views", desc: "Command history: run a recent command again on its branch", noPalette: true,
+	},
+	{
+		name: "help", binding: func(k *keyMap) *key.Binding { return &k.Help }, keys: []string{"?"}, help: "help",
+		section: "Views", desc: "Toggle this help screen", legend: "help",
+	},
+	{
+		name: "quit", binding: func(k *keyMap) *key.Binding { return &k.Quit }, keys: []string{"q", "ctrl+c"}, helpKey: "q", help: "quit",
+		section: "Views", desc: "Quit", legend: "quit",
+	},
+
+	// Diff View
+	{
+		name: "tab", binding: func(k *keyMap) *key.Binding { return &k.Tab }, keys: []string{"tab"}, help: "switch panel",
+		section: "Diff View", desc: "Switch panel focus", noPalette: true,
+	},
+	{
+		name: "openFile", binding: func(k *keyMap) *key.Binding { return &k.OpenFile }, keys: []string{"o"}, help: "open on GitHub",
+		section: "Diff View", desc: "Open selected file at the branch head on GitHub", noPalette: true,
+	},
+	{
+		name: "diffClose", binding: func(k *keyMap) *key.Binding { return &k.DiffClose }, keys: []string{"d", "esc"}, helpKey: "esc/d", help: "close",
+		section: "Diff View", desc: "Close diff view", noPalette: true,
+	},
+
+	// Used only inside other views
+	{
+		name: "edit", binding: func(k *keyMap) *key.Binding { return &k.Edit }, keys: []string{"e", "enter"}, helpKey: "e", help: "edit file",
+		noPalette: true,
+	},
+	{
+		name: "confirm", binding: func(k *keyMap) *key.Binding { return &k.Confirm }, keys: []string{"enter"}, help: "confirm",
+		noPalette: true,
+	},
+}
+
+// defaultHelpKey returns the key shown for s in help and hints.
+func (s actionSpec) defaultHelpKey() string {
+	if s.helpKey != "" {
+		return s.helpKey
+	}
+	return keyHelp(s.keys)
+}
+
+// applies reports whether s makes sense in m's current state, for the
+// command palette.
+func (s actionSpec) applies(m Model) bool {
+	if s.tracked && (m.selectedBranch() == nil || m.selectedUntracked() != "") {
+		return false
+	}
+	return s.when == nil || s.when(m)
+}
+
+// specFor returns the spec of the action msg triggers, or nil. Of the
+// actions bound to the key, the first that applies and runs in the tree
+// wins, like untrack over track on a tracked branch; failing that, the
+// first bound to it, so keys shared with another view's action (enter,
+// esc) are listed last.
+func (m Model) specFor(msg tea.KeyMsg) *actionSpec {
+	var first *actionSpec
+	for i := range actionSpecs {
+		s := &actionSpecs[i]
+		if !key.Matches(msg, *s.binding(&m.keys)) {
+			continue
+		}
+		if s.execute != nil && s.applies(m) {
+			return s
+		}
+		if first == nil {
+			first = s
+		}
+	}
+	return first
+}
+
+// runSpec runs s as its key does in the tree, unless the repeat guard
+// drops it, and records it in the guard and the command history once it
+// starts.
+func (m *Model) runSpec(s *actionSpec) []tea.Cmd {
+	action, target := m.specTarget(s)
+	if action != "" && m.actionGuard.repeated(action, target, time.Now()) {
+		return nil
+	}
+	cmds := s.execute(m)
+	if action != "" && (m.running || m.mode != modeTree) {
+		m.actionGuard.started(action, target, time.Now())
+		m.startedCommand(s, target)
+	}
+	return cmds
+}
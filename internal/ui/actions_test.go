@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestActionSpecs_CoverEveryBinding(t *testing.T) {
+	k := defaultKeyMap()
+	v := reflect.ValueOf(k)
+	for i := range v.NumField() {
+		b := v.Field(i).Interface().(key.Binding)
+		if len(b.Keys()) == 0 {
+			t.Errorf("keyMap.%s has no spec in actionSpecs", v.Type().Field(i).Name)
+		}
+	}
+	names := make(map[string]bool)
+	for _, s := range actionSpecs {
+		if names[s.name] {
+			t.Errorf("duplicate action name %q", s.name)
+		}
+		names[s.name] = true
+		if s.section != "" && s.desc == "" {
+			t.Errorf("%s is on the help screen without a description", s.name)
+		}
+	}
+}
+
+// keyMsgFor returns the key press bubbletea reports for k, a key as
+// written in a binding, e.g. "s", "ctrl+d", "enter" or " ".
+func keyMsgFor(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEscape}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	if c, ok := strings.CutPrefix(k, "ctrl+"); ok && len(c) == 1 && c[0] >= 'a' && c[0] <= 'z' {
+		return tea.KeyMsg{Type: tea.KeyCtrlA + tea.KeyType(c[0]-'a')}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+func TestKeyMsgFor_MatchesDefaultKeys(t *testing.T) {
+	k := defaultKeyMap()
+	for _, s := range actionSpecs {
+		for _, kk := range s.keys {
+			if !key.Matches(keyMsgFor(kk), *s.binding(&k)) {
+				t.Errorf("keyMsgFor(%q) = %q, doesn't trigger %s", kk, keyMsgFor(kk).String(), s.name)
+			}
+		}
+	}
+}
+
+func TestSpecTarget_FromSpecs(t *testing.T) {
+	m := loadedModel("│ ◉  feature\n◯─┘  main")
+	if action, branch := m.specTarget(m.specFor(keyMsgFor("s"))); action != "submit" || branch != "feature" {
+		t.Errorf("s = %q on %q, want submit on the selected branch", action, branch)
+	}
+	if action, branch := m.specTarget(m.specFor(keyMsgFor("f"))); action != "fetch" || branch != "" {
+		t.Errorf("f = %q on %q, want a repo-wide fetch", action, branch)
+	}
+	if action, _ := m.specTarget(m.specFor(keyMsgFor("?"))); action != "" {
+		t.Errorf("? = %q, want no mutation", action)
+	}
+}
+
+func TestActionSpecs_TreeActionsExecute(t *testing.T) {
+	for _, s := range actionSpecs {
+		if s.section != "" && s.section != "Diff View" && s.name != "cancelJob" && s.execute == nil {
+			t.Errorf("%s is on the help screen but has no handler in actionHandlers", s.name)
+		}
+	}
+	for name := range actionHandlers {
+		if !slices.ContainsFunc(actionSpecs, func(s actionSpec) bool { return s.name == name }) {
+			t.Errorf("actionHandlers has %q, which no spec is named", name)
+		}
+	}
+}
+
+func TestSpecFor_PrefersTheActionThatApplies(t *testing.T) {
+	m := loadedModel("│ ◉  feature\n◯─┘  main")
+	if s := m.specFor(keyMsgFor("T")); s == nil || s.name != "untrack" {
+		t.Errorf("T on a tracked branch = %v, want untrack", s)
+	}
+	if s := m.specFor(keyMsgFor("d")); s == nil || s.name != "diff" {
+		t.Errorf("d in the tree = %v, want diff", s)
+	}
+}
+
+func TestLegend_FromSpecs(t *testing.T) {
+	m := loadedModel("◉  main")
+	legend := m.legendView()
+	for _, want := range []string{"navigate", "checkout", "submit", "open PR", "quit"} {
+		if !strings.Contains(legend, want) {
+			t.Errorf("legend missing %q: %s", want, legend)
+		}
+	}
+}
+
+func TestPalette_ListsApplicableActionsAndRunsPicked(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 30)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature\n◯─┘  main"})
+	m = updated.(Model)
+
+	m = sendKey(m, ':')
+	if m.mode != modePicker {
+		t.Fatalf("mode = %d, want the palette open", m.mode)
+	}
+	for _, item := range m.picker.items {
+		if item.name == "Continue rebase (gt continue)" {
+			t.Error("rebase actions should not be offered outside a rebase")
+		}
+	}
+	m = typeText(m, "submit stack")
+	if got := m.picker.selected(); got == nil || got.item.name != "Submit stack" {
+		t.Fatalf("selected = %+v, want Submit stack", got)
+	}
+	if !strings.Contains(m.View(), "key s") {
+		t.Error("preview should show the action's key")
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	runBatch(cmd)
+	if len(*calls) == 0 || strings.Join((*calls)[0].args[:2], " ") != "stack submit" {
+		t.Errorf("calls = %v, want gt stack submit", *calls)
+	}
+}
+
+func TestPalette_RunsActionOnKeyItCannotSynthesize(t *testing.T) {
+	mock, calls := recordingMock()
+	m := NewWithConfig(gt.New(mock), "", config.Config{Keys: map[string][]string{"fetch": {"alt+x"}}})
+	m = sendWindowSize(m, 100, 30)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature\n◯─┘  main"})
+	m = updated.(Model)
+
+	m = sendKey(m, ':')
+	m = typeText(m, "fetch (repo sync)")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	runBatch(cmd)
+	if len(*calls) == 0 || strings.Join((*calls)[0].args[:2], " ") != "repo sync" {
+		t.Errorf("calls = %v, want gt repo sync", *calls)
+	}
+}
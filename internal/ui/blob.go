@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// blobResultMsg reports opening a diff file on GitHub.
+type blobResultMsg struct {
+	file string
+	err  error
+}
+
+// selectedFileRef returns the file at the diff view's cursor and the branch
+// whose version of it to show: the diffed branch, or for a combined diff
+// the topmost marked branch that touches the file.
+func (d diffView) selectedFileRef() (file, branch string, ok bool) {
+	if d.fileCursor >= len(d.files) {
+		return "", "", false
+	}
+	f := d.files[d.fileCursor]
+	if len(f.branches) > 0 {
+		return f.path, f.branches[len(f.branches)-1], true
+	}
+	return f.path, d.branchName, d.branchName != ""
+}
+
+// openDiffFile opens the selected diff file at its branch's head on GitHub.
+// The remote lookup runs in the command.
+func (m Model) openDiffFile() tea.Cmd {
+	file, branch, ok := m.diff.selectedFileRef()
+	if !ok {
+		return nil
+	}
+	client := m.gtClient
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		repo, ok := client.GitHubRepo(ctx)
+		if !ok {
+			return blobResultMsg{file: file, err: errors.New("origin is not a GitHub remote")}
+		}
+		return blobResultMsg{file: file, err: client.OpenURL(ctx, gt.BlobURL(repo, branch, file))}
+	}
+}
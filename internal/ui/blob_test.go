@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestSelectedFileRef(t *testing.T) {
+	d := diffView{branchName: "feature", files: []diffFileEntry{{path: "a.go"}, {path: "b.go", branches: []string{"low", "high"}}}}
+	if file, branch, ok := d.selectedFileRef(); !ok || file != "a.go" || branch != "feature" {
+		t.Errorf("single diff: %q %q %v", file, branch, ok)
+	}
+	d.fileCursor = 1
+	if file, branch, ok := d.selectedFileRef(); !ok || file != "b.go" || branch != "high" {
+		t.Errorf("combined diff should use the topmost branch: %q %q %v", file, branch, ok)
+	}
+	d.fileCursor = 2
+	if _, _, ok := d.selectedFileRef(); ok {
+		t.Error("no file selected should not be ok")
+	}
+}
+
+// blobModel returns a model in the diff view of feature-top with two files.
+func blobModel(mock *mockExecutor) Model {
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	m.mode = modeDiff
+	m.diff = newDiffView(80, 20)
+	m.diff.branchName = "feature-top"
+	m.diff.setFiles([]diffFileEntry{{path: "a.go"}, {path: "pkg/b.go"}})
+	m.diff.fileCursor = 1
+	return m
+}
+
+func TestOpenFileKey_OpensBlobURL(t *testing.T) {
+	var opened []string
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if name == "git" {
+			return "git@github.com:acme/app.git\n", nil
+		}
+		opened = args
+		return "", nil
+	}}
+	m := blobModel(mock)
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'o'}}))
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	want := "https://github.com/acme/app/blob/feature-top/pkg/b.go"
+	if len(opened) == 0 || opened[len(opened)-1] != want {
+		t.Errorf("opened %v, want %s", opened, want)
+	}
+	if m.statusBar.message != "Opened pkg/b.go on GitHub" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+func TestOpenFileKey_NotGitHub(t *testing.T) {
+	m := blobModel(&mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		return "", errors.New("no such remote")
+	}})
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'o'}}))
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.statusBar.message != "Could not open pkg/b.go on GitHub: origin is not a GitHub remote" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
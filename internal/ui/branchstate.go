@@ -0,0 +1,60 @@
+package ui
+
+import "github.com/elliotb/grit/internal/gt"
+
+// branchStates records when actions last changed each branch, so a PR info
+// refresh that was already in flight can't overwrite what an action just
+// did, e.g. a submit's new PR with the fetch from before it. Refreshes note
+// the sequence number when they start, and results for branches changed
+// since are dropped.
+type branchStates struct {
+	seq       int            // bumped by every change
+	changedAt map[string]int // seq of each branch's last change
+	allAt     int            // seq of the last change to every branch
+}
+
+// mark returns the sequence number a refresh starting now passes to stale.
+func (s *branchStates) mark() int {
+	return s.seq
+}
+
+// changed records that an action changed names, or every branch when
+// names is empty.
+func (s *branchStates) changed(names []string) {
+	s.seq++
+	if len(names) == 0 {
+		s.allAt = s.seq
+		return
+	}
+	if s.changedAt == nil {
+		s.changedAt = make(map[string]int)
+	}
+	for _, name := range names {
+		s.changedAt[name] = s.seq
+	}
+}
+
+// stale reports whether name was changed after the refresh that started at
+// mark.
+func (s *branchStates) stale(name string, mark int) bool {
+	return s.allAt > mark || s.changedAt[name] > mark
+}
+
+// dropStalePRInfo replaces in infos the PR of each branch changed since
+// mark with the one in prev, or removes it if prev has none, and returns
+// the branches it replaced.
+func dropStalePRInfo(states *branchStates, mark int, prev, infos map[string]gt.PRInfo) []string {
+	var dropped []string
+	for name := range infos {
+		if !states.stale(name, mark) {
+			continue
+		}
+		if info, ok := prev[name]; ok {
+			infos[name] = info
+		} else {
+			delete(infos, name)
+		}
+		dropped = append(dropped, name)
+	}
+	return dropped
+}
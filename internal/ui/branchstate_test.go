@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestBranchStates_Stale(t *testing.T) {
+	var s branchStates
+	before := s.mark()
+	s.changed([]string{"a"})
+	if !s.stale("a", before) || s.stale("b", before) {
+		t.Errorf("only a should be stale for a refresh started before the change")
+	}
+	if s.stale("a", s.mark()) {
+		t.Errorf("a refresh started after the change should not be stale")
+	}
+	s.changed(nil)
+	if !s.stale("b", before) {
+		t.Errorf("a change to every branch should make b stale")
+	}
+}
+
+func TestDropStalePRInfo(t *testing.T) {
+	var s branchStates
+	mark := s.mark()
+	s.changed([]string{"a", "b"})
+	prev := map[string]gt.PRInfo{"a": {Number: 5, State: "OPEN"}}
+	infos := map[string]gt.PRInfo{"a": {}, "b": {Number: 6}, "c": {Number: 7}}
+	dropped := dropStalePRInfo(&s, mark, prev, infos)
+	if len(dropped) != 2 {
+		t.Errorf("dropped = %v, want a and b", dropped)
+	}
+	if infos["a"].Number != 5 || infos["c"].Number != 7 {
+		t.Errorf("infos = %+v, want a's previous PR and c's fetched one", infos)
+	}
+	if _, ok := infos["b"]; ok {
+		t.Errorf("b had no previous PR and should be left for the next refresh")
+	}
+}
+
+func TestPRInfoResult_StaleRefreshDoesNotOverwriteSubmit(t *testing.T) {
+	m := loadedModel("│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main")
+	updated, _ := m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{
+		"feature-top":  {},
+		"feature-base": {Number: 1, State: "OPEN"},
+	}})
+	m = updated.(Model)
+
+	// A background refresh starts, then a submit of feature-top finishes
+	// and the refresh after it sees the new PR.
+	inFlight := m.branchStates.mark()
+	m.actionTargets = []string{"feature-top"}
+	updated, _ = m.Update(actionResultMsg{action: "submit", message: "Submitted"})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{
+		"feature-top":  {Number: 2, State: "OPEN"},
+		"feature-base": {Number: 1, State: "OPEN"},
+	}, mark: m.branchStates.mark()})
+	m = updated.(Model)
+
+	// The older refresh lands last.
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{
+		"feature-top":  {},
+		"feature-base": {Number: 1, State: "MERGED"},
+	}, mark: inFlight})
+	m = updated.(Model)
+	if got := m.prInfos["feature-top"]; got.Number != 2 {
+		t.Errorf("feature-top PR = %+v, want the post-submit #2", got)
+	}
+	if got := m.prInfos["feature-base"]; got.State != "MERGED" {
+		t.Errorf("feature-base PR = %+v, want the refresh applied to unchanged branches", got)
+	}
+}
+
+func TestPRInfoResult_StaleRefreshKeepsRefetchDue(t *testing.T) {
+	m := loadedModel("│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main")
+	inFlight := m.branchStates.mark()
+	m.actionTargets = []string{"feature-top"}
+	updated, _ := m.Update(actionResultMsg{action: "submit", message: "Submitted"})
+	m = updated.(Model)
+
+	// The refresh from before the submit lands before the reload after it.
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{
+		"feature-top":  {},
+		"feature-base": {Number: 1, State: "OPEN"},
+	}, mark: inFlight})
+	m = updated.(Model)
+	if !m.prInfoStale() {
+		t.Errorf("PR info should stay stale so the reload after the submit refetches it")
+	}
+	if _, ok := m.prInfos["feature-top"]; ok {
+		t.Errorf("feature-top PR = %+v, want none until the refetch", m.prInfos["feature-top"])
+	}
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// testTimeout bounds a single branch test run.
+const testTimeout = 10 * time.Minute
+
+// testCacheFile is where test results are persisted, relative to the git dir.
+const testCacheFile = "grit/tests.json"
+
+// testResultMsg is sent when a branch test run completes. err is set when
+// the run could not be started (e.g. the worktree could not be created);
+// a failing test command is reported via passed=false.
+type testResultMsg struct {
+	branch string
+	sha    string
+	passed bool
+	output string
+	err    error
+}
+
+// testRecord is the cached outcome of the test command on one commit.
+type testRecord struct {
+	Passed bool      `json:"passed"`
+	At     time.Time `json:"at"`
+}
+
+// testCache maps commit SHAs to test results. Keying on the SHA means a
+// result stays valid for exactly as long as the branch head is unchanged.
+type testCache map[string]testRecord
+
+// loadTestCache reads persisted results from gitDir. A missing or
+// unreadable cache starts empty.
+func loadTestCache(gitDir string) testCache {
+	cache := make(testCache)
+	if gitDir == "" {
+		return cache
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, testCacheFile))
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(testCache)
+	}
+	return cache
+}
+
+// save writes the cache to gitDir, creating the grit directory if needed.
+func (c testCache) save(gitDir string) error {
+	if gitDir == "" {
+		return nil
+	}
+	path := filepath.Join(gitDir, testCacheFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// status returns the test status of a head commit, given the set of
+// commits currently being tested.
+func (c testCache) status(sha string, running map[string]bool) gt.TestStatus {
+	if sha == "" {
+		return gt.TestUnknown
+	}
+	if running[sha] {
+		return gt.TestRunning
+	}
+	rec, ok := c[sha]
+	switch {
+	case !ok:
+		return gt.TestUnknown
+	case rec.Passed:
+		return gt.TestPassed
+	default:
+		return gt.TestFailed
+	}
+}
+
+// applyTestStatus walks the branch tree and sets each branch's head SHA and
+// test status.
+func applyTestStatus(branches []*gt.Branch, heads map[string]string, cache testCache, running map[string]bool) {
+	var walk func(b *gt.Branch)
+	walk = func(b *gt.Branch) {
+		if sha, ok := heads[b.Name]; ok {
+			b.Head = sha
+		}
+		b.Tests = cache.status(b.Head, running)
+		for _, child := range b.Children {
+			walk(child)
+		}
+	}
+	for _, root := range branches {
+		walk(root)
+	}
+}
+
+// branchTestJob checks sha out into a temporary worktree, runs command
+// there and removes the worktree again. The user's own checkout is never
+// touched, so tests can run while they keep working. A cancelled run is
+// reported as a setup error so it isn't cached as a failure.
+func branchTestJob(client *gt.Client, branch, sha, command string) jobFunc {
+	return func(jobCtx context.Context) (tea.Msg, error) {
+		ctx, cancel := context.WithTimeout(jobCtx, testTimeout)
+		defer cancel()
+
+		dir, err := os.MkdirTemp("", "grit-test-")
+		if err != nil {
+			return testResultMsg{branch: branch, sha: sha, err: err}, err
+		}
+		defer os.RemoveAll(dir)
+
+		if err := client.WorktreeAdd(ctx, dir, sha); err != nil {
+			return testResultMsg{branch: branch, sha: sha, err: err}, err
+		}
+		defer client.WorktreeRemove(context.Background(), dir)
+
+		out, err := client.RunShellIn(ctx, dir, command)
+		if jobCtx.Err() != nil {
+			return testResultMsg{branch: branch, sha: sha, err: jobCtx.Err()}, jobCtx.Err()
+		}
+		if err != nil {
+			return testResultMsg{branch: branch, sha: sha, output: err.Error()}, errors.New("tests failed")
+		}
+		return testResultMsg{branch: branch, sha: sha, passed: true, output: out}, nil
+	}
+}
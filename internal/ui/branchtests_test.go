@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestTestCache_SaveLoadRoundTrip(t *testing.T) {
+	gitDir := t.TempDir()
+	cache := testCache{"abc123": {Passed: true, At: time.Unix(100, 0).UTC()}}
+	if err := cache.save(gitDir); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	got := loadTestCache(gitDir)
+	if rec, ok := got["abc123"]; !ok || !rec.Passed || !rec.At.Equal(time.Unix(100, 0)) {
+		t.Errorf("loaded cache = %v", got)
+	}
+}
+
+func TestLoadTestCache_MissingOrCorrupt(t *testing.T) {
+	gitDir := t.TempDir()
+	if got := loadTestCache(gitDir); len(got) != 0 {
+		t.Errorf("missing cache = %v, want empty", got)
+	}
+
+	path := filepath.Join(gitDir, testCacheFile)
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	os.WriteFile(path, []byte("{not json"), 0o644)
+	if got := loadTestCache(gitDir); got == nil || len(got) != 0 {
+		t.Errorf("corrupt cache = %v, want empty", got)
+	}
+}
+
+func TestTestCache_Status(t *testing.T) {
+	cache := testCache{"pass": {Passed: true}, "fail": {Passed: false}}
+	running := map[string]bool{"pass": true, "run": true}
+
+	tests := []struct {
+		sha  string
+		want gt.TestStatus
+	}{
+		{"", gt.TestUnknown},
+		{"other", gt.TestUnknown},
+		{"fail", gt.TestFailed},
+		{"run", gt.TestRunning},
+		{"pass", gt.TestRunning}, // a re-run supersedes the cached result
+	}
+	for _, tt := range tests {
+		if got := cache.status(tt.sha, running); got != tt.want {
+			t.Errorf("status(%q) = %v, want %v", tt.sha, got, tt.want)
+		}
+	}
+	if got := cache.status("pass", nil); got != gt.TestPassed {
+		t.Errorf("status(pass) = %v, want TestPassed", got)
+	}
+}
+
+func TestApplyTestStatus_NewHeadInvalidatesResult(t *testing.T) {
+	child := &gt.Branch{Name: "feature-a"}
+	branches := []*gt.Branch{{Name: "main", Children: []*gt.Branch{child}}}
+	cache := testCache{"old": {Passed: true}}
+
+	applyTestStatus(branches, map[string]string{"feature-a": "old"}, cache, nil)
+	if child.Head != "old" || child.Tests != gt.TestPassed {
+		t.Fatalf("after first apply: head=%q tests=%v", child.Head, child.Tests)
+	}
+
+	applyTestStatus(branches, map[string]string{"feature-a": "new"}, cache, nil)
+	if child.Tests != gt.TestUnknown {
+		t.Errorf("tests = %v after head moved, want TestUnknown", child.Tests)
+	}
+}
+
+func TestTestLabelPlain(t *testing.T) {
+	tests := []struct {
+		status gt.TestStatus
+		want   string
+	}{
+		{gt.TestUnknown, ""},
+		{gt.TestRunning, " … testing"},
+		{gt.TestPassed, " ✓ tests"},
+		{gt.TestFailed, " ✗ tests"},
+	}
+	for _, tt := range tests {
+		if got := testLabelPlain(tt.status); got != tt.want {
+			t.Errorf("testLabelPlain(%v) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestBranchTestJob_UsesWorktree(t *testing.T) {
+	mock, calls := recordingMock()
+	msg := runJob(branchTestJob(gt.New(mock), "feature-a", "abc123", "make test"))
+
+	res := msg.(testResultMsg)
+	if !res.passed || res.err != nil {
+		t.Fatalf("result = %+v, want passed", res)
+	}
+	if len(*calls) != 3 {
+		t.Fatalf("expected 3 calls, got %d: %v", len(*calls), *calls)
+	}
+	add, run, remove := (*calls)[0], (*calls)[1], (*calls)[2]
+	if add.name != "git" || add.args[0] != "worktree" || add.args[1] != "add" || add.args[4] != "abc123" {
+		t.Errorf("first call = %v, want git worktree add", add)
+	}
+	dir := add.args[3]
+	if run.name != "sh" || run.args[3] != dir || run.args[4] != "make test" {
+		t.Errorf("second call = %v, want test command in %s", run, dir)
+	}
+	if remove.name != "git" || remove.args[1] != "remove" || remove.args[3] != dir {
+		t.Errorf("third call = %v, want git worktree remove %s", remove, dir)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("worktree dir %s should be cleaned up", dir)
+	}
+}
+
+func TestBranchTestJob_Failure(t *testing.T) {
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if name == "sh" {
+			return "", errors.New("FAIL\nexit status 1")
+		}
+		return "", nil
+	}}
+	res := runJob(branchTestJob(gt.New(mock), "feature-a", "abc123", "make test")).(testResultMsg)
+	if res.passed || res.err != nil {
+		t.Errorf("result = %+v, want failed without setup error", res)
+	}
+}
+
+func TestBranchTestJob_WorktreeError(t *testing.T) {
+	var ran bool
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if name == "sh" {
+			ran = true
+		}
+		if len(args) > 1 && args[1] == "add" {
+			return "", errors.New("invalid reference")
+		}
+		return "", nil
+	}}
+	res := runJob(branchTestJob(gt.New(mock), "feature-a", "abc123", "make test")).(testResultMsg)
+	if res.err == nil {
+		t.Error("expected setup error")
+	}
+	if ran {
+		t.Error("test command should not run without a worktree")
+	}
+}
+
+func testModel(command string) Model {
+	m := NewWithConfig(gt.New(simpleMock("", nil)), "", config.Config{TestCommand: command})
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{
+		output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main",
+		heads:  map[string]string{"main": "m1", "feature-base": "b1", "feature-top": "t1"},
+	})
+	return updated.(Model)
+}
+
+func TestTestKey_NoCommandConfigured(t *testing.T) {
+	m := testModel("")
+	m = sendKey(m, 't')
+	if !containsString(m.statusBar.message, "No test command configured") {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+	if len(m.testsRunning) != 0 {
+		t.Error("no test run should start")
+	}
+}
+
+func TestTestKey_RunsAndShowsBadge(t *testing.T) {
+	m := testModel("make test")
+	// Cursor starts on the current branch, feature-top.
+	m = sendKey(m, 't')
+	if last := m.jobs.jobs[len(m.jobs.jobs)-1]; last.label != "Tests on feature-top" {
+		t.Fatalf("last job = %q, want a test run job", last.label)
+	}
+	if m.running {
+		t.Error("tests run in the background and should not block input")
+	}
+	if !m.testsRunning["t1"] {
+		t.Fatalf("testsRunning = %v, want t1", m.testsRunning)
+	}
+	if !containsString(m.View(), "testing") {
+		t.Error("view should show a running badge")
+	}
+
+	updated, _ := m.Update(testResultMsg{branch: "feature-top", sha: "t1", passed: true})
+	m = updated.(Model)
+	if len(m.testsRunning) != 0 {
+		t.Errorf("testsRunning = %v, want empty", m.testsRunning)
+	}
+	if !containsString(m.View(), "✓ tests") {
+		t.Error("view should show a passed badge")
+	}
+	if m.statusBar.message != "Tests passed on feature-top" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+func TestTestResult_FailureShowsLastLine(t *testing.T) {
+	m := testModel("make test")
+	updated, _ := m.Update(testResultMsg{branch: "feature-top", sha: "t1", output: "--- FAIL: TestX\nexit status 1"})
+	m = updated.(Model)
+	if m.statusBar.message != "Tests failed on feature-top: exit status 1" || m.statusBar.severity != severityError {
+		t.Errorf("message = %q (severity=%d)", m.statusBar.message, m.statusBar.severity)
+	}
+	if !containsString(m.View(), "✗ tests") {
+		t.Error("view should show a failed badge")
+	}
+}
+
+func TestTestResult_SetupErrorNotCached(t *testing.T) {
+	m := testModel("make test")
+	updated, _ := m.Update(testResultMsg{branch: "feature-top", sha: "t1", err: errors.New("boom")})
+	m = updated.(Model)
+	if _, ok := m.tests["t1"]; ok {
+		t.Error("setup errors should not be cached as results")
+	}
+	if !containsString(m.statusBar.message, "Could not run tests on feature-top") {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
@@ -0,0 +1,396 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// cleanupPlan is the cleanup offered once a branch's PR has merged: sync
+// trunk, delete the local branch and restack its children onto its parent.
+type cleanupPlan struct {
+	branch   string
+	pr       int
+	parent   string
+	checkout bool     // the branch is checked out; move to parent before deleting
+	children []string // direct children, restacked after the delete
+}
+
+// planCleanup builds the cleanup plan for b, whose PR has merged.
+func planCleanup(branches []*gt.Branch, b *gt.Branch) cleanupPlan {
+	parent, _ := gt.FindParent(branches, b.Name)
+	p := cleanupPlan{branch: b.Name, pr: b.PR.Number, parent: parent, checkout: b.IsCurrent}
+	for _, child := range b.Children {
+		p.children = append(p.children, child.Name)
+	}
+	return p
+}
+
+// steps describes what running the plan will do, in order.
+func (p cleanupPlan) steps() []string {
+	steps := []string{"Sync trunk (gt repo sync)"}
+	if p.checkout {
+		steps = append(steps, "Check out "+p.parent+" ("+p.branch+" is checked out)")
+	}
+	steps = append(steps, "Delete local branch "+p.branch+" (gt delete)")
+	for _, child := range p.children {
+		steps = append(steps, "Restack "+child+" onto "+p.parent)
+	}
+	return steps
+}
+
+// renderCleanup renders the cleanup summary shown before confirming.
+func renderCleanup(p cleanupPlan) string {
+	var sb strings.Builder
+	sb.WriteString(helpTitleStyle.Render(fmt.Sprintf("Clean up merged branch: %s (#%d)", p.branch, p.pr)))
+	sb.WriteString("\n\n")
+	for i, step := range p.steps() {
+		sb.WriteString(helpDescStyle.Render(fmt.Sprintf("%d. %s", i+1, step)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(helpDescStyle.Render("Press enter to clean up, esc to cancel."))
+	return sb.String()
+}
+
+// startCleanup runs the plan's steps, stopping at the first failure.
+func (m *Model) startCleanup(p cleanupPlan) []tea.Cmd {
+	if p.checkout && m.inOtherWorktree(p.parent) {
+		return nil
+	}
+	m.actionTargets = p.children
+	return m.startAction("cleanup", "Cleaned up "+p.branch, "Cleaning up "+p.branch+"...", "", func(ctx context.Context, client *gt.Client) error {
+		if err := client.RepoSync(ctx); err != nil {
+			return err
+		}
+		if p.checkout {
+			if err := client.Checkout(ctx, p.parent); err != nil {
+				return err
+			}
+		}
+		if err := client.Delete(ctx, p.branch); err != nil {
+			return err
+		}
+		for _, child := range p.children {
+			if err := client.StackRestack(ctx, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// bulkCleanup is the cleanup of every branch whose PR merged or closed,
+// awaiting confirmation. Candidates can be deselected before running.
+type bulkCleanup struct {
+	candidates []cleanupCandidate
+	cursor     int
+}
+
+// cleanupCandidate is a branch offered for deletion by bulkCleanup.
+type cleanupCandidate struct {
+	name     string
+	pr       int
+	state    string // PR state, MERGED or CLOSED
+	selected bool
+}
+
+// planBulkCleanup offers every non-trunk branch in entries whose PR is
+// merged or closed, in display order, all selected.
+func planBulkCleanup(branches []*gt.Branch, entries []displayEntry) bulkCleanup {
+	var c bulkCleanup
+	for _, e := range entries {
+		state := strings.ToUpper(e.branch.PR.State)
+		if e.pinned || (state != "MERGED" && state != "CLOSED") {
+			continue
+		}
+		if _, hasParent := gt.FindParent(branches, e.branch.Name); !hasParent {
+			continue
+		}
+		c.candidates = append(c.candidates, cleanupCandidate{name: e.branch.Name, pr: e.branch.PR.Number, state: state, selected: true})
+	}
+	return c
+}
+
+// selected returns the names of the selected candidates.
+func (c bulkCleanup) selected() []string {
+	var names []string
+	for _, cand := range c.candidates {
+		if cand.selected {
+			names = append(names, cand.name)
+		}
+	}
+	return names
+}
+
+// bulkCleanupEffects works out what deleting names does beyond the
+// deletes: the branch to check out first if the current one is going
+// ("" if none), and the surviving branches left on a deleted parent, which
+// need a restack onto their new parent.
+func bulkCleanupEffects(branches []*gt.Branch, names []string, current string) (checkout string, orphans []string) {
+	deleted := make(map[string]bool, len(names))
+	for _, name := range names {
+		deleted[name] = true
+	}
+	if deleted[current] {
+		checkout = current
+		for deleted[checkout] {
+			checkout, _ = gt.FindParent(branches, checkout)
+		}
+	}
+	var collect func(b *gt.Branch)
+	collect = func(b *gt.Branch) {
+		for _, child := range b.Children {
+			if deleted[child.Name] {
+				collect(child)
+			} else if !slices.Contains(orphans, child.Name) {
+				orphans = append(orphans, child.Name)
+			}
+		}
+	}
+	for _, name := range names {
+		if b := gt.FindBranch(branches, name); b != nil {
+			collect(b)
+		}
+	}
+	return checkout, orphans
+}
+
+// renderBulkCleanup renders the candidates with their selection and what
+// running the cleanup will do.
+func renderBulkCleanup(c bulkCleanup, checkout string, orphans []string, highlight lipgloss.Style) string {
+	var sb strings.Builder
+	sb.WriteString(helpTitleStyle.Render("Clean up merged and closed branches"))
+	sb.WriteString("\n\n")
+	for i, cand := range c.candidates {
+		box := "[ ]"
+		if cand.selected {
+			box = "[x]"
+		}
+		row := fmt.Sprintf("%s %s  #%d %s", box, cand.name, cand.pr, strings.ToLower(cand.state))
+		if i == c.cursor {
+			sb.WriteString(highlight.Render(row))
+		} else {
+			sb.WriteString(row)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	if effects := cleanupEffectsText(checkout, orphans); effects != "" {
+		sb.WriteString(helpDescStyle.Render(effects))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(helpDescStyle.Render("Press space to toggle a branch, enter to delete the selected ones (gt delete), esc to cancel."))
+	return sb.String()
+}
+
+// cleanupEffectsText describes the checkout and restacks of
+// bulkCleanupEffects, one per line, or "" if there are none.
+func cleanupEffectsText(checkout string, orphans []string) string {
+	var lines []string
+	if checkout != "" {
+		lines = append(lines, "First check out "+checkout+", since the current branch is deleted.")
+	}
+	for _, name := range orphans {
+		lines = append(lines, "Then restack "+name+" onto its new parent.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// refreshBulkCleanupView re-renders the bulk cleanup for the current
+// selection.
+func (m *Model) refreshBulkCleanupView() {
+	checkout, orphans := bulkCleanupEffects(m.branches, m.bulkCleanup.selected(), currentBranchName(*m))
+	m.viewport.SetContent(renderBulkCleanup(m.bulkCleanup, checkout, orphans, cursorStyle(m.lowBandwidth)))
+}
+
+// startSyncPrune offers the branches with merged or closed PRs for
+// deletion before syncing, as a form with a ticked checkbox for each, so
+// the sync never deletes a branch unasked. With none to offer it syncs
+// straight away.
+func (m *Model) startSyncPrune() []tea.Cmd {
+	c := planBulkCleanup(m.branches, flattenForDisplay(m.branches))
+	if len(c.candidates) == 0 {
+		return m.startPrunedSync(nil)
+	}
+	fields := make([]formField, len(c.candidates))
+	for i, cand := range c.candidates {
+		fields[i] = checkboxField(fmt.Sprintf("%s  #%d %s", cand.name, cand.pr, strings.ToLower(cand.state)), true)
+	}
+	branches, current := m.branches, currentBranchName(*m)
+	m.form = newForm("Sync: delete these merged and closed branches?", fields, func(m *Model, f form) []tea.Cmd {
+		return m.startPrunedSync(syncDeletions(c.candidates, f))
+	})
+	m.form.note = func(f form) string {
+		effects := cleanupEffectsText(bulkCleanupEffects(branches, syncDeletions(c.candidates, f), current))
+		if effects != "" {
+			effects += "\n"
+		}
+		return effects + "Unticked branches are kept (gt sync without -f)."
+	}
+	return nil
+}
+
+// syncDeletions returns the candidates ticked in the sync form f, whose
+// fields are the candidates in order.
+func syncDeletions(candidates []cleanupCandidate, f form) []string {
+	var names []string
+	for i, cand := range candidates {
+		if f.fields[i].checked {
+			names = append(names, cand.name)
+		}
+	}
+	return names
+}
+
+// startPrunedSync deletes names, first checking out a surviving branch if
+// the current one is among them, then syncs without deleting anything
+// else. The sync restacks the branches left on the deleted ones.
+func (m *Model) startPrunedSync(names []string) []tea.Cmd {
+	checkout, orphans := bulkCleanupEffects(m.branches, names, currentBranchName(*m))
+	if checkout != "" && m.inOtherWorktree(checkout) {
+		return nil
+	}
+	m.actionTargets = orphans
+	return m.startAction("sync", "Synced", "Syncing...", "", func(ctx context.Context, client *gt.Client) error {
+		if checkout != "" {
+			if err := client.Checkout(ctx, checkout); err != nil {
+				return err
+			}
+		}
+		for _, name := range names {
+			if err := client.Delete(ctx, name); err != nil {
+				return err
+			}
+		}
+		return client.SyncKeep(ctx)
+	})
+}
+
+// startBulkCleanup deletes the selected branches and restacks the branches
+// left on them, stopping at the first failure. Unlike sync, trunk isn't
+// pulled and nothing else is touched.
+func (m *Model) startBulkCleanup() []tea.Cmd {
+	names := m.bulkCleanup.selected()
+	checkout, orphans := bulkCleanupEffects(m.branches, names, currentBranchName(*m))
+	if checkout != "" && m.inOtherWorktree(checkout) {
+		return nil
+	}
+	m.actionTargets = orphans
+	what := fmt.Sprintf("%d branches", len(names))
+	if len(names) == 1 {
+		what = names[0]
+	}
+	return m.startAction("cleanup-all", "Deleted "+what, "Deleting "+what+"...", "", func(ctx context.Context, client *gt.Client) error {
+		if checkout != "" {
+			if err := client.Checkout(ctx, checkout); err != nil {
+				return err
+			}
+		}
+		for _, name := range names {
+			if err := client.Delete(ctx, name); err != nil {
+				return err
+			}
+		}
+		for _, name := range orphans {
+			if err := client.StackRestack(ctx, name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// newlyMerged returns, sorted, the branches whose PR is merged in infos but
+// was not merged in the previous infos.
+func newlyMerged(prev, infos map[string]gt.PRInfo) []string {
+	var names []string
+	for name, info := range infos {
+		if !strings.EqualFold(info.State, "MERGED") {
+			continue
+		}
+		if old, ok := prev[name]; ok && strings.EqualFold(old.State, "MERGED") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mergedNotice is the status message offering cleanup, on cleanupKey, for
+// newly merged branches.
+func mergedNotice(names []string, cleanupKey string) string {
+	if len(names) == 1 {
+		return names[0] + " merged — press " + cleanupKey + " on it to clean up"
+	}
+	return fmt.Sprintf("%d PRs merged (%s) — press %s on one to clean up", len(names), strings.Join(names, ", "), cleanupKey)
+}
+
+// startDeleteEverywhere deletes the selected branch locally and on the
+// remote, for branches whose PR was merged outside Graphite. Deleting the
+// remote branch affects everyone, so it takes two confirmations: the
+// commands, then the remote delete on its own. The remote goes first so a
+// failed local delete can simply be retried.
+func (m *Model) startDeleteEverywhere() []tea.Cmd {
+	b := m.selectedBranch()
+	switch {
+	case b == nil:
+		return nil
+	case b.Parent == "":
+		m.statusBar.setStatus(severityWarning, "Cannot delete trunk branch")
+		return nil
+	}
+	name := b.Name
+	checkout, orphans := bulkCleanupEffects(m.branches, []string{name}, currentBranchName(*m))
+	if checkout != "" && m.inOtherWorktree(checkout) {
+		return nil
+	}
+	m.actionTargets = orphans
+	fn := func(ctx context.Context, client *gt.Client) error {
+		if err := client.DeleteRemote(ctx, name); err != nil {
+			return err
+		}
+		if checkout != "" {
+			if err := client.Checkout(ctx, checkout); err != nil {
+				return err
+			}
+		}
+		if err := client.Delete(ctx, name); err != nil {
+			return err
+		}
+		for _, child := range orphans {
+			if err := client.StackRestack(ctx, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	spinner := "Deleting " + name + " locally and on " + remoteName + "..."
+	warning := "Deletes " + name + " here and " + remoteRef(name) + " on the remote."
+	if len(orphans) > 0 {
+		warning += " " + strings.Join(orphans, ", ") + " will be restacked onto its parent."
+	}
+	return m.confirmAction("delete-remote", pendingAction{
+		desc:     spinner,
+		warning:  warning,
+		commands: previewCommands(fn),
+		run: func(m *Model) []tea.Cmd {
+			m.askConfirm(pendingAction{
+				desc:     spinner,
+				warning:  "Really delete " + remoteRef(name) + "? It is gone for everyone, and only comes back if someone pushes it again.",
+				commands: previewCommands(fn),
+				run:      actionRunner("delete-remote", "Deleted "+name+" locally and on "+remoteName, spinner, fn),
+			})
+			return nil
+		},
+	})
+}
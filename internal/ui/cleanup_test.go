@@ -0,0 +1,320 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+const cleanupLog = "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"
+
+func TestPlanCleanup_Steps(t *testing.T) {
+	m := loadedModel(cleanupLog)
+	base := m.displayEntries[1].branch
+	base.PR = gt.PRInfo{Number: 12, State: "MERGED"}
+
+	p := planCleanup(m.branches, base)
+	want := []string{
+		"Sync trunk (gt repo sync)",
+		"Check out main (feature-base is checked out)",
+		"Delete local branch feature-base (gt delete)",
+		"Restack feature-top onto main",
+	}
+	if !reflect.DeepEqual(p.steps(), want) {
+		t.Errorf("steps = %q, want %q", p.steps(), want)
+	}
+}
+
+func TestNewlyMerged(t *testing.T) {
+	prev := map[string]gt.PRInfo{
+		"a": {Number: 1, State: "OPEN"},
+		"b": {Number: 2, State: "MERGED"},
+	}
+	infos := map[string]gt.PRInfo{
+		"a": {Number: 1, State: "MERGED"},
+		"b": {Number: 2, State: "MERGED"},
+		"c": {Number: 3, State: "MERGED"},
+		"d": {Number: 4, State: "OPEN"},
+	}
+	if got := newlyMerged(prev, infos); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("newlyMerged() = %v, want [a c]", got)
+	}
+}
+
+func TestPRInfoResult_OffersCleanup(t *testing.T) {
+	m := loadedModel(cleanupLog)
+	updated, _ := m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{
+		"feature-base": {Number: 12, State: "MERGED"},
+		"feature-top":  {Number: 13, State: "OPEN"},
+	}})
+	m = updated.(Model)
+	if got := toastMessages(m); len(got) != 1 || got[0] != "feature-base merged — press X on it to clean up" {
+		t.Errorf("toasts = %q", got)
+	}
+
+	// The same result again is not news.
+	updated, _ = m.Update(prInfoResultMsg{infos: m.prInfos})
+	m = updated.(Model)
+	if got := toastMessages(m); len(got) != 1 {
+		t.Errorf("toasts = %q, want none added for an already-known merge", got)
+	}
+}
+
+func TestCleanupKey_RequiresMergedPR(t *testing.T) {
+	m := loadedModel(cleanupLog)
+	m.cursor = 1 // feature-base
+	m = sendKey(m, 'X')
+	if m.mode != modeTree || !strings.Contains(m.statusBar.message, "has not merged") {
+		t.Errorf("mode = %v, message = %q", m.mode, m.statusBar.message)
+	}
+}
+
+func TestCleanupKey_ConfirmRunsSteps(t *testing.T) {
+	mock, calls := recordingMock()
+	m := loadedModel(cleanupLog)
+	m.gtClient = gt.New(mock)
+	m.cursor = 1 // feature-base
+	m.displayEntries[1].branch.PR = gt.PRInfo{Number: 12, State: "MERGED"}
+
+	m = sendKey(m, 'X')
+	if m.mode != modeCleanup {
+		t.Fatalf("mode = %v, want modeCleanup", m.mode)
+	}
+	if !strings.Contains(m.viewport.View(), "Delete local branch feature-base") {
+		t.Error("summary should list the delete step")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	if m.mode != modeTree || !m.running {
+		t.Fatalf("mode = %v, running = %v after confirm", m.mode, m.running)
+	}
+	runBatch(cmd)
+	want := []callRecord{
+		{name: "gt", args: []string{"repo", "sync", "--no-interactive"}},
+		{name: "gt", args: []string{"checkout", "main", "--no-interactive"}},
+		{name: "gt", args: []string{"delete", "feature-base", "--force", "--no-interactive"}},
+		{name: "gt", args: []string{"stack", "restack", "--no-interactive", "--branch", "feature-top"}},
+	}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Errorf("calls = %v, want %v", *calls, want)
+	}
+}
+
+func TestCleanupKey_EscCancels(t *testing.T) {
+	m := loadedModel(cleanupLog)
+	m.cursor = 1
+	m.displayEntries[1].branch.PR = gt.PRInfo{Number: 12, State: "MERGED"}
+	m = sendKey(m, 'X')
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || m.running {
+		t.Errorf("mode = %v, running = %v after esc", m.mode, m.running)
+	}
+	if m.statusBar.message != "Cleanup cancelled" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+const bulkCleanupLog = "│ ◯  top\n│ ◉  mid\n│ ◯  base\n◯─┘  main"
+
+// bulkCleanupModel has mid (checked out) and base merged or closed, with
+// top left open on them.
+func bulkCleanupModel() Model {
+	m := loadedModel(bulkCleanupLog)
+	m.displayEntries[1].branch.PR = gt.PRInfo{Number: 12, State: "MERGED"}
+	m.displayEntries[2].branch.PR = gt.PRInfo{Number: 11, State: "CLOSED"}
+	m.displayEntries[0].branch.PR = gt.PRInfo{Number: 13, State: "OPEN"}
+	return m
+}
+
+func TestPlanBulkCleanup_MergedAndClosed(t *testing.T) {
+	m := bulkCleanupModel()
+	c := planBulkCleanup(m.branches, m.displayEntries)
+	if got := c.selected(); !reflect.DeepEqual(got, []string{"mid", "base"}) {
+		t.Errorf("selected() = %v, want [mid base]", got)
+	}
+}
+
+func TestBulkCleanupEffects(t *testing.T) {
+	m := bulkCleanupModel()
+	checkout, orphans := bulkCleanupEffects(m.branches, []string{"mid", "base"}, "mid")
+	if checkout != "main" || !reflect.DeepEqual(orphans, []string{"top"}) {
+		t.Errorf("effects = %q, %v, want main, [top]", checkout, orphans)
+	}
+	checkout, orphans = bulkCleanupEffects(m.branches, []string{"base"}, "mid")
+	if checkout != "" || !reflect.DeepEqual(orphans, []string{"mid"}) {
+		t.Errorf("effects = %q, %v, want no checkout, [mid]", checkout, orphans)
+	}
+}
+
+func TestBulkCleanupKey_NothingToClean(t *testing.T) {
+	m := loadedModel(bulkCleanupLog)
+	m = sendSpecialKey(m, tea.KeyCtrlX)
+	if m.mode != modeTree || m.statusBar.message != "No branches with merged or closed PRs" {
+		t.Errorf("mode = %v, message = %q", m.mode, m.statusBar.message)
+	}
+}
+
+func TestBulkCleanupKey_ToggleAndRun(t *testing.T) {
+	mock, calls := recordingMock()
+	m := bulkCleanupModel()
+	m.gtClient = gt.New(mock)
+
+	m = sendSpecialKey(m, tea.KeyCtrlX)
+	if m.mode != modeBulkCleanup {
+		t.Fatalf("mode = %v, want modeBulkCleanup", m.mode)
+	}
+	if !strings.Contains(m.viewport.View(), "First check out main") {
+		t.Error("preview should check out main first")
+	}
+
+	// Keep base: only mid goes, and top moves onto base.
+	m = sendSpecialKey(m, tea.KeyDown)
+	m = sendKey(m, ' ')
+	if !strings.Contains(m.viewport.View(), "[ ] base") {
+		t.Errorf("base should be deselected:\n%s", m.viewport.View())
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	if m.mode != modeTree || !m.running {
+		t.Fatalf("mode = %v, running = %v after confirm", m.mode, m.running)
+	}
+	runBatch(cmd)
+	want := []callRecord{
+		{name: "gt", args: []string{"checkout", "base", "--no-interactive"}},
+		{name: "gt", args: []string{"delete", "mid", "--force", "--no-interactive"}},
+		{name: "gt", args: []string{"stack", "restack", "--no-interactive", "--branch", "top"}},
+	}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Errorf("calls = %v, want %v", *calls, want)
+	}
+}
+
+func TestBulkCleanupKey_EscCancels(t *testing.T) {
+	m := bulkCleanupModel()
+	m = sendSpecialKey(m, tea.KeyCtrlX)
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || m.running || m.statusBar.message != "Cleanup cancelled" {
+		t.Errorf("mode = %v, running = %v, message = %q", m.mode, m.running, m.statusBar.message)
+	}
+}
+
+func TestSyncKey_PicksBranchesToDelete(t *testing.T) {
+	mock, calls := recordingMock()
+	m := bulkCleanupModel()
+	m.gtClient = gt.New(mock)
+
+	m = sendKey(m, 'y')
+	if !m.form.active() || len(*calls) != 0 {
+		t.Fatalf("sync should offer the merged branches in a form first, mode = %v", m.mode)
+	}
+	if view := m.View(); !strings.Contains(view, "Sync: delete these merged and closed branches?") || !strings.Contains(view, "First check out main") {
+		t.Errorf("form should be titled for the sync and say what it checks out:\n%s", view)
+	}
+
+	// Deselect everything: the sync still runs and keeps every branch.
+	for range m.form.fields {
+		m = sendSpecialKey(m, tea.KeySpace)
+		m = sendSpecialKey(m, tea.KeyDown)
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	want := []callRecord{{name: "gt", args: []string{"sync", "--no-interactive"}}}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Errorf("calls = %v, want %v", *calls, want)
+	}
+}
+
+func TestSyncKey_DeletesSelectedThenSyncs(t *testing.T) {
+	mock, calls := recordingMock()
+	m := bulkCleanupModel()
+	m.gtClient = gt.New(mock)
+
+	m = sendKey(m, 'y')
+	m = sendSpecialKey(m, tea.KeyDown)
+	m = sendSpecialKey(m, tea.KeySpace) // keep base
+	if view := m.View(); !strings.Contains(view, "First check out base") {
+		t.Errorf("form should follow the selection to checking out base:\n%s", view)
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	want := []callRecord{
+		{name: "gt", args: []string{"checkout", "base", "--no-interactive"}},
+		{name: "gt", args: []string{"delete", "mid", "--force", "--no-interactive"}},
+		{name: "gt", args: []string{"sync", "--no-interactive"}},
+	}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Errorf("calls = %v, want %v", *calls, want)
+	}
+}
+
+func TestSyncKey_NothingToDelete(t *testing.T) {
+	mock, calls := recordingMock()
+	m := loadedModel(bulkCleanupLog)
+	m.gtClient = gt.New(mock)
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'y'}}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if m.mode != modeTree || len(*calls) != 1 || (*calls)[0].args[0] != "sync" {
+		t.Errorf("sync should run straight away, mode = %v, calls = %v", m.mode, *calls)
+	}
+}
+
+func TestDeleteRemote_ConfirmsTwice(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	m.cursor = 1 // feature-base, checked out, with feature-top on it
+
+	m = sendKey(m, 'K')
+	if m.mode != modeConfirm || !strings.Contains(m.View(), "git push origin --delete feature-base") {
+		t.Fatalf("K should show the commands, mode = %d:\n%s", m.mode, m.View())
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.mode != modeConfirm || !strings.Contains(m.View(), "Really delete origin/feature-base?") || len(*calls) != 0 {
+		t.Fatalf("the first enter should ask again, mode = %d, calls = %v", m.mode, *calls)
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+
+	want := [][]string{
+		{"push", "origin", "--delete", "feature-base"},
+		{"checkout", "main", "--no-interactive"},
+		{"delete", "feature-base", "--force", "--no-interactive"},
+		{"stack", "restack", "--no-interactive", "--branch", "feature-top"},
+	}
+	if len(*calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", *calls, want)
+	}
+	for i, c := range *calls {
+		if !reflect.DeepEqual(c.args, want[i]) {
+			t.Errorf("call %d = %v, want %v", i, c.args, want[i])
+		}
+	}
+}
+
+func TestDeleteRemote_EscAtSecondStepCancels(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	m.cursor = 0
+
+	m = sendKey(m, 'K')
+	m = sendSpecialKey(m, tea.KeyEnter)
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || len(*calls) != 0 || m.statusBar.message != "Cancelled" {
+		t.Errorf("mode = %d, calls = %v, message = %q", m.mode, *calls, m.statusBar.message)
+	}
+}
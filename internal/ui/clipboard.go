@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// yankTarget returns the text under the cursor in the current view and a
+// description of it for the status bar, or "" if there is nothing to copy.
+func (m Model) yankTarget() (text, what string) {
+	switch m.mode {
+	case modeTree:
+		if b := m.selectedBranch(); b != nil {
+			return b.Name, "branch name"
+		}
+	case modeDiff:
+		if m.diff.focusedPanel == panelDiff {
+			if content := strings.TrimSpace(ansi.Strip(m.diff.content)); content != "" {
+				return content + "\n", "diff"
+			}
+		} else if m.diff.fileCursor < len(m.diff.files) {
+			return m.diff.files[m.diff.fileCursor].path, "file path"
+		}
+	case modeJobs:
+		listed := m.jobs.listed()
+		if m.jobCursor < len(listed) {
+			j := listed[m.jobCursor]
+			if j.err != "" {
+				return j.label + ": " + j.err, "job"
+			}
+			return j.label, "job"
+		}
+	}
+	return "", ""
+}
+
+// yankSelection copies whatever is under the cursor, in any view.
+func (m *Model) yankSelection() []tea.Cmd {
+	text, what := m.yankTarget()
+	if text == "" {
+		m.statusBar.setStatus(severityWarning, "Nothing to copy here")
+		return nil
+	}
+	m.statusBar.setStatus(severitySuccess, yankDescription(text, what))
+	return []tea.Cmd{m.yank(text)}
+}
+
+// remoteName is the remote grit assumes branches are pushed to.
+const remoteName = "origin"
+
+// remoteRef returns the remote-tracking ref of branch, e.g. origin/feature.
+func remoteRef(branch string) string {
+	return remoteName + "/" + branch
+}
+
+// yankPR copies the selected branch's PR URL, as reported by gt's
+// pr-info, warning when there is none.
+func (m *Model) yankPR() tea.Cmd {
+	branch := m.selectedBranch()
+	switch {
+	case branch == nil:
+		return nil
+	case branch.PR.Number == 0:
+		m.statusBar.setStatus(severityWarning, "No PR for "+branch.Name)
+		return nil
+	case branch.PR.URL == "":
+		m.statusBar.setStatus(severityWarning, fmt.Sprintf("No URL for #%d: gt didn't report one", branch.PR.Number))
+		return nil
+	}
+	m.statusBar.setStatus(severitySuccess, yankDescription(branch.PR.URL, "PR URL"))
+	return m.yank(branch.PR.URL)
+}
+
+// yank copies text to the system clipboard. The write happens in a
+// command so it stays off the Update path.
+func (m Model) yank(text string) tea.Cmd {
+	copyText := m.copyText
+	return func() tea.Msg {
+		copyText(text)
+		return nil
+	}
+}
+
+// yankDescription shortens copied text for the status bar.
+func yankDescription(text, what string) string {
+	text = strings.TrimSpace(text)
+	if strings.Contains(text, "\n") {
+		return "Copied " + what
+	}
+	return "Copied " + what + ": " + truncateToWidth(text, 60)
+}
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// yankModel returns a loaded model whose clipboard writes are captured.
+func yankModel(copied *string) Model {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.copyText = func(s string) { *copied = s }
+	return m
+}
+
+func yankKey(m Model) Model {
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'Y'}}))
+	m = updated.(Model)
+	runBatch(cmd)
+	return m
+}
+
+func TestYank_BranchName(t *testing.T) {
+	var copied string
+	m := yankModel(&copied)
+	m.cursor = 1
+	m = yankKey(m)
+	if copied != "feature-base" {
+		t.Errorf("copied %q, want feature-base", copied)
+	}
+	if m.statusBar.message != "Copied branch name: feature-base" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+func TestYank_DiffFileAndContent(t *testing.T) {
+	var copied string
+	m := yankModel(&copied)
+	m.mode = modeDiff
+	m.diff = newDiffView(100, 28)
+	m.diff.setFiles([]diffFileEntry{{path: "a.go"}, {path: "pkg/b.go"}})
+	m.diff.fileCursor = 1
+	m.diff.setDiffContent("\x1b[32m+added\x1b[0m\n-removed")
+
+	m = yankKey(m)
+	if copied != "pkg/b.go" {
+		t.Errorf("copied %q, want pkg/b.go", copied)
+	}
+
+	m.diff.focusedPanel = panelDiff
+	m = yankKey(m)
+	if copied != "+added\n-removed\n" {
+		t.Errorf("copied %q, want stripped diff", copied)
+	}
+	if m.statusBar.message != "Copied diff" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+func TestYank_Job(t *testing.T) {
+	var copied string
+	m := yankModel(&copied)
+	m.jobs.submit("Refreshing PRs", true, okJob(nil))
+	m.mode = modeJobs
+	m = yankKey(m)
+	if copied != "Refreshing PRs" {
+		t.Errorf("copied %q, want job label", copied)
+	}
+}
+
+func TestYank_NothingToCopy(t *testing.T) {
+	copied := "unchanged"
+	m := yankModel(&copied)
+	m.mode = modeHelp
+	m = yankKey(m)
+	if copied != "unchanged" {
+		t.Error("nothing should be copied from the help view")
+	}
+	if m.statusBar.severity != severityWarning {
+		t.Error("expected a warning")
+	}
+}
+
+func TestYankRef_RemoteRef(t *testing.T) {
+	var copied string
+	m := yankModel(&copied)
+	m.cursor = 1
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlY}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if copied != "origin/feature-base" {
+		t.Errorf("copied %q, want origin/feature-base", copied)
+	}
+	if m.statusBar.message != "Copied remote ref: origin/feature-base" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+func TestYankPR_CopiesURL(t *testing.T) {
+	var copied string
+	m := yankModel(&copied)
+	updated, _ := m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{
+		"feature-top":  {Number: 12, State: "OPEN", URL: "https://github.com/acme/app/pull/12"},
+		"feature-base": {Number: 11, State: "OPEN"},
+	}})
+	m = updated.(Model)
+	m.cursor = 0
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlU}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if copied != "https://github.com/acme/app/pull/12" {
+		t.Errorf("copied %q, want the PR URL", copied)
+	}
+	if m.statusBar.message != "Copied PR URL: https://github.com/acme/app/pull/12" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+
+	copied = ""
+	m.cursor = 1
+	updated, cmd = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlU}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if copied != "" || m.statusBar.severity != severityWarning {
+		t.Errorf("a PR without a URL should warn, copied %q, message %q", copied, m.statusBar.message)
+	}
+}
+
+func TestYankPR_NeedsPR(t *testing.T) {
+	copied := "unchanged"
+	m := yankModel(&copied)
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlU}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if copied != "unchanged" || m.statusBar.message != "No PR for feature-top" {
+		t.Errorf("copied %q, message %q", copied, m.statusBar.message)
+	}
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// codeownersLocations are the paths GitHub searches for a CODEOWNERS file,
+// relative to the repo root, in order of precedence.
+var codeownersLocations = []string{
+	filepath.Join(".github", "CODEOWNERS"),
+	"CODEOWNERS",
+	filepath.Join("docs", "CODEOWNERS"),
+}
+
+// codeownersRule pairs a path pattern with the owners it assigns.
+type codeownersRule struct {
+	rule   ignoreRule
+	owners []string
+}
+
+// codeowners maps file paths to owners. As on GitHub, the last matching
+// pattern wins, and a pattern with no owners clears ownership.
+type codeowners struct {
+	rules []codeownersRule
+}
+
+// parseCodeowners parses CODEOWNERS lines of the form "pattern @owner ...".
+func parseCodeowners(lines []string) codeowners {
+	var c codeowners
+	for _, line := range lines {
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		r, ok := parseIgnoreRule(fields[0])
+		if !ok || r.negate {
+			continue
+		}
+		rule := codeownersRule{rule: r}
+		if len(fields) > 1 {
+			rule.owners = fields[1:]
+		}
+		c.rules = append(c.rules, rule)
+	}
+	return c
+}
+
+// loadCodeowners reads the first CODEOWNERS file found under repoRoot.
+func loadCodeowners(repoRoot string) codeowners {
+	for _, loc := range codeownersLocations {
+		if lines := readLines(filepath.Join(repoRoot, loc)); lines != nil {
+			return parseCodeowners(lines)
+		}
+	}
+	return codeowners{}
+}
+
+// ownersOf returns the owners of a single file, or nil if unowned.
+func (c codeowners) ownersOf(file string) []string {
+	parts := strings.Split(file, "/")
+	var owners []string
+	for _, r := range c.rules {
+		if r.rule.matches(parts) {
+			owners = r.owners
+		}
+	}
+	return owners
+}
+
+// ownersFor returns the sorted, de-duplicated owners of all files.
+func (c codeowners) ownersFor(files []string) []string {
+	if len(c.rules) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var owners []string
+	for _, f := range files {
+		for _, o := range c.ownersOf(f) {
+			if !seen[o] {
+				seen[o] = true
+				owners = append(owners, o)
+			}
+		}
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// missingOwnerTeams returns the team owners ("@org/team") not covered by
+// reviewers. Reviewers are user logins or team slugs as reported by gh; a
+// team is covered when its slug is among them. Individual owners are not
+// checked since the PR author is frequently one of them.
+func missingOwnerTeams(owners, reviewers []string) []string {
+	have := make(map[string]bool)
+	for _, r := range reviewers {
+		have[strings.ToLower(r)] = true
+	}
+	var missing []string
+	for _, o := range owners {
+		idx := strings.LastIndex(o, "/")
+		if idx == -1 {
+			continue
+		}
+		if !have[strings.ToLower(o[idx+1:])] {
+			missing = append(missing, o)
+		}
+	}
+	return missing
+}
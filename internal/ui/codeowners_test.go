@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCodeowners_LastMatchWins(t *testing.T) {
+	c := parseCodeowners([]string{
+		"# Default owners",
+		"*           @org/core",
+		"services/api/  @org/team-api @alice  # api team",
+		"*.md        @org/docs",
+		"/services/api/generated/",
+	})
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"main.go", []string{"@org/core"}},
+		{"services/api/handler.go", []string{"@org/team-api", "@alice"}},
+		{"services/api/README.md", []string{"@org/docs"}},
+		{"services/api/generated/x.go", nil},
+	}
+	for _, tt := range tests {
+		if got := c.ownersOf(tt.file); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ownersOf(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestCodeowners_OwnersFor(t *testing.T) {
+	c := parseCodeowners([]string{
+		"services/api/ @org/team-api @alice",
+		"web/ @org/web",
+	})
+	got := c.ownersFor([]string{"services/api/a.go", "web/b.ts", "services/api/c.go", "README"})
+	want := []string{"@alice", "@org/team-api", "@org/web"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCodeowners_Empty(t *testing.T) {
+	if got := (codeowners{}).ownersFor([]string{"a.go"}); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestLoadCodeowners_GithubDir(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @org/core\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := loadCodeowners(root)
+	if got := c.ownersOf("x.go"); !reflect.DeepEqual(got, []string{"@org/core"}) {
+		t.Errorf("ownersOf = %v, want [@org/core]", got)
+	}
+}
+
+func TestMissingOwnerTeams(t *testing.T) {
+	owners := []string{"@alice", "@org/team-api", "@org/Web"}
+	reviewers := []string{"bob", "web"}
+
+	got := missingOwnerTeams(owners, reviewers)
+	want := []string{"@org/team-api"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
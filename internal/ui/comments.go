@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var (
+	commentAuthorStyle = lipgloss.NewStyle().Bold(true)
+	threadOpenStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	threadDoneStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// commentsView is the PR discussion shown in modeComments.
+type commentsView struct {
+	branch     string
+	number     int
+	loaded     bool
+	err        error
+	discussion gt.PRDiscussion
+}
+
+// prDiscussionMsg carries the discussion of branch's PR.
+type prDiscussionMsg struct {
+	branch     string
+	discussion gt.PRDiscussion
+	err        error
+}
+
+// openComments shows the selected branch's PR conversation and review
+// threads.
+func (m *Model) openComments() {
+	branch := m.selectedBranch()
+	if branch == nil {
+		return
+	}
+	if branch.PR.Number == 0 {
+		m.statusBar.setStatus(severityWarning, "No PR for "+branch.Name)
+		return
+	}
+	m.comments = commentsView{branch: branch.Name, number: branch.PR.Number}
+	m.setMode(modeComments)
+	m.resizeViewport()
+	m.refreshCommentsView()
+	m.viewport.GotoTop()
+}
+
+// enterComments starts loading the discussion of the PR being shown. A
+// discussion loaded before stays shown until it arrives.
+func (m *Model) enterComments() []tea.Cmd {
+	client, name, number := m.gtClient, m.comments.branch, m.comments.number
+	return []tea.Cmd{func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		d, err := client.PRDiscussion(ctx, number)
+		return prDiscussionMsg{branch: name, discussion: d, err: err}
+	}}
+}
+
+// showDiscussion fills in the loaded discussion, unless the view has
+// closed or moved on to another PR since, and updates the unresolved
+// thread count shown in the tree.
+func (m *Model) showDiscussion(msg prDiscussionMsg) {
+	if m.mode != modeComments || msg.branch != m.comments.branch {
+		return
+	}
+	m.comments.loaded, m.comments.err, m.comments.discussion = true, msg.err, msg.discussion
+	if msg.err == nil {
+		if info, ok := m.prInfos[msg.branch]; ok {
+			info.Unresolved = msg.discussion.Unresolved()
+			m.prInfos[msg.branch] = info
+			applyPRInfo(m.branches, m.prInfos)
+		}
+	}
+	m.refreshCommentsView()
+}
+
+func (m *Model) refreshCommentsView() {
+	m.viewport.SetContent(renderComments(m.comments, m.width, time.Now()))
+}
+
+// renderComments renders the conversation, oldest first, then the review
+// threads, unresolved ones first, with bodies wrapped to width.
+func renderComments(v commentsView, width int, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(helpTitleStyle.Render(fmt.Sprintf("#%d %s", v.number, v.branch)))
+	sb.WriteString("\n\n")
+	switch {
+	case !v.loaded:
+		sb.WriteString(helpDescStyle.Render("Loading comments..."))
+		return sb.String()
+	case v.err != nil:
+		sb.WriteString(promptErrorStyle.Render("Could not load comments: " + v.err.Error()))
+		return sb.String()
+	}
+	d := v.discussion
+	bodyWidth := max(width-4, 20)
+
+	sb.WriteString(helpTitleStyle.Render(fmt.Sprintf("Conversation (%d)", len(d.Comments))))
+	sb.WriteString("\n")
+	if len(d.Comments) == 0 {
+		sb.WriteString(helpDescStyle.Render("  No comments.") + "\n")
+	}
+	for _, c := range d.Comments {
+		sb.WriteString("\n" + renderComment(c, "  ", bodyWidth, now))
+	}
+
+	threads := slices.Clone(d.Threads)
+	slices.SortStableFunc(threads, func(a, b gt.ReviewThread) int {
+		return cmp.Compare(boolRank(a.Resolved), boolRank(b.Resolved))
+	})
+	sb.WriteString("\n")
+	sb.WriteString(helpTitleStyle.Render(fmt.Sprintf("Review threads (%d unresolved of %d)", d.Unresolved(), len(d.Threads))))
+	sb.WriteString("\n")
+	if len(threads) == 0 {
+		sb.WriteString(helpDescStyle.Render("  No review threads.") + "\n")
+	}
+	for _, t := range threads {
+		where := t.Path
+		if t.Line > 0 {
+			where += fmt.Sprintf(":%d", t.Line)
+		}
+		if t.Resolved {
+			sb.WriteString("\n" + threadDoneStyle.Render("✓ "+where+" (resolved)") + "\n")
+		} else {
+			sb.WriteString("\n" + threadOpenStyle.Render("● "+where) + "\n")
+		}
+		for _, c := range t.Comments {
+			sb.WriteString(renderComment(c, "    ", bodyWidth-2, now))
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// renderComment renders one comment's author, age and body, indented.
+func renderComment(c gt.PRComment, indent string, width int, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(indent + commentAuthorStyle.Render(c.Author) + helpDescStyle.Render(" · "+timeAgo(c.CreatedAt, now)) + "\n")
+	body := strings.TrimSpace(strings.ReplaceAll(c.Body, "\r\n", "\n"))
+	for _, line := range strings.Split(ansi.Wordwrap(body, width, ""), "\n") {
+		sb.WriteString(indent + line + "\n")
+	}
+	return sb.String()
+}
+
+// boolRank orders false before true.
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// startComment asks for a comment to post on the selected branch's open
+// PR. Up recalls comments posted earlier, e.g. "rebased, PTAL".
+func (m *Model) startComment() {
+	branch := m.selectedBranch()
+	switch {
+	case branch == nil:
+		return
+	case !prOpen(branch.PR):
+		m.statusBar.setStatus(severityWarning, "Cannot comment: "+branch.Name+" has no open PR")
+		return
+	}
+	p := newPrompt(promptComment, fmt.Sprintf("Comment on #%d (%s)", branch.PR.Number, branch.Name), "")
+	p.base = branch.Name
+	p.number = branch.PR.Number
+	p.validate = func(value string) string {
+		if value == "" {
+			return "Comment cannot be empty"
+		}
+		return ""
+	}
+	m.showPrompt(p)
+}
+
+// postComment runs `gh pr comment` on name's PR.
+func (m *Model) postComment(name string, number int, body string) []tea.Cmd {
+	m.actionTargets = []string{name}
+	return m.startAction("comment", fmt.Sprintf("Commented on #%d", number), fmt.Sprintf("Commenting on #%d...", number), "", func(ctx context.Context, client *gt.Client) error {
+		return client.CommentPR(ctx, name, body)
+	})
+}
+
+func (m Model) commentsLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"↑/↓", "scroll"},
+		{m.keys.Comments.Help().Key + "/esc", "close"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
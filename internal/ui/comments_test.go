@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestRenderComments(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	v := commentsView{branch: "feature", number: 7, loaded: true, discussion: gt.PRDiscussion{
+		Comments: []gt.PRComment{{Author: "alice", Body: "Looks good.", CreatedAt: now.Add(-3 * time.Hour)}},
+		Threads: []gt.ReviewThread{
+			{Path: "a.go", Line: 3, Resolved: true, Comments: []gt.PRComment{{Author: "bob", Body: "Fixed?", CreatedAt: now}}},
+			{Path: "b.go", Line: 9, Comments: []gt.PRComment{{Author: "carol", Body: "Typo here.", CreatedAt: now}}},
+		},
+	}}
+	out := ansi.Strip(renderComments(v, 80, now))
+	for _, want := range []string{"#7 feature", "Conversation (1)", "alice · 3h ago", "  Looks good.", "Review threads (1 unresolved of 2)", "● b.go:9", "✓ a.go:3 (resolved)", "    Typo here."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "b.go:9") > strings.Index(out, "a.go:3") {
+		t.Errorf("unresolved threads should come first:\n%s", out)
+	}
+}
+
+func TestCommentsKey_LoadsDiscussionAndUpdatesTree(t *testing.T) {
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if name == "gh" && args[0] == "api" {
+			return `{"data":{"repository":{"pullRequest":{"comments":{"nodes":[{"author":{"login":"alice"},"body":"Ship it","createdAt":"2026-02-03T10:00:00Z"}]},"reviews":{"nodes":[]},"reviewThreads":{"nodes":[{"isResolved":false,"path":"x.go","line":1,"comments":{"nodes":[]}},{"isResolved":false,"path":"y.go","line":2,"comments":{"nodes":[]}}]}}}}}`, nil
+		}
+		return "", nil
+	}}
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature\n◯─┘  main"})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{"feature": {Number: 7, State: "OPEN"}}})
+	m = updated.(Model)
+	m.cursor = 0
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlT}))
+	m = updated.(Model)
+	if m.mode != modeComments || !strings.Contains(m.viewport.View(), "Loading comments") {
+		t.Fatalf("ctrl+t should open the comments view, mode = %d", m.mode)
+	}
+	msg, ok := cmd().(prDiscussionMsg)
+	if !ok {
+		t.Fatal("the comments view should load the discussion")
+	}
+	updated, _ = m.Update(msg)
+	m = updated.(Model)
+	if view := ansi.Strip(m.viewport.View()); !strings.Contains(view, "Ship it") || !strings.Contains(view, "2 unresolved of 2") {
+		t.Errorf("view should show the discussion:\n%s", view)
+	}
+
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree {
+		t.Fatalf("esc should close the comments view, mode = %d", m.mode)
+	}
+	if view := ansi.Strip(m.viewport.View()); !strings.Contains(view, "#7 open 2 unresolved") {
+		t.Errorf("tree should count the unresolved threads:\n%s", view)
+	}
+}
+
+func TestCommentsKey_NeedsPR(t *testing.T) {
+	m := loadedModel("│ ◉  feature\n◯─┘  main")
+	m.cursor = 0
+	updated, _ := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlT}))
+	m = updated.(Model)
+	if m.mode != modeTree || m.statusBar.message != "No PR for feature" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+}
+
+func TestDiscussion_DroppedAfterClose(t *testing.T) {
+	m := loadedModel("│ ◉  feature\n◯─┘  main")
+	updated, _ := m.Update(prDiscussionMsg{branch: "feature", discussion: gt.PRDiscussion{Threads: []gt.ReviewThread{{}}}})
+	m = updated.(Model)
+	if m.comments.loaded {
+		t.Error("a discussion arriving after the view closed should be dropped")
+	}
+}
+
+func TestPostCommentKey_PostsAndRecalls(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature\n◯─┘  main"})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{"feature": {Number: 7, State: "OPEN"}}})
+	m = updated.(Model)
+	m.cursor = 0
+
+	m = sendSpecialKey(m, tea.KeyCtrlO)
+	if !m.prompt.active() || !strings.Contains(m.View(), "Comment on #7 (feature)") {
+		t.Fatal("ctrl+o should ask for a comment")
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if !m.prompt.active() || m.prompt.err != "Comment cannot be empty" {
+		t.Fatalf("an empty comment should be refused, err = %q", m.prompt.err)
+	}
+	m = typeText(m, "rebased, PTAL")
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	want := callRecord{name: "gh", args: []string{"pr", "comment", "feature", "--body", "rebased, PTAL"}}
+	if len(*calls) != 1 || !reflect.DeepEqual((*calls)[0], want) {
+		t.Fatalf("calls = %v, want %v", *calls, want)
+	}
+
+	updated, _ = m.Update(actionResultMsg{action: "comment", message: "Commented on #7"})
+	m = updated.(Model)
+	m = sendSpecialKey(m, tea.KeyCtrlO)
+	m = sendSpecialKey(m, tea.KeyUp)
+	if got := m.prompt.input.Value(); got != "rebased, PTAL" {
+		t.Errorf("up should recall the last comment, got %q", got)
+	}
+}
+
+func TestPostCommentKey_NeedsOpenPR(t *testing.T) {
+	m := loadedModel("│ ◉  feature\n◯─┘  main")
+	m.cursor = 0
+	m = sendSpecialKey(m, tea.KeyCtrlO)
+	if m.prompt.active() || m.statusBar.message != "Cannot comment: feature has no open PR" {
+		t.Errorf("prompt active = %v, message = %q", m.prompt.active(), m.statusBar.message)
+	}
+}
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var (
+	commitTitleStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	commitStagedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// commitHeaderHeight is the number of lines above the message input: the
+// title, the staged files and a blank line.
+const commitHeaderHeight = 3
+
+// commitEditor is the multi-line message input shown in modeCommit.
+type commitEditor struct {
+	branch       string // checked-out branch the commit goes on
+	input        textarea.Model
+	staged       []string
+	stagedLoaded bool
+	stagedErr    error
+}
+
+// stagedResultMsg carries the files staged for the commit being written.
+type stagedResultMsg struct {
+	files []string
+	err   error
+}
+
+// openCommit shows the commit message editor for the checked-out branch
+// and starts counting the staged files.
+func (m *Model) openCommit() tea.Cmd {
+	branch := currentBranchName(*m)
+	if branch == "" {
+		m.statusBar.setStatus(severityWarning, "No branch checked out to commit to")
+		return nil
+	}
+	input := textarea.New()
+	input.Placeholder = "Commit message (first line is the subject)"
+	input.ShowLineNumbers = false
+	input.CharLimit = 0
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+	m.commit = commitEditor{branch: branch, input: input}
+	m.setMode(modeCommit)
+	m.resizeViewport()
+
+	client := m.gtClient
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		files, err := client.StagedFiles(ctx)
+		return stagedResultMsg{files: files, err: err}
+	}
+}
+
+// setSize fits the message input to the space left below the header.
+func (c *commitEditor) setSize(width, height int) {
+	c.input.SetWidth(max(width-2, 10))
+	c.input.SetHeight(max(height-commitHeaderHeight, 1))
+}
+
+// closeCommit returns to the tree, discarding the editor.
+func (m *Model) closeCommit() {
+	m.commit = commitEditor{}
+	m.setMode(modeTree)
+	m.resizeViewport()
+	m.viewport.SetContent(m.treeContent())
+}
+
+// submitCommit commits the staged changes with the typed message.
+func (m *Model) submitCommit() []tea.Cmd {
+	message := strings.TrimSpace(m.commit.input.Value())
+	if message == "" {
+		m.statusBar.setStatus(severityWarning, "Commit message cannot be empty")
+		return nil
+	}
+	branch := m.commit.branch
+	m.closeCommit()
+	m.cursorTarget = branch
+	return m.startAction("commit", "Committed to "+branch, "Committing to "+branch+"...", "", func(ctx context.Context, client *gt.Client) error {
+		return client.CommitCreate(ctx, message)
+	})
+}
+
+// startAmend asks to confirm amending every working-tree change into the
+// checked-out branch's commit, which restacks the branches above it.
+func (m *Model) startAmend() []tea.Cmd {
+	var current *gt.Branch
+	for _, e := range m.displayEntries {
+		if e.branch.IsCurrent {
+			current = e.branch
+			break
+		}
+	}
+	switch {
+	case current == nil:
+		m.statusBar.setStatus(severityWarning, "No branch checked out to amend")
+		return nil
+	case current.Parent == "":
+		m.statusBar.setStatus(severityWarning, "Cannot amend trunk branch")
+		return nil
+	}
+	name := current.Name
+	m.cursorTarget = name
+	warning := "Amending rewrites the last commit of " + name + " with every working-tree change and restacks the branches above it."
+	return m.startAction("amend", "Amended "+name, "Amending "+name+"...", warning, func(ctx context.Context, client *gt.Client) error {
+		return client.Amend(ctx)
+	})
+}
+
+// stagedSummary describes the staged files, e.g. "2 files staged: a.go, b.go".
+func stagedSummary(files []string) string {
+	switch len(files) {
+	case 0:
+		return "Nothing staged — stage changes with git add before committing"
+	case 1:
+		return "1 file staged: " + files[0]
+	}
+	return fmt.Sprintf("%d files staged: %s", len(files), strings.Join(files, ", "))
+}
+
+// commitView renders the editor in place of the tree, height lines tall.
+func (m Model) commitView(height int) string {
+	staged := "Counting staged files..."
+	switch {
+	case m.commit.stagedErr != nil:
+		staged = "Could not list staged files: " + m.commit.stagedErr.Error()
+	case m.commit.stagedLoaded:
+		staged = stagedSummary(m.commit.staged)
+	}
+	content := commitTitleStyle.Render("Commit to "+m.commit.branch) + "\n" +
+		commitStagedStyle.Render(truncateToWidth(staged, m.width-2)) + "\n\n" +
+		m.commit.input.View()
+	return lipgloss.NewStyle().Width(m.width).Height(height).Padding(0, 1).Render(content)
+}
+
+func (m Model) commitLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"ctrl+s", "commit"},
+		{"enter", "new line"},
+		{"esc", "cancel"},
+	}
+	return renderLegend(pairs, m.width)
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestStagedSummary(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  string
+	}{
+		{nil, "Nothing staged — stage changes with git add before committing"},
+		{[]string{"a.go"}, "1 file staged: a.go"},
+		{[]string{"a.go", "pkg/b.go"}, "2 files staged: a.go, pkg/b.go"},
+	}
+	for _, tt := range tests {
+		if got := stagedSummary(tt.files); got != tt.want {
+			t.Errorf("stagedSummary(%v) = %q, want %q", tt.files, got, tt.want)
+		}
+	}
+}
+
+func TestCommitKey_WritesMultiLineMessage(t *testing.T) {
+	var calls []callRecord
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		calls = append(calls, callRecord{name: name, args: args})
+		if name == "git" {
+			return "a.go\nb.go\n", nil
+		}
+		return "", nil
+	}}
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	m.cursor = 0 // the commit goes on the checked-out branch, not the selection
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'w'}}))
+	m = updated.(Model)
+	if m.mode != modeCommit {
+		t.Fatalf("mode = %d, want modeCommit", m.mode)
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	view := m.View()
+	for _, want := range []string{"Commit to feature-base", "2 files staged: a.go, b.go"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("editor should show %q:\n%s", want, view)
+		}
+	}
+
+	// q and enter are typed into the message rather than quitting or submitting.
+	for _, r := range "Fix quirk" {
+		m = sendKey(m, r)
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	m = sendSpecialKey(m, tea.KeyEnter)
+	for _, r := range "Details." {
+		m = sendKey(m, r)
+	}
+	if m.mode != modeCommit {
+		t.Fatalf("typing should stay in the editor, mode = %d", m.mode)
+	}
+
+	calls = nil
+	updated, cmd = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlS}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if m.mode != modeTree || !m.running {
+		t.Fatalf("ctrl+s should start the commit, mode = %d", m.mode)
+	}
+	if len(calls) != 1 || strings.Join(calls[0].args[:4], " ") != "commit create -m Fix quirk\n\nDetails." {
+		t.Errorf("calls = %v", calls)
+	}
+	if m.cursorTarget != "feature-base" {
+		t.Errorf("cursorTarget = %q, want the current branch", m.cursorTarget)
+	}
+}
+
+func TestCommitKey_EmptyMessageAndCancel(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m = sendKey(m, 'w')
+	m = sendSpecialKey(m, tea.KeyCtrlS)
+	if m.mode != modeCommit || m.statusBar.message != "Commit message cannot be empty" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || m.statusBar.message != "Commit cancelled" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+}
+
+func TestAmendKey_ConfirmsThenAmends(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	m.cursor = 0
+
+	m = sendSpecialKey(m, tea.KeyCtrlA)
+	if m.mode != modeConfirm || len(*calls) != 0 {
+		t.Fatalf("amend should wait for confirmation, mode = %d", m.mode)
+	}
+	if view := m.View(); !strings.Contains(view, "gt modify --all") || !strings.Contains(view, "last commit of feature-base") {
+		t.Errorf("confirmation should show the command and warning:\n%s", view)
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if len(*calls) != 1 || (*calls)[0].args[0] != "modify" {
+		t.Errorf("calls = %v", *calls)
+	}
+	if m.cursorTarget != "feature-base" {
+		t.Errorf("cursorTarget = %q, want the current branch", m.cursorTarget)
+	}
+}
+
+func TestAmendKey_Trunk(t *testing.T) {
+	m := loadedModel("│ ◯  feature-top\n│ ◯  feature-base\n◉─┘  main")
+	m = sendSpecialKey(m, tea.KeyCtrlA)
+	if m.mode != modeTree || m.statusBar.message != "Cannot amend trunk branch" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+}
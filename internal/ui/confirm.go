@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// clientAction is the body of a mutating action. It takes the client so it
+// can run for real or against a gt.CommandRecorder to preview its commands.
+type clientAction func(ctx context.Context, client *gt.Client) error
+
+// pendingAction is a mutating action waiting for its commands to be
+// confirmed (confirmCommands config, or always for history rewrites).
+type pendingAction struct {
+	desc     string   // e.g. "Restacking (feature-a)...", shown as the title
+	warning  string   // why the action needs care, "" if none
+	detail   string   // what prompted the warning, shown below it
+	commands []string // command lines the action will run
+	run      func(m *Model) []tea.Cmd
+}
+
+var (
+	confirmCommandStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	confirmWarningStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("1"))
+)
+
+// previewCommands returns the command lines fn would run if every command
+// succeeded.
+func previewCommands(fn clientAction) []string {
+	rec := &gt.CommandRecorder{}
+	_ = fn(context.Background(), gt.New(rec))
+	return rec.Commands
+}
+
+// confirmPolicy is when a mutating action's commands are shown for
+// confirmation before they run. Each actionSpec sets the policy of the
+// action it mutates; actions without a spec use confirmIfAsked.
+type confirmPolicy int
+
+const (
+	confirmIfAsked confirmPolicy = iota // only with confirmCommands set
+	confirmAlways                       // always, e.g. history rewrites
+)
+
+// confirmFor returns the policy of the spec mutating action.
+func confirmFor(action string) confirmPolicy {
+	for _, s := range actionSpecs {
+		if s.mutates == action {
+			return s.confirm
+		}
+	}
+	return confirmIfAsked
+}
+
+// startAction runs a mutating action with a spinner, first showing its
+// commands, under warning if set, as its confirmPolicy asks.
+func (m *Model) startAction(action, successMsg, spinnerLabel, warning string, fn clientAction) []tea.Cmd {
+	return m.confirmAction(action, pendingAction{
+		desc:     spinnerLabel,
+		warning:  warning,
+		commands: previewCommands(fn),
+		run:      actionRunner(action, successMsg, spinnerLabel, fn),
+	})
+}
+
+// actionRunner returns the run function of a pendingAction for fn.
+func actionRunner(action, successMsg, spinnerLabel string, fn clientAction) func(m *Model) []tea.Cmd {
+	return func(m *Model) []tea.Cmd {
+		m.running = true
+		client := m.gtClient
+		spinnerCmd := m.statusBar.startSpinner(spinnerLabel)
+		actionCmd := runAction(action, successMsg, func(ctx context.Context) error {
+			return fn(ctx, client)
+		})
+		return []tea.Cmd{spinnerCmd, actionCmd}
+	}
+}
+
+// confirmAction starts p now, or, when action's confirmPolicy or
+// confirmCommands asks for it, holds it in modeConfirm until the user
+// confirms.
+func (m *Model) confirmAction(action string, p pendingAction) []tea.Cmd {
+	if confirmFor(action) == confirmIfAsked && !m.confirmCommands {
+		return p.run(m)
+	}
+	m.askConfirm(p)
+	return nil
+}
+
+// askConfirm shows p in modeConfirm, over the tree, until the user runs or
+// cancels it.
+func (m *Model) askConfirm(p pendingAction) {
+	m.confirm = p
+	m.setMode(modeConfirm)
+	m.resizeViewport()
+	m.viewport.SetContent(m.treeContent())
+}
+
+// confirmOverlay renders the commands an action is about to run.
+func confirmOverlay(p pendingAction) overlay {
+	var sb strings.Builder
+	if p.warning != "" {
+		sb.WriteString(confirmWarningStyle.Render(p.warning))
+		sb.WriteString("\n\n")
+	}
+	if p.detail != "" {
+		sb.WriteString(p.detail)
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString(helpDescStyle.Render("grit will run:"))
+	sb.WriteString("\n\n")
+	for i, c := range p.commands {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(confirmCommandStyle.Render("$ " + c))
+	}
+	if len(p.commands) > 1 {
+		sb.WriteString("\n\n")
+		sb.WriteString(helpDescStyle.Render("Commands after a failing one are skipped."))
+	}
+	return overlay{
+		title:  strings.TrimSuffix(p.desc, "..."),
+		body:   sb.String(),
+		footer: "Press enter to run, esc to cancel.",
+	}
+}
@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func confirmModel(mock *mockExecutor) Model {
+	m := NewWithConfig(gt.New(mock), "", config.Config{ConfirmCommands: true})
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	return updated.(Model)
+}
+
+func TestPreviewCommands(t *testing.T) {
+	got := previewCommands(func(ctx context.Context, client *gt.Client) error {
+		if err := client.Checkout(ctx, "main"); err != nil {
+			return err
+		}
+		return client.Create(ctx, "feat/new thing")
+	})
+	want := []string{"gt checkout main --no-interactive", "gt create 'feat/new thing' --no-interactive"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("previewCommands = %q, want %q", got, want)
+	}
+}
+
+func TestConfirmCommands_ShowsThenRuns(t *testing.T) {
+	mock, calls := recordingMock()
+	m := confirmModel(mock)
+	m.cursor = 1 // feature-base
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'r'}}))
+	m = updated.(Model)
+	if cmd != nil {
+		runBatch(cmd)
+	}
+	if len(*calls) != 0 {
+		t.Fatalf("nothing should run before confirmation, got %v", *calls)
+	}
+	if m.mode != modeConfirm || m.running {
+		t.Fatalf("expected confirm mode (mode=%v running=%v)", m.mode, m.running)
+	}
+	if !containsString(m.View(), "$ gt stack restack --no-interactive --branch feature-base") {
+		t.Errorf("view should show the command, got:\n%s", m.View())
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	if m.mode != modeTree || !m.running {
+		t.Fatalf("enter should start the action (mode=%v running=%v)", m.mode, m.running)
+	}
+	runBatch(cmd)
+	if len(*calls) != 1 || strings.Join((*calls)[0].args, " ") != "stack restack --no-interactive --branch feature-base" {
+		t.Errorf("calls = %v", *calls)
+	}
+}
+
+func TestConfirmCommands_Cancel(t *testing.T) {
+	mock, calls := recordingMock()
+	m := confirmModel(mock)
+	m = sendKey(m, 'f')
+	if m.mode != modeConfirm {
+		t.Fatal("expected confirm mode")
+	}
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || m.running {
+		t.Error("esc should return to the tree without running")
+	}
+	if len(*calls) != 0 {
+		t.Errorf("nothing should run, got %v", *calls)
+	}
+	if m.statusBar.message != "Cancelled" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+func TestConfirmCommands_Submit(t *testing.T) {
+	mock, _ := recordingMock()
+	m := confirmModel(mock)
+	m = sendKey(m, 's')
+	if m.mode != modeConfirm {
+		t.Fatal("submit should ask for confirmation")
+	}
+	if !containsString(m.View(), "$ gt stack submit --no-interactive --branch feature-top") {
+		t.Errorf("view should show the submit command, got:\n%s", m.View())
+	}
+}
+
+func TestConfirmCommands_Disabled(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m = sendKey(m, 'f')
+	if m.mode != modeTree || !m.running {
+		t.Error("without confirmCommands actions should run immediately")
+	}
+}
+
+func TestFold_AlwaysConfirms(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	m.cursor = 0 // feature-top
+
+	m = sendKey(m, 'F')
+	if m.mode != modeConfirm || m.running || len(*calls) != 0 {
+		t.Fatalf("fold should wait for confirmation without confirmCommands, mode = %d", m.mode)
+	}
+	view := m.View()
+	for _, want := range []string{"gt fold --no-interactive --branch feature-top", "rewrites feature-base"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("confirmation should contain %q:\n%s", want, view)
+		}
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if !m.running || len(*calls) != 1 || (*calls)[0].args[0] != "fold" {
+		t.Fatalf("enter should run the fold, calls = %v", *calls)
+	}
+	if m.cursorTarget != "feature-base" {
+		t.Errorf("cursorTarget = %q, want the parent", m.cursorTarget)
+	}
+}
+
+func TestFold_Guards(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.cursor = 2 // main
+	m = sendKey(m, 'F')
+	if m.mode != modeTree || m.statusBar.message != "Cannot fold trunk branch" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+
+	m.cursor = 1 // feature-base, parent is trunk
+	m = sendKey(m, 'F')
+	if m.mode != modeTree || m.statusBar.message != "Cannot fold into trunk branch main" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+}
+
+func TestPop_ChecksOutThenPops(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	m.cursor = 0 // feature-top, not checked out
+
+	m = sendKey(m, 'p')
+	if m.mode != modeConfirm || len(*calls) != 0 {
+		t.Fatalf("pop should wait for confirmation, mode = %d", m.mode)
+	}
+	view := m.View()
+	for _, want := range []string{"gt checkout feature-top", "gt pop --no-interactive", "folds the commits of feature-top"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("confirmation should contain %q:\n%s", want, view)
+		}
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if len(*calls) != 2 || (*calls)[0].args[0] != "checkout" || (*calls)[1].args[0] != "pop" {
+		t.Fatalf("calls = %v, want checkout then pop", *calls)
+	}
+	if m.cursorTarget != "feature-base" {
+		t.Errorf("cursorTarget = %q, want the parent", m.cursorTarget)
+	}
+}
+
+func TestPop_Guards(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.cursor = 2 // main
+	m = sendKey(m, 'p')
+	if m.mode != modeTree || m.statusBar.message != "Cannot pop trunk branch" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+
+	m.cursor = 1 // feature-base has feature-top stacked on it
+	m = sendKey(m, 'p')
+	if m.mode != modeTree || m.statusBar.message != "Cannot pop feature-base: branches are stacked on it" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+}
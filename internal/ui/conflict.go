@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// conflictView lists the files left conflicted when an action stops on a
+// rebase conflict, until the rebase is continued or aborted.
+type conflictView struct {
+	root   string   // working tree root the paths are relative to
+	files  []string // unresolved paths
+	cursor int
+	loaded bool
+}
+
+// conflictFilesMsg carries the conflicted files, loaded on entering
+// modeConflict and after each edit.
+type conflictFilesMsg struct {
+	root  string
+	files []string
+	err   error
+}
+
+// editorDoneMsg is sent when the editor exits and grit has the terminal
+// back.
+type editorDoneMsg struct{ err error }
+
+// isConflict reports whether an action's error means it stopped on a
+// conflict.
+func isConflict(errMsg string) bool {
+	return strings.Contains(errMsg, "conflict") || strings.Contains(errMsg, "CONFLICT")
+}
+
+// openConflicts shows modeConflict and loads the conflicted files.
+func (m *Model) openConflicts() {
+	m.setMode(modeConflict)
+	m.resizeViewport()
+	m.refreshConflictView()
+	m.viewport.GotoTop()
+}
+
+// closeConflicts returns to the tree.
+func (m *Model) closeConflicts() {
+	m.setMode(modeTree)
+	m.resizeViewport()
+	m.viewport.SetContent(m.treeContent())
+}
+
+// loadConflicts lists the conflicted files and the working tree root.
+func (m Model) loadConflicts() tea.Cmd {
+	client := m.gtClient
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		root, err := client.TopLevel(ctx)
+		if err != nil {
+			return conflictFilesMsg{err: err}
+		}
+		files, err := client.ConflictedFiles(ctx)
+		return conflictFilesMsg{root: root, files: files, err: err}
+	}
+}
+
+func (m *Model) refreshConflictView() {
+	if m.conflict.cursor >= len(m.conflict.files) {
+		m.conflict.cursor = max(len(m.conflict.files)-1, 0)
+	}
+	m.viewport.SetContent(renderConflicts(m.conflict, m.keys.Continue.Help().Key, cursorStyle(m.lowBandwidth)))
+}
+
+// renderConflicts renders the conflicted file list with the cursor on one.
+func renderConflicts(c conflictView, continueKey string, highlight lipgloss.Style) string {
+	var sb strings.Builder
+	sb.WriteString(helpTitleStyle.Render("Rebase stopped on a conflict"))
+	sb.WriteString("\n\n")
+	switch {
+	case !c.loaded:
+		sb.WriteString(helpDescStyle.Render("Loading conflicted files..."))
+	case len(c.files) == 0:
+		sb.WriteString(helpDescStyle.Render("No conflicted files left. Press " + continueKey + " to continue the rebase."))
+	default:
+		for i, path := range c.files {
+			if i == c.cursor {
+				sb.WriteString(highlight.Render(path))
+			} else {
+				sb.WriteString(path)
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+		sb.WriteString(helpDescStyle.Render("Fix each file and stage it with git add, then press " + continueKey + " to continue."))
+	}
+	return sb.String()
+}
+
+// editConflict opens the file at the cursor in the editor, suspending the
+// UI until it exits.
+func (m Model) editConflict() tea.Cmd {
+	if m.conflict.cursor >= len(m.conflict.files) {
+		return nil
+	}
+	path := filepath.Join(m.conflict.root, m.conflict.files[m.conflict.cursor])
+	return tea.ExecProcess(editorCommand(os.Getenv, path), func(err error) tea.Msg {
+		return editorDoneMsg{err: err}
+	})
+}
+
+func (m Model) conflictLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"↑↓", "navigate"},
+		{m.keys.Edit.Help().Key, "edit"},
+		{m.keys.Continue.Help().Key, "continue"},
+		{m.keys.Abort.Help().Key, "abort"},
+		{"esc", "close"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func conflictModel(t *testing.T) (Model, *[]callRecord) {
+	t.Helper()
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	m.running = true
+	updated, cmd := m.Update(actionResultMsg{action: "restack", err: errors.New("CONFLICT (content): Merge conflict in api.go")})
+	m = updated.(Model)
+	if m.mode != modeConflict {
+		t.Fatalf("mode = %d, want modeConflict", m.mode)
+	}
+	var loaded bool
+	for _, msg := range batchMsgs(cmd) {
+		if _, ok := msg.(conflictFilesMsg); ok {
+			loaded = true
+		}
+	}
+	if !loaded {
+		t.Fatal("entering conflict mode should load the conflicted files")
+	}
+	updated, _ = m.Update(conflictFilesMsg{root: "/repo", files: []string{"api.go", "web/app.ts"}})
+	m = updated.(Model)
+	*calls = nil
+	return m, calls
+}
+
+func TestConflict_ListsFiles(t *testing.T) {
+	m, _ := conflictModel(t)
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"api.go", "web/app.ts", "press C to continue", "abort"} {
+		if !containsString(view, want) {
+			t.Errorf("view should contain %q:\n%s", want, view)
+		}
+	}
+
+	m = sendSpecialKey(m, tea.KeyDown)
+	if m.conflict.cursor != 1 {
+		t.Errorf("cursor = %d, want 1", m.conflict.cursor)
+	}
+	// A reload keeps the conflict view on screen.
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	if !containsString(m.View(), "web/app.ts") {
+		t.Error("reload should not replace the conflict view")
+	}
+
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || !containsString(m.View(), "feature-top") {
+		t.Errorf("esc should return to the tree, mode = %d", m.mode)
+	}
+}
+
+func TestConflict_ContinueClosesOnSuccess(t *testing.T) {
+	m, calls := conflictModel(t)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	m = updated.(Model)
+	runBatch(cmd)
+	if len(*calls) != 1 || !slices.Equal((*calls)[0].args, []string{"continue", "--no-interactive"}) {
+		t.Fatalf("calls = %+v, want gt continue", *calls)
+	}
+
+	updated, _ = m.Update(actionResultMsg{action: "continue", message: "Rebase continued"})
+	m = updated.(Model)
+	if m.mode != modeTree {
+		t.Errorf("mode = %d, want the tree after continuing", m.mode)
+	}
+}
+
+func TestConflict_AbortConfirms(t *testing.T) {
+	m, calls := conflictModel(t)
+	m = sendKey(m, 'A')
+	if m.mode != modeConfirm || !containsString(m.View(), "gt abort --force") {
+		t.Fatalf("abort should confirm first, mode = %d:\n%s", m.mode, m.View())
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runBatch(cmd)
+	m = updated.(Model)
+	if len(*calls) != 1 || (*calls)[0].name != "gt" || (*calls)[0].args[0] != "abort" {
+		t.Errorf("calls = %+v, want gt abort", *calls)
+	}
+}
+
+func TestAbort_NeedsRebase(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n◯─┘  main")
+	m = sendKey(m, 'A')
+	if m.mode != modeTree || m.statusBar.message != "No rebase in progress" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	for _, tc := range []struct {
+		env  map[string]string
+		want []string
+	}{
+		{map[string]string{"VISUAL": "code --wait", "EDITOR": "nano"}, []string{"code", "--wait", "/repo/a.go"}},
+		{map[string]string{"EDITOR": "nano"}, []string{"nano", "/repo/a.go"}},
+		{nil, []string{"vi", "/repo/a.go"}},
+	} {
+		cmd := editorCommand(func(k string) string { return tc.env[k] }, "/repo/a.go")
+		if !slices.Equal(cmd.Args, tc.want) {
+			t.Errorf("args = %v, want %v", cmd.Args, tc.want)
+		}
+	}
+}
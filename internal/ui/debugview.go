@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// quotaResultMsg carries the GitHub API quota fetched for the debug view.
+type quotaResultMsg struct {
+	quotas []gt.Quota
+	err    error
+}
+
+// debugState holds data shown in the debug view that has to be fetched.
+type debugState struct {
+	loading  bool
+	quotas   []gt.Quota
+	quotaErr error
+}
+
+// loadQuota fetches the GitHub API quota as a background job.
+func (m Model) loadQuota() tea.Cmd {
+	client := m.gtClient
+	return m.jobs.submit("Check GitHub quota", true, func(jobCtx context.Context) (tea.Msg, error) {
+		ctx, cancel := context.WithTimeout(jobCtx, 10*time.Second)
+		defer cancel()
+		quotas, err := client.RateLimit(ctx)
+		return quotaResultMsg{quotas: quotas, err: err}, err
+	})
+}
+
+// refreshDebugView re-renders the debug view into the viewport.
+func (m *Model) refreshDebugView() {
+	stats, hasStats := m.gtClient.RemoteStats()
+	m.viewport.SetContent(renderDebug(stats, hasStats, m.debug, m.jobs, time.Now()))
+}
+
+// renderDebug renders remote call counters, the GitHub quota and a job
+// summary.
+func renderDebug(stats gt.RemoteStats, hasStats bool, d debugState, jobs *jobScheduler, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(helpTitleStyle.Render("Debug"))
+	sb.WriteString("\n\n")
+
+	row := func(label, value string) {
+		sb.WriteString("  " + helpKeyStyle.Render(label) + helpDescStyle.Render(value) + "\n")
+	}
+
+	sb.WriteString(helpSectionStyle.Render("Remote calls") + "\n")
+	if hasStats {
+		row("rate limit", fmt.Sprintf("%d/s, burst %d", gt.DefaultRemoteRate, gt.DefaultRemoteBurst))
+		row("calls", fmt.Sprintf("%d", stats.Calls))
+		row("coalesced", fmt.Sprintf("%d", stats.Coalesced))
+		row("throttled", fmt.Sprintf("%d", stats.Throttled))
+	} else {
+		row("", "not rate limited")
+	}
+
+	sb.WriteString("\n" + helpSectionStyle.Render("GitHub quota") + "\n")
+	switch {
+	case d.loading:
+		row("", "loading...")
+	case d.quotaErr != nil:
+		row("", "unavailable: "+lastLine(d.quotaErr.Error()))
+	case len(d.quotas) == 0:
+		row("", "unavailable")
+	default:
+		for _, q := range d.quotas {
+			value := fmt.Sprintf("%d / %d remaining", q.Remaining, q.Limit)
+			if reset := q.Reset.Sub(now); reset > 0 {
+				value += ", resets in " + reset.Round(time.Minute).String()
+			}
+			row(q.Resource, value)
+		}
+	}
+
+	running, queued := 0, 0
+	for _, j := range jobs.jobs {
+		switch j.state {
+		case jobRunning:
+			running++
+		case jobQueued:
+			queued++
+		}
+	}
+	sb.WriteString("\n" + helpSectionStyle.Render("Jobs") + "\n")
+	row("running", fmt.Sprintf("%d", running))
+	row("queued", fmt.Sprintf("%d", queued))
+
+	return strings.TrimRight(sb.String(), "\n")
+}
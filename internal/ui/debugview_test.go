@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestRenderDebug(t *testing.T) {
+	now := time.Unix(1000, 0)
+	jobs := newJobScheduler()
+	jobs.submit("Refresh PR info", true, okJob(nil))
+	d := debugState{quotas: []gt.Quota{
+		{Resource: "core", Limit: 5000, Remaining: 4990, Reset: now.Add(30 * time.Minute)},
+		{Resource: "graphql", Limit: 5000, Remaining: 12},
+	}}
+
+	got := ansi.Strip(renderDebug(gt.RemoteStats{Calls: 42, Coalesced: 3, Throttled: 5}, true, d, jobs, now))
+	for _, want := range []string{"Remote calls", "42", "coalesced", "throttled", "5/s, burst 10",
+		"GitHub quota", "4990 / 5000 remaining, resets in 30m0s", "12 / 5000 remaining", "running"} {
+		if !containsString(got, want) {
+			t.Errorf("debug view should contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderDebug_QuotaStates(t *testing.T) {
+	jobs := newJobScheduler()
+	tests := []struct {
+		d    debugState
+		want string
+	}{
+		{debugState{loading: true}, "loading..."},
+		{debugState{quotaErr: errors.New("gh: not logged in")}, "unavailable: gh: not logged in"},
+		{debugState{}, "unavailable"},
+	}
+	for _, tt := range tests {
+		got := ansi.Strip(renderDebug(gt.RemoteStats{}, false, tt.d, jobs, time.Now()))
+		if !containsString(got, tt.want) {
+			t.Errorf("debug view should contain %q, got:\n%s", tt.want, got)
+		}
+		if !containsString(got, "not rate limited") {
+			t.Errorf("debug view without stats should say so, got:\n%s", got)
+		}
+	}
+}
+
+func TestDebugView_OpenLoadsQuotaAndCloses(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+
+	m = sendKey(m, 'D')
+	if m.mode != modeDebug || !m.debug.loading {
+		t.Fatalf("mode = %v loading = %v, want debug view loading quota", m.mode, m.debug.loading)
+	}
+	if last := m.jobs.jobs[len(m.jobs.jobs)-1]; last.label != "Check GitHub quota" {
+		t.Errorf("last job = %q, want quota check", last.label)
+	}
+
+	updated, _ := m.Update(quotaResultMsg{quotas: []gt.Quota{{Resource: "core", Limit: 5000, Remaining: 77}}})
+	m = updated.(Model)
+	if !containsString(m.View(), "77 / 5000 remaining") {
+		t.Error("debug view should show the quota")
+	}
+
+	m = sendKey(m, 'D')
+	if m.mode != modeTree {
+		t.Errorf("mode = %v, want modeTree", m.mode)
+	}
+}
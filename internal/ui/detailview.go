@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/journal"
+)
+
+const (
+	// detailMinTermWidth is the narrowest terminal the detail panel is shown on.
+	detailMinTermWidth = 100
+	// detailWidth is the width of the detail panel, including its left border.
+	detailWidth = 40
+)
+
+var (
+	detailTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	detailLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Width(8)
+	detailValueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
+	// detailBorderStyle only supplies the border color.
+	detailBorderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// detailRow is a single "label  value" line in the detail panel.
+type detailRow struct {
+	label string
+	value string
+}
+
+// detailRows builds the rows describing a branch.
+func detailRows(b *gt.Branch, parent string) []detailRow {
+	var rows []detailRow
+	if parent != "" {
+		rows = append(rows, detailRow{"parent", parent})
+	}
+	if b.Orphan != "" {
+		rows = append(rows, detailRow{"orphaned", b.Orphan + " (T re-tracks)"})
+	}
+	if b.Worktree != "" {
+		rows = append(rows, detailRow{"worktree", b.Worktree + " (enter goes there)"})
+	}
+	if pr := strings.TrimSpace(prLabelPlain(b.PR)); pr != "" {
+		rows = append(rows, detailRow{"PR", pr})
+	}
+	switch b.PR.CI {
+	case gt.CIPassing:
+		rows = append(rows, detailRow{"CI", "passing"})
+	case gt.CIFailing:
+		rows = append(rows, detailRow{"CI", "failing"})
+	case gt.CIPending:
+		rows = append(rows, detailRow{"CI", "running"})
+	}
+	if b.PR.Conflicts && prOpen(b.PR) {
+		rows = append(rows, detailRow{"merge", "conflicts with its base (r restacks)"})
+	}
+	if len(b.PR.Labels) > 0 {
+		rows = append(rows, detailRow{"labels", strings.Join(b.PR.Labels, ", ")})
+	}
+	if len(b.PR.Assignees) > 0 {
+		rows = append(rows, detailRow{"assigned", strings.Join(b.PR.Assignees, ", ")})
+	}
+	if b.Stack.Size > 1 {
+		rows = append(rows, detailRow{"stack", fmt.Sprintf("%d of %d in review order", b.Stack.Index, b.Stack.Size)})
+	}
+	if unsubmitted(b) {
+		rows = append(rows, detailRow{"PR head", shortSHA(b.PR.HeadSHA) + ", local ahead of PR (U resubmits)"})
+	}
+	if b.Changes.Loaded {
+		files := fmt.Sprintf("%d changed", b.Changes.Files)
+		if b.Changes.InScope != b.Changes.Files {
+			files += fmt.Sprintf(" (%d in scope)", b.Changes.InScope)
+		}
+		rows = append(rows, detailRow{"files", files})
+		if len(b.Changes.Owners) > 0 {
+			rows = append(rows, detailRow{"owners", strings.Join(b.Changes.Owners, ", ")})
+		}
+	}
+	switch b.Tests {
+	case gt.TestRunning:
+		rows = append(rows, detailRow{"tests", "running"})
+	case gt.TestPassed:
+		rows = append(rows, detailRow{"tests", "passed"})
+	case gt.TestFailed:
+		rows = append(rows, detailRow{"tests", "failed"})
+	}
+	return rows
+}
+
+// actionPast is how the detail panel's history names each journaled action.
+var actionPast = map[string]string{
+	"submit":  "submitted",
+	"restack": "restacked",
+	"fold":    "folded",
+	"rename":  "renamed",
+	"move":    "moved",
+}
+
+// historyRows builds the rows listing a branch's recent actions, newest
+// first, e.g. "submitted 2h ago".
+func historyRows(history []journal.Event, now time.Time) []detailRow {
+	rows := make([]detailRow, 0, len(history))
+	for i, e := range history {
+		label := ""
+		if i == 0 {
+			label = "history"
+		}
+		action, ok := actionPast[e.Action]
+		if !ok {
+			action = e.Action
+		}
+		rows = append(rows, detailRow{label, action + " " + timeAgo(e.Time, now)})
+	}
+	return rows
+}
+
+// timeAgo describes how long before now t was, coarsely.
+func timeAgo(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 48*time.Hour:
+		return "yesterday"
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+	return "on " + t.Format("Jan 2")
+}
+
+// renderDetail renders the detail panel for the selected branch at the
+// given size, followed by its history rows. b may be nil when the tree is
+// empty.
+func renderDetail(b *gt.Branch, parent string, history []detailRow, width, height int) string {
+	innerWidth := width - 3 // border + padding
+	if innerWidth < 1 {
+		innerWidth = 1
+	}
+
+	var lines []string
+	if b != nil {
+		lines = append(lines, detailTitleStyle.Render(truncateToWidth(b.Name, innerWidth)), "")
+		valueStyle := detailValueStyle.Width(innerWidth - 8)
+		for _, r := range append(detailRows(b, parent), history...) {
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+				detailLabelStyle.Render(r.label),
+				valueStyle.Render(r.value)))
+		}
+	}
+
+	style := lipgloss.NewStyle().
+		Width(innerWidth+1).
+		Height(height).
+		MaxHeight(height).
+		PaddingLeft(1).
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(detailBorderStyle.GetForeground())
+	return style.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/journal"
+)
+
+func TestRenderDetail_ShowsBranchInfo(t *testing.T) {
+	b := &gt.Branch{
+		Name: "feature-a",
+		PR:   gt.PRInfo{Number: 142, State: "OPEN"},
+		Changes: gt.ChangeInfo{
+			Loaded: true, Files: 3, InScope: 3,
+			Owners: []string{"@org/team-api", "@alice"},
+		},
+	}
+	got := ansi.Strip(renderDetail(b, "main", nil, detailWidth, 10))
+	for _, want := range []string{"feature-a", "parent", "main", "#142 open", "3 changed", "owners", "@org/team-api, @alice"} {
+		if !containsString(got, want) {
+			t.Errorf("detail should contain %q, got:\n%s", want, got)
+		}
+	}
+	if h := lipgloss.Height(got); h != 10 {
+		t.Errorf("height = %d, want 10", h)
+	}
+}
+
+func TestRenderDetail_ShowsTestStatus(t *testing.T) {
+	b := &gt.Branch{Name: "feature-a", Tests: gt.TestFailed}
+	got := ansi.Strip(renderDetail(b, "main", nil, detailWidth, 10))
+	if !containsString(got, "tests") || !containsString(got, "failed") {
+		t.Errorf("detail should show test status, got:\n%s", got)
+	}
+}
+
+func TestDetailRows_Conflicts(t *testing.T) {
+	b := &gt.Branch{Name: "feature-a", PR: gt.PRInfo{Number: 142, State: "OPEN", Conflicts: true}}
+	found := false
+	for _, row := range detailRows(b, "main") {
+		if row.label == "merge" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a merge row for a PR with conflicts")
+	}
+}
+
+func TestRenderDetail_ShowsHistory(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	history := historyRows([]journal.Event{
+		{Time: now.Add(-2 * time.Hour), Action: "submit"},
+		{Time: now.Add(-30 * time.Hour), Action: "restack"},
+	}, now)
+	got := ansi.Strip(renderDetail(&gt.Branch{Name: "feature-a"}, "main", history, detailWidth, 10))
+	for _, want := range []string{"history", "submitted 2h ago", "restacked yesterday"} {
+		if !containsString(got, want) {
+			t.Errorf("detail should contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestTimeAgo(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		ago  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{23 * time.Hour, "23h ago"},
+		{36 * time.Hour, "yesterday"},
+		{5 * 24 * time.Hour, "5d ago"},
+		{60 * 24 * time.Hour, "on Aug 17"},
+	} {
+		if got := timeAgo(now.Add(-tc.ago), now); got != tc.want {
+			t.Errorf("timeAgo(%v) = %q, want %q", tc.ago, got, tc.want)
+		}
+	}
+}
+
+func TestRenderDetail_NilBranch(t *testing.T) {
+	got := renderDetail(nil, "", nil, detailWidth, 5)
+	if h := lipgloss.Height(got); h != 5 {
+		t.Errorf("height = %d, want 5", h)
+	}
+}
+
+func TestDetailPanel_WideTerminalOnly(t *testing.T) {
+	content := "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"
+
+	m := loadedModel(content)
+	if m.detailVisible() {
+		t.Error("detail panel should be hidden at 80 columns")
+	}
+
+	m = sendWindowSize(m, 140, 24)
+	if !m.detailVisible() {
+		t.Fatal("detail panel should be visible at 140 columns")
+	}
+	if m.viewport.Width != 140-detailWidth {
+		t.Errorf("viewport width = %d, want %d", m.viewport.Width, 140-detailWidth)
+	}
+	if !containsString(m.View(), "parent") {
+		t.Error("view should contain detail panel")
+	}
+}
+
+func TestDetailPanel_Toggle(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n◯─┘  main")
+	m = sendWindowSize(m, 140, 24)
+
+	m = sendKey(m, 'v')
+	if m.detailVisible() {
+		t.Fatal("v should hide the detail panel")
+	}
+	if m.viewport.Width != 140 {
+		t.Errorf("viewport width = %d, want full width", m.viewport.Width)
+	}
+
+	m = sendKey(m, 'v')
+	if !m.detailVisible() {
+		t.Error("v should show the detail panel again")
+	}
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// maxCachedDiffs bounds how many closed diffs diffCache keeps.
+const maxCachedDiffs = 10
+
+// diffCache keeps the state of recently closed diffs by branch and path
+// scope, so reopening one resumes on the file, panel and scroll it was
+// left at instead of the first file.
+type diffCache struct {
+	views map[string]diffView
+	order []string // keys, least recently closed first
+}
+
+// diffCacheKey identifies a diff for diffCache. Combined diffs are keyed
+// by their joined branch names.
+func diffCacheKey(d diffView) string {
+	return d.branchName + "\x00" + d.scope
+}
+
+// put keeps d, evicting the diff closed longest ago when full.
+func (c *diffCache) put(d diffView) {
+	if d.branchName == "" {
+		return
+	}
+	if c.views == nil {
+		c.views = make(map[string]diffView)
+	}
+	key := diffCacheKey(d)
+	c.order = slices.DeleteFunc(c.order, func(k string) bool { return k == key })
+	c.order = append(c.order, key)
+	c.views[key] = d
+	if len(c.order) > maxCachedDiffs {
+		delete(c.views, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// branchHeads maps every branch in the tree to its head SHA, where known.
+func branchHeads(branches []*gt.Branch) map[string]string {
+	heads := make(map[string]string)
+	var walk func(b *gt.Branch)
+	walk = func(b *gt.Branch) {
+		if b.Head != "" {
+			heads[b.Name] = b.Head
+		}
+		for _, child := range b.Children {
+			walk(child)
+		}
+	}
+	for _, root := range branches {
+		walk(root)
+	}
+	return heads
+}
+
+// diffHeads returns the head SHAs of the branches d compares, or "" if any
+// is unknown. Loaded file diffs are only reused while it is unchanged.
+func diffHeads(d diffView, heads map[string]string) string {
+	names := []string{d.parentBranch, d.branchName}
+	if len(d.parts) > 0 {
+		names = nil
+		for _, p := range d.parts {
+			names = append(names, p.parent, p.branch)
+		}
+	}
+	shas := make([]string, len(names))
+	for i, name := range names {
+		if shas[i] = heads[name]; shas[i] == "" {
+			return ""
+		}
+	}
+	return strings.Join(shas, ",")
+}
+
+// resume carries over what the cached diff for the same branch and scope
+// had: the file under the cursor, if still in the diff, and the focused
+// panel. While neither branch has moved, the loaded file diffs and the
+// diff's scroll come too.
+func (d *diffView) resume(cached diffView) {
+	if len(cached.files) == 0 {
+		return
+	}
+	path := cached.files[min(cached.fileCursor, len(cached.files)-1)].path
+	i := slices.IndexFunc(d.files, func(f diffFileEntry) bool { return f.path == path })
+	if i < 0 {
+		return
+	}
+	d.fileCursor = i
+	d.focusedPanel = cached.focusedPanel
+	if d.heads == "" || d.heads != cached.heads {
+		return
+	}
+	d.loaded = cached.loaded
+	if content, ok := d.loaded[path]; ok {
+		d.setDiffContent(content)
+		d.diffViewport.SetYOffset(cached.diffViewport.YOffset)
+	}
+}
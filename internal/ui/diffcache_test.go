@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// setHeads gives every branch in m's tree a head SHA of prefix+name.
+func setHeads(m Model, prefix string) {
+	var walk func(b *gt.Branch)
+	walk = func(b *gt.Branch) {
+		b.Head = prefix + b.Name
+		for _, child := range b.Children {
+			walk(child)
+		}
+	}
+	for _, root := range m.branches {
+		walk(root)
+	}
+}
+
+// openCacheDiff opens feature-top's diff of three files.
+func openCacheDiff(m Model) (Model, tea.Cmd) {
+	updated, cmd := m.Update(diffDataMsg{
+		branchName:   "feature-top",
+		parentBranch: "main",
+		files:        []diffFileEntry{{path: "a.go"}, {path: "b.go"}, {path: "c.go"}},
+	})
+	return updated.(Model), cmd
+}
+
+// leaveDiffOnB moves to b.go, loads a long diff for it, scrolls it and
+// closes the diff.
+func leaveDiffOnB(m Model) Model {
+	m, _ = openCacheDiff(m)
+	m = sendKey(m, 'j')
+	var lines []string
+	for i := range 100 {
+		lines = append(lines, fmt.Sprintf("+line %d", i))
+	}
+	updated, _ := m.Update(diffFileContentMsg{session: m.diff.session, file: "b.go", content: strings.Join(lines, "\n")})
+	m = updated.(Model)
+	m = sendSpecialKey(m, tea.KeyTab)
+	for range 7 {
+		m = sendKey(m, 'j')
+	}
+	return sendSpecialKey(m, tea.KeyEscape)
+}
+
+func TestDiffCache_ResumesUnchangedBranch(t *testing.T) {
+	m := loadedDiffModel("│ ◉  feature-top\n◯─┘  main")
+	setHeads(m, "sha-")
+	m = leaveDiffOnB(m)
+
+	m, cmd := openCacheDiff(m)
+	if m.diff.fileCursor != 1 || m.diff.focusedPanel != panelDiff {
+		t.Fatalf("file %d, panel %d; want b.go with the diff focused", m.diff.fileCursor, m.diff.focusedPanel)
+	}
+	if loadsDiffFile(cmd) {
+		t.Error("an unchanged branch's loaded file should not load again")
+	}
+	if !strings.HasPrefix(m.diff.content, "+line 0") || m.diff.diffViewport.YOffset != 7 {
+		t.Errorf("scroll = %d, want b.go's diff shown at 7", m.diff.diffViewport.YOffset)
+	}
+}
+
+func TestDiffCache_ReloadsMovedBranch(t *testing.T) {
+	m := loadedDiffModel("│ ◉  feature-top\n◯─┘  main")
+	setHeads(m, "sha-")
+	m = leaveDiffOnB(m)
+	setHeads(m, "new-")
+
+	m, cmd := openCacheDiff(m)
+	if m.diff.fileCursor != 1 {
+		t.Errorf("file %d, want the cursor kept on b.go", m.diff.fileCursor)
+	}
+	if m.diff.content != "" || !loadsDiffFile(cmd) {
+		t.Error("after the branch moved, b.go's diff should load again")
+	}
+}
+
+func TestDiffCache_UnknownHeadsReload(t *testing.T) {
+	m := loadedDiffModel("│ ◉  feature-top\n◯─┘  main")
+	m = leaveDiffOnB(m)
+
+	m, cmd := openCacheDiff(m)
+	if m.diff.fileCursor != 1 || !loadsDiffFile(cmd) {
+		t.Error("without head SHAs the cursor should be kept and the file loaded again")
+	}
+}
+
+func TestDiffCache_FileGoneStartsAtTop(t *testing.T) {
+	m := loadedDiffModel("│ ◉  feature-top\n◯─┘  main")
+	m = leaveDiffOnB(m)
+
+	updated, _ := m.Update(diffDataMsg{
+		branchName:   "feature-top",
+		parentBranch: "main",
+		files:        []diffFileEntry{{path: "a.go"}, {path: "c.go"}},
+	})
+	m = updated.(Model)
+	if m.diff.fileCursor != 0 || m.diff.focusedPanel != panelFileList {
+		t.Errorf("file %d, panel %d; want a fresh start when b.go left the diff", m.diff.fileCursor, m.diff.focusedPanel)
+	}
+}
+
+func TestDiffView_RevisitedFileNotReloaded(t *testing.T) {
+	m := loadedDiffModel("│ ◉  feature-top\n◯─┘  main")
+	m, _ = openCacheDiff(m)
+	updated, _ := m.Update(diffFileContentMsg{session: m.diff.session, file: "a.go", content: "+a"})
+	m = updated.(Model)
+	m = sendKey(m, 'j')
+
+	// b.go's load is overtaken by moving back to a.go.
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'k'}}))
+	m = updated.(Model)
+	if loadsDiffFile(cmd) {
+		t.Error("a.go was loaded already")
+	}
+	updated, _ = m.Update(diffFileContentMsg{session: m.diff.session, file: "b.go", content: "+b"})
+	m = updated.(Model)
+	if m.diff.content != "+a" {
+		t.Errorf("content = %q, want a.go's diff kept when b.go's arrives late", m.diff.content)
+	}
+}
+
+func TestDiffCache_EvictsOldest(t *testing.T) {
+	var c diffCache
+	for i := range maxCachedDiffs + 1 {
+		c.put(diffView{branchName: fmt.Sprintf("b%d", i)})
+	}
+	if _, ok := c.views[diffCacheKey(diffView{branchName: "b0"})]; ok || len(c.views) != maxCachedDiffs {
+		t.Errorf("kept %d diffs, want %d without the oldest", len(c.views), maxCachedDiffs)
+	}
+	c.put(diffView{branchName: "b1"})
+	c.put(diffView{branchName: "b11"})
+	if _, ok := c.views[diffCacheKey(diffView{branchName: "b1"})]; !ok {
+		t.Error("closing b1 again should make it the newest")
+	}
+}
+
+func TestDiffHeads(t *testing.T) {
+	heads := map[string]string{"main": "m1", "a": "a1", "b": "b1"}
+	if got := diffHeads(diffView{branchName: "a", parentBranch: "main"}, heads); got != "m1,a1" {
+		t.Errorf("single = %q", got)
+	}
+	combined := diffView{parts: []diffPart{{parent: "main", branch: "a"}, {parent: "a", branch: "b"}}}
+	if got := diffHeads(combined, heads); got != "m1,a1,a1,b1" {
+		t.Errorf("combined = %q", got)
+	}
+	if got := diffHeads(diffView{branchName: "c", parentBranch: "main"}, heads); got != "" {
+		t.Errorf("unknown head = %q, want empty", got)
+	}
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
@@ -15,6 +17,20 @@ const (
 	modeTree viewMode = iota
 	modeDiff
 	modeHelp
+	modePreflight
+	modeJobs
+	modeDebug
+	modeOverlaps
+	modeCleanup
+	modeConfirm
+	modeSplit
+	modeConflict
+	modeBulkCleanup
+	modeCommit
+	modeSyncPreview
+	modePicker
+	modeComments
+	modeStats
 )
 
 // diffPanel tracks which panel has focus in the diff view.
@@ -27,20 +43,32 @@ const (
 
 // diffFileEntry represents a single file from git diff --stat output.
 type diffFileEntry struct {
-	path    string
-	summary string // e.g. "5 +++--"
+	path     string
+	summary  string   // e.g. "5 +++--"
+	branches []string // combined diffs: marked branches that touch the file
 }
 
 // diffView holds all state for the diff view.
 type diffView struct {
 	branchName   string
 	parentBranch string
+	scope        string     // path scope the file list is limited to, "" for none
+	parts        []diffPart // combined diff of marked branches; nil for a single branch
 	files        []diffFileEntry
 	fileCursor   int
+	content      string            // diff of the selected file, as loaded
+	loaded       map[string]string // file diffs loaded so far, by path
+	heads        string            // head SHAs of the compared branches, see diffHeads
 	diffViewport viewport.Model
 	focusedPanel diffPanel
 	width        int
 	height       int
+
+	// session numbers the diff opened, so file loads finishing after it
+	// closed are dropped; ctx is cancelled when it closes.
+	session int
+	ctx     context.Context
+	cancel  context.CancelFunc
 }
 
 const (
@@ -75,6 +103,14 @@ func newDiffView(width, height int) diffView {
 	return d
 }
 
+// context returns the context the diff's file loads run under.
+func (d diffView) context() context.Context {
+	if d.ctx == nil {
+		return context.Background()
+	}
+	return d.ctx
+}
+
 func (d *diffView) setSize(width, height int) {
 	d.width = width
 	d.height = height
@@ -109,10 +145,42 @@ func (d *diffView) setFiles(files []diffFileEntry) {
 }
 
 func (d *diffView) setDiffContent(content string) {
+	d.content = content
 	d.diffViewport.SetContent(content)
 	d.diffViewport.SetYOffset(0)
 }
 
+// remember records file's loaded diff, shown again without reloading when
+// the cursor comes back to it.
+func (d *diffView) remember(file, content string) {
+	if d.loaded == nil {
+		d.loaded = make(map[string]string)
+	}
+	d.loaded[file] = content
+}
+
+// fileLoaded reports whether the diff of the file under the cursor has
+// been loaded.
+func (d diffView) fileLoaded() bool {
+	if d.fileCursor >= len(d.files) {
+		return false
+	}
+	_, ok := d.loaded[d.files[d.fileCursor].path]
+	return ok
+}
+
+// selectFile moves the cursor to file i and shows its diff if it was
+// loaded before, reporting whether it still needs loading.
+func (d *diffView) selectFile(i int) bool {
+	d.fileCursor = i
+	if content, ok := d.loaded[d.files[i].path]; ok {
+		d.setDiffContent(content)
+		return false
+	}
+	d.setDiffContent("")
+	return true
+}
+
 // ensureFileCursorVisible returns the offset for the file list so the cursor is visible.
 func (d diffView) fileListOffset() int {
 	listHeight := d.height - 1 // minus header
@@ -139,8 +207,11 @@ func (d diffView) view() string {
 	}
 
 	fileHeader := fileHeaderStyle.Render(truncateToWidth("Files", fileListWidth))
-	diffHeader := diffHeaderSt.Render(truncateToWidth(
-		"Diff: "+d.branchName+" (vs "+d.parentBranch+")", diffWidth))
+	title := "Diff: " + d.branchName + " (vs " + d.parentBranch
+	if d.scope != "" {
+		title += ", in " + d.scope
+	}
+	diffHeader := diffHeaderSt.Render(truncateToWidth(title+")", diffWidth))
 
 	// Render file list.
 	listHeight := d.height - 1
@@ -159,9 +230,15 @@ func (d diffView) view() string {
 		}
 		for i := offset; i < end; i++ {
 			name := d.files[i].path
+			overlap := len(d.files[i].branches) > 1
+			if overlap {
+				name += fmt.Sprintf(" ×%d", len(d.files[i].branches))
+			}
 			displayName := truncateToWidth(name, fileListWidth)
 			if i == d.fileCursor {
 				fileLines = append(fileLines, diffFileSelectedStyle.Render(padToWidth(displayName, fileListWidth)))
+			} else if overlap {
+				fileLines = append(fileLines, diffOverlapStyle.Render(padToWidth(displayName, fileListWidth)))
 			} else {
 				fileLines = append(fileLines, diffFileStyle.Render(padToWidth(displayName, fileListWidth)))
 			}
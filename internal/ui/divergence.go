@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var unsubmittedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true)
+
+// prOpen reports whether pr is an open or draft PR, i.e. one that a
+// resubmit would update.
+func prOpen(pr gt.PRInfo) bool {
+	switch strings.ToUpper(pr.State) {
+	case "OPEN", "DRAFT":
+		return true
+	}
+	return false
+}
+
+// unsubmitted reports whether b's local head differs from the commit its
+// open PR shows, meaning reviewers are looking at stale code. It is false
+// whenever either SHA is unknown.
+func unsubmitted(b *gt.Branch) bool {
+	return prOpen(b.PR) && b.PR.HeadSHA != "" && b.Head != "" && b.PR.HeadSHA != b.Head
+}
+
+// unsubmittedLabelPlain returns an unstyled badge for branches whose local
+// head has not been submitted to their PR, or "".
+func unsubmittedLabelPlain(b *gt.Branch) string {
+	if !unsubmitted(b) {
+		return ""
+	}
+	return " ⇡ unsubmitted"
+}
+
+// unsubmittedLabel returns a styled unsubmitted badge, or empty string.
+func unsubmittedLabel(b *gt.Branch) string {
+	plain := unsubmittedLabelPlain(b)
+	if plain == "" {
+		return ""
+	}
+	return " " + unsubmittedStyle.Render(plain[1:])
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestUnsubmitted(t *testing.T) {
+	tests := []struct {
+		name string
+		b    gt.Branch
+		want bool
+	}{
+		{"heads differ", gt.Branch{Head: "bbb", PR: gt.PRInfo{Number: 1, State: "OPEN", HeadSHA: "aaa"}}, true},
+		{"draft differs", gt.Branch{Head: "bbb", PR: gt.PRInfo{Number: 1, State: "DRAFT", HeadSHA: "aaa"}}, true},
+		{"heads match", gt.Branch{Head: "aaa", PR: gt.PRInfo{Number: 1, State: "OPEN", HeadSHA: "aaa"}}, false},
+		{"merged PR", gt.Branch{Head: "bbb", PR: gt.PRInfo{Number: 1, State: "MERGED", HeadSHA: "aaa"}}, false},
+		{"PR head unknown", gt.Branch{Head: "bbb", PR: gt.PRInfo{Number: 1, State: "OPEN"}}, false},
+		{"local head unknown", gt.Branch{PR: gt.PRInfo{Number: 1, State: "OPEN", HeadSHA: "aaa"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unsubmitted(&tt.b); got != tt.want {
+				t.Errorf("unsubmitted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBranchLabel_Unsubmitted(t *testing.T) {
+	b := &gt.Branch{Name: "feature", Head: "bbb", PR: gt.PRInfo{Number: 7, State: "OPEN", HeadSHA: "aaa"}}
+	if got := ansi.Strip(branchLabel(b)); got != "◯ feature #7 open ⇡ unsubmitted" {
+		t.Errorf("branchLabel = %q", got)
+	}
+	if got := ansi.Strip(selectedBranchLabel(b, selectedBranchStyle)); got != "◯ feature #7 open ⇡ unsubmitted" {
+		t.Errorf("selectedBranchLabel = %q", got)
+	}
+}
+
+func TestPRInfoJob_FetchesHeadForOpenPRs(t *testing.T) {
+	mock, calls := recordingMock()
+	var mu sync.Mutex // branches are fetched concurrently
+	mock.fn = func(ctx context.Context, name string, args ...string) (string, error) {
+		mu.Lock()
+		*calls = append(*calls, callRecord{name: name, args: args})
+		mu.Unlock()
+		if name == "gh" {
+			return `{"headRefOid":"abc"}`, nil
+		}
+		if args[3] == "feature-top" {
+			return `{"prNumber": 2, "state": "OPEN"}`, nil
+		}
+		return `{"prNumber": 1, "state": "MERGED"}`, nil
+	}
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.gtClient = gt.New(mock)
+
+	msg := runJob(m.prInfoJob()).(prInfoResultMsg)
+	if got := msg.infos["feature-top"].HeadSHA; got != "abc" {
+		t.Errorf("feature-top HeadSHA = %q, want abc", got)
+	}
+	var ghCalls [][]string
+	var threadCalls []string
+	for _, c := range *calls {
+		switch {
+		case c.name == "gh" && c.args[0] == "api":
+			threadCalls = append(threadCalls, c.args[7])
+		case c.name == "gh":
+			ghCalls = append(ghCalls, c.args)
+		}
+	}
+	want := [][]string{{"pr", "view", "feature-top", "--json", "headRefOid,labels,assignees,statusCheckRollup,mergeable"}}
+	if !reflect.DeepEqual(ghCalls, want) {
+		t.Errorf("gh calls = %v, want only the open PR's head lookup", ghCalls)
+	}
+	if !reflect.DeepEqual(threadCalls, []string{"number=2"}) {
+		t.Errorf("thread calls = %v, want only the open PR's threads counted", threadCalls)
+	}
+}
+
+func TestPRInfoJob_FallsBackToGitHub(t *testing.T) {
+	var mu sync.Mutex // branches are fetched concurrently
+	var lists []string
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if name == "gt" {
+			return "", nil // older gt versions print nothing
+		}
+		if args[1] == "list" {
+			mu.Lock()
+			lists = append(lists, args[3])
+			mu.Unlock()
+			if args[3] == "feature-top" {
+				return `[{"number":9,"state":"MERGED","isDraft":false,"url":"https://github.com/acme/app/pull/9"}]`, nil
+			}
+			return "[]", nil
+		}
+		return "", errors.New("unexpected")
+	}}
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.gtClient = gt.New(mock)
+
+	msg := runJob(m.prInfoJob()).(prInfoResultMsg)
+	if got := msg.infos["feature-top"]; got.Number != 9 || got.State != "MERGED" || got.URL == "" {
+		t.Errorf("feature-top = %+v, want PR #9 from gh", got)
+	}
+	if got := msg.infos["feature-base"]; got.Number != 0 {
+		t.Errorf("feature-base = %+v, want no PR", got)
+	}
+	if !slices.Contains(lists, "feature-base") {
+		t.Errorf("gh pr list calls = %v, want one per branch", lists)
+	}
+}
+
+func TestResubmitKey(t *testing.T) {
+	mock, calls := recordingMock()
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.gtClient = gt.New(mock)
+	m.cursor = 0 // feature-top
+
+	m = sendKey(m, 'U')
+	if m.running || !strings.Contains(m.statusBar.message, "up to date") {
+		t.Fatalf("branch without divergence: running=%v message=%q", m.running, m.statusBar.message)
+	}
+
+	branch := m.selectedBranch()
+	branch.Head = "local"
+	branch.PR = gt.PRInfo{Number: 2, State: "OPEN", HeadSHA: "remote"}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'U'}}))
+	m = updated.(Model)
+	if !m.running {
+		t.Fatal("resubmit should start")
+	}
+	m, cmd = finishUpstreamCheck(t, m, cmd)
+	runBatch(cmd)
+	want := callRecord{name: "gt", args: []string{"downstack", "submit", "--no-interactive", "--branch", "feature-top"}}
+	found := false
+	for _, c := range *calls {
+		if reflect.DeepEqual(c, want) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("calls = %v, want downstack submit of feature-top", *calls)
+	}
+}
+
+func TestDetailRows_Unsubmitted(t *testing.T) {
+	b := &gt.Branch{Name: "feature", Head: "bbbbbbbbbb", PR: gt.PRInfo{Number: 7, State: "OPEN", HeadSHA: "aaaaaaaaaa"}}
+	for _, r := range detailRows(b, "main") {
+		if r.label == "PR head" {
+			if !strings.HasPrefix(r.value, "aaaaaaa,") {
+				t.Errorf("PR head row = %q", r.value)
+			}
+			return
+		}
+	}
+	t.Error("expected a PR head row")
+}
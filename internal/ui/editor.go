@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorCommand returns the command opening path in the user's editor:
+// $VISUAL, then $EDITOR, then vi. The variables may include arguments,
+// e.g. "code --wait".
+func editorCommand(getenv func(string) string, path string) *exec.Cmd {
+	editor := getenv("VISUAL")
+	if editor == "" {
+		editor = getenv("EDITOR")
+	}
+	words := strings.Fields(editor)
+	if len(words) == 0 {
+		words = []string{"vi"}
+	}
+	return exec.Command(words[0], append(words[1:], path)...)
+}
+
+// editText lets the user edit text in their editor, suspending the UI
+// until it exits. The text goes in a temporary file named after pattern
+// (see os.CreateTemp), so the editor can pick its syntax from the
+// extension, and is removed afterwards. done turns the edited text, or
+// the error that stopped the edit, into the message reporting it.
+func editText(text, pattern string, done func(edited string, err error) tea.Msg) tea.Cmd {
+	path, err := writeTemp(text, pattern)
+	if err != nil {
+		return func() tea.Msg { return done("", err) }
+	}
+	return tea.ExecProcess(editorCommand(os.Getenv, path), func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return done("", err)
+		}
+		edited, err := os.ReadFile(path)
+		return done(string(edited), err)
+	})
+}
+
+// writeTemp writes text to a new temporary file and returns its path.
+func writeTemp(text, pattern string) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString(text)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// prTextMsg carries the title and body of the PR about to be edited.
+type prTextMsg struct {
+	branch string
+	number int
+	text   gt.PRText
+	err    error
+}
+
+// prEditedMsg is sent when the editor showing a PR's title and body exits.
+type prEditedMsg struct {
+	branch string
+	number int
+	before gt.PRText // as loaded, to tell whether anything changed
+	edited string    // the file as saved
+	err    error
+}
+
+// startPREdit loads the selected branch's PR title and body to edit them
+// in the user's editor.
+func (m *Model) startPREdit() []tea.Cmd {
+	branch := m.selectedBranch()
+	if branch == nil {
+		return nil
+	}
+	if branch.PR.Number == 0 {
+		m.statusBar.setStatus(severityWarning, "No PR for "+branch.Name)
+		return nil
+	}
+	name, number := branch.Name, branch.PR.Number
+	m.running = true
+	spinnerCmd := m.statusBar.startSpinner(fmt.Sprintf("Loading #%d...", number))
+	client := m.gtClient
+	return []tea.Cmd{spinnerCmd, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		text, err := client.PRText(ctx, name)
+		return prTextMsg{branch: name, number: number, text: text, err: err}
+	}}
+}
+
+// editPRText opens the loaded title and body in the editor, the title on
+// the first line and the body below a blank line.
+func (m *Model) editPRText(msg prTextMsg) tea.Cmd {
+	m.running = false
+	m.statusBar.stopSpinner()
+	if msg.err != nil {
+		m.statusBar.setStatus(severityError, fmt.Sprintf("Could not load #%d: %s", msg.number, msg.err))
+		return nil
+	}
+	return editText(gt.FormatPRText(msg.text), "grit-pr-*.md", func(edited string, err error) tea.Msg {
+		return prEditedMsg{branch: msg.branch, number: msg.number, before: msg.text, edited: edited, err: err}
+	})
+}
+
+// finishPREdit pushes the edited title and body with `gh pr edit`, unless
+// the title was emptied or nothing changed.
+func (m *Model) finishPREdit(msg prEditedMsg) []tea.Cmd {
+	if msg.err != nil {
+		m.statusBar.setStatus(severityError, "Editor failed: "+msg.err.Error())
+		return nil
+	}
+	text := gt.ParsePRText(msg.edited)
+	switch {
+	case text.Title == "":
+		m.statusBar.setStatus(severityWarning, fmt.Sprintf("Empty title — #%d left unchanged", msg.number))
+		return nil
+	case text == gt.ParsePRText(gt.FormatPRText(msg.before)):
+		m.statusBar.setStatus(severityInfo, fmt.Sprintf("#%d unchanged", msg.number))
+		return nil
+	}
+	name := msg.branch
+	return m.startAction("edit-pr", fmt.Sprintf("Updated #%d", msg.number), fmt.Sprintf("Updating #%d...", msg.number), "", func(ctx context.Context, client *gt.Client) error {
+		return client.EditPR(ctx, name, text)
+	})
+}
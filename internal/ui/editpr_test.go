@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func prEditModel(t *testing.T) (Model, *[]callRecord) {
+	t.Helper()
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n◯─┘  main"})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{"feature-top": {Number: 7, State: "OPEN"}}})
+	return updated.(Model), calls
+}
+
+func TestEditPR_LoadsTextThenOpensEditor(t *testing.T) {
+	m, calls := prEditModel(t)
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlE}))
+	m = updated.(Model)
+	if !m.running {
+		t.Fatal("ctrl+e should start loading the PR text")
+	}
+	runBatch(cmd)
+	want := []string{"pr", "view", "feature-top", "--json", "title,body"}
+	if len(*calls) != 1 || (*calls)[0].name != "gh" || !slices.Equal((*calls)[0].args, want) {
+		t.Fatalf("calls = %v, want gh %v", *calls, want)
+	}
+
+	updated, cmd = m.Update(prTextMsg{branch: "feature-top", number: 7, text: gt.PRText{Title: "Old", Body: "Body"}})
+	m = updated.(Model)
+	if m.running || cmd == nil {
+		t.Errorf("running = %v, cmd = %v, want the editor opened", m.running, cmd)
+	}
+}
+
+func TestEditPR_NeedsPR(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n◯─┘  main")
+	m = sendSpecialKey(m, tea.KeyCtrlE)
+	if m.running || m.statusBar.message != "No PR for feature-top" {
+		t.Errorf("running = %v, message = %q", m.running, m.statusBar.message)
+	}
+}
+
+func TestEditPR_PushesEditedText(t *testing.T) {
+	m, calls := prEditModel(t)
+	updated, cmd := m.Update(prEditedMsg{
+		branch: "feature-top",
+		number: 7,
+		before: gt.PRText{Title: "Old", Body: "Body"},
+		edited: "New title\n\nNew body\n",
+	})
+	m = updated.(Model)
+	runBatch(cmd)
+	want := []string{"pr", "edit", "feature-top", "--title", "New title", "--body", "New body"}
+	if len(*calls) != 1 || !slices.Equal((*calls)[0].args, want) {
+		t.Errorf("calls = %v, want gh %v", *calls, want)
+	}
+}
+
+func TestEditPR_SkipsEmptyOrUnchanged(t *testing.T) {
+	before := gt.PRText{Title: "Old", Body: "Body"}
+	for _, tc := range []struct{ edited, want string }{
+		{"\n  \n", "Empty title — #7 left unchanged"},
+		{gt.FormatPRText(before) + "\n", "#7 unchanged"},
+	} {
+		m, calls := prEditModel(t)
+		updated, _ := m.Update(prEditedMsg{branch: "feature-top", number: 7, before: before, edited: tc.edited})
+		m = updated.(Model)
+		if m.running || len(*calls) != 0 || m.statusBar.message != tc.want {
+			t.Errorf("edited %q: running = %v, calls = %v, message = %q, want %q", tc.edited, m.running, *calls, m.statusBar.message, tc.want)
+		}
+	}
+}
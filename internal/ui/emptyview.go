@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	emptyTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	emptyTextStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// hasStacks reports whether any branch besides trunk is tracked.
+func hasStacks(entries []displayEntry) bool {
+	tracked := 0
+	for _, e := range entries {
+		if !e.untracked && e.group == "" {
+			tracked++
+		}
+	}
+	return tracked > 1
+}
+
+// renderEmptyState renders the onboarding screen shown below the trunk line
+// when there are no stacks yet, with the keys k binds. needsInit is set when
+// gt reported that the repo has not been initialized, in which case branch
+// creation is not offered.
+func renderEmptyState(trunk string, needsInit bool, k keyMap) string {
+	var sb strings.Builder
+	if needsInit {
+		sb.WriteString(emptyTitleStyle.Render("Graphite is not initialized in this repository."))
+	} else {
+		sb.WriteString(emptyTitleStyle.Render("No stacks yet."))
+	}
+	sb.WriteString("\n\n")
+
+	var entries []helpEntry
+	if !needsInit && trunk != "" {
+		entries = append(entries, helpEntry{k.Create.Help().Key, "Create your first branch on " + trunk})
+	}
+	entries = append(entries,
+		helpEntry{k.Fetch.Help().Key, "Fetch (gt repo sync)"},
+		helpEntry{k.RepoInit.Help().Key, "Initialize Graphite (gt repo init)"},
+	)
+	for _, e := range entries {
+		sb.WriteString("  ")
+		sb.WriteString(helpKeyStyle.Render(e.key))
+		sb.WriteString(helpDescStyle.Render(e.desc))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(emptyTextStyle.Render("Branches created with gt in another terminal will appear here automatically."))
+	return sb.String()
+}
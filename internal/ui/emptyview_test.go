@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestHasStacks(t *testing.T) {
+	if hasStacks(nil) {
+		t.Error("no entries should have no stacks")
+	}
+	if hasStacks(make([]displayEntry, 1)) {
+		t.Error("trunk alone should have no stacks")
+	}
+	if !hasStacks(make([]displayEntry, 2)) {
+		t.Error("trunk plus a branch should have stacks")
+	}
+}
+
+func TestRenderEmptyState_OffersActions(t *testing.T) {
+	got := ansi.Strip(renderEmptyState("main", false, defaultKeyMap()))
+	for _, want := range []string{"No stacks yet", "Create your first branch on main", "Fetch", "Initialize Graphite"} {
+		if !containsString(got, want) {
+			t.Errorf("should contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderEmptyState_NeedsInit(t *testing.T) {
+	got := ansi.Strip(renderEmptyState("", true, defaultKeyMap()))
+	if !containsString(got, "not initialized") {
+		t.Errorf("should explain init, got:\n%s", got)
+	}
+	if containsString(got, "Create your first branch") {
+		t.Error("should not offer branch creation before init")
+	}
+}
+
+func TestEmptyState_ShowsReboundKeys(t *testing.T) {
+	m := NewWithConfig(gt.New(simpleMock("◉  main", nil)), "", config.Config{Keys: map[string][]string{"create": {"ctrl+n"}}})
+	m = sendWindowSize(m, 100, 30)
+	updated, _ := m.Update(logResultMsg{output: "◉  main"})
+	m = updated.(Model)
+	got := ansi.Strip(m.View())
+	if !containsString(got, "ctrl+n") || !containsString(got, "Create your first branch on main") {
+		t.Errorf("empty view should offer create on ctrl+n, got:\n%s", got)
+	}
+	if containsString(got, "  c ") {
+		t.Errorf("empty view still shows the default key c:\n%s", got)
+	}
+}
@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// filtersFile is where the tree filters are persisted, relative to the git dir.
+const filtersFile = "grit/filters.json"
+
+var filterBannerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Bold(true)
+
+// prStateFilters is the cycle of PR-state filters, "" showing every branch
+// and "NONE" only branches without a PR.
+var prStateFilters = []string{"", "OPEN", "DRAFT", "NONE"}
+
+// branchFilter narrows the tree to the branches worth looking at. It is
+// saved per repository so it survives restarts.
+type branchFilter struct {
+	HideMerged bool   `json:"hideMerged,omitempty"`
+	PRState    string `json:"prState,omitempty"` // one of prStateFilters
+	Text       string `json:"text,omitempty"`    // case-insensitive branch name substring
+}
+
+// active reports whether any filter is set.
+func (f branchFilter) active() bool {
+	return f.HideMerged || f.PRState != "" || f.Text != ""
+}
+
+// matches reports whether b passes every filter.
+func (f branchFilter) matches(b *gt.Branch) bool {
+	if f.HideMerged && strings.EqualFold(b.PR.State, "MERGED") {
+		return false
+	}
+	switch f.PRState {
+	case "":
+	case "NONE":
+		if b.PR.Number != 0 {
+			return false
+		}
+	default:
+		if !strings.EqualFold(b.PR.State, f.PRState) {
+			return false
+		}
+	}
+	return f.Text == "" || strings.Contains(strings.ToLower(b.Name), strings.ToLower(f.Text))
+}
+
+// nextPRState returns the PR-state filter after state in the cycle.
+func nextPRState(state string) string {
+	for i, s := range prStateFilters {
+		if s == state {
+			return prStateFilters[(i+1)%len(prStateFilters)]
+		}
+	}
+	return ""
+}
+
+// prStateLabel describes a PR-state filter for the banner and status bar.
+func prStateLabel(state string) string {
+	switch state {
+	case "":
+		return "all PRs"
+	case "NONE":
+		return "no PR"
+	}
+	return strings.ToLower(state) + " PRs"
+}
+
+// description lists the active filters, e.g. `merged hidden, open PRs`.
+func (f branchFilter) description() string {
+	var parts []string
+	if f.HideMerged {
+		parts = append(parts, "merged hidden")
+	}
+	if f.PRState != "" {
+		parts = append(parts, prStateLabel(f.PRState))
+	}
+	if f.Text != "" {
+		parts = append(parts, "name contains \""+f.Text+"\"")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// loadFilters reads the saved filters from gitDir. A missing or unreadable
+// file means no filters.
+func loadFilters(gitDir string) branchFilter {
+	var f branchFilter
+	if gitDir == "" {
+		return f
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, filtersFile))
+	if err != nil {
+		return f
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return branchFilter{}
+	}
+	return f
+}
+
+// saveFilters writes f to gitDir.
+func saveFilters(gitDir string, f branchFilter) error {
+	if gitDir == "" {
+		return nil
+	}
+	path := filepath.Join(gitDir, filtersFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// visibleBranches returns the names of the branches that pass f, along with
+// their ancestors so the tree keeps its shape. Trunk is always visible.
+func visibleBranches(branches []*gt.Branch, f branchFilter) map[string]bool {
+	visible := make(map[string]bool)
+	var walk func(b *gt.Branch) bool
+	walk = func(b *gt.Branch) bool {
+		keep := f.matches(b)
+		for _, child := range b.Children {
+			if walk(child) {
+				keep = true
+			}
+		}
+		if keep {
+			visible[b.Name] = true
+		}
+		return keep
+	}
+	for _, root := range branches {
+		walk(root)
+		visible[root.Name] = true
+	}
+	return visible
+}
+
+// withFilter drops the entries hidden by f. Untracked branches are matched
+// on their own, having no place in the tree.
+func withFilter(entries []displayEntry, branches []*gt.Branch, f branchFilter) []displayEntry {
+	if !f.active() {
+		return entries
+	}
+	visible := visibleBranches(branches, f)
+	var kept []displayEntry
+	for _, e := range entries {
+		if e.untracked && f.matches(e.branch) || !e.untracked && visible[e.branch.Name] {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// renderFilterBanner renders the line shown above the tree while filters
+// are active, or "" when none are.
+func renderFilterBanner(f branchFilter, width int, clear string) string {
+	if !f.active() {
+		return ""
+	}
+	text := filterBannerStyle.Render("Filtered: "+f.description()) + "  " +
+		legendKeyStyle.Render(clear) + " " + legendDescStyle.Render("clear filters")
+	return lipgloss.NewStyle().Width(width).Padding(0, 1).Render(text)
+}
+
+// applyFilter applies f to the tree, keeping the cursor on the selected
+// branch where it is still shown, and saves it for the next session.
+func (m *Model) applyFilter(f branchFilter) {
+	name := ""
+	if b := m.selectedBranch(); b != nil {
+		name = b.Name
+	}
+	m.filter = f
+	m.resizeViewport()
+	m.rebuildEntries(name)
+	switch err := saveFilters(m.gitDir, f); {
+	case err != nil:
+		m.statusBar.setStatus(severityError, "Could not save filters: "+err.Error())
+	case f.active():
+		m.statusBar.setStatus(severitySuccess, "Filtered: "+f.description())
+	default:
+		m.statusBar.setStatus(severitySuccess, "Filters cleared")
+	}
+}
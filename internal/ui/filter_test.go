@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func entryNames(entries []displayEntry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.branch.Name)
+	}
+	return names
+}
+
+func TestBranchFilter_Matches(t *testing.T) {
+	merged := &gt.Branch{Name: "fix-login", PR: gt.PRInfo{Number: 3, State: "MERGED"}}
+	open := &gt.Branch{Name: "Auth-API", PR: gt.PRInfo{Number: 4, State: "OPEN"}}
+	none := &gt.Branch{Name: "auth-ui"}
+	tests := []struct {
+		name   string
+		filter branchFilter
+		want   []bool // merged, open, none
+	}{
+		{"none", branchFilter{}, []bool{true, true, true}},
+		{"hide merged", branchFilter{HideMerged: true}, []bool{false, true, true}},
+		{"open", branchFilter{PRState: "OPEN"}, []bool{false, true, false}},
+		{"no PR", branchFilter{PRState: "NONE"}, []bool{false, false, true}},
+		{"text ignores case", branchFilter{Text: "auth"}, []bool{false, true, true}},
+		{"combined", branchFilter{PRState: "NONE", Text: "AUTH"}, []bool{false, false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, b := range []*gt.Branch{merged, open, none} {
+				if got := tt.filter.matches(b); got != tt.want[i] {
+					t.Errorf("matches(%s) = %v, want %v", b.Name, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNextPRState_Cycles(t *testing.T) {
+	state := ""
+	var seen []string
+	for range prStateFilters {
+		state = nextPRState(state)
+		seen = append(seen, state)
+	}
+	if got := strings.Join(seen, ","); got != "OPEN,DRAFT,NONE," {
+		t.Errorf("cycle = %q", got)
+	}
+}
+
+func TestFilters_SaveLoadRoundTrip(t *testing.T) {
+	gitDir := t.TempDir()
+	if got := loadFilters(gitDir); got.active() {
+		t.Errorf("loadFilters() = %+v, want no filters with no file", got)
+	}
+	want := branchFilter{HideMerged: true, PRState: "DRAFT", Text: "auth"}
+	if err := saveFilters(gitDir, want); err != nil {
+		t.Fatalf("saveFilters: %v", err)
+	}
+	if got := loadFilters(gitDir); got != want {
+		t.Errorf("loadFilters() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWithFilter_KeepsAncestors(t *testing.T) {
+	m := loadedModel("│ ◯  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.untracked = []string{"scratch", "feature-wip"}
+	m.filter = branchFilter{Text: "top"}
+	if got := strings.Join(entryNames(m.buildEntries()), ","); got != "feature-top,feature-base,main" {
+		t.Errorf("entries = %s, want the match with its ancestors", got)
+	}
+	m.filter = branchFilter{Text: "wip"}
+	if got := strings.Join(entryNames(m.buildEntries()), ","); got != "main,feature-wip" {
+		t.Errorf("entries = %s, want trunk and the matching untracked branch", got)
+	}
+}
+
+func TestFilterKeys_PersistAndRestore(t *testing.T) {
+	gitDir := t.TempDir()
+	log := "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"
+	m := New(gt.New(simpleMock("", nil)), gitDir)
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: log})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{"feature-top": {Number: 7, State: "MERGED"}}})
+	m = updated.(Model)
+
+	m = sendKey(m, 'H')
+	if got := strings.Join(entryNames(m.displayEntries), ","); got != "feature-base,main" {
+		t.Errorf("entries = %s, want merged feature-top hidden", got)
+	}
+	if !strings.Contains(m.View(), "Filtered: merged hidden") {
+		t.Errorf("header should show the active filters:\n%s", m.View())
+	}
+
+	// A new session in the same repo restores the filters.
+	m = New(gt.New(simpleMock("", nil)), gitDir)
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+	if !m.filter.HideMerged {
+		t.Fatalf("filters were not restored: %+v", m.filter)
+	}
+
+	m = sendWindowSize(m, 80, 24)
+	updated, _ = m.Update(logResultMsg{output: log})
+	m = updated.(Model)
+	m = sendSpecialKey(m, tea.KeyCtrlG)
+	if m.filter.active() || loadFilters(gitDir).active() || strings.Contains(m.View(), "Filtered:") {
+		t.Errorf("ctrl+g should clear and save the filters, filter = %+v", m.filter)
+	}
+}
+
+func TestFilterPrompt_SetsText(t *testing.T) {
+	m := loadedModel("│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main")
+	m = sendSpecialKey(m, tea.KeyCtrlF)
+	if !m.prompt.active() {
+		t.Fatal("ctrl+f should open the filter prompt")
+	}
+	for _, r := range "zzz" {
+		m = sendKey(m, r)
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.filter.Text != "zzz" || !strings.Contains(m.View(), "No branches match the filters.") {
+		t.Errorf("filter = %+v, view:\n%s", m.filter, m.View())
+	}
+}
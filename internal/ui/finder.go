@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// finderItems lists every tracked and untracked branch by name.
+func finderItems(branches []*gt.Branch, untracked []string) []pickerItem {
+	var items []pickerItem
+	var walk func(b *gt.Branch)
+	walk = func(b *gt.Branch) {
+		items = append(items, pickerItem{name: b.Name, current: b.IsCurrent})
+		for _, child := range b.Children {
+			walk(child)
+		}
+	}
+	for _, root := range branches {
+		walk(root)
+	}
+	for _, name := range untracked {
+		items = append(items, pickerItem{name: name})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].name < items[j].name })
+	return items
+}
+
+// openFinder shows the fuzzy branch picker, which checks out the branch
+// picked. It lists every branch flat, tracked or not and regardless of the
+// tree filters.
+func (m *Model) openFinder() {
+	m.openPicker(picker{
+		title:   "Check out a branch",
+		noun:    "branches",
+		items:   finderItems(m.branches, m.untracked),
+		pick:    (*Model).checkoutFound,
+		preview: branchPreview,
+	})
+}
+
+// checkoutFound checks out the branch picked in the finder.
+func (m *Model) checkoutFound(item pickerItem) []tea.Cmd {
+	if item.current {
+		m.rebuildEntries(item.name)
+		m.statusBar.setStatus(severityInfo, "Already on "+item.name)
+		return nil
+	}
+	m.cursorTarget = item.name
+	return m.startCheckout(item.name)
+}
+
+// openMovePicker picks the new parent of the branch being moved by name,
+// listing only the branches it can move onto.
+func (m *Model) openMovePicker() {
+	var items []pickerItem
+	for _, item := range finderItems(m.branches, nil) {
+		if moveRefusal(m.branches, m.moving, item.name) == "" {
+			items = append(items, item)
+		}
+	}
+	m.openPicker(picker{
+		title:   "Move " + m.moving + " onto",
+		noun:    "branches",
+		items:   items,
+		pick:    (*Model).moveOntoPicked,
+		preview: branchPreview,
+	})
+}
+
+// moveOntoPicked moves the branch being moved onto the branch picked.
+func (m *Model) moveOntoPicked(item pickerItem) []tea.Cmd {
+	m.preserveCursor(item.name)
+	return m.finishMove()
+}
+
+// branchPreview describes a branch for a picker: its parent, PR and
+// changed files.
+func branchPreview(m Model, item pickerItem) string {
+	b := gt.FindBranch(m.branches, item.name)
+	if b == nil {
+		return "untracked"
+	}
+	var parts []string
+	if b.Parent == "" {
+		parts = append(parts, "trunk")
+	} else {
+		parts = append(parts, "on "+b.Parent)
+	}
+	if pr := prLabelPlain(b.PR); pr != "" {
+		parts = append(parts, pr)
+	}
+	if b.Changes.Loaded {
+		parts = append(parts, fmt.Sprintf("%d files changed", b.Changes.Files))
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestFinder_RanksAndIncludesUntracked(t *testing.T) {
+	m := loadedModel("│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main")
+	m.untracked = []string{"scratch"}
+	m = sendKey(m, '/')
+	if m.mode != modePicker || len(m.picker.matches) != 4 {
+		t.Fatalf("mode = %d, matches = %d, want every branch listed", m.mode, len(m.picker.matches))
+	}
+	for _, r := range "ft" {
+		m = sendKey(m, r)
+	}
+	if got := m.picker.selected(); got == nil || got.item.name != "feature-top" {
+		t.Errorf("selected = %+v, want feature-top first", got)
+	}
+	for _, r := range "zz" {
+		m = sendKey(m, r)
+	}
+	if !strings.Contains(m.View(), "0 of 4 branches") {
+		t.Errorf("view should show no matches:\n%s", m.View())
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.mode != modePicker || m.statusBar.severity != severityWarning {
+		t.Errorf("enter with no match should stay open, mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree {
+		t.Errorf("esc should close the finder, mode = %d", m.mode)
+	}
+}
+
+func TestFinder_EnterChecksOut(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	m = sendKey(m, '/')
+	for _, r := range "top" {
+		m = sendKey(m, r)
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if m.mode != modeTree || !m.running {
+		t.Fatalf("enter should start the checkout, mode = %d", m.mode)
+	}
+	if len(*calls) != 1 || strings.Join((*calls)[0].args, " ") != "checkout feature-top --no-interactive" {
+		t.Errorf("calls = %v", *calls)
+	}
+	if m.cursorTarget != "feature-top" {
+		t.Errorf("cursorTarget = %q, want the checked-out branch", m.cursorTarget)
+	}
+}
+
+func TestFinder_CurrentBranch(t *testing.T) {
+	m := loadedModel("│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main")
+	m = sendKey(m, '/')
+	for _, r := range "base" {
+		m = sendKey(m, r)
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.running || m.statusBar.message != "Already on feature-base" {
+		t.Errorf("running = %v, message = %q", m.running, m.statusBar.message)
+	}
+	if b := m.selectedBranch(); b == nil || b.Name != "feature-base" {
+		t.Errorf("cursor should move to feature-base, selected = %v", b)
+	}
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// errFixupRebase marks a fixup whose autosquash rebase failed. Its
+// conflicts aren't gt's to continue, so it doesn't open the conflict view.
+var errFixupRebase = errors.New("autosquash rebase failed")
+
+// startFixup asks to confirm committing every working-tree change as a
+// fixup of the selected branch's head commit. The fixup is squashed in by
+// an autosquash rebase of the current stack, which is then restacked. The
+// selected branch must be the current branch or one below it, since only
+// those commits are rewritten by rebasing the current branch. If the
+// rebase fails, it is aborted and the fixup commit undone.
+func (m *Model) startFixup() []tea.Cmd {
+	target := m.selectedBranch()
+	current := currentBranchName(*m)
+	switch {
+	case target == nil:
+		return nil
+	case current == "":
+		m.statusBar.setStatus(severityWarning, "No branch checked out to fixup from")
+		return nil
+	case target.Parent == "":
+		m.statusBar.setStatus(severityWarning, "Cannot fixup into trunk branch")
+		return nil
+	}
+	name, parent := target.Name, target.Parent
+	inStack := false
+	for _, b := range stackBranches(m.branches, current, false) {
+		if b.Name == name {
+			inStack = true
+			break
+		}
+	}
+	if !inStack {
+		m.statusBar.setStatus(severityWarning, "Cannot fixup into "+name+": it is not below "+current)
+		return nil
+	}
+	m.cursorTarget = name
+	warning := "Fixup commits every working-tree change into the last commit of " + name + " and rewrites the branches from " + name + " up to " + current + "."
+	return m.startAction("fixup", "Fixed up "+name, "Fixing up "+name+"...", warning, func(ctx context.Context, client *gt.Client) error {
+		if err := client.FixupCommit(ctx, name); err != nil {
+			return err
+		}
+		if err := client.AutosquashRebase(ctx, parent); err != nil {
+			// The rebase was aborted; take the fixup back out too, so the
+			// changes are uncommitted again.
+			if undoErr := client.UndoCommit(ctx); undoErr != nil {
+				return fmt.Errorf("%w, and the fixup commit is still on %s (%v): %w", errFixupRebase, current, undoErr, err)
+			}
+			return fmt.Errorf("%w, fixup undone: %w", errFixupRebase, err)
+		}
+		return client.StackRestack(ctx, current)
+	})
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestFixupKey_ConfirmsThenFixesUp(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	m.cursor = 1
+
+	m = sendKey(m, 'z')
+	if m.mode != modeConfirm || len(*calls) != 0 {
+		t.Fatalf("fixup should wait for confirmation, mode = %d", m.mode)
+	}
+	if view := m.View(); !strings.Contains(view, "git commit --all --fixup feature-base") {
+		t.Errorf("confirmation should show the fixup commit:\n%s", view)
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	var got []string
+	for _, c := range *calls {
+		got = append(got, c.name+" "+strings.Join(c.args, " "))
+	}
+	want := []string{
+		"git commit --all --fixup feature-base",
+		"git -c sequence.editor=: rebase --interactive --autosquash --update-refs main",
+		"gt stack restack --no-interactive --branch feature-top",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %q, want %q", got, want)
+	}
+	if m.cursorTarget != "feature-base" {
+		t.Errorf("cursorTarget = %q, want the fixup target", m.cursorTarget)
+	}
+}
+
+func TestFixupKey_FailedRebaseUndoesFixup(t *testing.T) {
+	var calls []string
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		if slices.Contains(args, "--autosquash") {
+			return "", errors.New("CONFLICT (content): Merge conflict in a.go")
+		}
+		return "", nil
+	}}
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	m.cursor = 1
+
+	m = sendKey(m, 'z')
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	for _, msg := range batchMsgs(cmd) {
+		if result, ok := msg.(actionResultMsg); ok {
+			updated, _ = m.Update(result)
+			m = updated.(Model)
+		}
+	}
+
+	want := []string{
+		"git commit --all --fixup feature-base",
+		"git -c sequence.editor=: rebase --interactive --autosquash --update-refs main",
+		"git rebase --abort",
+		"git reset --mixed HEAD~1",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+	if m.mode != modeTree {
+		t.Errorf("mode = %d, want the tree: gt can't continue this rebase", m.mode)
+	}
+	if !strings.Contains(m.statusBar.message, "fixup undone") {
+		t.Errorf("message = %q, want the fixup reported undone", m.statusBar.message)
+	}
+}
+
+func TestFixupKey_RefusesOutsideDownstack(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor int
+		want   string
+	}{
+		{"upstack", 0, "Cannot fixup into feature-top: it is not below feature-base"},
+		{"trunk", 2, "Cannot fixup into trunk branch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := loadedModel("│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main")
+			m.cursor = tt.cursor
+			m = sendKey(m, 'z')
+			if m.mode != modeTree || m.statusBar.message != tt.want {
+				t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+			}
+		})
+	}
+}
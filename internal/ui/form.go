@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	formLabelStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	formFocusStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	formChoiceStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("3"))
+)
+
+// fieldKind is the kind of input a form field takes.
+type fieldKind int
+
+const (
+	fieldText     fieldKind = iota // a line of text
+	fieldCheckbox                  // on or off, toggled with space
+	fieldSelect                    // one of options, cycled with ←/→
+)
+
+// formField is one labeled row of a form.
+type formField struct {
+	kind    fieldKind
+	label   string
+	input   textinput.Model // fieldText
+	checked bool            // fieldCheckbox
+	options []string        // fieldSelect
+	choice  int             // fieldSelect: index into options
+}
+
+func textField(label, value, placeholder string) formField {
+	ti := textinput.New()
+	ti.Prompt = ""
+	ti.Placeholder = placeholder
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	ti.SetValue(value)
+	ti.CursorEnd()
+	return formField{kind: fieldText, label: label, input: ti}
+}
+
+func checkboxField(label string, checked bool) formField {
+	return formField{kind: fieldCheckbox, label: label, checked: checked}
+}
+
+func selectField(label string, options []string, choice int) formField {
+	return formField{kind: fieldSelect, label: label, options: options, choice: choice}
+}
+
+// text returns a text field's value without surrounding space.
+func (f formField) text() string {
+	return strings.TrimSpace(f.input.Value())
+}
+
+// form is a dialog of several fields, shown as an overlay over the tree.
+// Tab and ↑/↓ move between fields, enter submits the whole form and esc
+// cancels it. While active it captures all key input except ctrl+c.
+type form struct {
+	title  string
+	fields []formField
+	focus  int
+	submit func(m *Model, f form) []tea.Cmd // acts on the submitted values
+	note   func(f form) string              // what the current values will do, shown below the fields; nil for none
+}
+
+func newForm(title string, fields []formField, submit func(m *Model, f form) []tea.Cmd) form {
+	f := form{title: title, fields: fields, submit: submit}
+	f.focusField(0)
+	return f
+}
+
+func (f form) active() bool {
+	return f.title != ""
+}
+
+// focusField moves the focus to field i, focusing its text input if it
+// has one so the cursor shows only there.
+func (f *form) focusField(i int) {
+	f.focus = i
+	for j := range f.fields {
+		if f.fields[j].kind != fieldText {
+			continue
+		}
+		if j == i {
+			f.fields[j].input.Focus()
+		} else {
+			f.fields[j].input.Blur()
+		}
+	}
+}
+
+// update feeds a key to the form. It reports whether the user submitted
+// (enter) or cancelled (esc) it.
+func (f *form) update(msg tea.KeyMsg) (submitted, cancelled bool, cmd tea.Cmd) {
+	switch classifyOverlayKey(msg) {
+	case overlaySubmit:
+		return true, false, nil
+	case overlayCancel:
+		return false, true, nil
+	}
+	switch msg.Type {
+	case tea.KeyTab, tea.KeyDown:
+		f.focusField((f.focus + 1) % len(f.fields))
+		return false, false, nil
+	case tea.KeyShiftTab, tea.KeyUp:
+		f.focusField((f.focus + len(f.fields) - 1) % len(f.fields))
+		return false, false, nil
+	}
+
+	field := &f.fields[f.focus]
+	switch field.kind {
+	case fieldText:
+		field.input, cmd = field.input.Update(msg)
+	case fieldCheckbox:
+		if msg.Type == tea.KeySpace || msg.String() == "x" {
+			field.checked = !field.checked
+		}
+	case fieldSelect:
+		switch msg.Type {
+		case tea.KeyRight, tea.KeySpace:
+			field.choice = (field.choice + 1) % len(field.options)
+		case tea.KeyLeft:
+			field.choice = (field.choice + len(field.options) - 1) % len(field.options)
+		}
+	}
+	return false, false, cmd
+}
+
+// overlay renders the form as a dialog with one row per field, labels
+// aligned and the focused field marked.
+func (f form) overlay() overlay {
+	labelWidth := 0
+	for _, field := range f.fields {
+		labelWidth = max(labelWidth, lipgloss.Width(field.label))
+	}
+	var rows []string
+	for i, field := range f.fields {
+		marker, label := "  ", formLabelStyle.Render(field.label)
+		if i == f.focus {
+			marker, label = formFocusStyle.Render("› "), formFocusStyle.Render(field.label)
+		}
+		label += strings.Repeat(" ", labelWidth-lipgloss.Width(field.label)+2)
+		rows = append(rows, marker+label+field.view())
+	}
+	body := strings.Join(rows, "\n")
+	if f.note != nil {
+		if note := f.note(f); note != "" {
+			body += "\n\n" + helpDescStyle.Render(note)
+		}
+	}
+	return overlay{
+		title:  f.title,
+		body:   body,
+		footer: "tab/↑↓ move, space toggles, ←/→ choose. Press enter to run, esc to cancel.",
+	}
+}
+
+// view renders a field's value.
+func (f formField) view() string {
+	switch f.kind {
+	case fieldCheckbox:
+		if f.checked {
+			return formChoiceStyle.Render("[x]")
+		}
+		return "[ ]"
+	case fieldSelect:
+		parts := make([]string, len(f.options))
+		for i, option := range f.options {
+			if i == f.choice {
+				parts[i] = formChoiceStyle.Render(option)
+			} else {
+				parts[i] = formLabelStyle.Render(option)
+			}
+		}
+		return strings.Join(parts, "  ")
+	}
+	return f.input.View()
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func testForm() form {
+	return newForm("Test", []formField{
+		selectField("Scope", []string{"a", "b", "c"}, 0),
+		checkboxField("Flag", false),
+		textField("Name", "", "placeholder"),
+	}, nil)
+}
+
+func formKey(f *form, k tea.Key) (submitted, cancelled bool) {
+	submitted, cancelled, _ = f.update(tea.KeyMsg(k))
+	return submitted, cancelled
+}
+
+func TestForm_TabMovesFocusAndWraps(t *testing.T) {
+	f := testForm()
+	formKey(&f, tea.Key{Type: tea.KeyTab})
+	formKey(&f, tea.Key{Type: tea.KeyTab})
+	if f.focus != 2 || !f.fields[2].input.Focused() {
+		t.Fatalf("focus = %d, want the text field focused", f.focus)
+	}
+	formKey(&f, tea.Key{Type: tea.KeyTab})
+	if f.focus != 0 || f.fields[2].input.Focused() {
+		t.Errorf("focus = %d, want tab to wrap to the first field and blur the text", f.focus)
+	}
+	formKey(&f, tea.Key{Type: tea.KeyShiftTab})
+	if f.focus != 2 {
+		t.Errorf("focus = %d, want shift+tab to wrap to the last field", f.focus)
+	}
+}
+
+func TestForm_FieldsTakeTheirKeys(t *testing.T) {
+	f := testForm()
+	formKey(&f, tea.Key{Type: tea.KeyLeft})
+	if f.fields[0].choice != 2 {
+		t.Errorf("choice = %d, want ← to wrap to the last option", f.fields[0].choice)
+	}
+	formKey(&f, tea.Key{Type: tea.KeyDown})
+	formKey(&f, tea.Key{Type: tea.KeySpace})
+	if !f.fields[1].checked {
+		t.Error("space should toggle the checkbox")
+	}
+	formKey(&f, tea.Key{Type: tea.KeyDown})
+	formKey(&f, tea.Key{Type: tea.KeyRunes, Runes: []rune("x y")})
+	if got := f.fields[2].text(); got != "x y" {
+		t.Errorf("text = %q, want typed keys", got)
+	}
+	if !f.fields[1].checked {
+		t.Error("typing in the text field should not toggle the checkbox")
+	}
+}
+
+func TestForm_SubmitAndCancel(t *testing.T) {
+	f := testForm()
+	if submitted, _ := formKey(&f, tea.Key{Type: tea.KeyEnter}); !submitted {
+		t.Error("enter should submit")
+	}
+	if _, cancelled := formKey(&f, tea.Key{Type: tea.KeyEscape}); !cancelled {
+		t.Error("esc should cancel")
+	}
+}
+
+func TestForm_OverlayListsFields(t *testing.T) {
+	f := testForm()
+	f.fields[1].checked = true
+	body := f.overlay().body
+	for _, want := range []string{"› Scope", "a  b  c", "Flag   [x]", "placeholder"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestSubmitForm_RunsChosenOptions(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	m = sendKey(m, 'N')
+	if !m.form.active() {
+		t.Fatal("N should open the submit form")
+	}
+	m = sendSpecialKey(m, tea.KeyLeft) // downstack
+	m = sendSpecialKey(m, tea.KeyTab)
+	m = sendSpecialKey(m, tea.KeySpace) // draft
+	m = sendSpecialKey(m, tea.KeyTab)
+	m = sendSpecialKey(m, tea.KeyTab)
+	m = typeText(m, "alice, bob")
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+
+	if m.form.active() {
+		t.Error("enter should close the form")
+	}
+	want := []string{"downstack", "submit", "--no-interactive", "--draft", "--reviewers", "alice,bob", "--branch", "feature-top"}
+	if len(*calls) == 0 || !slices.Equal((*calls)[0].args, want) {
+		t.Errorf("calls = %v, want %v", *calls, want)
+	}
+}
+
+func TestSubmitForm_RefusesTrunk(t *testing.T) {
+	m := loadedModel("│ ◯  feature\n◉─┘  main")
+	m.cursor = 1
+	m = sendKey(m, 'N')
+	if m.form.active() || m.statusBar.message != "Cannot submit trunk branch" {
+		t.Errorf("form active = %v, status = %q", m.form.active(), m.statusBar.message)
+	}
+}
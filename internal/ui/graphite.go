@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// graphiteResultMsg reports opening a PR in the Graphite web app.
+type graphiteResultMsg struct {
+	number int
+	err    error
+}
+
+// openPR opens the selected branch's PR in the browser: on GitHub with
+// `gt pr`, or in the Graphite web app when graphite is set. o opens it
+// wherever the openPRIn setting says and ctrl+w on the other site.
+func (m *Model) openPR(graphite bool) []tea.Cmd {
+	branch := m.selectedBranch()
+	if branch == nil {
+		return nil
+	}
+	name := branch.Name
+	if !graphite {
+		return m.startAction("openpr", "Opened PR for "+name, "Opening PR ("+name+")...", "", func(ctx context.Context, client *gt.Client) error {
+			return client.OpenPR(ctx, name)
+		})
+	}
+	if branch.PR.Number == 0 {
+		m.statusBar.setStatus(severityWarning, "No PR for "+name)
+		return nil
+	}
+	return []tea.Cmd{m.openInGraphite(branch.PR.Number)}
+}
+
+// openInGraphite opens PR number's page in the Graphite web app. The
+// remote lookup runs in the command.
+func (m Model) openInGraphite(number int) tea.Cmd {
+	client := m.gtClient
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		repo, ok := client.GitHubRepo(ctx)
+		if !ok {
+			return graphiteResultMsg{number: number, err: errors.New("origin is not a GitHub remote")}
+		}
+		return graphiteResultMsg{number: number, err: client.OpenURL(ctx, gt.GraphitePRURL(repo, number))}
+	}
+}
+
+// showGraphiteResult reports whether the PR opened.
+func (m *Model) showGraphiteResult(msg graphiteResultMsg) {
+	if msg.err != nil {
+		m.statusBar.setStatus(severityError, fmt.Sprintf("Could not open #%d in Graphite: %s", msg.number, msg.err))
+		return
+	}
+	m.statusBar.setStatus(severitySuccess, fmt.Sprintf("Opened #%d in Graphite", msg.number))
+}
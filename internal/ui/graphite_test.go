@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// graphiteModel returns a loaded model whose feature-top has PR #12,
+// recording every command run.
+func graphiteModel(cfg config.Config, commands *[]string) Model {
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		*commands = append(*commands, gt.FormatCommand(name, args...))
+		if name == "git" && len(args) > 0 && args[0] == "remote" {
+			return "git@github.com:acme/app.git\n", nil
+		}
+		return "", nil
+	}}
+	m := NewWithConfig(gt.New(mock), "", cfg)
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{"feature-top": {Number: 12, State: "OPEN"}}})
+	m = updated.(Model)
+	m.cursor = 0
+	return m
+}
+
+// runOpenKey presses k and feeds back the results of its commands.
+func runOpenKey(m Model, k tea.KeyMsg) Model {
+	updated, cmd := m.Update(k)
+	m = updated.(Model)
+	for _, msg := range batchMsgs(cmd) {
+		updated, _ = m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestOpenPRGraphiteKey_OpensGraphite(t *testing.T) {
+	var commands []string
+	m := graphiteModel(config.Config{}, &commands)
+	m = runOpenKey(m, tea.KeyMsg(tea.Key{Type: tea.KeyCtrlW}))
+	want := "https://app.graphite.dev/github/pr/acme/app/12"
+	if len(commands) == 0 || !strings.HasSuffix(commands[len(commands)-1], want) {
+		t.Errorf("commands = %v, want %s opened", commands, want)
+	}
+	if m.statusBar.message != "Opened #12 in Graphite" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+func TestOpenPRKey_GraphiteDefault(t *testing.T) {
+	var commands []string
+	m := graphiteModel(config.Config{OpenPRIn: "graphite"}, &commands)
+	m = runOpenKey(m, tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'o'}}))
+	if len(commands) == 0 || !strings.HasSuffix(commands[len(commands)-1], "/acme/app/12") {
+		t.Errorf("o should open Graphite, commands = %v", commands)
+	}
+
+	commands = nil
+	m = runOpenKey(m, tea.KeyMsg(tea.Key{Type: tea.KeyCtrlW}))
+	if !slices.Contains(commands, "gt pr feature-top") {
+		t.Errorf("ctrl+w should open GitHub with gt pr, commands = %v", commands)
+	}
+}
+
+func TestOpenPRGraphiteKey_NeedsPR(t *testing.T) {
+	var commands []string
+	m := graphiteModel(config.Config{}, &commands)
+	m.cursor = 1
+	commands = nil
+	m = runOpenKey(m, tea.KeyMsg(tea.Key{Type: tea.KeyCtrlW}))
+	if len(commands) != 0 || m.statusBar.message != "No PR for feature-base" {
+		t.Errorf("commands = %v, message = %q", commands, m.statusBar.message)
+	}
+}
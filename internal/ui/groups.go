@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var groupHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("5"))
+
+const (
+	groupExpandedMarker  = "▾ "
+	groupCollapsedMarker = "▸ "
+)
+
+// groupOf returns the first of prefixes that the bottom branch of name's
+// stack, the one on trunk, starts with, or "" if none does. Trunk itself
+// is in no group.
+func groupOf(branches []*gt.Branch, name string, prefixes []string) string {
+	b := gt.FindBranch(branches, name)
+	if b == nil || b.Parent == "" {
+		return ""
+	}
+	for {
+		parent := gt.FindBranch(branches, b.Parent)
+		if parent == nil || parent.Parent == "" {
+			break
+		}
+		b = parent
+	}
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(b.Name, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// withGroups gathers the stacks of each group in prefixes under a header
+// entry, placed where the group's first stack was. A collapsed group shows
+// only its header. Stacks in no group, pinned copies and untracked
+// branches keep their place.
+func withGroups(entries []displayEntry, branches []*gt.Branch, prefixes []string, collapsed map[string]bool) []displayEntry {
+	if len(prefixes) == 0 {
+		return entries
+	}
+	members := make(map[string][]displayEntry)
+	groups := make([]string, len(entries))
+	for i, e := range entries {
+		if e.pinned || e.untracked {
+			continue
+		}
+		groups[i] = groupOf(branches, e.branch.Name, prefixes)
+		if groups[i] != "" {
+			members[groups[i]] = append(members[groups[i]], e)
+		}
+	}
+	var grouped []displayEntry
+	shown := make(map[string]bool)
+	for i, e := range entries {
+		g := groups[i]
+		if g == "" {
+			grouped = append(grouped, e)
+			continue
+		}
+		if shown[g] {
+			continue
+		}
+		shown[g] = true
+		header := displayEntry{branch: &gt.Branch{}, depth: members[g][0].depth, group: g, size: len(members[g])}
+		for _, m := range members[g] {
+			header.depth = min(header.depth, m.depth)
+			header.hasCurrent = header.hasCurrent || m.branch.IsCurrent
+		}
+		grouped = append(grouped, header)
+		if !collapsed[g] {
+			grouped = append(grouped, members[g]...)
+		}
+	}
+	return grouped
+}
+
+// groupHeaderLabel renders a group's header row: its prefix, whether it is
+// collapsed, and how many branches it holds.
+func groupHeaderLabel(e displayEntry, collapsed bool) string {
+	marker := groupExpandedMarker
+	if collapsed {
+		marker = groupCollapsedMarker
+	}
+	text := fmt.Sprintf("%d branch", e.size)
+	if e.size != 1 {
+		text += "es"
+	}
+	if collapsed && e.hasCurrent {
+		text += ", checked out inside"
+	}
+	return marker + e.group + " (" + text + ")"
+}
+
+// selectedGroup returns the group whose header is at the cursor, or "".
+func (m Model) selectedGroup() string {
+	if m.cursor >= 0 && m.cursor < len(m.displayEntries) {
+		return m.displayEntries[m.cursor].group
+	}
+	return ""
+}
+
+// toggleGroup collapses group, or expands it if collapsed, keeping the
+// cursor on its header.
+func (m *Model) toggleGroup(group string) {
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[group] = !m.collapsed[group]
+	row := m.cursor - m.viewport.YOffset
+	m.displayEntries = m.buildEntries()
+	for i, e := range m.displayEntries {
+		if e.group == group {
+			m.cursor = i
+		}
+	}
+	m.viewport.SetContent(m.treeContent())
+	m.viewport.SetYOffset(m.cursor - row)
+	m.ensureCursorVisible()
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// groupedBranches has ann/base with ann/top on it and 02-04-fix directly
+// on main.
+func groupedBranches() []*gt.Branch {
+	return []*gt.Branch{{
+		Name: "main",
+		Children: []*gt.Branch{
+			{Name: "ann/base", Parent: "main", Depth: 1, Children: []*gt.Branch{
+				{Name: "ann/top", Parent: "ann/base", Depth: 1, IsCurrent: true},
+			}},
+			{Name: "02-04-fix", Parent: "main"},
+		},
+	}}
+}
+
+func TestGroupOf_UsesBottomOfStack(t *testing.T) {
+	branches := groupedBranches()
+	prefixes := []string{"ann/", "02-04-"}
+	for name, want := range map[string]string{"ann/top": "ann/", "ann/base": "ann/", "02-04-fix": "02-04-", "main": ""} {
+		if got := groupOf(branches, name, prefixes); got != want {
+			t.Errorf("groupOf(%s) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestWithGroups_HeaderAndCollapse(t *testing.T) {
+	branches := groupedBranches()
+	entries := flattenForDisplay(branches)
+
+	grouped := withGroups(entries, branches, []string{"ann/"}, nil)
+	if len(grouped) != len(entries)+1 {
+		t.Fatalf("entries = %v, want one header added", entryNames(grouped))
+	}
+	var header displayEntry
+	for _, e := range grouped {
+		if e.group != "" {
+			header = e
+		}
+	}
+	if header.group != "ann/" || header.size != 2 || !header.hasCurrent {
+		t.Errorf("header = %+v, want ann/ holding 2 branches with the current one", header)
+	}
+
+	collapsed := withGroups(entries, branches, []string{"ann/"}, map[string]bool{"ann/": true})
+	for _, e := range collapsed {
+		if strings.HasPrefix(e.branch.Name, "ann/") {
+			t.Errorf("collapsed group still shows %s", e.branch.Name)
+		}
+	}
+	if len(collapsed) != len(entries)-1 {
+		t.Errorf("entries = %v, want the group's branches replaced by its header", entryNames(collapsed))
+	}
+}
+
+func TestWithGroups_NoPrefixesUnchanged(t *testing.T) {
+	branches := groupedBranches()
+	entries := flattenForDisplay(branches)
+	if got := withGroups(entries, branches, nil, nil); !reflect.DeepEqual(got, entries) {
+		t.Errorf("entries = %v, want unchanged", entryNames(got))
+	}
+}
+
+func TestEnterOnGroupHeader_TogglesCollapse(t *testing.T) {
+	mock, calls := recordingMock()
+	m := NewWithConfig(gt.New(mock), "", config.Config{Groups: []string{"ann/"}})
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "◯    02-04-fix\n│ ◉  ann/top\n│ ◯  ann/base\n◯─┘  main"})
+	m = updated.(Model)
+	m.cursor = -1
+	for i, e := range m.displayEntries {
+		if e.group == "ann/" {
+			m.cursor = i
+		}
+	}
+	if m.cursor < 0 {
+		t.Fatalf("entries = %v, want an ann/ header", entryNames(m.displayEntries))
+	}
+	if m.selectedBranch() != nil {
+		t.Error("a group header is not a branch")
+	}
+
+	before := len(*calls)
+	updated, _ = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	if !m.collapsed["ann/"] || m.selectedGroup() != "ann/" {
+		t.Fatalf("collapsed = %v, selected group = %q", m.collapsed, m.selectedGroup())
+	}
+	if len(*calls) != before {
+		t.Errorf("calls = %v, want no checkout", (*calls)[before:])
+	}
+	if tree := ansi.Strip(m.treeContent()); strings.Contains(tree, "ann/top") || !strings.Contains(tree, groupCollapsedMarker+"ann/ (2 branches, checked out inside)") {
+		t.Errorf("tree =\n%s", tree)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	if m.collapsed["ann/"] || !strings.Contains(m.treeContent(), "ann/top") {
+		t.Errorf("enter again should expand the group")
+	}
+}
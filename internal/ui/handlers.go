@@ -0,0 +1,666 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// actionHandlers runs each action by spec name, as its key does in the
+// tree. They are set as actionSpecs' execute funcs in init rather than in
+// the actionSpecs literal, which the handlers refer to (through the help
+// screen, the palette and startAction's confirmPolicy) and so can't be
+// initialized with.
+var actionHandlers = map[string]func(m *Model) []tea.Cmd{
+	"up": func(m *Model) []tea.Cmd {
+		if m.cursor > 0 {
+			m.cursor--
+			m.viewport.SetContent(m.treeContent())
+			m.ensureCursorVisible()
+		}
+		return nil
+	},
+	"down": func(m *Model) []tea.Cmd {
+		if m.cursor < len(m.displayEntries)-1 {
+			m.cursor++
+			m.viewport.SetContent(m.treeContent())
+			m.ensureCursorVisible()
+		}
+		return nil
+	},
+	"checkout": func(m *Model) []tea.Cmd {
+		if group := m.selectedGroup(); group != "" {
+			m.toggleGroup(group)
+		} else if branch := m.selectedBranch(); branch != nil {
+			return m.startCheckout(branch.Name)
+		}
+		return nil
+	},
+	"finder": func(m *Model) []tea.Cmd {
+		m.openFinder()
+		return nil
+	},
+	"trunk": func(m *Model) []tea.Cmd {
+		if len(m.branches) > 0 {
+			return m.startCheckout(m.branches[0].Name)
+		}
+		return nil
+	},
+	"stackDown":   func(m *Model) []tea.Cmd { return m.startStackStep("down") },
+	"stackUp":     func(m *Model) []tea.Cmd { return m.startStackStep("up") },
+	"stackBottom": func(m *Model) []tea.Cmd { return m.startStackStep("bottom") },
+	"stackTop":    func(m *Model) []tea.Cmd { return m.startStackStep("top") },
+
+	"stackSubmit": func(m *Model) []tea.Cmd {
+		return m.submitSelected(func(branch *gt.Branch) pendingSubmit {
+			name := branch.Name
+			return pendingSubmit{
+				action:       "submit",
+				desc:         "Submit stack (" + name + ")",
+				successMsg:   "Stack submitted",
+				spinnerLabel: "Submitting stack (" + name + ")...",
+				targets:      stackBranches(m.branches, name, true),
+				submit: func(ctx context.Context, client *gt.Client) error {
+					return client.StackSubmit(ctx, name)
+				},
+			}
+		})
+	},
+	"downstackSubmit": func(m *Model) []tea.Cmd {
+		return m.submitSelected(func(branch *gt.Branch) pendingSubmit {
+			name := branch.Name
+			return pendingSubmit{
+				action:       "downstack-submit",
+				desc:         "Submit downstack (" + name + ")",
+				successMsg:   "Downstack submitted",
+				spinnerLabel: "Submitting downstack (" + name + ")...",
+				targets:      stackBranches(m.branches, name, false),
+				submit: func(ctx context.Context, client *gt.Client) error {
+					return client.DownstackSubmit(ctx, name)
+				},
+			}
+		})
+	},
+	"branchSubmit": func(m *Model) []tea.Cmd {
+		return m.submitSelected(func(branch *gt.Branch) pendingSubmit {
+			name := branch.Name
+			return pendingSubmit{
+				action:       "branch-submit",
+				desc:         "Submit branch (" + name + ")",
+				successMsg:   "Submitted " + name,
+				spinnerLabel: "Submitting " + name + "...",
+				targets:      []*gt.Branch{branch},
+				submit: func(ctx context.Context, client *gt.Client) error {
+					return client.BranchSubmit(ctx, name)
+				},
+			}
+		})
+	},
+	"draftSubmit": func(m *Model) []tea.Cmd {
+		return m.submitSelected(func(branch *gt.Branch) pendingSubmit {
+			name := branch.Name
+			return pendingSubmit{
+				action:       "draft-submit",
+				desc:         "Submit draft (" + name + ")",
+				successMsg:   "Submitted " + name + " as draft",
+				spinnerLabel: "Submitting " + name + " as draft...",
+				targets:      []*gt.Branch{branch},
+				submit: func(ctx context.Context, client *gt.Client) error {
+					return client.DraftSubmit(ctx, name)
+				},
+			}
+		})
+	},
+	"submitOptions": func(m *Model) []tea.Cmd {
+		if branch := m.selectedBranch(); branch != nil {
+			if branch.Parent == "" {
+				m.statusBar.setStatus(severityWarning, "Cannot submit trunk branch")
+			} else {
+				m.openSubmitForm(branch)
+			}
+		}
+		return nil
+	},
+	"publish": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		name := branch.Name
+		if !strings.EqualFold(branch.PR.State, "DRAFT") {
+			m.statusBar.setStatus(severityWarning, "PR for "+name+" is not a draft")
+			return nil
+		}
+		return m.startSubmit(pendingSubmit{
+			action:       "publish",
+			desc:         "Publish (" + name + ")",
+			successMsg:   "Published " + name,
+			spinnerLabel: "Publishing " + name + "...",
+			targets:      []*gt.Branch{branch},
+			submit: func(ctx context.Context, client *gt.Client) error {
+				return client.Publish(ctx, name)
+			},
+		})
+	},
+	"editPR": func(m *Model) []tea.Cmd { return m.startPREdit() },
+	"requestReview": func(m *Model) []tea.Cmd {
+		m.startRequestReview()
+		return nil
+	},
+	"postComment": func(m *Model) []tea.Cmd {
+		m.startComment()
+		return nil
+	},
+	"editLabels": func(m *Model) []tea.Cmd { return m.startLabelEdit() },
+	"mergeQueue": func(m *Model) []tea.Cmd { return m.startMerge() },
+	"mergePR":    func(m *Model) []tea.Cmd { return m.startPRMerge() },
+	"resubmit": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		name := branch.Name
+		if !unsubmitted(branch) {
+			m.statusBar.setStatus(severityInfo, "PR for "+name+" is up to date")
+			return nil
+		}
+		return m.startSubmit(pendingSubmit{
+			action:       "resubmit",
+			desc:         "Resubmit (" + name + ")",
+			successMsg:   "Resubmitted " + name,
+			spinnerLabel: "Resubmitting (" + name + ")...",
+			targets:      stackBranches(m.branches, name, false),
+			submit: func(ctx context.Context, client *gt.Client) error {
+				return client.DownstackSubmit(ctx, name)
+			},
+		})
+	},
+	"cleanup": func(m *Model) []tea.Cmd {
+		if branch := m.selectedBranch(); branch != nil {
+			if branch.Parent == "" {
+				m.statusBar.setStatus(severityWarning, "Cannot clean up trunk branch")
+			} else if !strings.EqualFold(branch.PR.State, "MERGED") {
+				m.statusBar.setStatus(severityWarning, "PR for "+branch.Name+" has not merged")
+			} else {
+				m.cleanup = planCleanup(m.branches, branch)
+				m.setMode(modeCleanup)
+				m.resizeViewport()
+				m.viewport.SetContent(renderCleanup(m.cleanup))
+				m.viewport.GotoTop()
+			}
+		}
+		return nil
+	},
+	"cleanupAll": func(m *Model) []tea.Cmd {
+		m.bulkCleanup = planBulkCleanup(m.branches, m.displayEntries)
+		if len(m.bulkCleanup.candidates) == 0 {
+			m.statusBar.setStatus(severityInfo, "No branches with merged or closed PRs")
+		} else {
+			m.setMode(modeBulkCleanup)
+			m.resizeViewport()
+			m.refreshBulkCleanupView()
+			m.viewport.GotoTop()
+		}
+		return nil
+	},
+	"deleteRemote": func(m *Model) []tea.Cmd { return m.startDeleteEverywhere() },
+	"restack": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		if branch.Parent == "" {
+			m.statusBar.setStatus(severityWarning, "Cannot restack trunk branch")
+			return nil
+		}
+		name := branch.Name
+		m.actionTargets = branchNames(stackBranches(m.branches, name, true))
+		return m.startAction("restack", "Restacked", "Restacking ("+name+")...", "", func(ctx context.Context, client *gt.Client) error {
+			return client.StackRestack(ctx, name)
+		})
+	},
+	"branchRestack": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		if branch.Parent == "" {
+			m.statusBar.setStatus(severityWarning, "Cannot restack trunk branch")
+			return nil
+		}
+		name := branch.Name
+		m.actionTargets = []string{name}
+		return m.startAction("branch-restack", "Restacked "+name, "Restacking "+name+"...", "", func(ctx context.Context, client *gt.Client) error {
+			return client.BranchRestack(ctx, name)
+		})
+	},
+	"upstackRestack": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		if branch.Parent == "" {
+			m.statusBar.setStatus(severityWarning, "Cannot restack trunk branch")
+			return nil
+		}
+		name := branch.Name
+		upstack := []*gt.Branch{branch}
+		collectDescendants(branch, &upstack)
+		m.actionTargets = branchNames(upstack)
+		return m.startAction("upstack-restack", "Restacked upstack of "+name, "Restacking upstack of "+name+"...", "", func(ctx context.Context, client *gt.Client) error {
+			return client.UpstackRestack(ctx, name)
+		})
+	},
+	"fetch": func(m *Model) []tea.Cmd {
+		return m.startAction("fetch", "Fetched", "Fetching...", "", func(ctx context.Context, client *gt.Client) error {
+			return client.RepoSync(ctx)
+		})
+	},
+	"sync": func(m *Model) []tea.Cmd { return m.startSyncPrune() },
+	"syncPreview": func(m *Model) []tea.Cmd {
+		if len(m.branches) > 0 {
+			return m.startSyncPreview()
+		}
+		return nil
+	},
+	"openPR":         func(m *Model) []tea.Cmd { return m.openPR(m.graphitePRs) },
+	"openPRGraphite": func(m *Model) []tea.Cmd { return m.openPR(!m.graphitePRs) },
+	"test": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		switch {
+		case m.testCommand == "":
+			m.statusBar.setStatus(severityWarning, "No test command configured — set testCommand in your grit config")
+		case branch.Head == "":
+			m.statusBar.setStatus(severityWarning, "Cannot resolve head commit of "+branch.Name)
+		case m.testsRunning[branch.Head]:
+			m.statusBar.setStatus(severityWarning, "Tests already running on "+branch.Name)
+		default:
+			m.testsRunning[branch.Head] = true
+			applyTestStatus(m.branches, nil, m.tests, m.testsRunning)
+			m.viewport.SetContent(m.treeContent())
+			m.statusBar.setStatus(severityInfo, "Running tests on "+branch.Name+"...")
+			return []tea.Cmd{m.jobs.submit("Tests on "+branch.Name, true, branchTestJob(m.gtClient, branch.Name, branch.Head, m.testCommand))}
+		}
+		return nil
+	},
+	"create": func(m *Model) []tea.Cmd {
+		if b := m.selectedBranch(); b != nil && !m.needsInit {
+			m.openCreatePrompt(b.Name, "")
+		}
+		return nil
+	},
+	"insert": func(m *Model) []tea.Cmd {
+		if b := m.selectedBranch(); b != nil && !m.needsInit {
+			m.openInsertPrompt(b)
+		}
+		return nil
+	},
+	"rename": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		if branch.Parent == "" {
+			m.statusBar.setStatus(severityWarning, "Cannot rename trunk branch")
+			return nil
+		}
+		p := newPrompt(promptRename, "Rename "+branch.Name, branch.Name)
+		p.base = branch.Name
+		p.validate = func(name string) string {
+			if name == branch.Name {
+				return ""
+			}
+			return m.newBranchNameError(name)
+		}
+		m.showPrompt(p)
+		return nil
+	},
+	"fold": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		name := branch.Name
+		parent, hasParent := gt.FindParent(m.branches, name)
+		if !hasParent {
+			m.statusBar.setStatus(severityWarning, "Cannot fold trunk branch")
+			return nil
+		}
+		if parent == m.branches[0].Name {
+			m.statusBar.setStatus(severityWarning, "Cannot fold into trunk branch "+parent)
+			return nil
+		}
+		m.cursorTarget = parent
+		m.actionTargets = []string{parent}
+		warning := "Folding rewrites " + parent + " with the commits of " + name + " and deletes " + name + "."
+		return m.startAction("fold", "Folded "+name+" into "+parent, "Folding "+name+" into "+parent+"...", warning, func(ctx context.Context, client *gt.Client) error {
+			return client.Fold(ctx, name)
+		})
+	},
+	"pop": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		name, parent := branch.Name, branch.Parent
+		if parent == "" {
+			m.statusBar.setStatus(severityWarning, "Cannot pop trunk branch")
+			return nil
+		}
+		if len(branch.Children) > 0 {
+			m.statusBar.setStatus(severityWarning, "Cannot pop "+name+": branches are stacked on it")
+			return nil
+		}
+		current := branch.IsCurrent
+		if !current && m.inOtherWorktree(name) || m.inOtherWorktree(parent) {
+			return nil
+		}
+		m.cursorTarget = parent
+		warning := "Popping folds the commits of " + name + " into the working tree as uncommitted changes and deletes " + name + "."
+		return m.startAction("pop", "Popped "+name+" into the working tree", "Popping "+name+"...", warning, func(ctx context.Context, client *gt.Client) error {
+			if !current {
+				if err := client.Checkout(ctx, name); err != nil {
+					return err
+				}
+			}
+			return client.Pop(ctx)
+		})
+	},
+	"commit": func(m *Model) []tea.Cmd {
+		if len(m.branches) > 0 {
+			return []tea.Cmd{m.openCommit()}
+		}
+		return nil
+	},
+	"amend": func(m *Model) []tea.Cmd {
+		if len(m.branches) > 0 {
+			return m.startAmend()
+		}
+		return nil
+	},
+	"fixup": func(m *Model) []tea.Cmd {
+		if len(m.branches) > 0 {
+			return m.startFixup()
+		}
+		return nil
+	},
+	"absorb": func(m *Model) []tea.Cmd {
+		if len(m.branches) > 0 {
+			return m.startAbsorb()
+		}
+		return nil
+	},
+	"move": func(m *Model) []tea.Cmd {
+		if branch := m.selectedBranch(); branch != nil {
+			if branch.Parent == "" {
+				m.statusBar.setStatus(severityWarning, "Cannot move trunk branch")
+			} else {
+				m.beginMove(branch.Name)
+			}
+		}
+		return nil
+	},
+	"track": func(m *Model) []tea.Cmd {
+		if name := m.selectedUntracked(); name != "" {
+			m.beginTrack(name)
+		} else if branch := m.selectedBranch(); branch != nil && branch.Orphan != "" {
+			m.beginTrack(branch.Name)
+		}
+		return nil
+	},
+	"untrack": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		if branch.Parent == "" {
+			m.statusBar.setStatus(severityWarning, "Cannot untrack trunk branch")
+			return nil
+		}
+		return m.startUntrack(branch)
+	},
+	"split": func(m *Model) []tea.Cmd {
+		if branch := m.selectedBranch(); branch != nil {
+			if branch.Parent == "" {
+				m.statusBar.setStatus(severityWarning, "Cannot split trunk branch")
+			} else {
+				m.split = branch.Name
+				m.setMode(modeSplit)
+				m.resizeViewport()
+				m.viewport.SetContent(renderSplit(m.split))
+				m.viewport.GotoTop()
+			}
+		}
+		return nil
+	},
+	"repoInit": func(m *Model) []tea.Cmd {
+		if hasStacks(m.displayEntries) {
+			return nil
+		}
+		return m.startAction("init", "Graphite initialized", "Initializing Graphite...", "", func(ctx context.Context, client *gt.Client) error {
+			return client.RepoInit(ctx)
+		})
+	},
+
+	"checkoutNearest": func(m *Model) []tea.Cmd {
+		if m.repo.head.Detached && !m.repo.rebasing && m.repo.head.Nearest != "" {
+			return m.startCheckout(m.repo.head.Nearest)
+		}
+		return nil
+	},
+	"continue": func(m *Model) []tea.Cmd {
+		if !m.repo.rebasing {
+			m.statusBar.setStatus(severityWarning, "No rebase in progress")
+			return nil
+		}
+		return m.startContinue()
+	},
+	"abort": func(m *Model) []tea.Cmd {
+		if !m.repo.rebasing {
+			m.statusBar.setStatus(severityWarning, "No rebase in progress")
+			return nil
+		}
+		return m.startAbort()
+	},
+
+	"diff": func(m *Model) []tea.Cmd {
+		if parts := markedParts(m.branches, m.marked); len(parts) > 0 {
+			m.running = true
+			spinnerCmd := m.statusBar.startSpinner(fmt.Sprintf("Loading combined diff (%d branches)...", len(parts)))
+			return []tea.Cmd{spinnerCmd, m.loadCombinedDiffData(parts)}
+		}
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		name := branch.Name
+		if branch.Parent == "" {
+			m.statusBar.setStatus(severityWarning, "No parent branch for "+name)
+			return nil
+		}
+		m.running = true
+		spinnerCmd := m.statusBar.startSpinner("Loading diff for " + name + "...")
+		return []tea.Cmd{spinnerCmd, m.loadDiffData(branch.Parent, name)}
+	},
+	"mark": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		name := branch.Name
+		if _, hasParent := gt.FindParent(m.branches, name); !hasParent {
+			m.statusBar.setStatus(severityWarning, "Cannot mark trunk branch")
+			return nil
+		}
+		if m.marked[name] {
+			delete(m.marked, name)
+		} else {
+			m.marked[name] = true
+		}
+		m.statusBar.setStatus(severityInfo, fmt.Sprintf("%d marked for diff", len(m.marked)))
+		m.viewport.SetContent(m.treeContent())
+		return nil
+	},
+	"toggleDetail": func(m *Model) []tea.Cmd {
+		m.showDetail = !m.showDetail
+		m.resizeViewport()
+		return nil
+	},
+	"pin": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		if branch.Parent == "" {
+			m.statusBar.setStatus(severityWarning, "Cannot pin trunk branch")
+			return nil
+		}
+		name := branch.Name
+		var pinned bool
+		m.pins, pinned = togglePin(m.pins, name)
+		m.rebuildEntries(name)
+		switch err := savePins(m.gitDir, m.pins); {
+		case err != nil:
+			m.statusBar.setStatus(severityError, "Could not save pins: "+err.Error())
+		case pinned:
+			m.statusBar.setStatus(severitySuccess, "Pinned "+name)
+		default:
+			m.statusBar.setStatus(severitySuccess, "Unpinned "+name)
+		}
+		return nil
+	},
+	"hideMerged": func(m *Model) []tea.Cmd {
+		f := m.filter
+		f.HideMerged = !f.HideMerged
+		m.applyFilter(f)
+		return nil
+	},
+	"prFilter": func(m *Model) []tea.Cmd {
+		f := m.filter
+		f.PRState = nextPRState(f.PRState)
+		m.applyFilter(f)
+		return nil
+	},
+	"filter": func(m *Model) []tea.Cmd {
+		m.showPrompt(newPrompt(promptFilter, "Filter branches", m.filter.Text))
+		return nil
+	},
+	"clearFilters": func(m *Model) []tea.Cmd {
+		if m.filter.active() {
+			m.applyFilter(branchFilter{})
+		}
+		return nil
+	},
+	"jobs": func(m *Model) []tea.Cmd {
+		m.setMode(modeJobs)
+		m.jobCursor = 0
+		m.resizeViewport()
+		m.refreshJobsView()
+		m.viewport.GotoTop()
+		return []tea.Cmd{m.viewTick()}
+	},
+	"debug": func(m *Model) []tea.Cmd {
+		m.setMode(modeDebug)
+		m.debug.loading = true
+		m.resizeViewport()
+		m.refreshDebugView()
+		m.viewport.GotoTop()
+		return []tea.Cmd{m.loadQuota(), m.viewTick()}
+	},
+	"errorDetails": func(m *Model) []tea.Cmd {
+		if m.statusBar.lastError == "" {
+			m.statusBar.setStatus(severityInfo, "No errors yet")
+		} else {
+			m.dialog = m.statusBar.errorDetails()
+		}
+		return nil
+	},
+	"overlaps": func(m *Model) []tea.Cmd {
+		m.setMode(modeOverlaps)
+		m.resizeViewport()
+		m.refreshOverlapsView()
+		m.viewport.GotoTop()
+		return nil
+	},
+	"comments": func(m *Model) []tea.Cmd {
+		m.openComments()
+		return nil
+	},
+	"stats": func(m *Model) []tea.Cmd {
+		m.openStats()
+		return nil
+	},
+	"lastView": func(m *Model) []tea.Cmd {
+		if switchableViews[m.mode] {
+			return m.switchView()
+		}
+		return nil
+	},
+	"yank": func(m *Model) []tea.Cmd { return m.yankSelection() },
+	"yankRef": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		ref := remoteRef(branch.Name)
+		m.statusBar.setStatus(severitySuccess, yankDescription(ref, "remote ref"))
+		return []tea.Cmd{m.yank(ref)}
+	},
+	"yankPR": func(m *Model) []tea.Cmd {
+		if cmd := m.yankPR(); cmd != nil {
+			return []tea.Cmd{cmd}
+		}
+		return nil
+	},
+	"share": func(m *Model) []tea.Cmd {
+		branch := m.selectedBranch()
+		if branch == nil {
+			return nil
+		}
+		if branch.Parent == "" {
+			m.statusBar.setStatus(severityWarning, "Select a stack branch to share")
+			return nil
+		}
+		return []tea.Cmd{m.shareStack(branch.Name)}
+	},
+	"palette": func(m *Model) []tea.Cmd {
+		m.openPalette()
+		return nil
+	},
+	"history": func(m *Model) []tea.Cmd {
+		m.openHistory()
+		return nil
+	},
+	"help": func(m *Model) []tea.Cmd {
+		m.openHelp()
+		return nil
+	},
+	"quit": func(m *Model) []tea.Cmd { return []tea.Cmd{m.quit()} },
+}
+
+func init() {
+	for i := range actionSpecs {
+		actionSpecs[i].execute = actionHandlers[actionSpecs[i].name]
+	}
+}
+
+// submitSelected starts the submit p builds for the selected branch,
+// refusing trunk.
+func (m *Model) submitSelected(p func(branch *gt.Branch) pendingSubmit) []tea.Cmd {
+	branch := m.selectedBranch()
+	if branch == nil {
+		return nil
+	}
+	if branch.Parent == "" {
+		m.statusBar.setStatus(severityWarning, "Cannot submit trunk branch")
+		return nil
+	}
+	return m.startSubmit(p(branch))
+}
@@ -1,8 +1,15 @@
 package ui
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -13,73 +20,256 @@ var (
 	helpSectionStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 )
 
+// helpHeaderHeight is the title line and the blank line under it, which
+// stay put while the keybindings scroll.
+const helpHeaderHeight = 2
+
 type helpEntry struct {
 	key  string
 	desc string
 }
 
-func renderHelp() string {
-	sections := []struct {
-		header  string
-		entries []helpEntry
-	}{
-		{
-			header: "Navigation",
-			entries: []helpEntry{
-				{"^/k", "Move cursor up"},
-				{"v/j", "Move cursor down"},
-				{"enter", "Check out selected branch"},
-				{"m", "Check out trunk (main/master)"},
-			},
-		},
-		{
-			header: "Actions",
-			entries: []helpEntry{
-				{"s", "Submit stack"},
-				{"S", "Submit downstack"},
-				{"r", "Restack stack"},
-				{"f", "Fetch (repo sync)"},
-				{"y", "Sync"},
-				{"o", "Open PR in browser"},
-			},
-		},
-		{
-			header: "Views",
-			entries: []helpEntry{
-				{"d", "Open diff view for selected branch"},
-				{"?", "Toggle this help screen"},
-				{"q", "Quit"},
-			},
-		},
-		{
-			header: "Diff View",
-			entries: []helpEntry{
-				{"^v", "Navigate files / scroll diff"},
-				{"tab", "Switch panel focus"},
-				{"esc/d", "Close diff view"},
-			},
-		},
+type helpSection struct {
+	header  string
+	entries []helpEntry
+}
+
+// helpView is the keybinding reference shown in modeHelp. It lives on the
+// model rather than in the main viewport, so its scroll position and
+// search are still there when help is opened again.
+type helpView struct {
+	sections  []helpSection
+	viewport  viewport.Model
+	search    textinput.Model
+	searching bool  // the search has focus and takes every key
+	headers   []int // line of each shown section's header, for jumping
+}
+
+func newHelpView() helpView {
+	search := textinput.New()
+	search.Prompt = ""
+	search.Placeholder = "search keys and actions"
+	search.Cursor.SetMode(cursor.CursorStatic)
+	return helpView{search: search}
+}
+
+// helpContents lists every keybinding by help screen section, with the
+// templates after Actions.
+func helpContents(k keyMap, templates []branchTemplate) []helpSection {
+	sections := make([]helpSection, len(helpSections))
+	for i, header := range helpSections {
+		sections[i].header = header
 	}
+	for _, s := range actionSpecs {
+		i := slices.Index(helpSections, s.section)
+		if i < 0 {
+			continue
+		}
+		sections[i].entries = append(sections[i].entries, helpEntry{s.binding(&k).Help().Key, s.desc})
+	}
+	// Arrow keys move through files and scroll in the diff view rather
+	// than through branches.
+	diff := &sections[slices.Index(helpSections, "Diff View")]
+	diff.entries = slices.Insert(diff.entries, 0, helpEntry{"^v", "Navigate files / scroll diff"})
 
-	var sb strings.Builder
-	sb.WriteString(helpTitleStyle.Render("grit - Keybindings"))
-	sb.WriteString("\n\n")
+	if len(templates) > 0 {
+		var entries []helpEntry
+		for _, t := range templates {
+			entries = append(entries, helpEntry{t.binding.Help().Key, t.binding.Help().Desc})
+		}
+		// After Actions.
+		sections = slices.Insert(sections, 2, helpSection{header: "Templates", entries: entries})
+	}
+	return sections
+}
 
+// filterHelp keeps the entries whose key or description contains query,
+// ignoring case, and drops sections left empty.
+func filterHelp(sections []helpSection, query string) []helpSection {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return sections
+	}
+	var filtered []helpSection
+	for _, s := range sections {
+		var entries []helpEntry
+		for _, e := range s.entries {
+			if strings.Contains(strings.ToLower(e.key), query) || strings.Contains(strings.ToLower(e.desc), query) {
+				entries = append(entries, e)
+			}
+		}
+		if len(entries) > 0 {
+			filtered = append(filtered, helpSection{header: s.header, entries: entries})
+		}
+	}
+	return filtered
+}
+
+// renderHelp renders every keybinding, as the help screen shows them
+// before a search.
+func renderHelp(k keyMap, templates []branchTemplate) string {
+	body, _ := renderHelpSections(helpContents(k, templates), "")
+	return helpTitleStyle.Render("grit - Keybindings") + "\n\n" + body
+}
+
+// renderHelpSections renders sections under their headers and returns the
+// line each header is on. query is only used to say nothing matched it.
+func renderHelpSections(sections []helpSection, query string) (string, []int) {
+	var sb strings.Builder
+	var headers []int
+	line := 0
+	if len(sections) == 0 {
+		sb.WriteString(helpDescStyle.Render(fmt.Sprintf("No keybindings match %q", query)))
+		sb.WriteString("\n")
+		line++
+	}
 	for i, section := range sections {
 		if i > 0 {
 			sb.WriteString("\n")
+			line++
 		}
+		headers = append(headers, line)
 		sb.WriteString(helpSectionStyle.Render("--- " + section.header + " ---"))
 		sb.WriteString("\n")
+		line++
 		for _, e := range section.entries {
 			sb.WriteString(helpKeyStyle.Render(e.key))
 			sb.WriteString(helpDescStyle.Render(e.desc))
 			sb.WriteString("\n")
+			line++
 		}
 	}
 
 	sb.WriteString("\n")
-	sb.WriteString(helpSectionStyle.Render("Press ? or esc to close"))
+	sb.WriteString(helpSectionStyle.Render("Press ? or esc to close, / to search"))
+	return sb.String(), headers
+}
+
+// refresh reloads the keybindings, which profiles and templates change,
+// keeping the scroll position where the content allows.
+func (h *helpView) refresh(k keyMap, templates []branchTemplate) {
+	h.sections = helpContents(k, templates)
+	h.render()
+}
+
+// render fills the viewport with the sections matching the search.
+func (h *helpView) render() {
+	var content string
+	content, h.headers = renderHelpSections(filterHelp(h.sections, h.search.Value()), h.search.Value())
+	h.viewport.SetContent(content)
+}
+
+func (h *helpView) setSize(width, height int) {
+	h.viewport.Width = width
+	h.viewport.Height = max(height-helpHeaderHeight, 1)
+}
+
+// jumpSection scrolls the next (delta > 0) or previous section's header to
+// the top.
+func (h *helpView) jumpSection(delta int) {
+	y := h.viewport.YOffset
+	if delta > 0 {
+		if i := slices.IndexFunc(h.headers, func(line int) bool { return line > y }); i >= 0 {
+			h.viewport.SetYOffset(h.headers[i])
+		}
+		return
+	}
+	for _, line := range slices.Backward(h.headers) {
+		if line < y {
+			h.viewport.SetYOffset(line)
+			return
+		}
+	}
+}
+
+// matches counts the entries shown.
+func (h helpView) matches() int {
+	n := 0
+	for _, s := range filterHelp(h.sections, h.search.Value()) {
+		n += len(s.entries)
+	}
+	return n
+}
+
+// view renders the title, with the search while there is one, over the
+// scrolling keybindings.
+func (h helpView) view() string {
+	title := helpTitleStyle.Render("grit - Keybindings")
+	if h.searching || h.search.Value() != "" {
+		title += "  " + promptLabelStyle.Render("/ ") + h.search.View()
+		if h.search.Value() != "" {
+			title += "  " + pickerCountStyle.Render(pluralize(h.matches(), "keybinding"))
+		}
+	}
+	return title + "\n\n" + h.viewport.View()
+}
+
+// openHelp shows the help screen where it was last left.
+func (m *Model) openHelp() {
+	m.help.refresh(m.keys, m.templates)
+	m.setMode(modeHelp)
+	m.resizeViewport()
+}
 
-	return sb.String()
+// closeHelp returns to the tree. The help's scroll position and search are
+// kept for next time.
+func (m *Model) closeHelp() {
+	m.help.searching = false
+	m.help.search.Blur()
+	m.setMode(modeTree)
+	m.resizeViewport()
+	m.viewport.SetContent(m.treeContent())
+}
+
+// updateHelp handles a key on the help screen. While searching, keys edit
+// the search: enter keeps it and esc clears it. Otherwise / starts a
+// search, tab and shift+tab jump between sections, esc clears a search
+// or closes help, and the usual keys scroll.
+func (m *Model) updateHelp(msg tea.KeyMsg) []tea.Cmd {
+	h := &m.help
+	if h.searching {
+		switch msg.Type {
+		case tea.KeyEnter:
+			h.searching = false
+			h.search.Blur()
+		case tea.KeyEscape:
+			h.searching = false
+			h.search.Blur()
+			h.search.SetValue("")
+			h.render()
+			h.viewport.GotoTop()
+		default:
+			var cmd tea.Cmd
+			h.search, cmd = h.search.Update(msg)
+			h.render()
+			h.viewport.GotoTop()
+			return []tea.Cmd{cmd}
+		}
+		m.resizeViewport()
+		return nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Help):
+		m.closeHelp()
+	case msg.Type == tea.KeyEscape && h.search.Value() != "":
+		h.search.SetValue("")
+		h.render()
+		m.resizeViewport()
+	case msg.Type == tea.KeyEscape:
+		m.closeHelp()
+	case msg.String() == "/":
+		h.searching = true
+		m.resizeViewport()
+		return []tea.Cmd{h.search.Focus()}
+	case msg.Type == tea.KeyTab:
+		h.jumpSection(1)
+	case msg.Type == tea.KeyShiftTab:
+		h.jumpSection(-1)
+	default:
+		var cmd tea.Cmd
+		h.viewport, cmd = h.viewport.Update(msg)
+		return []tea.Cmd{cmd}
+	}
+	return nil
 }
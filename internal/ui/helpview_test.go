@@ -1,13 +1,15 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/ansi"
 )
 
 func TestRenderHelp_ContainsSections(t *testing.T) {
-	result := ansi.Strip(renderHelp())
+	result := ansi.Strip(renderHelp(defaultKeyMap(), nil))
 
 	sections := []string{"Navigation", "Actions", "Views", "Diff View"}
 	for _, section := range sections {
@@ -18,7 +20,7 @@ func TestRenderHelp_ContainsSections(t *testing.T) {
 }
 
 func TestRenderHelp_ContainsKeys(t *testing.T) {
-	result := ansi.Strip(renderHelp())
+	result := ansi.Strip(renderHelp(defaultKeyMap(), nil))
 
 	keys := []string{
 		"enter", "Check out selected branch",
@@ -36,9 +38,86 @@ func TestRenderHelp_ContainsKeys(t *testing.T) {
 }
 
 func TestRenderHelp_ContainsCloseInstruction(t *testing.T) {
-	result := ansi.Strip(renderHelp())
+	result := ansi.Strip(renderHelp(defaultKeyMap(), nil))
 
 	if !containsString(result, "Press ? or esc to close") {
 		t.Error("help should contain close instruction")
 	}
 }
+
+func TestFilterHelp(t *testing.T) {
+	sections := []helpSection{
+		{header: "Actions", entries: []helpEntry{{"s", "Submit stack"}, {"r", "Restack"}}},
+		{header: "Views", entries: []helpEntry{{"%", "Stats view"}}},
+	}
+	got := filterHelp(sections, " SUBMIT ")
+	if len(got) != 1 || len(got[0].entries) != 1 || got[0].entries[0].key != "s" {
+		t.Errorf("filterHelp = %+v, want only Submit stack", got)
+	}
+	if got := filterHelp(sections, "%"); len(got) != 1 || got[0].header != "Views" {
+		t.Errorf("keys should match too, got %+v", got)
+	}
+	if got := filterHelp(sections, ""); len(got) != 2 {
+		t.Errorf("an empty query should keep every section, got %d", len(got))
+	}
+}
+
+func TestHelpView_JumpSection(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m = sendKey(m, '?')
+	headers := m.help.headers
+	if len(headers) < 3 {
+		t.Fatalf("headers = %v, want one per section", headers)
+	}
+	m = sendSpecialKey(m, tea.KeyTab)
+	if m.help.viewport.YOffset != headers[1] {
+		t.Errorf("tab: offset = %d, want the second section at %d", m.help.viewport.YOffset, headers[1])
+	}
+	m = sendSpecialKey(m, tea.KeyTab)
+	m = sendSpecialKey(m, tea.KeyShiftTab)
+	if m.help.viewport.YOffset != headers[1] {
+		t.Errorf("shift+tab: offset = %d, want back at %d", m.help.viewport.YOffset, headers[1])
+	}
+}
+
+func TestHelpView_Search(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m = sendKey(m, '?')
+	m = sendKey(m, '/')
+	m = typeText(m, "quit")
+	if m.mode != modeHelp || m.help.search.Value() != "quit" {
+		t.Fatalf("typing should edit the search, not run keys: mode %d, search %q", m.mode, m.help.search.Value())
+	}
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "Quit") || strings.Contains(view, "Submit stack") {
+		t.Errorf("only matching keybindings should show:\n%s", view)
+	}
+
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.help.searching {
+		t.Fatal("enter should keep the search and leave it")
+	}
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeHelp || m.help.search.Value() != "" {
+		t.Errorf("esc should clear a kept search first, mode %d, search %q", m.mode, m.help.search.Value())
+	}
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree {
+		t.Error("esc without a search should close help")
+	}
+}
+
+func TestHelpView_KeepsPlaceWhenReopened(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m = sendKey(m, '?')
+	m = sendSpecialKey(m, tea.KeyTab)
+	offset := m.help.viewport.YOffset
+	if offset == 0 {
+		t.Fatal("tab should have scrolled")
+	}
+	m = sendKey(m, '?')
+	m = sendKey(m, '?')
+	if m.help.viewport.YOffset != offset {
+		t.Errorf("offset = %d after reopening, want %d", m.help.viewport.YOffset, offset)
+	}
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// historyFile is where the command history is persisted, relative to the
+// git dir.
+const historyFile = "grit/history.json"
+
+// historyMax is how many commands the history keeps; older ones drop off.
+const historyMax = 50
+
+// historyEntry is a mutating command that ran: an action on a branch.
+// Running the same action on the same branch again replaces the entry.
+type historyEntry struct {
+	Action string    `json:"action"`           // actionSpec name, e.g. "stackSubmit"
+	Branch string    `json:"branch,omitempty"` // "" for repo-wide actions
+	At     time.Time `json:"at"`
+	Failed bool      `json:"failed,omitempty"`
+}
+
+// pendingCommand is the command whose actionResultMsg is awaited, so it
+// can be recorded once it has run.
+type pendingCommand struct {
+	action string // actionSpec.mutates, as actionResultMsg reports it
+	entry  historyEntry
+}
+
+// loadHistory reads the command history from gitDir, oldest first. A
+// missing or unreadable file means an empty history.
+func loadHistory(gitDir string) []historyEntry {
+	if gitDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, historyFile))
+	if err != nil {
+		return nil
+	}
+	var entries []historyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil
+	}
+	return entries
+}
+
+// saveHistory writes the command history to gitDir.
+func saveHistory(gitDir string, entries []historyEntry) error {
+	if gitDir == "" {
+		return nil
+	}
+	path := filepath.Join(gitDir, historyFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// addHistory appends e to entries, dropping an earlier run of the same
+// command and the oldest entries past historyMax.
+func addHistory(entries []historyEntry, e historyEntry) []historyEntry {
+	entries = slices.DeleteFunc(slices.Clone(entries), func(old historyEntry) bool {
+		return old.Action == e.Action && old.Branch == e.Branch
+	})
+	entries = append(entries, e)
+	if len(entries) > historyMax {
+		entries = entries[len(entries)-historyMax:]
+	}
+	return entries
+}
+
+// startedCommand notes that s started on branch, to be recorded when its
+// result arrives.
+func (m *Model) startedCommand(s *actionSpec, branch string) {
+	if s == nil || s.noHistory {
+		return
+	}
+	m.pendingCommand = pendingCommand{action: s.mutates, entry: historyEntry{Action: s.name, Branch: branch}}
+}
+
+// recordCommand adds the pending command to the history if action is its
+// result, failed or not: a failed command is often the one to run again.
+func (m *Model) recordCommand(action string, failed bool, now time.Time) {
+	if m.pendingCommand.action == "" || m.pendingCommand.action != action {
+		return
+	}
+	e := m.pendingCommand.entry
+	m.pendingCommand = pendingCommand{}
+	e.At, e.Failed = now, failed
+	m.history = addHistory(m.history, e)
+	_ = saveHistory(m.gitDir, m.history)
+}
+
+// historyLabel names e in the history picker, e.g. "submit stack · feature".
+func historyLabel(s *actionSpec, e historyEntry) string {
+	if e.Branch == "" {
+		return s.help
+	}
+	return s.help + " · " + e.Branch
+}
+
+// openHistory lists the commands run, newest first, and runs the one
+// picked again on its branch.
+func (m *Model) openHistory() {
+	specs := make(map[string]*actionSpec, len(actionSpecs))
+	for i := range actionSpecs {
+		specs[actionSpecs[i].name] = &actionSpecs[i]
+	}
+	entries := make(map[string]historyEntry)
+	var items []pickerItem
+	for _, e := range slices.Backward(m.history) {
+		s := specs[e.Action]
+		if s == nil {
+			continue // an action since removed or renamed
+		}
+		label := historyLabel(s, e)
+		entries[label] = e
+		items = append(items, pickerItem{name: label})
+	}
+	if len(items) == 0 {
+		m.statusBar.setStatus(severityInfo, "No commands run yet")
+		return
+	}
+	m.openPicker(picker{
+		title:   "Run a command again",
+		noun:    "commands",
+		items:   items,
+		ordered: true,
+		pick: func(m *Model, item pickerItem) []tea.Cmd {
+			e := entries[item.name]
+			return m.rerunCommand(specs[e.Action], e)
+		},
+		preview: func(m Model, item pickerItem) string {
+			e := entries[item.name]
+			preview := fmt.Sprintf("key %s · ran %s", specs[e.Action].binding(&m.keys).Help().Key, timeAgo(e.At, time.Now()))
+			if e.Failed {
+				preview += " · failed"
+			}
+			return preview
+		},
+	})
+}
+
+// rerunCommand selects e's branch and runs its action there, as its key
+// does.
+func (m *Model) rerunCommand(s *actionSpec, e historyEntry) []tea.Cmd {
+	if e.Branch != "" {
+		if gt.FindBranch(m.branches, e.Branch) == nil {
+			m.statusBar.setStatus(severityWarning, e.Branch+" no longer exists")
+			return nil
+		}
+		m.rebuildEntries(e.Branch)
+		if b := m.selectedBranch(); b == nil || b.Name != e.Branch {
+			m.statusBar.setStatus(severityWarning, e.Branch+" is hidden by the tree filters")
+			return nil
+		}
+	}
+	if !s.applies(*m) {
+		m.statusBar.setStatus(severityWarning, "Can't "+s.help+" right now")
+		return nil
+	}
+	// Picking from the history is deliberate, not a key repeat to drop.
+	m.actionGuard = actionGuard{}
+	return m.runSpec(s)
+}
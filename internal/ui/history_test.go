@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestHistory_RecordsAndRerunsOnBranch(t *testing.T) {
+	gitDir := t.TempDir()
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), gitDir)
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	m.cursor = 1 // feature-base
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlR}))
+	m = updated.(Model)
+	runBatch(cmd)
+	updated, _ = m.Update(actionResultMsg{action: "branch-restack", err: errors.New("exit status 1")})
+	m = updated.(Model)
+
+	want := []historyEntry{{Action: "branchRestack", Branch: "feature-base", Failed: true}}
+	saved := loadHistory(gitDir)
+	if len(saved) != 1 || saved[0].Action != want[0].Action || saved[0].Branch != want[0].Branch || !saved[0].Failed {
+		t.Fatalf("saved history = %+v, want %+v", saved, want)
+	}
+
+	*calls = nil
+	m.cursor = 0
+	m = sendKey(m, 'h')
+	if m.mode != modePicker {
+		t.Fatalf("h should open the history picker, mode = %d", m.mode)
+	}
+	if view := m.View(); !strings.Contains(view, "restack branch · feature-base") || !strings.Contains(view, "failed") {
+		t.Errorf("picker should list the command and that it failed:\n%s", view)
+	}
+	updated, cmd = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if b := m.selectedBranch(); b == nil || b.Name != "feature-base" {
+		t.Errorf("selected = %v, want the command's branch", b)
+	}
+	wantArgs := []string{"branch", "restack", "--no-interactive", "--branch", "feature-base"}
+	if len(*calls) != 1 || !slices.Equal((*calls)[0].args, wantArgs) {
+		t.Errorf("calls = %v, want %v", *calls, wantArgs)
+	}
+}
+
+func TestHistory_SkipsRefusedAndCheckouts(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.cursor = 1
+	m = sendKey(m, 'Q') // refused: feature-base has no PR
+	updated, _ := m.Update(actionResultMsg{action: "merge"})
+	m = updated.(Model)
+	m = sendSpecialKey(m, tea.KeyEnter) // checkout
+	updated, _ = m.Update(actionResultMsg{action: "checkout"})
+	m = updated.(Model)
+	if len(m.history) != 0 {
+		t.Errorf("history = %+v, want nothing recorded", m.history)
+	}
+	m = sendKey(m, 'h')
+	if m.mode != modeTree || m.statusBar.message != "No commands run yet" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+}
+
+func TestAddHistory_ReplacesRepeatsAndCaps(t *testing.T) {
+	var entries []historyEntry
+	for i := range historyMax + 5 {
+		entries = addHistory(entries, historyEntry{Action: "restack", Branch: fmt.Sprint(i)})
+	}
+	if len(entries) != historyMax || entries[0].Branch != "5" {
+		t.Fatalf("len = %d, first = %q, want the oldest dropped past %d", len(entries), entries[0].Branch, historyMax)
+	}
+	entries = addHistory(entries, historyEntry{Action: "restack", Branch: "5"})
+	if len(entries) != historyMax || entries[0].Branch != "6" || entries[len(entries)-1].Branch != "5" {
+		t.Errorf("a repeat should move to the end without growing the history: first %q, last %q",
+			entries[0].Branch, entries[len(entries)-1].Branch)
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleCheckMsg fires when the idle timeout may have elapsed.
+type idleCheckMsg struct{}
+
+// idleCheck schedules an idle check after d, or returns nil when the idle
+// timeout is disabled.
+func (m Model) idleCheck(d time.Duration) tea.Cmd {
+	if m.idleTimeout <= 0 {
+		return nil
+	}
+	return tea.Tick(d, func(time.Time) tea.Msg { return idleCheckMsg{} })
+}
+
+// checkIdle handles an idle check: once there has been no input for the
+// idle timeout, auto-refresh is paused (or grit exits, if configured);
+// otherwise the next check is scheduled for when the timeout would elapse.
+// Only one check is outstanding at a time, and none while idle.
+func (m *Model) checkIdle(now time.Time) tea.Cmd {
+	if m.idle || m.idleTimeout <= 0 {
+		return nil
+	}
+	remaining := m.idleTimeout - now.Sub(m.lastActivity)
+	if remaining > 0 {
+		return m.idleCheck(remaining)
+	}
+	if m.idleExit {
+		if m.watcher != nil {
+			m.watcher.Close()
+		}
+		return tea.Quit
+	}
+	m.idle = true
+	m.statusBar.setStatus(severityInfo, "Idle — auto-refresh paused; press any key to resume")
+	return nil
+}
+
+// wake records input. If grit was idle it resumes auto-refresh, reloading
+// the tree to catch up on changes missed while paused and restarting the
+// PR poll, and reports true so the waking key is not acted on.
+func (m *Model) wake(now time.Time) (bool, []tea.Cmd) {
+	m.lastActivity = now
+	if !m.idle {
+		return false, nil
+	}
+	m.idle = false
+	m.statusBar.setStatus(severityInfo, "Resumed auto-refresh")
+	m.pollSeq++
+	return true, []tea.Cmd{m.loadLog(), m.idleCheck(m.idleTimeout), m.schedulePoll()}
+}
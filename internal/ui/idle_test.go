@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func idleModel(cfg config.Config) Model {
+	m := NewWithConfig(gt.New(simpleMock("", nil)), "", cfg)
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n◯─┘  main"})
+	return updated.(Model)
+}
+
+func TestCheckIdle_Disabled(t *testing.T) {
+	m := idleModel(config.Config{})
+	if m.idleCheck(time.Minute) != nil {
+		t.Error("no idle checks should be scheduled without a timeout")
+	}
+	m.lastActivity = time.Now().Add(-time.Hour)
+	if cmd := m.checkIdle(time.Now()); cmd != nil || m.idle {
+		t.Error("disabled timeout should never go idle")
+	}
+}
+
+func TestCheckIdle_ReschedulesBeforeTimeout(t *testing.T) {
+	m := idleModel(config.Config{IdleTimeout: 5})
+	now := time.Now()
+	m.lastActivity = now.Add(-2 * time.Minute)
+	if cmd := m.checkIdle(now); cmd == nil {
+		t.Error("expected the next check to be scheduled")
+	}
+	if m.idle {
+		t.Error("should not be idle before the timeout")
+	}
+}
+
+func TestCheckIdle_PausesAndWakes(t *testing.T) {
+	m := idleModel(config.Config{IdleTimeout: 5})
+	m.lastActivity = time.Now().Add(-10 * time.Minute)
+	updated, _ := m.Update(idleCheckMsg{})
+	m = updated.(Model)
+	if !m.idle {
+		t.Fatal("expected idle after the timeout")
+	}
+
+	// Watcher events are drained but don't reload while idle.
+	updated, cmd := m.Update(gitChangeMsg{})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("git change while idle (no watcher) should schedule nothing")
+	}
+	seq := m.debounceSeq
+	updated, cmd = m.Update(debounceFireMsg{seq: seq})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("pending debounce should not reload while idle")
+	}
+
+	// The waking key is swallowed: j must not move the cursor.
+	cursor := m.cursor
+	updated, cmd = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'j'}}))
+	m = updated.(Model)
+	if m.idle {
+		t.Error("key press should wake")
+	}
+	if m.cursor != cursor {
+		t.Error("waking key should not be acted on")
+	}
+	if cmd == nil {
+		t.Error("waking should reload the tree")
+	}
+	if m.statusBar.message != "Resumed auto-refresh" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+func TestCheckIdle_Exit(t *testing.T) {
+	m := idleModel(config.Config{IdleTimeout: 1, IdleExit: true})
+	m.lastActivity = time.Now().Add(-2 * time.Minute)
+	_, cmd := m.Update(idleCheckMsg{})
+	if cmd == nil {
+		t.Fatal("expected quit command")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("idle exit should quit")
+	}
+}
+
+func TestWake_QuitKeyStillQuits(t *testing.T) {
+	m := idleModel(config.Config{IdleTimeout: 5})
+	m.idle = true
+	_, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'q'}}))
+	if cmd == nil {
+		t.Fatal("expected quit command")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("q while idle should quit")
+	}
+}
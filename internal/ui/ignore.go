@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreFileName is the per-repo file listing paths to hide from diff file
+// lists and changed-file counts, using .gitignore-style patterns.
+const ignoreFileName = ".gritignore"
+
+// ignoreRule is a single parsed .gitignore-style pattern.
+type ignoreRule struct {
+	segments []string // pattern split on "/"
+	negate   bool     // "!pattern" re-includes a path
+	dirOnly  bool     // "pattern/" only matches directories
+	anchored bool     // contains a slash, so matches from the repo root
+}
+
+// ignoreMatcher decides whether a repo-relative file path is ignored.
+// As with .gitignore, the last matching rule wins.
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// parseIgnorePatterns builds a matcher from .gitignore-style lines.
+// Blank lines and "#" comments are skipped.
+func parseIgnorePatterns(lines []string) ignoreMatcher {
+	var m ignoreMatcher
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if r, ok := parseIgnoreRule(line); ok {
+			m.rules = append(m.rules, r)
+		}
+	}
+	return m
+}
+
+// parseIgnoreRule parses a single .gitignore-style pattern. Returns false
+// if the pattern is empty after stripping modifiers.
+func parseIgnoreRule(pattern string) (ignoreRule, bool) {
+	var r ignoreRule
+	if strings.HasPrefix(pattern, "!") {
+		r.negate = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if strings.Contains(pattern, "/") {
+		r.anchored = true
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+	if pattern == "" {
+		return r, false
+	}
+	r.segments = strings.Split(pattern, "/")
+	return r, true
+}
+
+// loadIgnoreMatcher combines patterns from the repo's .gritignore, files
+// marked linguist-vendored or linguist-generated in .gitattributes, and any
+// extra patterns from config. Missing files are skipped.
+func loadIgnoreMatcher(repoRoot string, extra []string) ignoreMatcher {
+	var lines []string
+	lines = append(lines, linguistPatterns(readLines(filepath.Join(repoRoot, ".gitattributes")))...)
+	lines = append(lines, readLines(filepath.Join(repoRoot, ignoreFileName))...)
+	lines = append(lines, extra...)
+	return parseIgnorePatterns(lines)
+}
+
+// readLines returns the lines of a file, or nil if it can't be read.
+func readLines(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	return lines
+}
+
+// linguistPatterns extracts the patterns of .gitattributes lines that set
+// linguist-vendored or linguist-generated (and don't unset them).
+func linguistPatterns(lines []string) []string {
+	var patterns []string
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			if attr == "linguist-vendored" || attr == "linguist-generated" ||
+				attr == "linguist-vendored=true" || attr == "linguist-generated=true" {
+				patterns = append(patterns, fields[0])
+				break
+			}
+		}
+	}
+	return patterns
+}
+
+func (m ignoreMatcher) empty() bool {
+	return len(m.rules) == 0
+}
+
+// ignored reports whether file matches the patterns.
+func (m ignoreMatcher) ignored(file string) bool {
+	parts := strings.Split(file, "/")
+	ignored := false
+	for _, r := range m.rules {
+		if r.matches(parts) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the rule matches the file or one of its parent
+// directories. parts is the file path split on "/".
+func (r ignoreRule) matches(parts []string) bool {
+	// n is the number of leading path components being tested; n < len(parts)
+	// tests a parent directory, n == len(parts) the file itself.
+	for n := 1; n <= len(parts); n++ {
+		if r.dirOnly && n == len(parts) {
+			break
+		}
+		if r.anchored {
+			if matchSegments(r.segments, parts[:n]) {
+				return true
+			}
+		} else if ok, _ := path.Match(r.segments[0], parts[n-1]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches glob segments against path components, where a
+// "**" segment matches zero or more components.
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
+
+// filterIgnored returns files that are not ignored.
+func (m ignoreMatcher) filterIgnored(files []string) []string {
+	if m.empty() {
+		return files
+	}
+	var kept []string
+	for _, f := range files {
+		if !m.ignored(f) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	m := parseIgnorePatterns([]string{
+		"# comment",
+		"",
+		"vendor/",
+		"*.snap",
+		"/docs/generated",
+		"web/**/fixtures/*.json",
+		"!keep.snap",
+	})
+
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"vendor/lib/a.go", true},
+		{"pkg/vendor/b.go", true},
+		{"vendor", false}, // dirOnly: a file named vendor is kept
+		{"ui/__snapshots__/x.snap", true},
+		{"ui/keep.snap", false}, // negated
+		{"docs/generated/api.md", true},
+		{"sub/docs/generated/api.md", false}, // anchored to root
+		{"web/fixtures/a.json", true},
+		{"web/a/b/fixtures/a.json", true},
+		{"web/fixtures/a.ts", false},
+		{"main.go", false},
+	}
+	for _, tt := range tests {
+		if got := m.ignored(tt.file); got != tt.want {
+			t.Errorf("ignored(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestIgnoreMatcher_Empty(t *testing.T) {
+	m := parseIgnorePatterns(nil)
+	if !m.empty() {
+		t.Error("matcher with no patterns should be empty")
+	}
+	files := []string{"a.go"}
+	if got := m.filterIgnored(files); len(got) != 1 {
+		t.Errorf("filterIgnored = %v, want unchanged", got)
+	}
+}
+
+func TestLinguistPatterns(t *testing.T) {
+	lines := []string{
+		"# comment linguist-vendored",
+		"third_party/** linguist-vendored",
+		"*.pb.go linguist-generated=true",
+		"*.md linguist-documentation",
+		"gen/** -linguist-generated",
+		"*.go text eol=lf",
+	}
+	got := linguistPatterns(lines)
+	want := []string{"third_party/**", "*.pb.go"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("pattern[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadIgnoreMatcher_CombinesSources(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".gritignore"), []byte("*.snap\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitattributes"), []byte("*.pb.go linguist-generated\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := loadIgnoreMatcher(root, []string{"vendor/"})
+	for _, f := range []string{"a.snap", "api.pb.go", "vendor/x.go"} {
+		if !m.ignored(f) {
+			t.Errorf("%q should be ignored", f)
+		}
+	}
+	if m.ignored("main.go") {
+		t.Error("main.go should not be ignored")
+	}
+}
+
+func TestIgnore_FiltersDiffFilesAndCounts(t *testing.T) {
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if args[1] == "--stat" {
+			return " main.go | 2 +-\n vendor/x.go | 9 +++++\n 2 files changed\n", nil
+		}
+		return "main.go\nvendor/x.go\n", nil
+	}}
+	m := NewWithConfig(gt.New(mock), "", config.Config{Ignore: []string{"vendor/"}})
+
+	diff := m.loadDiffData("main", "feature-a")().(diffDataMsg)
+	if len(diff.files) != 1 || diff.files[0].path != "main.go" {
+		t.Errorf("diff files = %v, want only main.go", diff.files)
+	}
+
+	m.branches = []*gt.Branch{{Name: "main", Children: []*gt.Branch{{Name: "feature-a"}}}}
+	changes := runJob(m.changesJob()).(changesResultMsg)
+	if got := changes.changes["feature-a"]; got.Files != 1 {
+		t.Errorf("feature-a = %+v, want 1 file after ignoring vendor/", got)
+	}
+}
@@ -0,0 +1,350 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+const (
+	// maxBackgroundJobs is how many background jobs may run at once; the
+	// rest wait in the queue. Foreground jobs always start immediately.
+	maxBackgroundJobs = 2
+	// maxJobHistory is how many finished jobs are kept for the jobs view.
+	maxJobHistory = 50
+)
+
+// jobFunc is the body of a job. It must honour ctx cancellation and returns
+// the message to deliver to Update, plus an error if the job failed.
+type jobFunc func(ctx context.Context) (tea.Msg, error)
+
+// jobDoneMsg is sent when a job's function returns. result is forwarded to
+// Update as if the job's command had produced it directly.
+type jobDoneMsg struct {
+	id     int
+	result tea.Msg
+	err    error
+}
+
+// viewTickMsg refreshes live views (jobs durations, debug counters) once a
+// second while they are open.
+type viewTickMsg struct{}
+
+type jobState int
+
+const (
+	jobQueued jobState = iota
+	jobRunning
+	jobDone
+	jobFailed
+	jobCancelled
+)
+
+// job is one unit of async work tracked by the scheduler.
+type job struct {
+	id         int
+	label      string
+	background bool
+	state      jobState
+	err        string
+	queuedAt   time.Time
+	startedAt  time.Time
+	finishedAt time.Time
+	run        jobFunc
+	cancel     context.CancelFunc
+}
+
+func (j *job) finished() bool {
+	return j.state == jobDone || j.state == jobFailed || j.state == jobCancelled
+}
+
+// duration returns how long the job has run (or waited, while queued).
+func (j *job) duration(now time.Time) time.Duration {
+	switch {
+	case j.state == jobQueued:
+		return now.Sub(j.queuedAt)
+	case j.finishedAt.IsZero():
+		return now.Sub(j.startedAt)
+	default:
+		return j.finishedAt.Sub(j.startedAt)
+	}
+}
+
+// jobScheduler tracks async work started from the UI. It is only touched
+// from Update, so it needs no locking; job functions run in tea.Cmd
+// goroutines and report back via jobDoneMsg.
+type jobScheduler struct {
+	jobs   []*job // in submission order
+	nextID int
+	now    func() time.Time
+}
+
+func newJobScheduler() *jobScheduler {
+	return &jobScheduler{now: time.Now}
+}
+
+// submit registers a job and returns the command that runs it, or nil if
+// it was queued behind other background jobs. A background job with the
+// same label as one still queued replaces that job's work instead of
+// queueing a duplicate, so repeated refreshes coalesce.
+func (s *jobScheduler) submit(label string, background bool, run jobFunc) tea.Cmd {
+	if background {
+		for _, j := range s.jobs {
+			if j.background && j.state == jobQueued && j.label == label {
+				j.run = run
+				return nil
+			}
+		}
+	}
+	s.nextID++
+	j := &job{id: s.nextID, label: label, background: background, state: jobQueued, queuedAt: s.now(), run: run}
+	s.jobs = append(s.jobs, j)
+	if background && s.runningBackground() >= maxBackgroundJobs {
+		return nil
+	}
+	return s.start(j, context.Background())
+}
+
+func (s *jobScheduler) start(j *job, parent context.Context) tea.Cmd {
+	ctx, cancel := context.WithCancel(parent)
+	j.cancel = cancel
+	if j.state == jobQueued {
+		j.state = jobRunning
+	}
+	j.startedAt = s.now()
+	id, run := j.id, j.run
+	return func() tea.Msg {
+		result, err := run(ctx)
+		return jobDoneMsg{id: id, result: result, err: err}
+	}
+}
+
+func (s *jobScheduler) runningBackground() int {
+	n := 0
+	for _, j := range s.jobs {
+		if j.background && j.state == jobRunning {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *jobScheduler) find(id int) *job {
+	for _, j := range s.jobs {
+		if j.id == id {
+			return j
+		}
+	}
+	return nil
+}
+
+// finish records a job's completion and returns it, or nil if unknown.
+// A cancelled job stays cancelled whatever its function returned.
+func (s *jobScheduler) finish(id int, err error) *job {
+	j := s.find(id)
+	if j == nil {
+		return nil
+	}
+	if j.cancel != nil {
+		j.cancel()
+	}
+	j.finishedAt = s.now()
+	if j.state != jobCancelled {
+		if err != nil {
+			j.state = jobFailed
+			j.err = gt.Redact(err.Error())
+		} else {
+			j.state = jobDone
+		}
+	}
+	s.prune()
+	return j
+}
+
+// cancel stops a queued or running job. A running job's context is
+// cancelled and its jobDoneMsg arrives once it unwinds. A queued job is
+// started with an already-cancelled context so its function still reports
+// back and callers can clean up; the returned command does that.
+func (s *jobScheduler) cancel(id int) (tea.Cmd, bool) {
+	j := s.find(id)
+	if j == nil || j.finished() {
+		return nil, false
+	}
+	wasQueued := j.state == jobQueued
+	j.state = jobCancelled
+	if !wasQueued {
+		j.cancel()
+		return nil, true
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return s.start(j, ctx), true
+}
+
+// startQueued starts queued background jobs while slots are free.
+func (s *jobScheduler) startQueued() []tea.Cmd {
+	var cmds []tea.Cmd
+	for _, j := range s.jobs {
+		if s.runningBackground() >= maxBackgroundJobs {
+			break
+		}
+		if j.state == jobQueued {
+			cmds = append(cmds, s.start(j, context.Background()))
+		}
+	}
+	return cmds
+}
+
+// prune drops the oldest finished jobs beyond maxJobHistory.
+func (s *jobScheduler) prune() {
+	finished := 0
+	for _, j := range s.jobs {
+		if j.finished() {
+			finished++
+		}
+	}
+	if finished <= maxJobHistory {
+		return
+	}
+	drop := finished - maxJobHistory
+	kept := s.jobs[:0]
+	for _, j := range s.jobs {
+		if drop > 0 && j.finished() {
+			drop--
+			continue
+		}
+		kept = append(kept, j)
+	}
+	s.jobs = kept
+}
+
+// listed returns jobs in display order, newest first.
+func (s *jobScheduler) listed() []*job {
+	out := make([]*job, len(s.jobs))
+	for i, j := range s.jobs {
+		out[len(s.jobs)-1-i] = j
+	}
+	return out
+}
+
+// pending reports whether a job labelled label is queued or running.
+func (s *jobScheduler) pending(label string) bool {
+	for _, j := range s.jobs {
+		if j.label == label && !j.finished() {
+			return true
+		}
+	}
+	return false
+}
+
+// active reports whether any job is queued or running.
+func (s *jobScheduler) active() bool {
+	for _, j := range s.jobs {
+		if !j.finished() {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	jobRunningStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	jobQueuedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	jobDoneStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	jobFailedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	jobCancelledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+)
+
+// jobStatus returns the styled marker and state name for a job.
+func jobStatus(state jobState) string {
+	style := jobCancelledStyle
+	switch state {
+	case jobQueued:
+		style = jobQueuedStyle
+	case jobRunning:
+		style = jobRunningStyle
+	case jobDone:
+		style = jobDoneStyle
+	case jobFailed:
+		style = jobFailedStyle
+	}
+	return style.Render(jobStateName(state))
+}
+
+// formatJobDuration renders a duration compactly: tenths of a second under
+// a minute, whole seconds above.
+func formatJobDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
+
+// renderJobs renders the jobs view with the row at cursor highlighted
+// using highlight.
+func renderJobs(jobs []*job, cursor int, now time.Time, highlight lipgloss.Style) string {
+	var sb strings.Builder
+	sb.WriteString(helpTitleStyle.Render("Jobs"))
+	sb.WriteString("\n\n")
+	if len(jobs) == 0 {
+		sb.WriteString(helpDescStyle.Render("No jobs yet."))
+		return sb.String()
+	}
+	for i, j := range jobs {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		label := j.label
+		if j.err != "" {
+			label += " — " + lastLine(j.err)
+		}
+		duration := formatJobDuration(j.duration(now))
+		if i == cursor {
+			sb.WriteString(highlight.Render(fmt.Sprintf("%s  %7s  %s", jobStateName(j.state), duration, label)))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s  %7s  %s", jobStatus(j.state), duration, label))
+		}
+	}
+	return sb.String()
+}
+
+// jobStateName is the unstyled form of jobStatus, for the highlighted row.
+func jobStateName(state jobState) string {
+	switch state {
+	case jobQueued:
+		return "○ queued   "
+	case jobRunning:
+		return "● running  "
+	case jobDone:
+		return "✓ done     "
+	case jobFailed:
+		return "✗ failed   "
+	default:
+		return "⊘ cancelled"
+	}
+}
+
+// viewTick schedules the next live view refresh: every second, or every
+// few seconds in low-bandwidth mode.
+func (m Model) viewTick() tea.Cmd {
+	interval := time.Second
+	if m.lowBandwidth {
+		interval = lowBandwidthTick
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg { return viewTickMsg{} })
+}
+
+// refreshJobsView re-renders the jobs list into the viewport.
+func (m *Model) refreshJobsView() {
+	listed := m.jobs.listed()
+	if m.jobCursor >= len(listed) && len(listed) > 0 {
+		m.jobCursor = len(listed) - 1
+	}
+	m.viewport.SetContent(renderJobs(listed, m.jobCursor, m.jobs.now(), cursorStyle(m.lowBandwidth)))
+}
@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// runJob runs a job body to completion and returns its result message.
+func runJob(run jobFunc) tea.Msg {
+	msg, _ := run(context.Background())
+	return msg
+}
+
+// runJobCmd runs a job's command and unwraps the result message.
+func runJobCmd(cmd tea.Cmd) tea.Msg {
+	return cmd().(jobDoneMsg).result
+}
+
+func okJob(msg tea.Msg) jobFunc {
+	return func(ctx context.Context) (tea.Msg, error) { return msg, nil }
+}
+
+func TestJobScheduler_QueuesBackgroundJobsBeyondLimit(t *testing.T) {
+	s := newJobScheduler()
+	var cmds []tea.Cmd
+	for i := 0; i < maxBackgroundJobs+1; i++ {
+		cmds = append(cmds, s.submit("job"+string(rune('a'+i)), true, okJob(nil)))
+	}
+	if cmds[maxBackgroundJobs] != nil {
+		t.Fatal("job beyond the limit should be queued")
+	}
+	if s.jobs[maxBackgroundJobs].state != jobQueued {
+		t.Errorf("state = %v, want queued", s.jobs[maxBackgroundJobs].state)
+	}
+
+	// Foreground jobs never wait.
+	if s.submit("submit", false, okJob(nil)) == nil {
+		t.Error("foreground job should start immediately")
+	}
+
+	done := cmds[0]().(jobDoneMsg)
+	s.finish(done.id, nil)
+	started := s.startQueued()
+	if len(started) != 1 {
+		t.Fatalf("startQueued started %d jobs, want 1", len(started))
+	}
+	if s.jobs[maxBackgroundJobs].state != jobRunning {
+		t.Errorf("queued job state = %v, want running", s.jobs[maxBackgroundJobs].state)
+	}
+}
+
+func TestJobScheduler_CoalescesQueuedDuplicates(t *testing.T) {
+	s := newJobScheduler()
+	for i := 0; i < maxBackgroundJobs; i++ {
+		s.submit("busy", false, okJob(nil))
+		s.submit("bg"+string(rune('a'+i)), true, okJob(nil))
+	}
+	s.submit("Refresh PR info", true, okJob("old"))
+	s.submit("Refresh PR info", true, okJob("new"))
+
+	var queued []*job
+	for _, j := range s.jobs {
+		if j.state == jobQueued {
+			queued = append(queued, j)
+		}
+	}
+	if len(queued) != 1 {
+		t.Fatalf("queued = %d jobs, want 1", len(queued))
+	}
+	if msg := runJob(queued[0].run); msg != "new" {
+		t.Errorf("queued job runs %v, want the latest submission", msg)
+	}
+}
+
+func TestJobScheduler_FinishRecordsOutcome(t *testing.T) {
+	s := newJobScheduler()
+	s.submit("ok", true, okJob(nil))
+	s.submit("bad", true, okJob(nil))
+
+	s.finish(1, nil)
+	s.finish(2, errors.New("exit status 1"))
+	if s.jobs[0].state != jobDone {
+		t.Errorf("job 1 = %v, want done", s.jobs[0].state)
+	}
+	if s.jobs[1].state != jobFailed || s.jobs[1].err != "exit status 1" {
+		t.Errorf("job 2 = %v %q, want failed", s.jobs[1].state, s.jobs[1].err)
+	}
+	if s.active() {
+		t.Error("no jobs should be active")
+	}
+}
+
+func TestJobScheduler_CancelRunning(t *testing.T) {
+	s := newJobScheduler()
+	cmd := s.submit("slow", true, func(ctx context.Context) (tea.Msg, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	if _, ok := s.cancel(1); !ok {
+		t.Fatal("cancel should succeed")
+	}
+	done := cmd().(jobDoneMsg) // returns because the context was cancelled
+	j := s.finish(done.id, done.err)
+	if j.state != jobCancelled {
+		t.Errorf("state = %v, want cancelled", j.state)
+	}
+	if _, ok := s.cancel(1); ok {
+		t.Error("cancelling a finished job should fail")
+	}
+}
+
+func TestJobScheduler_CancelQueuedStillReports(t *testing.T) {
+	s := newJobScheduler()
+	for i := 0; i < maxBackgroundJobs; i++ {
+		s.submit("bg"+string(rune('a'+i)), true, okJob(nil))
+	}
+	s.submit("queued", true, func(ctx context.Context) (tea.Msg, error) {
+		return "cleanup", ctx.Err()
+	})
+
+	cmd, ok := s.cancel(3)
+	if !ok || cmd == nil {
+		t.Fatal("cancelling a queued job should return a cmd")
+	}
+	done := cmd().(jobDoneMsg)
+	if done.result != "cleanup" || !errors.Is(done.err, context.Canceled) {
+		t.Errorf("done = %+v, want result with cancelled context", done)
+	}
+	if len(s.startQueued()) != 0 {
+		t.Error("cancelled job should not be started again")
+	}
+}
+
+func TestJobScheduler_PrunesHistory(t *testing.T) {
+	s := newJobScheduler()
+	for i := 0; i < maxJobHistory+5; i++ {
+		s.submit("job", false, okJob(nil))
+		s.finish(s.nextID, nil)
+	}
+	if len(s.jobs) != maxJobHistory {
+		t.Errorf("len(jobs) = %d, want %d", len(s.jobs), maxJobHistory)
+	}
+	if s.jobs[0].id != 6 {
+		t.Errorf("oldest kept job = %d, want 6", s.jobs[0].id)
+	}
+}
+
+func TestFormatJobDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{1234 * time.Millisecond, "1.2s"},
+		{0, "0.0s"},
+		{125 * time.Second, "2m5s"},
+	}
+	for _, tt := range tests {
+		if got := formatJobDuration(tt.d); got != tt.want {
+			t.Errorf("formatJobDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestRenderJobs(t *testing.T) {
+	now := time.Unix(1000, 0)
+	jobs := []*job{
+		{label: "Tests on feature-a", state: jobRunning, startedAt: now.Add(-3 * time.Second)},
+		{label: "Refresh PR info", state: jobFailed, err: "boom\nrate limited", startedAt: now.Add(-2 * time.Second), finishedAt: now.Add(-time.Second)},
+	}
+	got := ansi.Strip(renderJobs(jobs, 0, now, selectedBranchStyle))
+	for _, want := range []string{"Jobs", "● running", "3.0s", "Tests on feature-a", "✗ failed", "1.0s", "Refresh PR info — rate limited"} {
+		if !containsString(got, want) {
+			t.Errorf("jobs view should contain %q, got:\n%s", want, got)
+		}
+	}
+
+	if got := ansi.Strip(renderJobs(nil, 0, now, selectedBranchStyle)); !containsString(got, "No jobs yet") {
+		t.Errorf("empty jobs view = %q", got)
+	}
+}
+
+func TestJobsView_OpenCancelClose(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	// Loading the tree started the PR info and changed-files jobs.
+	if len(m.jobs.jobs) != 2 {
+		t.Fatalf("jobs = %d, want 2", len(m.jobs.jobs))
+	}
+
+	m = sendKey(m, 'J')
+	if m.mode != modeJobs {
+		t.Fatalf("mode = %v, want modeJobs", m.mode)
+	}
+	view := m.View()
+	for _, want := range []string{"Refresh PR info", "Count changed files", "cancel job"} {
+		if !containsString(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+
+	// The newest job is listed first.
+	m = sendKey(m, 'x')
+	if m.jobs.jobs[1].state != jobCancelled {
+		t.Errorf("state = %v, want cancelled", m.jobs.jobs[1].state)
+	}
+	updated, _ := m.Update(jobDoneMsg{id: m.jobs.jobs[1].id, err: context.Canceled})
+	m = updated.(Model)
+	if m.statusBar.message != "Cancelled: Count changed files" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree {
+		t.Errorf("mode = %v, want modeTree after esc", m.mode)
+	}
+}
+
+func TestJobDone_ForwardsResult(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	id := m.jobs.jobs[0].id
+
+	updated, _ := m.Update(jobDoneMsg{id: id, result: testResultMsg{branch: "feature-top", sha: "t1", passed: true}})
+	m = updated.(Model)
+	if m.jobs.jobs[0].state != jobDone {
+		t.Errorf("state = %v, want done", m.jobs.jobs[0].state)
+	}
+	if m.statusBar.message != "Tests passed on feature-top" {
+		t.Errorf("forwarded result not handled, message = %q", m.statusBar.message)
+	}
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/journal"
+)
+
+// loadJournal replays the activity journal in gitDir. Without a git dir
+// nothing is journaled.
+func loadJournal(gitDir string) journal.State {
+	if gitDir == "" {
+		return journal.State{}
+	}
+	events, _ := journal.Load(gitDir)
+	return journal.Replay(events)
+}
+
+// journalBranches records branches created or deleted since the last
+// reload. The journal is best-effort: write errors are ignored.
+func (m *Model) journalBranches(now time.Time) {
+	if m.gitDir == "" {
+		return
+	}
+	parents := branchParents(m.branches)
+	names := make([]string, 0, len(parents))
+	for name := range parents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	_ = journal.Append(m.gitDir, m.journal.Observe(names, now))
+}
+
+// journalMerged records PRs seen merged for the first time.
+func (m *Model) journalMerged(infos map[string]gt.PRInfo, now time.Time) {
+	if m.gitDir == "" {
+		return
+	}
+	merged := make(map[string]int)
+	for name, info := range infos {
+		if strings.EqualFold(info.State, "MERGED") {
+			merged[name] = info.Number
+		}
+	}
+	_ = journal.Append(m.gitDir, m.journal.ObserveMerged(merged, now))
+}
+
+// journalActionNames maps grit's actions to the action names recorded for
+// each branch they change. Other actions aren't journaled.
+var journalActionNames = map[string]string{
+	"submit":           "submit",
+	"downstack-submit": "submit",
+	"branch-submit":    "submit",
+	"draft-submit":     "submit",
+	"publish":          "submit",
+	"resubmit":         "submit",
+	"restack":          "restack",
+	"branch-restack":   "restack",
+	"upstack-restack":  "restack",
+	"cleanup":          "restack", // the merged branch's children
+	"insert":           "restack", // the branches above the new one
+	"cleanup-all":      "restack", // branches left on deleted ones
+	"delete-remote":    "restack", // the deleted branch's children
+	"fold":             "fold",
+	"rename":           "rename",
+	"move":             "move",
+	"merge":            "merge",
+	"merge-pr":         "merge",
+}
+
+// journalAction records a successful action against the branches it
+// changed, and clears them.
+func (m *Model) journalAction(action string, now time.Time) {
+	targets := m.actionTargets
+	m.actionTargets = nil
+	name, ok := journalActionNames[action]
+	if m.gitDir == "" || !ok || len(targets) == 0 {
+		return
+	}
+	_ = journal.Append(m.gitDir, m.journal.RecordAction(name, targets, now))
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/journal"
+)
+
+func TestJournal_RecordsReloadsAndMerges(t *testing.T) {
+	dir := t.TempDir()
+	m := New(gt.New(simpleMock("", nil)), dir)
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-a\n◯─┘  main"})
+	m = updated.(Model)
+	updated, _ = m.Update(logResultMsg{output: "│ ◉  feature-b\n│ ◯  feature-a\n◯─┘  main"})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{"feature-a": {Number: 4, State: "MERGED"}}})
+	m = updated.(Model)
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+
+	events, err := journal.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range events {
+		got = append(got, e.Kind+" "+e.Branch)
+	}
+	want := []string{"seen feature-a", "created feature-b", "merged feature-a"}
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestJournal_RecordsActions(t *testing.T) {
+	dir := t.TempDir()
+	m := New(gt.New(simpleMock("", nil)), dir)
+	m = sendWindowSize(m, 140, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+
+	m = sendKey(m, 'r') // restacks the stack feature-top is in
+	if want := []string{"feature-base", "feature-top"}; !slices.Equal(m.actionTargets, want) {
+		t.Fatalf("targets = %v, want %v", m.actionTargets, want)
+	}
+	updated, _ = m.Update(actionResultMsg{action: "restack", message: "Restacked"})
+	m = updated.(Model)
+	if m.actionTargets != nil {
+		t.Error("targets should be cleared after the action")
+	}
+	if got := m.journal.History("feature-base"); len(got) != 1 || got[0].Action != "restack" {
+		t.Errorf("feature-base history = %+v, want a restack", got)
+	}
+	if !containsString(m.View(), "restacked just now") {
+		t.Errorf("detail panel should show the restack:\n%s", m.View())
+	}
+
+	// Failed actions and actions that don't change branches aren't recorded.
+	m.actionTargets = []string{"feature-top"}
+	updated, _ = m.Update(actionResultMsg{action: "submit", err: errors.New("boom")})
+	m = updated.(Model)
+	m.actionTargets = []string{"feature-top"}
+	updated, _ = m.Update(actionResultMsg{action: "fetch", message: "Fetched"})
+	m = updated.(Model)
+	if got := m.journal.History("feature-top"); len(got) != 1 {
+		t.Errorf("feature-top history = %+v, want only the restack", got)
+	}
+
+	events, err := journal.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := journal.Replay(events).History("feature-top"); len(got) != 1 || got[0].Action != "restack" {
+		t.Errorf("persisted history = %+v", got)
+	}
+}
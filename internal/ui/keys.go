@@ -1,86 +1,202 @@
 package ui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/elliotb/grit/internal/config"
+)
 
 type keyMap struct {
 	Quit            key.Binding
 	Up              key.Binding
 	Down            key.Binding
 	Checkout        key.Binding
+	Finder          key.Binding
 	Trunk           key.Binding
+	StackUp         key.Binding
+	StackDown       key.Binding
+	StackTop        key.Binding
+	StackBottom     key.Binding
 	StackSubmit     key.Binding
 	DownstackSubmit key.Binding
+	BranchSubmit    key.Binding
+	DraftSubmit     key.Binding
+	SubmitOptions   key.Binding
+	Publish         key.Binding
+	MergeQueue      key.Binding
+	MergePR         key.Binding
+	EditPR          key.Binding
+	RequestReview   key.Binding
+	PostComment     key.Binding
+	EditLabels      key.Binding
 	Restack         key.Binding
+	BranchRestack   key.Binding
+	UpstackRestack  key.Binding
 	Fetch           key.Binding
 	Sync            key.Binding
+	SyncPreview     key.Binding
 	OpenPR          key.Binding
+	OpenPRGraphite  key.Binding
 	Diff            key.Binding
 	DiffClose       key.Binding
 	Tab             key.Binding
 	Help            key.Binding
+	CheckoutNearest key.Binding
+	Continue        key.Binding
+	Abort           key.Binding
+	Edit            key.Binding
+	Create          key.Binding
+	Insert          key.Binding
+	Rename          key.Binding
+	Fold            key.Binding
+	Pop             key.Binding
+	Absorb          key.Binding
+	Commit          key.Binding
+	Amend           key.Binding
+	Fixup           key.Binding
+	Split           key.Binding
+	Move            key.Binding
+	Track           key.Binding
+	Untrack         key.Binding
+	RepoInit        key.Binding
+	ToggleDetail    key.Binding
+	Confirm         key.Binding
+	Test            key.Binding
+	Jobs            key.Binding
+	CancelJob       key.Binding
+	Debug           key.Binding
+	ErrorDetails    key.Binding
+	Pin             key.Binding
+	HideMerged      key.Binding
+	PRFilter        key.Binding
+	Filter          key.Binding
+	ClearFilters    key.Binding
+	Mark            key.Binding
+	Overlaps        key.Binding
+	Comments        key.Binding
+	Stats           key.Binding
+	LastView        key.Binding
+	Resubmit        key.Binding
+	Cleanup         key.Binding
+	CleanupAll      key.Binding
+	DeleteRemote    key.Binding
+	Yank            key.Binding
+	YankRef         key.Binding
+	YankPR          key.Binding
+	OpenFile        key.Binding
+	Share           key.Binding
+	Palette         key.Binding
+	History         key.Binding
 }
 
 func defaultKeyMap() keyMap {
-	return keyMap{
-		Quit: key.NewBinding(
-			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
-		Up: key.NewBinding(
-			key.WithKeys("up", "k"),
-			key.WithHelp("↑/k", "up"),
-		),
-		Down: key.NewBinding(
-			key.WithKeys("down", "j"),
-			key.WithHelp("↓/j", "down"),
-		),
-		Checkout: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "checkout"),
-		),
-		Trunk: key.NewBinding(
-			key.WithKeys("m"),
-			key.WithHelp("m", "trunk"),
-		),
-		StackSubmit: key.NewBinding(
-			key.WithKeys("s"),
-			key.WithHelp("s", "submit stack"),
-		),
-		DownstackSubmit: key.NewBinding(
-			key.WithKeys("S"),
-			key.WithHelp("S", "submit downstack"),
-		),
-		Restack: key.NewBinding(
-			key.WithKeys("r"),
-			key.WithHelp("r", "restack"),
-		),
-		Fetch: key.NewBinding(
-			key.WithKeys("f"),
-			key.WithHelp("f", "fetch"),
-		),
-		Sync: key.NewBinding(
-			key.WithKeys("y"),
-			key.WithHelp("y", "sync"),
-		),
-		OpenPR: key.NewBinding(
-			key.WithKeys("o"),
-			key.WithHelp("o", "open PR"),
-		),
-		Diff: key.NewBinding(
-			key.WithKeys("d"),
-			key.WithHelp("d", "diff"),
-		),
-		DiffClose: key.NewBinding(
-			key.WithKeys("d", "esc"),
-			key.WithHelp("esc/d", "close"),
-		),
-		Tab: key.NewBinding(
-			key.WithKeys("tab"),
-			key.WithHelp("tab", "switch panel"),
-		),
-		Help: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
-		),
+	var k keyMap
+	for _, s := range actionSpecs {
+		*s.binding(&k) = key.NewBinding(key.WithKeys(s.keys...), key.WithHelp(s.defaultHelpKey(), s.help))
+	}
+	return k
+}
+
+// byName maps the action names used in config and profiles to bindings.
+func (k *keyMap) byName() map[string]*key.Binding {
+	bindings := make(map[string]*key.Binding, len(actionSpecs))
+	for _, s := range actionSpecs {
+		bindings[s.name] = s.binding(k)
+	}
+	return bindings
+}
+
+// rebind replaces the keys of the named actions. Help and legend text
+// show the new keys. A key left bound to two actions is an error, unless
+// the default keymap shares a key between them too: those are never
+// active in the same view, like checkout and confirm on enter.
+func (k *keyMap) rebind(keys map[string][]string) error {
+	bindings := k.byName()
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b, ok := bindings[name]
+		if !ok {
+			return fmt.Errorf("unknown key action %q", name)
+		}
+		if len(keys[name]) == 0 {
+			return fmt.Errorf("no keys for action %q", name)
+		}
+		b.SetKeys(keys[name]...)
+		b.SetHelp(keyHelp(keys[name]), b.Help().Desc)
+	}
+	return k.checkConflicts(nil)
+}
+
+// checkConflicts reports the first key bound to two actions that the
+// default keymap keeps apart, or a template key that is bound to an
+// action or another template. Templates are checked against every action,
+// since a template key would shadow it.
+func (k *keyMap) checkConflicts(templates []config.Template) error {
+	bindings := k.byName()
+	owners := make(map[string][]string)
+	for _, s := range actionSpecs {
+		for _, bound := range bindings[s.name].Keys() {
+			for _, other := range owners[bound] {
+				if !shareDefaultKey(other, s.name) {
+					return fmt.Errorf("key %q is bound to both %q and %q", bound, other, s.name)
+				}
+			}
+			owners[bound] = append(owners[bound], s.name)
+		}
+	}
+	for _, t := range templates {
+		if others := owners[t.Key]; len(others) > 0 {
+			return fmt.Errorf("template %q: key is bound to %q", t.Key, others[0])
+		}
+		owners[t.Key] = []string{"template " + t.Prefix}
+	}
+	return nil
+}
+
+// shareDefaultKey reports whether the default keymap binds a key to both
+// named actions.
+func shareDefaultKey(a, b string) bool {
+	defaults := make(map[string][]string, len(actionSpecs))
+	for _, s := range actionSpecs {
+		defaults[s.name] = s.keys
+	}
+	return slices.ContainsFunc(defaults[a], func(bound string) bool {
+		return slices.Contains(defaults[b], bound)
+	})
+}
+
+// export returns the keys bound to every action, by name.
+func (k *keyMap) export() map[string][]string {
+	keys := make(map[string][]string)
+	for name, b := range k.byName() {
+		keys[name] = b.Keys()
+	}
+	return keys
+}
+
+// keyHelp renders keys the way the default help text does.
+func keyHelp(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case "up":
+			labels[i] = "↑"
+		case "down":
+			labels[i] = "↓"
+		case " ":
+			labels[i] = "space"
+		default:
+			labels[i] = k
+		}
 	}
+	return strings.Join(labels, "/")
 }
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var labelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+
+// shownLabels returns the PR labels to badge: those named in the labels
+// setting, matched case-insensitively, or all of them when it is empty.
+func shownLabels(labels, setting []string) []string {
+	if len(setting) == 0 {
+		return labels
+	}
+	var shown []string
+	for _, l := range labels {
+		if slices.ContainsFunc(setting, func(s string) bool { return strings.EqualFold(s, l) }) {
+			shown = append(shown, l)
+		}
+	}
+	return shown
+}
+
+// labelsLabelPlain returns unstyled label badges, e.g. " [breaking]
+// [needs-qa]", or empty string if there are none.
+func labelsLabelPlain(labels []string) string {
+	var sb strings.Builder
+	for _, l := range labels {
+		sb.WriteString(" [" + l + "]")
+	}
+	return sb.String()
+}
+
+// labelsLabel returns styled label badges, or empty string if none.
+func labelsLabel(labels []string) string {
+	var sb strings.Builder
+	for _, l := range labels {
+		sb.WriteString(" " + labelStyle.Render("["+l+"]"))
+	}
+	return sb.String()
+}
+
+// repoLabelsMsg carries the repository's labels for editing the labels of
+// branch's PR.
+type repoLabelsMsg struct {
+	branch string
+	number int
+	labels []string
+	err    error
+}
+
+// startLabelEdit loads the repository's labels to tick the selected
+// branch's PR labels from.
+func (m *Model) startLabelEdit() []tea.Cmd {
+	branch := m.selectedBranch()
+	if branch == nil {
+		return nil
+	}
+	if branch.PR.Number == 0 {
+		m.statusBar.setStatus(severityWarning, "No PR for "+branch.Name)
+		return nil
+	}
+	name, number := branch.Name, branch.PR.Number
+	m.running = true
+	spinnerCmd := m.statusBar.startSpinner("Loading labels...")
+	client := m.gtClient
+	return []tea.Cmd{spinnerCmd, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		labels, err := client.RepoLabels(ctx)
+		return repoLabelsMsg{branch: name, number: number, labels: labels, err: err}
+	}}
+}
+
+// openLabelPicker lists the repository's labels with the PR's current ones
+// ticked. Applying it adds the newly ticked labels and removes the
+// unticked ones.
+func (m *Model) openLabelPicker(msg repoLabelsMsg) {
+	m.running = false
+	m.statusBar.stopSpinner()
+	if msg.err != nil {
+		m.statusBar.setStatus(severityError, "Could not load labels: "+msg.err.Error())
+		return
+	}
+	var current []string
+	if b := gt.FindBranch(m.branches, msg.branch); b != nil {
+		current = b.PR.Labels
+	}
+	// Labels on the PR stay listed even if the repository no longer has them.
+	labels := slices.Clone(msg.labels)
+	for _, l := range current {
+		if !slices.Contains(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	if len(labels) == 0 {
+		m.statusBar.setStatus(severityInfo, "The repository has no labels")
+		return
+	}
+	items := make([]pickerItem, len(labels))
+	for i, l := range labels {
+		items[i] = pickerItem{name: l, checked: slices.Contains(current, l)}
+	}
+	name, number := msg.branch, msg.number
+	m.openPicker(picker{
+		title:   fmt.Sprintf("Labels on #%d (%s)", number, name),
+		noun:    "labels",
+		items:   items,
+		ordered: true,
+		check: func(m *Model, items []pickerItem) []tea.Cmd {
+			var ticked []string
+			for _, item := range items {
+				ticked = append(ticked, item.name)
+			}
+			add, remove := labelChanges(current, ticked)
+			if len(add) == 0 && len(remove) == 0 {
+				m.statusBar.setStatus(severityInfo, fmt.Sprintf("#%d labels unchanged", number))
+				return nil
+			}
+			m.actionTargets = []string{name}
+			return m.startAction("edit-labels", fmt.Sprintf("Updated labels on #%d", number), fmt.Sprintf("Updating labels on #%d...", number), "", func(ctx context.Context, client *gt.Client) error {
+				return client.EditPRLabels(ctx, name, add, remove)
+			})
+		},
+	})
+}
+
+// labelChanges returns the labels in ticked but not current, to add, and
+// those in current but not ticked, to remove.
+func labelChanges(current, ticked []string) (add, remove []string) {
+	for _, l := range ticked {
+		if !slices.Contains(current, l) {
+			add = append(add, l)
+		}
+	}
+	for _, l := range current {
+		if !slices.Contains(ticked, l) {
+			remove = append(remove, l)
+		}
+	}
+	return add, remove
+}
@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestShownLabels(t *testing.T) {
+	labels := []string{"breaking", "Needs-QA", "chore"}
+	if got := shownLabels(labels, nil); !reflect.DeepEqual(got, labels) {
+		t.Errorf("no setting should show every label, got %v", got)
+	}
+	if got := shownLabels(labels, []string{"needs-qa", "breaking"}); !reflect.DeepEqual(got, []string{"breaking", "Needs-QA"}) {
+		t.Errorf("shownLabels() = %v", got)
+	}
+}
+
+func TestBranchLabel_LabelBadges(t *testing.T) {
+	b := &gt.Branch{Name: "feature", PR: gt.PRInfo{Number: 7, State: "OPEN", Labels: []string{"breaking", "needs-qa"}}}
+	want := "◯ feature #7 open [breaking] [needs-qa]"
+	if got := ansi.Strip(branchLabel(b)); got != want {
+		t.Errorf("branchLabel = %q, want %q", got, want)
+	}
+	if got := ansi.Strip(selectedBranchLabel(b, selectedBranchStyle)); got != want {
+		t.Errorf("selectedBranchLabel = %q, want %q", got, want)
+	}
+}
+
+func TestPRInfoJob_LabelsAndAssignees(t *testing.T) {
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if name == "gh" {
+			return `{"headRefOid":"abc","labels":[{"name":"breaking"},{"name":"chore"}],"assignees":[{"login":"dana"}],"statusCheckRollup":[{"__typename":"CheckRun","status":"COMPLETED","conclusion":"FAILURE"}]}`, nil
+		}
+		return `{"prNumber": 2, "state": "OPEN"}`, nil
+	}}
+	m := NewWithConfig(gt.New(mock), "", config.Config{Labels: []string{"breaking"}})
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature\n◯─┘  main"})
+	m = updated.(Model)
+
+	info := runJob(m.prInfoJob()).(prInfoResultMsg).infos["feature"]
+	if !reflect.DeepEqual(info.Labels, []string{"breaking"}) || !reflect.DeepEqual(info.Assignees, []string{"dana"}) {
+		t.Errorf("labels = %v, assignees = %v", info.Labels, info.Assignees)
+	}
+	if info.CI != gt.CIFailing {
+		t.Errorf("CI = %d, want failing", info.CI)
+	}
+
+	rows := detailRows(&gt.Branch{Name: "feature", PR: info}, "main")
+	var got []detailRow
+	for _, r := range rows {
+		if r.label == "CI" || r.label == "labels" || r.label == "assigned" {
+			got = append(got, r)
+		}
+	}
+	if want := []detailRow{{"CI", "failing"}, {"labels", "breaking"}, {"assigned", "dana"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("detail rows = %v, want %v", got, want)
+	}
+}
+
+func TestLabelChanges(t *testing.T) {
+	add, remove := labelChanges([]string{"breaking", "wip"}, []string{"breaking", "perf"})
+	if !slices.Equal(add, []string{"perf"}) || !slices.Equal(remove, []string{"wip"}) {
+		t.Errorf("add = %v, remove = %v", add, remove)
+	}
+}
+
+func TestLabelsKey_TicksAndEditsLabels(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature\n◯─┘  main"})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{"feature": {Number: 7, State: "OPEN", Labels: []string{"wip"}}}})
+	m = updated.(Model)
+	m.cursor = 0
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'l'}}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if len(*calls) != 1 || !slices.Equal((*calls)[0].args, []string{"label", "list", "--json", "name", "--limit", "200"}) {
+		t.Fatalf("calls = %v, want the repository's labels listed", *calls)
+	}
+	updated, _ = m.Update(repoLabelsMsg{branch: "feature", number: 7, labels: []string{"breaking", "perf"}})
+	m = updated.(Model)
+	if m.mode != modePicker {
+		t.Fatalf("labels should open a checklist, mode = %d", m.mode)
+	}
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"Labels on #7 (feature)", "[ ] breaking", "[x] wip"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("checklist should show %q:\n%s", want, view)
+		}
+	}
+
+	m = typeText(m, "perf")
+	m = sendSpecialKey(m, tea.KeyTab)
+	for range 4 {
+		m = sendSpecialKey(m, tea.KeyBackspace)
+	}
+	m = typeText(m, "wip")
+	m = sendSpecialKey(m, tea.KeyTab)
+	updated, cmd = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	want := []string{"pr", "edit", "feature", "--add-label", "perf", "--remove-label", "wip"}
+	if len(*calls) != 2 || !slices.Equal((*calls)[1].args, want) {
+		t.Fatalf("calls = %v, want %v", *calls, want)
+	}
+}
+
+func TestLabelsKey_NeedsPR(t *testing.T) {
+	m := loadedModel("│ ◉  feature\n◯─┘  main")
+	m.cursor = 0
+	updated, _ := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'l'}}))
+	m = updated.(Model)
+	if m.running || m.statusBar.message != "No PR for feature" {
+		t.Errorf("running = %v, message = %q", m.running, m.statusBar.message)
+	}
+}
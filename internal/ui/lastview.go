@@ -0,0 +1,68 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// switchableViews are the views switchView moves between. Modes that
+// wait on an answer, like confirmations and pickers, aren't among them.
+var switchableViews = map[viewMode]bool{
+	modeTree:     true,
+	modeDiff:     true,
+	modeHelp:     true,
+	modeJobs:     true,
+	modeDebug:    true,
+	modeOverlaps: true,
+	modeComments: true,
+	modeStats:    true,
+}
+
+// switchView goes back to the view shown before the current one, as it
+// was left: the diff keeps its file and scroll, the tree its cursor, and
+// the other views their scroll. Views that load data load it again.
+func (m *Model) switchView() []tea.Cmd {
+	to := m.prevView
+	if to == m.mode {
+		m.statusBar.setStatus(severityWarning, "No other view to switch to")
+		return nil
+	}
+	scroll := m.viewScrolls[to]
+	var cmds []tea.Cmd
+	switch to {
+	case modeDiff:
+		m.setMode(modeDiff)
+		m.resizeViewport()
+		return nil
+	case modeHelp:
+		m.openHelp()
+		return nil
+	case modeTree:
+		m.setMode(modeTree)
+		m.resizeViewport()
+		m.viewport.SetContent(m.treeContent())
+	case modeJobs:
+		m.setMode(modeJobs)
+		m.jobCursor = min(m.jobCursor, max(len(m.jobs.listed())-1, 0))
+		m.resizeViewport()
+		m.refreshJobsView()
+		cmds = append(cmds, m.viewTick())
+	case modeDebug:
+		m.setMode(modeDebug)
+		m.debug.loading = true
+		m.resizeViewport()
+		m.refreshDebugView()
+		cmds = append(cmds, m.loadQuota(), m.viewTick())
+	case modeOverlaps:
+		m.setMode(modeOverlaps)
+		m.resizeViewport()
+		m.refreshOverlapsView()
+	case modeComments, modeStats:
+		// The hooks reload them; resizing redraws what was loaded before.
+		m.setMode(to)
+		m.resizeViewport()
+	}
+	m.viewport.SetYOffset(scroll)
+	if to == modeTree {
+		// The tree may have reloaded since.
+		m.ensureCursorVisible()
+	}
+	return cmds
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestSwitchView_ReturnsToDiffAsLeft(t *testing.T) {
+	m := loadedDiffModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m = sendKey(m, 'j')
+	updated, _ := m.Update(diffDataMsg{
+		branchName:   "feature-base",
+		parentBranch: "main",
+		files:        []diffFileEntry{{path: "a.go"}, {path: "b.go"}},
+	})
+	m = updated.(Model)
+	m = sendKey(m, 'j')
+	var lines []string
+	for i := range 100 {
+		lines = append(lines, fmt.Sprintf("+line %d", i))
+	}
+	updated, _ = m.Update(diffFileContentMsg{session: m.diff.session, file: "b.go", content: strings.Join(lines, "\n")})
+	m = updated.(Model)
+	m = sendSpecialKey(m, tea.KeyTab)
+	for range 5 {
+		m = sendKey(m, 'j')
+	}
+
+	m = sendKey(m, '`')
+	if m.mode != modeTree || m.cursor != 1 {
+		t.Fatalf("mode = %d, cursor = %d, want the tree with the cursor left on feature-base", m.mode, m.cursor)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'`'}}))
+	m = updated.(Model)
+	if m.mode != modeDiff {
+		t.Fatalf("mode = %d, want back in the diff", m.mode)
+	}
+	if loadsDiffFile(cmd) {
+		t.Error("returning to a loaded file should not load it again")
+	}
+	if m.diff.fileCursor != 1 || m.diff.focusedPanel != panelDiff || m.diff.diffViewport.YOffset != 5 {
+		t.Errorf("file %d, panel %d, scroll %d; want b.go's diff focused and scrolled to 5", m.diff.fileCursor, m.diff.focusedPanel, m.diff.diffViewport.YOffset)
+	}
+}
+
+func TestSwitchView_ReloadsFileLeftLoading(t *testing.T) {
+	m := openTestDiff(loadedDiffModel("│ ◉  feature-top\n◯─┘  main"))
+	m = sendSpecialKey(m, tea.KeyEscape)
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'`'}}))
+	m = updated.(Model)
+	if m.mode != modeDiff || !loadsDiffFile(cmd) {
+		t.Error("a file whose load was cancelled should load again")
+	}
+}
+
+// loadsDiffFile reports whether cmd loads a file into the diff.
+func loadsDiffFile(cmd tea.Cmd) bool {
+	for _, msg := range batchMsgs(cmd) {
+		if _, ok := msg.(diffFileContentMsg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSwitchView_KeepsCommentsScroll(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n◯─┘  main")
+	var d gt.PRDiscussion
+	for i := range 50 {
+		d.Comments = append(d.Comments, gt.PRComment{Author: "alice", Body: fmt.Sprintf("comment %d", i)})
+	}
+	m.comments = commentsView{branch: "feature-top", number: 1, loaded: true, discussion: d}
+	m.setMode(modeComments)
+	m.resizeViewport()
+	m.viewport.SetYOffset(20)
+
+	m = sendKey(m, '`')
+	if m.mode != modeTree || m.viewport.YOffset != 0 {
+		t.Fatalf("mode = %d, scroll = %d, want the tree at its own scroll", m.mode, m.viewport.YOffset)
+	}
+	m = sendKey(m, '`')
+	if m.mode != modeComments || !m.comments.loaded {
+		t.Fatal("switching back should show the discussion loaded before while it reloads")
+	}
+	if m.viewport.YOffset != 20 {
+		t.Errorf("scroll = %d, want the comments view's 20", m.viewport.YOffset)
+	}
+}
+
+func TestSwitchView_NothingToSwitchTo(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n◯─┘  main")
+	m = sendKey(m, '`')
+	if m.mode != modeTree || m.statusBar.severity != severityWarning {
+		t.Errorf("mode = %d, status = %q, want a warning in the tree", m.mode, m.statusBar.message)
+	}
+}
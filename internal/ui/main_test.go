@@ -0,0 +1,13 @@
+package ui
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Status messages don't expire in tests: the expiry ticks would block
+	// every test that runs the commands an update returns.
+	statusTTL = nil
+	os.Exit(m.Run())
+}
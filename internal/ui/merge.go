@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// startMerge asks to confirm sending the PRs from trunk up to the selected
+// branch to Graphite's merge queue with `gt merge`, which merges the stack
+// below the checked-out branch. Every PR on the way must be open and ready
+// for review; merged ones are skipped. The previously checked-out branch
+// is checked out again afterwards.
+func (m *Model) startMerge() []tea.Cmd {
+	target := m.selectedBranch()
+	switch {
+	case target == nil:
+		return nil
+	case target.Parent == "":
+		m.statusBar.setStatus(severityWarning, "Cannot merge trunk branch")
+		return nil
+	}
+	var queue []string
+	for _, b := range stackBranches(m.branches, target.Name, false) {
+		switch strings.ToUpper(b.PR.State) {
+		case "MERGED":
+			continue
+		case "OPEN":
+			queue = append(queue, b.Name)
+		case "DRAFT":
+			m.statusBar.setStatus(severityWarning, "Cannot merge: PR for "+b.Name+" is a draft")
+			return nil
+		default:
+			m.statusBar.setStatus(severityWarning, "Cannot merge: "+b.Name+" has no open PR")
+			return nil
+		}
+	}
+	if len(queue) == 0 {
+		m.statusBar.setStatus(severityWarning, "Nothing to merge: every PR up to "+target.Name+" has merged")
+		return nil
+	}
+
+	name, current := target.Name, currentBranchName(*m)
+	if current != name && m.inOtherWorktree(name) {
+		return nil
+	}
+	m.actionTargets = queue
+	warning := fmt.Sprintf("Merging sends %s to the Graphite merge queue (%s); they land on trunk once their checks pass.",
+		pluralize(len(queue), "PR"), strings.Join(queue, ", "))
+	return m.startAction("merge", "Queued "+pluralize(len(queue), "PR")+" for merge", "Queueing "+name+" for merge...", warning, func(ctx context.Context, client *gt.Client) error {
+		if current != name {
+			if err := client.Checkout(ctx, name); err != nil {
+				return err
+			}
+		}
+		if err := client.Merge(ctx); err != nil {
+			return err
+		}
+		if current != "" && current != name {
+			return client.Checkout(ctx, current)
+		}
+		return nil
+	})
+}
+
+// markQueued flags the PRs of names as queued until PR info shows them
+// merged or closed.
+func (m *Model) markQueued(names []string) {
+	if m.prInfos == nil {
+		m.prInfos = make(map[string]gt.PRInfo)
+	}
+	for _, name := range names {
+		info := m.prInfos[name]
+		info.Queued = true
+		m.prInfos[name] = info
+	}
+}
+
+// carryQueued keeps the queued flag from prev on the PRs in infos that are
+// still open. gt's PR info doesn't report the merge queue.
+func carryQueued(prev, infos map[string]gt.PRInfo) {
+	for name, info := range infos {
+		if prev[name].Queued && prOpen(info) {
+			info.Queued = true
+			infos[name] = info
+		}
+	}
+}
+
+// prMergeMethods are the ways `gh pr merge` can land a PR, in the order
+// the merge picker offers them.
+var prMergeMethods = []struct {
+	method gt.MergeMethod
+	desc   string
+}{
+	{gt.MergeSquash, "Combine the PR's commits into one commit on the base"},
+	{gt.MergeCommit, "Keep the PR's commits and add a merge commit"},
+	{gt.MergeRebase, "Replay the PR's commits onto the base, one by one"},
+}
+
+// startPRMerge merges the selected branch's PR on GitHub with `gh pr
+// merge`, bypassing the merge queue. It picks the merge method first, then
+// asks to confirm. Only an open PR that isn't already queued can merge.
+func (m *Model) startPRMerge() []tea.Cmd {
+	branch := m.selectedBranch()
+	switch {
+	case branch == nil:
+		return nil
+	case branch.Parent == "":
+		m.statusBar.setStatus(severityWarning, "Cannot merge trunk branch")
+		return nil
+	case strings.ToUpper(branch.PR.State) == "DRAFT":
+		m.statusBar.setStatus(severityWarning, "Cannot merge: PR for "+branch.Name+" is a draft")
+		return nil
+	case !prOpen(branch.PR):
+		m.statusBar.setStatus(severityWarning, "Cannot merge: "+branch.Name+" has no open PR")
+		return nil
+	case branch.PR.Queued:
+		m.statusBar.setStatus(severityWarning, fmt.Sprintf("#%d is already in the merge queue", branch.PR.Number))
+		return nil
+	}
+	items := make([]pickerItem, len(prMergeMethods))
+	for i, method := range prMergeMethods {
+		items[i] = pickerItem{name: string(method.method)}
+	}
+	name, parent, number := branch.Name, branch.Parent, branch.PR.Number
+	m.openPicker(picker{
+		title:   fmt.Sprintf("Merge #%d (%s) into %s", number, name, parent),
+		noun:    "merge methods",
+		items:   items,
+		ordered: true,
+		pick: func(m *Model, item pickerItem) []tea.Cmd {
+			method := gt.MergeMethod(item.name)
+			m.actionTargets = []string{name}
+			success := fmt.Sprintf("Merged #%d into %s — press %s to sync", number, parent, m.keys.Sync.Help().Key)
+			warning := fmt.Sprintf("Merging #%d into %s on GitHub (%s) can't be undone.", number, parent, method)
+			return m.startAction("merge-pr", success, fmt.Sprintf("Merging #%d...", number), warning, func(ctx context.Context, client *gt.Client) error {
+				return client.MergePR(ctx, name, method)
+			})
+		},
+		preview: func(_ Model, item pickerItem) string {
+			for _, method := range prMergeMethods {
+				if string(method.method) == item.name {
+					return method.desc
+				}
+			}
+			return ""
+		},
+	})
+	return nil
+}
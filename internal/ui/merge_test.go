@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestMergeKey_QueuesStackAndShowsQueued(t *testing.T) {
+	log := "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: log})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{
+		"feature-top":  {Number: 2, State: "OPEN"},
+		"feature-base": {Number: 1, State: "OPEN"},
+	}})
+	m = updated.(Model)
+	m.cursor = 0 // feature-top
+
+	m = sendKey(m, 'Q')
+	if m.mode != modeConfirm || len(*calls) != 0 {
+		t.Fatalf("merge should wait for confirmation, mode = %d", m.mode)
+	}
+	if view := m.View(); !strings.Contains(view, "gt merge") || !strings.Contains(view, "feature-base, feature-top") {
+		t.Errorf("confirmation should show the command and the PRs queued:\n%s", view)
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	want := [][]string{
+		{"checkout", "feature-top", "--no-interactive"},
+		{"merge", "--no-interactive"},
+		{"checkout", "feature-base", "--no-interactive"},
+	}
+	if len(*calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", *calls, want)
+	}
+	for i, c := range *calls {
+		if !slices.Equal(c.args, want[i]) {
+			t.Errorf("call %d = %v, want %v", i, c.args, want[i])
+		}
+	}
+
+	updated, _ = m.Update(actionResultMsg{action: "merge", message: "Queued 2 PRs for merge"})
+	m = updated.(Model)
+	updated, _ = m.Update(logResultMsg{output: log})
+	m = updated.(Model)
+	if view := m.viewport.View(); !strings.Contains(view, "#1 queued") || !strings.Contains(view, "#2 queued") {
+		t.Errorf("tree should show both PRs queued:\n%s", view)
+	}
+
+	// Refreshed PR info keeps the flag until the PR merges.
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{
+		"feature-top":  {Number: 2, State: "OPEN"},
+		"feature-base": {Number: 1, State: "MERGED"},
+	}, mark: m.branchStates.mark()})
+	m = updated.(Model)
+	if !m.prInfos["feature-top"].Queued || m.prInfos["feature-base"].Queued {
+		t.Errorf("prInfos = %+v", m.prInfos)
+	}
+	if view := m.viewport.View(); !strings.Contains(view, "#1 merged") {
+		t.Errorf("merged PR should no longer show queued:\n%s", view)
+	}
+}
+
+func TestMergeKey_NeedsOpenPRs(t *testing.T) {
+	tests := []struct {
+		name  string
+		infos map[string]gt.PRInfo
+		want  string
+	}{
+		{"draft", map[string]gt.PRInfo{"feature-top": {Number: 2, State: "OPEN"}, "feature-base": {Number: 1, State: "DRAFT"}},
+			"Cannot merge: PR for feature-base is a draft"},
+		{"no PR", map[string]gt.PRInfo{"feature-base": {Number: 1, State: "OPEN"}},
+			"Cannot merge: feature-top has no open PR"},
+		{"all merged", map[string]gt.PRInfo{"feature-top": {Number: 2, State: "MERGED"}, "feature-base": {Number: 1, State: "MERGED"}},
+			"Nothing to merge: every PR up to feature-top has merged"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := loadedModel("│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main")
+			updated, _ := m.Update(prInfoResultMsg{infos: tt.infos})
+			m = updated.(Model)
+			m.cursor = 0
+			m = sendKey(m, 'Q')
+			if m.mode != modeTree || m.statusBar.message != tt.want {
+				t.Errorf("mode = %d, message = %q, want %q", m.mode, m.statusBar.message, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergePRKey_PicksMethodAndSuggestsSync(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{
+		"feature-top":  {Number: 2, State: "OPEN"},
+		"feature-base": {Number: 1, State: "OPEN"},
+	}})
+	m = updated.(Model)
+	m.cursor = 0 // feature-top
+
+	m = sendKey(m, 'G')
+	if m.mode != modePicker {
+		t.Fatalf("G should offer the merge methods, mode = %d", m.mode)
+	}
+	if view := m.View(); !strings.Contains(view, "Merge #2 (feature-top) into feature-base") || !strings.Contains(view, "one commit") {
+		t.Errorf("picker should name the PR and describe squash:\n%s", view)
+	}
+	m = typeText(m, "reb")
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.mode != modeConfirm || len(*calls) != 0 {
+		t.Fatalf("merge should wait for confirmation, mode = %d", m.mode)
+	}
+	if view := m.View(); !strings.Contains(view, "gh pr merge feature-top --rebase") || !strings.Contains(view, "can't be undone") {
+		t.Errorf("confirmation should show the command and warn:\n%s", view)
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if len(*calls) != 1 || (*calls)[0].name != "gh" || !slices.Equal((*calls)[0].args, []string{"pr", "merge", "feature-top", "--rebase"}) {
+		t.Fatalf("calls = %v", *calls)
+	}
+	updated, _ = m.Update(actionResultMsg{action: "merge-pr", message: "Merged #2 into feature-base — press y to sync"})
+	m = updated.(Model)
+	if !strings.Contains(m.statusBar.message, "Merged #2 into feature-base — press y to sync") {
+		t.Errorf("status = %q, want the merge reported with a sync suggestion", m.statusBar.message)
+	}
+}
+
+func TestMergePRKey_NeedsOpenPR(t *testing.T) {
+	tests := []struct {
+		name string
+		info gt.PRInfo
+		want string
+	}{
+		{"draft", gt.PRInfo{Number: 2, State: "DRAFT"}, "Cannot merge: PR for feature-top is a draft"},
+		{"merged", gt.PRInfo{Number: 2, State: "MERGED"}, "Cannot merge: feature-top has no open PR"},
+		{"queued", gt.PRInfo{Number: 2, State: "OPEN", Queued: true}, "#2 is already in the merge queue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := loadedModel("│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main")
+			updated, _ := m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{"feature-top": tt.info}})
+			m = updated.(Model)
+			m.cursor = 0
+			m = sendKey(m, 'G')
+			if m.mode != modeTree || m.statusBar.message != tt.want {
+				t.Errorf("mode = %d, message = %q, want %q", m.mode, m.statusBar.message, tt.want)
+			}
+		})
+	}
+}
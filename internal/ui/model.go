@@ -2,7 +2,11 @@ package ui
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -11,14 +15,22 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/fsnotify/fsnotify"
+	"github.com/muesli/termenv"
 
+	"github.com/elliotb/grit/internal/config"
 	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/journal"
 )
 
 // logResultMsg is sent when `gt log short` completes.
 type logResultMsg struct {
-	output string
-	err    error
+	output    string
+	err       error
+	repo      repoState
+	heads     map[string]string          // branch name → head SHA, nil if unavailable
+	parents   map[string]gt.ParentRecord // branch name → parent recorded by gt, nil if unavailable
+	orphans   map[string]string          // branch name → why its recorded parent is broken
+	worktrees map[string]string          // branch name → path of the other worktree it is checked out in
 }
 
 // actionResultMsg is sent when an async gt action completes.
@@ -26,6 +38,7 @@ type actionResultMsg struct {
 	action  string
 	err     error
 	message string // success message to display
+	warning string // shown instead of a plain success when non-empty
 }
 
 // debounceFireMsg is sent after a debounce delay to trigger a reload.
@@ -35,58 +48,201 @@ type debounceFireMsg struct{ seq int }
 // debounceDuration is the delay before reloading after a filesystem event.
 const debounceDuration = 300 * time.Millisecond
 
+// prInfoMaxAge is how long fetched PR info is reused across tree reloads
+// before it is fetched again. Auto-refresh reloads the tree on every .git
+// change; refetching PR info each time would hammer GitHub on big stacks.
+const prInfoMaxAge = 30 * time.Second
+
+// prInfoWorkers is how many branches' PR info is fetched at once. The
+// remote rate limit still paces the calls across all of them.
+const prInfoWorkers = 4
+
 // diffDataMsg carries the result of loading diff metadata (parent + file list).
 type diffDataMsg struct {
 	branchName   string
 	parentBranch string
 	files        []diffFileEntry
+	parts        []diffPart // set for a combined diff of marked branches
 	err          error
 }
 
 // diffFileContentMsg carries the diff content for a single file.
 type diffFileContentMsg struct {
+	session int // the diff session that loaded it
 	file    string
 	content string
 	err     error
 }
 
-// prInfoResultMsg carries PR info for all branches.
+// prInfoResultMsg carries PR info for all branches. failed lists the
+// branches whose lookup failed, which have no PR in infos. mark is the
+// branchStates sequence number when the refresh started.
 type prInfoResultMsg struct {
-	infos map[string]gt.PRInfo
+	infos  map[string]gt.PRInfo
+	failed []string
+	mark   int
 }
 
 // Model is the root bubbletea model for grit.
 type Model struct {
-	gtClient       *gt.Client
-	viewport       viewport.Model
-	statusBar      statusBar
-	keys           keyMap
-	ready          bool
-	branches       []*gt.Branch
-	displayEntries []displayEntry
-	cursor         int
-	rawOutput      string
-	err            error
-	width          int
-	height         int
-	gitDir         string
-	watcher        *fsnotify.Watcher
-	debounceSeq    int
-	running        bool
-	mode           viewMode
-	diff           diffView
-}
-
-// New creates a new root model. If gitDir is non-empty, a file watcher is
-// created for auto-refresh on .git changes.
+	gtClient        *gt.Client
+	viewport        viewport.Model
+	statusBar       statusBar
+	toasts          toasts
+	keys            keyMap
+	ready           bool
+	branches        []*gt.Branch
+	displayEntries  []displayEntry
+	cursor          int
+	rawOutput       string
+	err             error
+	width           int
+	height          int
+	gitDir          string
+	watcher         *fsnotify.Watcher
+	debounceSeq     int
+	running         bool
+	mode            viewMode
+	modeCmds        []tea.Cmd        // queued by mode hooks, run after the update
+	prevView        viewMode         // view shown before the current one, for switching back
+	viewScrolls     map[viewMode]int // scroll position each view was left at
+	diff            diffView
+	diffSession     int       // number of the newest diff session
+	diffs           diffCache // diffs closed recently, resumed when reopened
+	repo            repoState
+	prompt          prompt
+	promptHistory   map[promptKind][]string // submitted prompt values, oldest first
+	form            form
+	loaded          bool   // at least one gt log short has completed
+	scrolled        bool   // the first tree shown has been scrolled to the cursor
+	needsInit       bool   // gt reported the repo is not initialized
+	cursorTarget    string // branch to place the cursor on after the next reload
+	scope           string // repo-relative path that diffs and badges are limited to
+	ignore          ignoreMatcher
+	codeowners      codeowners
+	showDetail      bool // detail panel toggle; only shown on wide terminals
+	preflight       config.Preflight
+	pending         pendingSubmit // submit awaiting pre-flight confirmation
+	testCommand     string
+	tests           testCache
+	testsRunning    map[string]bool // head SHAs with a test run in flight
+	jobs            *jobScheduler
+	jobCursor       int
+	debug           debugState
+	prInfos         map[string]gt.PRInfo // last fetched PR info, reapplied on reload
+	prInfoAt        time.Time            // when prInfos was fetched; zero forces a refetch
+	branchStates    branchStates         // when actions last changed each branch, to drop stale PR info
+	lowBandwidth    bool
+	maxHeight       int               // inline mode: cap on the rendered height; 0 uses the full terminal
+	pins            []string          // pinned branch names, shown above the tree
+	history         []historyEntry    // commands run, oldest first
+	reviewers       []string          // recently requested reviewers, oldest first, for completion
+	pendingCommand  pendingCommand    // started command awaiting its result
+	filter          branchFilter      // hides branches from the tree; saved per repo
+	labels          []string          // PR labels shown as badges; empty shows all
+	groups          []string          // branch name prefixes whose stacks are grouped
+	collapsed       map[string]bool   // groups showing only their header
+	marked          map[string]bool   // branches marked for a combined diff
+	cleanup         cleanupPlan       // merged-branch cleanup awaiting confirmation
+	bulkCleanup     bulkCleanup       // cleanup of all merged and closed branches awaiting confirmation
+	syncPreview     syncPreview       // expected outcome of a sync awaiting confirmation
+	commit          commitEditor      // commit message being written in modeCommit
+	picker          picker            // fuzzy list shown in modePicker
+	comments        commentsView      // PR discussion shown in modeComments
+	stats           statsView         // cycle-time report shown in modeStats
+	help            helpView          // keybinding reference shown in modeHelp
+	statusHeight    int               // status bar lines the viewport was last sized for
+	dialog          overlay           // message box over the tree, e.g. error details
+	confirmCommands bool              // show each mutating action's commands before running it
+	graphitePRs     bool              // o opens PRs in the Graphite web app instead of on GitHub
+	confirm         pendingAction     // action awaiting confirmation of its commands
+	split           string            // branch awaiting a split mode choice
+	moving          string            // branch whose new parent the cursor is picking
+	untracked       []string          // local branches Graphite doesn't track, listed below the tree
+	tracking        string            // untracked or orphaned branch whose parent the cursor is picking
+	orphans         map[string]string // branch name → why its recorded parent is broken
+	worktrees       map[string]string // branch name → path of the other worktree it is checked out in
+	conflict        conflictView      // files left conflicted by a stopped rebase
+	templates       []branchTemplate
+	journal         journal.State // activity journal, for grit digest and branch history
+	actionTargets   []string      // branches the running action changes, journaled on success
+	tutorial        tutorial      // --tutorial guidance
+	idleTimeout     time.Duration // pause auto-refresh after this long without input; 0 disables
+	idleExit        bool          // quit instead of pausing when idle
+	idle            bool          // auto-refresh is paused for inactivity
+	pollInterval    time.Duration // refresh PR info this often; 0 disables
+	pollSeq         int           // number of the current poll loop; older polls are dropped
+	lastActivity    time.Time
+	actionGuard     actionGuard  // drops key-repeated actions
+	copyText        func(string) // writes to the system clipboard
+}
+
+// New creates a new root model with the default configuration. If gitDir is
+// non-empty, a file watcher is created for auto-refresh on .git changes.
 func New(gtClient *gt.Client, gitDir string) Model {
+	return NewWithConfig(gtClient, gitDir, config.Config{})
+}
+
+// NewWithConfig creates a new root model using the given configuration.
+func NewWithConfig(gtClient *gt.Client, gitDir string, cfg config.Config) Model {
 	m := Model{
-		gtClient:  gtClient,
-		gitDir:    gitDir,
-		keys:      defaultKeyMap(),
-		statusBar: newStatusBar(),
+		gtClient:        gtClient,
+		gitDir:          gitDir,
+		keys:            defaultKeyMap(),
+		statusBar:       newStatusBar(),
+		scope:           normalizeScope(cfg.Path),
+		showDetail:      true,
+		preflight:       cfg.Preflight,
+		confirmCommands: cfg.ConfirmCommands,
+		graphitePRs:     cfg.OpensPRsInGraphite(),
+
+		testCommand:  cfg.TestCommand,
+		tests:        loadTestCache(gitDir),
+		testsRunning: make(map[string]bool),
+		jobs:         newJobScheduler(),
+		pins:         loadPins(gitDir),
+		history:      loadHistory(gitDir),
+		reviewers:    loadReviewers(gitDir),
+		labels:       cfg.Labels,
+		groups:       cfg.Groups,
+		filter:       loadFilters(gitDir),
+		journal:      loadJournal(gitDir),
+		marked:       make(map[string]bool),
+		viewScrolls:  make(map[viewMode]int),
+		help:         newHelpView(),
+		copyText:     termenv.Copy,
+
+		idleTimeout:  time.Duration(cfg.IdleTimeout) * time.Minute,
+		idleExit:     cfg.IdleExit,
+		pollInterval: cfg.PollEvery(),
+		lastActivity: time.Now(),
+	}
+	if err := m.keys.rebind(cfg.Keys); err != nil {
+		m.keys = defaultKeyMap()
+		m.toasts.push(severityError, "Config keys ignored: "+err.Error())
+	}
+	if err := checkTemplates(cfg.Templates, m.keys); err != nil {
+		m.toasts.push(severityError, "Config templates ignored: "+err.Error())
+	} else {
+		m.templates = newTemplates(cfg.Templates)
+	}
+	m.statusBar.scope = m.scope
+	m.statusBar.reduceMotion = cfg.ReduceMotion
+	if cfg.Inline {
+		m.maxHeight = cfg.InlineLines()
+	}
+	if cfg.LowBandwidth {
+		m.lowBandwidth = true
+		m.statusBar.spinner.Spinner = lowBandwidthSpinner
 	}
 
+	repoRoot := ""
+	if gitDir != "" {
+		repoRoot = filepath.Dir(gitDir)
+	}
+	m.ignore = loadIgnoreMatcher(repoRoot, cfg.Ignore)
+	m.codeowners = loadCodeowners(repoRoot)
+
 	if gitDir != "" {
 		watcher, err := createWatcher(gitDir)
 		if err == nil {
@@ -98,30 +254,87 @@ func New(gtClient *gt.Client, gitDir string) Model {
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.loadLog(), waitForChange(m.watcher))
+	return tea.Batch(m.loadLog(), waitForChange(m.watcher), m.idleCheck(m.idleTimeout), m.schedulePoll())
 }
 
 func (m Model) loadLog() tea.Cmd {
 	client := m.gtClient
+	gitDir := m.gitDir
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 		output, err := client.LogShort(ctx)
-		return logResultMsg{output: output, err: err}
+
+		// HEAD state is best-effort: a failure just means no banner.
+		var repo repoState
+		var worktrees map[string]string
+		if head, headErr := client.HeadState(ctx); headErr == nil {
+			repo.head = head
+			// Without HEAD there is no telling which worktree is this one.
+			if list, wtErr := client.Worktrees(ctx); wtErr == nil {
+				worktrees = gt.CheckedOutElsewhere(list, head.Branch)
+			}
+		}
+		repo.rebasing, repo.rebaseBranch = detectRebase(gitDir)
+		heads, _ := client.BranchHeads(ctx)
+		parents, _ := client.BranchParents(ctx)
+		var orphans map[string]string
+		if heads != nil && parents != nil {
+			missing, _ := client.MissingCommits(ctx, gt.ParentRevisions(parents))
+			orphans = gt.FindOrphans(parents, heads, missing)
+		}
+
+		return logResultMsg{output: output, err: err, repo: repo, heads: heads, parents: parents, orphans: orphans, worktrees: worktrees}
 	}
 }
 
 // selectedBranch returns the branch at the current cursor position, or nil.
 func (m Model) selectedBranch() *gt.Branch {
-	if m.cursor >= 0 && m.cursor < len(m.displayEntries) {
+	if m.cursor >= 0 && m.cursor < len(m.displayEntries) && m.displayEntries[m.cursor].group == "" {
 		return m.displayEntries[m.cursor].branch
 	}
 	return nil
 }
 
+// currentBranchName returns the checked-out branch, or "" if unknown.
+func currentBranchName(m Model) string {
+	for _, e := range m.displayEntries {
+		if e.branch.IsCurrent {
+			return e.branch.Name
+		}
+	}
+	return ""
+}
+
+// treeContent returns the tree-mode viewport content: the branch tree, or
+// the onboarding screen when there are no stacks yet.
+func (m Model) treeContent() string {
+	if hasStacks(m.displayEntries) || !m.loaded {
+		return renderTreeWith(m.displayEntries, m.cursor, m.treeOptions())
+	}
+	if m.filter.active() {
+		return renderTreeWith(m.displayEntries, m.cursor, m.treeOptions()) + "\n\n" +
+			emptyTextStyle.Render("No branches match the filters.")
+	}
+	trunk := ""
+	var sb strings.Builder
+	if len(m.displayEntries) > 0 {
+		trunk = m.displayEntries[0].branch.Name
+		sb.WriteString(renderTreeWith(m.displayEntries, m.cursor, m.treeOptions()))
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString(renderEmptyState(trunk, m.needsInit, m.keys))
+	return sb.String()
+}
+
+// treeOptions returns the tree rendering options for the current settings.
+func (m Model) treeOptions() treeOptions {
+	return treeOptions{plainCursor: m.lowBandwidth, marked: m.marked, moving: m.moving, tracking: m.tracking, collapsed: m.collapsed}
+}
+
 // preserveCursor tries to keep the cursor on the same branch after a tree
-// reload. It searches by name first, falls back to the IsCurrent branch,
-// then falls back to index 0.
+// reload. It searches by name first, falls back to the IsCurrent branch
+// or the collapsed group hiding it, then falls back to index 0.
 func (m *Model) preserveCursor(oldBranchName string) {
 	if oldBranchName != "" {
 		for i, e := range m.displayEntries {
@@ -132,7 +345,7 @@ func (m *Model) preserveCursor(oldBranchName string) {
 		}
 	}
 	for i, e := range m.displayEntries {
-		if e.branch.IsCurrent {
+		if e.branch.IsCurrent || (e.hasCurrent && m.collapsed[e.group]) {
 			m.cursor = i
 			return
 		}
@@ -140,6 +353,73 @@ func (m *Model) preserveCursor(oldBranchName string) {
 	m.cursor = 0
 }
 
+// rebuildEntries rebuilds the display entries after the tree, pins or
+// filters change, keeping the cursor on name as preserveCursor does. In
+// the tree view it redraws with the cursor on the same screen row as
+// before, so updates arriving in the background don't scroll the view.
+func (m *Model) rebuildEntries(name string) {
+	row := m.cursor - m.viewport.YOffset
+	m.displayEntries = m.buildEntries()
+	m.preserveCursor(name)
+	if m.mode != modeTree || !m.ready {
+		return
+	}
+	m.viewport.SetContent(m.treeContent())
+	m.viewport.SetYOffset(m.cursor - row)
+	m.ensureCursorVisible()
+}
+
+// resizeViewport recomputes the viewport height from the terminal height and
+// the current chrome, which changes with the view mode and repo banner.
+func (m *Model) resizeViewport() {
+	if !m.ready {
+		return
+	}
+	viewportHeight := m.height - m.chromeHeight()
+	m.viewport.Width = m.treeWidth()
+	m.viewport.Height = viewportHeight
+	if m.mode == modeDiff {
+		m.diff.setSize(m.width, viewportHeight)
+	}
+	if m.mode == modeCommit {
+		m.commit.setSize(m.width, viewportHeight)
+	}
+	if m.mode == modeHelp {
+		m.help.setSize(m.width, viewportHeight)
+	}
+	if m.mode == modeComments {
+		m.refreshCommentsView()
+	}
+	if m.mode == modeStats {
+		m.refreshStatsView()
+	}
+}
+
+// detailVisible reports whether the detail panel is shown beside the tree.
+func (m Model) detailVisible() bool {
+	return m.mode == modeTree && m.showDetail && m.width >= detailMinTermWidth
+}
+
+// treeWidth returns the width available to the tree viewport.
+func (m Model) treeWidth() int {
+	if m.detailVisible() {
+		return m.width - detailWidth
+	}
+	return m.width
+}
+
+// detailView renders the detail panel for the selected branch.
+func (m Model) detailView() string {
+	b := m.selectedBranch()
+	parent := ""
+	var history []detailRow
+	if b != nil {
+		parent = b.Parent
+		history = historyRows(m.journal.History(b.Name), time.Now())
+	}
+	return renderDetail(b, parent, history, detailWidth, m.viewport.Height)
+}
+
 // ensureCursorVisible adjusts the viewport scroll so the cursor line is visible.
 func (m *Model) ensureCursorVisible() {
 	if m.cursor < m.viewport.YOffset {
@@ -149,6 +429,191 @@ func (m *Model) ensureCursorVisible() {
 	}
 }
 
+// quit stops watching the repo and exits.
+func (m *Model) quit() tea.Cmd {
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+	return tea.Quit
+}
+
+// scrollToCurrent centers the cursor in the tree the first time a tree is
+// shown, so a current branch far down a long tree starts on screen. Later
+// loads keep the scroll position.
+func (m *Model) scrollToCurrent() {
+	if m.scrolled || !m.ready || m.mode != modeTree || len(m.displayEntries) == 0 {
+		return
+	}
+	m.scrolled = true
+	m.viewport.SetYOffset(m.cursor - m.viewport.Height/2)
+}
+
+// submitPrompt acts on the value of the active prompt and closes it. The
+// prompt's validation has already passed.
+func (m *Model) submitPrompt() tea.Cmd {
+	p := m.prompt
+	m.prompt = prompt{}
+	m.rememberPrompt(p)
+	name := p.value()
+
+	switch p.kind {
+	case promptCreate:
+		return tea.Batch(m.startCreate(p.base, name)...)
+	case promptInsert:
+		return tea.Batch(m.startInsert(p.base, p.child, name)...)
+	case promptFilter:
+		f := m.filter
+		f.Text = name
+		m.applyFilter(f)
+		return nil
+	case promptRename:
+		if name == p.base {
+			return nil
+		}
+		return tea.Batch(m.startRename(p.base, name)...)
+	case promptReviewers:
+		return tea.Batch(m.requestReview(p.base, p.number, splitReviewers(name))...)
+	case promptComment:
+		return tea.Batch(m.postComment(p.base, p.number, name)...)
+	}
+	return nil
+}
+
+// openCreatePrompt asks for the name of a new branch stacked on base,
+// prefilled with prefix.
+func (m *Model) openCreatePrompt(base, prefix string) {
+	p := newPrompt(promptCreate, "New branch on "+base, prefix)
+	p.base = base
+	p.prefix = prefix
+	p.validate = func(name string) string {
+		if strings.TrimPrefix(name, prefix) == "" {
+			return "Branch name cannot be empty"
+		}
+		return m.newBranchNameError(name)
+	}
+	m.showPrompt(p)
+}
+
+// startCreate creates branch name stacked on base, checking base out first
+// if needed. The cursor lands on the new branch after the reload.
+func (m *Model) startCreate(base, name string) []tea.Cmd {
+	current := false
+	for _, e := range m.displayEntries {
+		if e.branch.Name == base && e.branch.IsCurrent {
+			current = true
+		}
+	}
+	if !current && m.inOtherWorktree(base) {
+		return nil
+	}
+	m.cursorTarget = name
+	return m.startAction("create", "Created "+name, "Creating "+name+"...", "", func(ctx context.Context, client *gt.Client) error {
+		if !current {
+			if err := client.Checkout(ctx, base); err != nil {
+				return err
+			}
+		}
+		return client.Create(ctx, name)
+	})
+}
+
+// openInsertPrompt asks for the name of a new branch to insert between
+// base and the one branch stacked on it. gt would ask which branch to move
+// if there were several, so that case is refused.
+func (m *Model) openInsertPrompt(base *gt.Branch) {
+	switch len(base.Children) {
+	case 0:
+		m.statusBar.setStatus(severityWarning, "Nothing is stacked on "+base.Name+" — use "+m.keys.Create.Help().Key+" to create a branch on it")
+		return
+	case 1:
+	default:
+		m.statusBar.setStatus(severityWarning, fmt.Sprintf("Cannot insert above %s: %d branches are stacked on it", base.Name, len(base.Children)))
+		return
+	}
+	child := base.Children[0].Name
+	p := newPrompt(promptInsert, "Insert between "+base.Name+" and "+child, "")
+	p.base = base.Name
+	p.child = child
+	p.validate = m.newBranchNameError
+	m.showPrompt(p)
+}
+
+// startInsert creates branch name between base and child with
+// `gt create --insert`, checking base out first if needed, then restacks
+// child and the branches above it onto the new branch.
+func (m *Model) startInsert(base, child, name string) []tea.Cmd {
+	current := currentBranchName(*m) == base
+	if !current && m.inOtherWorktree(base) {
+		return nil
+	}
+	var upstack []*gt.Branch
+	if b := gt.FindBranch(m.branches, child); b != nil {
+		upstack = append(upstack, b)
+		collectDescendants(b, &upstack)
+	}
+	m.actionTargets = branchNames(upstack)
+	m.cursorTarget = name
+	return m.startAction("insert", "Inserted "+name+" below "+child, "Inserting "+name+"...", "", func(ctx context.Context, client *gt.Client) error {
+		if !current {
+			if err := client.Checkout(ctx, base); err != nil {
+				return err
+			}
+		}
+		if err := client.CreateInsert(ctx, name); err != nil {
+			return err
+		}
+		return client.UpstackRestack(ctx, name)
+	})
+}
+
+// startRename renames oldName to newName. gt renames the checked-out
+// branch, so if another branch was current it is checked out again
+// afterwards. The cursor follows the branch to its new name.
+func (m *Model) startRename(oldName, newName string) []tea.Cmd {
+	current := currentBranchName(*m)
+	if current != oldName && m.inOtherWorktree(oldName) {
+		return nil
+	}
+	m.cursorTarget = newName
+	m.actionTargets = []string{newName}
+	return m.startAction("rename", "Renamed "+oldName+" to "+newName, "Renaming "+oldName+"...", "", func(ctx context.Context, client *gt.Client) error {
+		if err := client.Rename(ctx, oldName, newName); err != nil {
+			return err
+		}
+		if current != "" && current != oldName {
+			return client.Checkout(ctx, current)
+		}
+		return nil
+	})
+}
+
+// startContinue resumes the rebase gt stopped on a conflict.
+func (m *Model) startContinue() []tea.Cmd {
+	return m.startAction("continue", "Rebase continued", "Continuing rebase...", "", func(ctx context.Context, client *gt.Client) error {
+		return client.Continue(ctx)
+	})
+}
+
+// startAbort abandons the gt command stopped on a conflict, after
+// confirming.
+func (m *Model) startAbort() []tea.Cmd {
+	warning := "Aborting discards your conflict resolutions and stops the restack; branches it already restacked stay restacked."
+	return m.startAction("abort", "Rebase aborted", "Aborting rebase...", warning, func(ctx context.Context, client *gt.Client) error {
+		return client.Abort(ctx)
+	})
+}
+
+// startCheckout checks out name, unless another worktree has it checked
+// out, in which case it offers to go there instead.
+func (m *Model) startCheckout(name string) []tea.Cmd {
+	if m.inOtherWorktree(name) {
+		return nil
+	}
+	return m.startAction("checkout", "Checked out "+name, "Checking out "+name+"...", "", func(ctx context.Context, client *gt.Client) error {
+		return client.Checkout(ctx, name)
+	})
+}
+
 // runAction returns a tea.Cmd that runs fn asynchronously and produces an
 // actionResultMsg when it completes.
 func runAction(action, successMsg string, fn func(ctx context.Context) error) tea.Cmd {
@@ -161,36 +626,76 @@ func runAction(action, successMsg string, fn func(ctx context.Context) error) te
 }
 
 // loadDiffData fetches the file list for a branch diffed against its parent.
+// When a path scope is set, only files inside it are listed.
 func (m Model) loadDiffData(parentBranch, branchName string) tea.Cmd {
 	client := m.gtClient
+	ignore := m.ignore
+	var paths []string
+	if m.scope != "" {
+		paths = []string{m.scope}
+	}
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		statOutput, err := client.DiffStat(ctx, parentBranch, branchName)
+		statOutput, err := client.DiffStat(ctx, parentBranch, branchName, paths...)
 		if err != nil {
 			return diffDataMsg{branchName: branchName, err: err}
 		}
-		files := parseDiffStat(statOutput)
+		var files []diffFileEntry
+		for _, f := range parseDiffStat(statOutput) {
+			if !ignore.ignored(f.path) {
+				files = append(files, f)
+			}
+		}
 		return diffDataMsg{branchName: branchName, parentBranch: parentBranch, files: files}
 	}
 }
 
-// loadDiffFile fetches the diff content for a specific file.
+// loadDiffFile fetches the diff content for a specific file, as part of
+// the open diff's session.
 func (m Model) loadDiffFile(parent, branch, file string) tea.Cmd {
 	client := m.gtClient
+	session, parentCtx := m.diff.session, m.diff.context()
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(parentCtx, 10*time.Second)
 		defer cancel()
 		content, err := client.DiffFile(ctx, parent, branch, file)
 		if err != nil {
-			return diffFileContentMsg{file: file, err: err}
+			return diffFileContentMsg{session: session, file: file, err: err}
 		}
-		return diffFileContentMsg{file: file, content: content}
+		return diffFileContentMsg{session: session, file: file, content: content}
 	}
 }
 
-// loadPRInfo fetches PR info for all non-trunk branches asynchronously.
+// prInfoJobLabel names the PR info refresh in the jobs view.
+const prInfoJobLabel = "Refresh PR info"
+
+// loadPRInfo fetches PR info for all non-trunk branches as a background job.
 func (m Model) loadPRInfo() tea.Cmd {
+	job := m.prInfoJob()
+	if job == nil {
+		return nil
+	}
+	return m.jobs.submit(prInfoJobLabel, true, job)
+}
+
+// prInfoStale reports whether PR info must be refetched: it is older than
+// prInfoMaxAge, was invalidated by an action, or a branch has none yet.
+func (m Model) prInfoStale() bool {
+	if time.Since(m.prInfoAt) >= prInfoMaxAge {
+		return true
+	}
+	for name := range branchParents(m.branches) {
+		if _, ok := m.prInfos[name]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// prInfoJob returns the job body for loadPRInfo, or nil if there are no
+// non-trunk branches.
+func (m Model) prInfoJob() jobFunc {
 	// Collect all non-root branch names.
 	var names []string
 	var collectNames func(b *gt.Branch, isRoot bool)
@@ -210,23 +715,82 @@ func (m Model) loadPRInfo() tea.Cmd {
 		return nil
 	}
 
-	client := m.gtClient
-	return func() tea.Msg {
-		infos := make(map[string]gt.PRInfo)
-		for _, name := range names {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			output, err := client.BranchPRInfo(ctx, name)
-			cancel()
-			if err != nil {
-				infos[name] = gt.PRInfo{}
-				continue
+	client, labels, mark := m.gtClient, m.labels, m.branchStates.mark()
+	return func(jobCtx context.Context) (tea.Msg, error) {
+		results := make([]gt.PRInfo, len(names))
+		errs := make([]error, len(names))
+		sem := make(chan struct{}, prInfoWorkers)
+		var wg sync.WaitGroup
+		for i, name := range names {
+			wg.Go(func() {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				results[i], errs[i] = fetchPRInfo(jobCtx, client, name, labels)
+			})
+		}
+		wg.Wait()
+		if jobCtx.Err() != nil {
+			// Cancelled: keep the labels already shown.
+			return nil, jobCtx.Err()
+		}
+
+		infos := make(map[string]gt.PRInfo, len(names))
+		var failed []string
+		for i, name := range names {
+			infos[name] = results[i]
+			if errs[i] != nil {
+				failed = append(failed, name)
 			}
-			infos[name] = gt.ParsePRInfo(output)
 		}
-		return prInfoResultMsg{infos: infos}
+		return prInfoResultMsg{infos: infos, failed: failed, mark: mark}, nil
 	}
 }
 
+// fetchPRInfo looks up the PR of one branch, falling back to gh when gt
+// reports nothing, along with its head, labels, assignees and unresolved
+// review threads when it is open.
+func fetchPRInfo(jobCtx context.Context, client *gt.Client, name string, labels []string) (gt.PRInfo, error) {
+	if err := jobCtx.Err(); err != nil {
+		return gt.PRInfo{}, err
+	}
+	ctx, cancel := context.WithTimeout(jobCtx, 10*time.Second)
+	info, err := client.BranchPR(ctx, name)
+	cancel()
+	if err != nil {
+		return gt.PRInfo{}, err
+	}
+	if prOpen(info) {
+		// Best-effort: without the PR head the branch just can't be
+		// flagged as unsubmitted.
+		ctx, cancel := context.WithTimeout(jobCtx, 5*time.Second)
+		details, _ := client.PRDetails(ctx, name)
+		cancel()
+		info.HeadSHA = details.HeadSHA
+		info.Labels = shownLabels(details.Labels, labels)
+		info.Assignees = details.Assignees
+		info.CI = details.CI
+		info.Conflicts = details.Conflicts
+		ctx, cancel = context.WithTimeout(jobCtx, 5*time.Second)
+		info.Unresolved, _ = client.UnresolvedThreads(ctx, info.Number)
+		cancel()
+	}
+	return info, nil
+}
+
+// keepStalePRInfo copies into infos the previous PR of each failed branch
+// that had one, so a failed lookup doesn't make the PR vanish, and returns
+// how many it kept.
+func keepStalePRInfo(prev, infos map[string]gt.PRInfo, failed []string) int {
+	kept := 0
+	for _, name := range failed {
+		if info, ok := prev[name]; ok && info.Number != 0 {
+			infos[name] = info
+			kept++
+		}
+	}
+	return kept
+}
+
 // applyPRInfo walks the branch tree and sets PR info from the map.
 func applyPRInfo(branches []*gt.Branch, infos map[string]gt.PRInfo) {
 	var walk func(b *gt.Branch)
@@ -244,15 +808,103 @@ func applyPRInfo(branches []*gt.Branch, infos map[string]gt.PRInfo) {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	if lines := m.statusLines(); lines != m.statusHeight {
+		// The status bar grew or shrank with its message.
+		m.statusHeight = lines
+		m.resizeViewport()
+	}
+	if modeCmds := m.takeModeCmds(); len(modeCmds) > 0 {
+		cmd = tea.Batch(append(modeCmds, cmd)...)
+	}
+	if expiry := m.statusBar.scheduleExpiry(); expiry != nil {
+		cmd = tea.Batch(cmd, expiry)
+	}
+	if expiries := m.toasts.scheduleExpiry(); len(expiries) > 0 {
+		cmd = tea.Batch(append(expiries, cmd)...)
+	}
+	return m, cmd
+}
+
+// update handles msg for Update.
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if key.Matches(msg, m.keys.Quit) {
-			if m.watcher != nil {
-				m.watcher.Close()
+		// A key press while idle only wakes grit up, unless it quits.
+		if woke, wakeCmds := m.wake(time.Now()); woke && !key.Matches(msg, m.keys.Quit) {
+			cmds = append(cmds, wakeCmds...)
+			break
+		}
+
+		// An active prompt captures all keys except ctrl+c.
+		if m.prompt.active() && msg.Type != tea.KeyCtrlC {
+			submitted, cancelled, cmd := m.prompt.update(msg)
+			cmds = append(cmds, cmd)
+			switch {
+			case cancelled:
+				m.prompt = prompt{}
+			case submitted:
+				cmds = append(cmds, m.submitPrompt())
+			}
+			break
+		}
+
+		// So does an open form.
+		if m.form.active() && msg.Type != tea.KeyCtrlC {
+			submitted, cancelled, cmd := m.form.update(msg)
+			cmds = append(cmds, cmd)
+			switch {
+			case cancelled:
+				m.form = form{}
+			case submitted:
+				f := m.form
+				m.form = form{}
+				cmds = append(cmds, f.submit(&m, f)...)
+			}
+			break
+		}
+
+		// So does an open dialog, which only closes.
+		if m.dialog.active() && msg.Type != tea.KeyCtrlC {
+			if classifyOverlayKey(msg) != overlayOther {
+				m.dialog = overlay{}
+			}
+			break
+		}
+
+		// The commit editor captures all keys except ctrl+c, like a prompt,
+		// and keeps them from scrolling the viewport behind it.
+		if m.mode == modeCommit && msg.Type != tea.KeyCtrlC {
+			switch msg.Type {
+			case tea.KeyEscape:
+				m.closeCommit()
+				m.statusBar.setStatus(severityInfo, "Commit cancelled")
+			case tea.KeyCtrlS:
+				cmds = append(cmds, m.submitCommit()...)
+			default:
+				var cmd tea.Cmd
+				m.commit.input, cmd = m.commit.input.Update(msg)
+				cmds = append(cmds, cmd)
 			}
-			return m, tea.Quit
+			return m, tea.Batch(cmds...)
+		}
+
+		// So does a picker, whose query can contain any key.
+		if m.mode == modePicker && msg.Type != tea.KeyCtrlC {
+			cmds = append(cmds, m.updatePicker(msg)...)
+			return m, tea.Batch(cmds...)
+		}
+
+		// So does the help screen's search.
+		if m.mode == modeHelp && m.help.searching && msg.Type != tea.KeyCtrlC {
+			cmds = append(cmds, m.updateHelp(msg)...)
+			return m, tea.Batch(cmds...)
+		}
+
+		if key.Matches(msg, m.keys.Quit) {
+			return m, m.quit()
 		}
 
 		// Block all other input while an action is running.
@@ -260,11 +912,269 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			break
 		}
 
+		// Copy whatever is under the cursor, in any view.
+		if key.Matches(msg, m.keys.Yank) {
+			cmds = append(cmds, m.yankSelection()...)
+			break
+		}
+
+		// Switch back to the previous view, from any view.
+		if key.Matches(msg, m.keys.LastView) && switchableViews[m.mode] {
+			cmds = append(cmds, m.switchView()...)
+			break
+		}
+
 		// Help mode key handling.
 		if m.mode == modeHelp {
-			if key.Matches(msg, m.keys.Help) || msg.Type == tea.KeyEscape {
-				m.mode = modeTree
-				m.viewport.SetContent(renderTree(m.displayEntries, m.cursor))
+			cmds = append(cmds, m.updateHelp(msg)...)
+			break
+		}
+
+		// Pre-flight checklist: confirm or cancel the pending submit.
+		if m.mode == modePreflight {
+			switch {
+			case key.Matches(msg, m.keys.Confirm):
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				cmds = append(cmds, m.runSubmit(m.pending)...)
+				m.pending = pendingSubmit{}
+			case msg.Type == tea.KeyEscape:
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				m.pending = pendingSubmit{}
+				m.statusBar.setStatus(severityInfo, "Submit cancelled")
+			}
+			break
+		}
+
+		// Command confirmation: run or cancel the pending action.
+		if m.mode == modeConfirm {
+			switch {
+			case key.Matches(msg, m.keys.Confirm):
+				// run may ask for a second confirmation, replacing m.confirm.
+				run := m.confirm.run
+				m.confirm = pendingAction{}
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				cmds = append(cmds, run(&m)...)
+			case msg.Type == tea.KeyEscape:
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				m.confirm = pendingAction{}
+				m.cursorTarget = ""
+				m.statusBar.setStatus(severityInfo, "Cancelled")
+			}
+			break
+		}
+
+		// Split: choose how to split, or cancel.
+		if m.mode == modeSplit {
+			if msg.Type == tea.KeyEscape {
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				m.split = ""
+				m.statusBar.setStatus(severityInfo, "Split cancelled")
+				break
+			}
+			for _, c := range splitChoices {
+				if msg.String() == c.key {
+					m.setMode(modeTree)
+					m.resizeViewport()
+					m.viewport.SetContent(m.treeContent())
+					cmds = append(cmds, m.startSplit(m.split, c.mode)...)
+					m.split = ""
+				}
+			}
+			break
+		}
+
+		// Conflict resolution: edit conflicted files, then continue or abort.
+		if m.mode == modeConflict {
+			switch {
+			case msg.Type == tea.KeyEscape:
+				m.closeConflicts()
+			case key.Matches(msg, m.keys.Up):
+				if m.conflict.cursor > 0 {
+					m.conflict.cursor--
+					m.refreshConflictView()
+				}
+			case key.Matches(msg, m.keys.Down):
+				if m.conflict.cursor < len(m.conflict.files)-1 {
+					m.conflict.cursor++
+					m.refreshConflictView()
+				}
+			case key.Matches(msg, m.keys.Edit):
+				if cmd := m.editConflict(); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
+			case key.Matches(msg, m.keys.Continue):
+				cmds = append(cmds, m.startContinue()...)
+			case key.Matches(msg, m.keys.Abort):
+				cmds = append(cmds, m.startAbort()...)
+			}
+			break
+		}
+
+		// Bulk cleanup: pick which merged and closed branches to delete.
+		if m.mode == modeBulkCleanup {
+			switch {
+			case key.Matches(msg, m.keys.Up):
+				if m.bulkCleanup.cursor > 0 {
+					m.bulkCleanup.cursor--
+					m.refreshBulkCleanupView()
+				}
+			case key.Matches(msg, m.keys.Down):
+				if m.bulkCleanup.cursor < len(m.bulkCleanup.candidates)-1 {
+					m.bulkCleanup.cursor++
+					m.refreshBulkCleanupView()
+				}
+			case key.Matches(msg, m.keys.Mark):
+				cand := &m.bulkCleanup.candidates[m.bulkCleanup.cursor]
+				cand.selected = !cand.selected
+				m.refreshBulkCleanupView()
+			case key.Matches(msg, m.keys.Confirm):
+				if len(m.bulkCleanup.selected()) == 0 {
+					m.statusBar.setStatus(severityWarning, "No branches selected")
+					break
+				}
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				cmds = append(cmds, m.startBulkCleanup()...)
+				m.bulkCleanup = bulkCleanup{}
+			case msg.Type == tea.KeyEscape:
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				m.statusBar.setStatus(severityInfo, "Cleanup cancelled")
+				m.bulkCleanup = bulkCleanup{}
+			}
+			break
+		}
+
+		// Merged-branch cleanup: confirm or cancel the plan.
+		if m.mode == modeCleanup {
+			switch {
+			case key.Matches(msg, m.keys.Confirm):
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				cmds = append(cmds, m.startCleanup(m.cleanup)...)
+				m.cleanup = cleanupPlan{}
+			case msg.Type == tea.KeyEscape:
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				m.cleanup = cleanupPlan{}
+				m.statusBar.setStatus(severityInfo, "Cleanup cancelled")
+			}
+			break
+		}
+
+		// Sync preview: sync or cancel.
+		if m.mode == modeSyncPreview {
+			switch {
+			case key.Matches(msg, m.keys.Confirm):
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				m.syncPreview = syncPreview{}
+				cmds = append(cmds, m.startSync()...)
+			case msg.Type == tea.KeyEscape:
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+				m.syncPreview = syncPreview{}
+				m.statusBar.setStatus(severityInfo, "Sync cancelled")
+			}
+			break
+		}
+
+		// Debug view: read-only; close with D or esc.
+		if m.mode == modeDebug {
+			if key.Matches(msg, m.keys.Debug) || msg.Type == tea.KeyEscape {
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+			}
+			break
+		}
+
+		// Overlaps view: read-only; close with O or esc.
+		if m.mode == modeOverlaps {
+			if key.Matches(msg, m.keys.Overlaps) || msg.Type == tea.KeyEscape {
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+			}
+			break
+		}
+
+		// Comments view: read-only; scroll, or close with ctrl+t or esc.
+		if m.mode == modeComments {
+			switch {
+			case key.Matches(msg, m.keys.Comments) || msg.Type == tea.KeyEscape:
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+			case key.Matches(msg, m.keys.Up):
+				m.viewport.ScrollUp(1)
+			case key.Matches(msg, m.keys.Down):
+				m.viewport.ScrollDown(1)
+			}
+			break
+		}
+
+		// Stats view: read-only; scroll, or close with % or esc.
+		if m.mode == modeStats {
+			switch {
+			case key.Matches(msg, m.keys.Stats) || msg.Type == tea.KeyEscape:
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+			case key.Matches(msg, m.keys.Up):
+				m.viewport.ScrollUp(1)
+			case key.Matches(msg, m.keys.Down):
+				m.viewport.ScrollDown(1)
+			}
+			break
+		}
+
+		// Jobs view: move between jobs, cancel one, or close.
+		if m.mode == modeJobs {
+			switch {
+			case key.Matches(msg, m.keys.Jobs) || msg.Type == tea.KeyEscape:
+				m.setMode(modeTree)
+				m.resizeViewport()
+				m.viewport.SetContent(m.treeContent())
+			case key.Matches(msg, m.keys.Up):
+				if m.jobCursor > 0 {
+					m.jobCursor--
+					m.refreshJobsView()
+				}
+			case key.Matches(msg, m.keys.Down):
+				if m.jobCursor < len(m.jobs.jobs)-1 {
+					m.jobCursor++
+					m.refreshJobsView()
+				}
+			case key.Matches(msg, m.keys.CancelJob):
+				listed := m.jobs.listed()
+				if m.jobCursor < len(listed) {
+					j := listed[m.jobCursor]
+					if cmd, ok := m.jobs.cancel(j.id); ok {
+						cmds = append(cmds, cmd)
+						cmds = append(cmds, m.jobs.startQueued()...)
+						m.statusBar.setStatus(severityInfo, "Cancelling: "+j.label)
+					} else {
+						m.statusBar.setStatus(severityWarning, "Job already finished")
+					}
+					m.refreshJobsView()
+				}
 			}
 			break
 		}
@@ -273,9 +1183,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.mode == modeDiff {
 			switch {
 			case key.Matches(msg, m.keys.DiffClose):
-				m.mode = modeTree
-				m.diff = diffView{}
-				m.viewport.SetContent(renderTree(m.displayEntries, m.cursor))
+				m.setMode(modeTree)
+				m.viewport.SetContent(m.treeContent())
+			case key.Matches(msg, m.keys.OpenFile):
+				if cmd := m.openDiffFile(); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
 			case key.Matches(msg, m.keys.Tab):
 				if m.diff.focusedPanel == panelFileList {
 					m.diff.focusedPanel = panelDiff
@@ -284,22 +1197,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			case key.Matches(msg, m.keys.Up):
 				if m.diff.focusedPanel == panelFileList {
-					if m.diff.fileCursor > 0 {
-						m.diff.fileCursor--
-						file := m.diff.files[m.diff.fileCursor].path
-						m.diff.setDiffContent("")
-						cmds = append(cmds, m.loadDiffFile(m.diff.parentBranch, m.diff.branchName, file))
+					if m.diff.fileCursor > 0 && m.diff.selectFile(m.diff.fileCursor-1) {
+						cmds = append(cmds, m.loadDiffFileAt(m.diff.fileCursor))
 					}
 				} else {
 					m.diff.diffViewport.LineUp(1)
 				}
 			case key.Matches(msg, m.keys.Down):
 				if m.diff.focusedPanel == panelFileList {
-					if m.diff.fileCursor < len(m.diff.files)-1 {
-						m.diff.fileCursor++
-						file := m.diff.files[m.diff.fileCursor].path
-						m.diff.setDiffContent("")
-						cmds = append(cmds, m.loadDiffFile(m.diff.parentBranch, m.diff.branchName, file))
+					if m.diff.fileCursor < len(m.diff.files)-1 && m.diff.selectFile(m.diff.fileCursor+1) {
+						cmds = append(cmds, m.loadDiffFileAt(m.diff.fileCursor))
 					}
 				} else {
 					m.diff.diffViewport.LineDown(1)
@@ -308,257 +1215,447 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			break
 		}
 
-		switch {
-		case key.Matches(msg, m.keys.Up):
-			if m.cursor > 0 {
-				m.cursor--
-				m.viewport.SetContent(renderTree(m.displayEntries, m.cursor))
-				m.ensureCursorVisible()
-			}
-		case key.Matches(msg, m.keys.Down):
-			if m.cursor < len(m.displayEntries)-1 {
-				m.cursor++
-				m.viewport.SetContent(renderTree(m.displayEntries, m.cursor))
-				m.ensureCursorVisible()
-			}
-		case key.Matches(msg, m.keys.Checkout):
-			if branch := m.selectedBranch(); branch != nil {
-				m.running = true
-				name := branch.Name
-				client := m.gtClient
-				spinnerCmd := m.statusBar.startSpinner("Checking out " + name + "...")
-				actionCmd := runAction("checkout", "Checked out "+name, func(ctx context.Context) error {
-					return client.Checkout(ctx, name)
-				})
-				cmds = append(cmds, spinnerCmd, actionCmd)
-			}
-		case key.Matches(msg, m.keys.Trunk):
-			if len(m.branches) > 0 {
-				m.running = true
-				name := m.branches[0].Name
-				client := m.gtClient
-				spinnerCmd := m.statusBar.startSpinner("Checking out " + name + "...")
-				actionCmd := runAction("checkout", "Checked out "+name, func(ctx context.Context) error {
-					return client.Checkout(ctx, name)
-				})
-				cmds = append(cmds, spinnerCmd, actionCmd)
-			}
-		case key.Matches(msg, m.keys.StackSubmit):
-			if branch := m.selectedBranch(); branch != nil {
-				if _, hasParent := gt.FindParent(m.branches, branch.Name); !hasParent {
-					m.statusBar.setMessage("Cannot submit trunk branch", true)
-				} else {
-					m.running = true
-					name := branch.Name
-					client := m.gtClient
-					spinnerCmd := m.statusBar.startSpinner("Submitting stack (" + name + ")...")
-					actionCmd := runAction("submit", "Stack submitted", func(ctx context.Context) error {
-						return client.StackSubmit(ctx, name)
-					})
-					cmds = append(cmds, spinnerCmd, actionCmd)
-				}
-			}
-		case key.Matches(msg, m.keys.DownstackSubmit):
-			if branch := m.selectedBranch(); branch != nil {
-				if _, hasParent := gt.FindParent(m.branches, branch.Name); !hasParent {
-					m.statusBar.setMessage("Cannot submit trunk branch", true)
-				} else {
-					m.running = true
-					name := branch.Name
-					client := m.gtClient
-					spinnerCmd := m.statusBar.startSpinner("Submitting downstack (" + name + ")...")
-					actionCmd := runAction("downstack-submit", "Downstack submitted", func(ctx context.Context) error {
-						return client.DownstackSubmit(ctx, name)
-					})
-					cmds = append(cmds, spinnerCmd, actionCmd)
-				}
-			}
-		case key.Matches(msg, m.keys.Restack):
-			if branch := m.selectedBranch(); branch != nil {
-				if _, hasParent := gt.FindParent(m.branches, branch.Name); !hasParent {
-					m.statusBar.setMessage("Cannot restack trunk branch", true)
-				} else {
-					m.running = true
-					name := branch.Name
-					client := m.gtClient
-					spinnerCmd := m.statusBar.startSpinner("Restacking (" + name + ")...")
-					actionCmd := runAction("restack", "Restacked", func(ctx context.Context) error {
-						return client.StackRestack(ctx, name)
-					})
-					cmds = append(cmds, spinnerCmd, actionCmd)
-				}
+		// Move: the cursor picks the new parent; other tree keys are ignored.
+		if m.moving != "" && !key.Matches(msg, m.keys.Up, m.keys.Down) {
+			switch {
+			case key.Matches(msg, m.keys.Confirm):
+				cmds = append(cmds, m.finishMove()...)
+			case key.Matches(msg, m.keys.Finder):
+				m.openMovePicker()
+			case msg.Type == tea.KeyEscape:
+				m.cancelMove()
 			}
-		case key.Matches(msg, m.keys.Fetch):
-			m.running = true
-			client := m.gtClient
-			spinnerCmd := m.statusBar.startSpinner("Fetching...")
-			actionCmd := runAction("fetch", "Fetched", func(ctx context.Context) error {
-				return client.RepoSync(ctx)
-			})
-			cmds = append(cmds, spinnerCmd, actionCmd)
-		case key.Matches(msg, m.keys.Sync):
-			m.running = true
-			client := m.gtClient
-			spinnerCmd := m.statusBar.startSpinner("Syncing...")
-			actionCmd := runAction("sync", "Synced", func(ctx context.Context) error {
-				return client.Sync(ctx)
-			})
-			cmds = append(cmds, spinnerCmd, actionCmd)
-		case key.Matches(msg, m.keys.OpenPR):
-			if branch := m.selectedBranch(); branch != nil {
-				m.running = true
-				name := branch.Name
-				client := m.gtClient
-				spinnerCmd := m.statusBar.startSpinner("Opening PR (" + name + ")...")
-				actionCmd := runAction("openpr", "Opened PR for "+name, func(ctx context.Context) error {
-					return client.OpenPR(ctx, name)
-				})
-				cmds = append(cmds, spinnerCmd, actionCmd)
-			}
-		case key.Matches(msg, m.keys.Diff):
-			if branch := m.selectedBranch(); branch != nil {
-				name := branch.Name
-				parent, ok := gt.FindParent(m.branches, name)
-				if !ok {
-					m.statusBar.setMessage("No parent branch for "+name, true)
-				} else {
-					m.running = true
-					spinnerCmd := m.statusBar.startSpinner("Loading diff for " + name + "...")
-					diffCmd := m.loadDiffData(parent, name)
-					cmds = append(cmds, spinnerCmd, diffCmd)
-				}
+			break
+		}
+
+		// Track: the cursor picks the parent; other tree keys are ignored.
+		if m.tracking != "" && !key.Matches(msg, m.keys.Up, m.keys.Down) {
+			switch {
+			case key.Matches(msg, m.keys.Confirm):
+				cmds = append(cmds, m.finishTrack()...)
+			case msg.Type == tea.KeyEscape:
+				m.cancelTrack()
 			}
-		case key.Matches(msg, m.keys.Help):
-			m.mode = modeHelp
-			m.viewport.SetContent(renderHelp())
+			break
+		}
+
+		if name := m.selectedUntracked(); name != "" && m.needsTracking(msg) {
+			m.statusBar.setStatus(severityWarning, name+" isn't tracked by Graphite — press "+m.keys.Track.Help().Key+" to track it")
+			break
+		}
+
+		// Configured quick-create templates, on keys no action is bound to.
+		if t, ok := m.matchTemplate(msg); ok {
+			m.startTemplate(t)
+			break
+		}
+
+		if s := m.specFor(msg); s != nil && s.execute != nil {
+			cmds = append(cmds, m.runSpec(s)...)
+		} else if msg.Type == tea.KeyEscape && len(m.marked) > 0 {
+			clear(m.marked)
+			m.statusBar.setStatus(severityInfo, "Cleared marks")
+			m.viewport.SetContent(m.treeContent())
 		}
 
 	case tea.WindowSizeMsg:
 		// Set width/height first so chromeHeight() can render the legend at the correct width.
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.maxHeight > 0 && m.height > m.maxHeight {
+			m.height = m.maxHeight
+		}
 		m.statusBar.setSize(msg.Width)
 
-		viewportHeight := msg.Height - m.chromeHeight()
-
 		if !m.ready {
-			m.viewport = viewport.New(msg.Width, viewportHeight)
+			m.viewport = viewport.New(m.treeWidth(), m.height-m.chromeHeight())
 			m.viewport.KeyMap = viewport.KeyMap{}
-			m.viewport.SetContent(renderTree(m.displayEntries, m.cursor))
+			m.viewport.SetContent(m.treeContent())
 			m.ready = true
+			m.scrollToCurrent()
 		} else {
-			m.viewport.Width = msg.Width
-			m.viewport.Height = viewportHeight
-		}
-
-		if m.mode == modeDiff {
-			m.diff.setSize(msg.Width, viewportHeight)
+			m.resizeViewport()
 		}
 
 	case logResultMsg:
+		m.loaded = true
+		m.repo = msg.repo
+		m.resizeViewport()
 		if msg.err != nil {
 			m.err = msg.err
 			m.rawOutput = msg.output
 			errMsg := msg.err.Error()
 			switch {
 			case strings.Contains(errMsg, "executable file not found") || strings.Contains(errMsg, "not found in"):
-				m.statusBar.setMessage("gt CLI not found — install from https://graphite.dev", true)
+				m.statusBar.setStatus(severityError, "gt CLI not found — install from https://graphite.dev")
+			case strings.Contains(errMsg, "not been initialized") || strings.Contains(errMsg, "not initialized"):
+				m.needsInit = true
+				m.statusBar.setStatus(severityWarning, "Graphite is not initialized — press "+m.keys.RepoInit.Help().Key+" to run gt repo init")
 			case strings.Contains(errMsg, "detached HEAD") || strings.Contains(errMsg, "not a branch"):
-				m.statusBar.setMessage("Detached HEAD — checkout a branch to view stacks", true)
+				if m.repo.head.Nearest != "" && !m.repo.rebasing {
+					m.statusBar.setStatus(severityWarning, "Detached HEAD — press "+m.keys.CheckoutNearest.Help().Key+" to check out "+m.repo.head.Nearest)
+				} else {
+					m.statusBar.setStatus(severityWarning, "Detached HEAD — checkout a branch to view stacks")
+				}
 			default:
 				// Preserve existing tree on refresh failure.
 				if len(m.branches) > 0 {
-					m.statusBar.setMessage("Refresh failed: "+errMsg, true)
+					m.statusBar.setStatus(severityError, "Refresh failed: "+errMsg)
 				} else {
-					m.statusBar.setMessage("Error: "+errMsg, true)
+					m.statusBar.setStatus(severityError, "Error: "+errMsg)
 				}
 			}
 		} else {
 			m.err = nil
+			m.needsInit = false
 			m.rawOutput = msg.output
-			m.statusBar.setMessage("", false)
+			m.statusBar.setStatus(severityInfo, "")
 			m.statusBar.setRefreshTime(time.Now())
 		}
 
 		// Parse and render the tree, falling back to raw output on parse error.
 		// On error, keep existing tree content if available.
 		if m.err == nil {
-			content := m.rawOutput
+			content := gt.Redact(m.rawOutput)
 			branches, parseErr := gt.ParseLogShort(m.rawOutput)
 			if parseErr == nil {
+				gt.ApplyParents(branches, msg.parents)
+				gt.ApplyOrphans(branches, msg.orphans)
+				gt.ApplyWorktrees(branches, msg.worktrees)
 				m.branches = branches
+				m.untracked = gt.Untracked(branches, msg.heads)
+				m.orphans = msg.orphans
+				m.worktrees = msg.worktrees
+				m.journalBranches(time.Now())
+				pruneMarks(m.marked, branches)
+				applyTestStatus(m.branches, msg.heads, m.tests, m.testsRunning)
+				applyPRInfo(m.branches, m.prInfos)
 				oldName := ""
 				if b := m.selectedBranch(); b != nil {
 					oldName = b.Name
 				}
-				m.displayEntries = flattenForDisplay(branches)
-				m.preserveCursor(oldName)
-				content = renderTree(m.displayEntries, m.cursor)
-				cmds = append(cmds, m.loadPRInfo())
-			}
-			if m.ready {
+				if m.cursorTarget != "" {
+					oldName = m.cursorTarget
+					m.cursorTarget = ""
+				}
+				m.rebuildEntries(oldName)
+				m.scrollToCurrent()
+				if m.prInfoStale() {
+					cmds = append(cmds, m.loadPRInfo())
+				}
+				cmds = append(cmds, m.loadChanges())
+			} else if m.ready && m.mode == modeTree {
 				m.viewport.SetContent(content)
 			}
+		} else if m.needsInit && m.ready && m.mode == modeTree {
+			m.viewport.SetContent(m.treeContent())
 		}
 
 	case diffDataMsg:
 		m.running = false
 		m.statusBar.stopSpinner()
 		if msg.err != nil {
-			m.statusBar.setMessage("Error: "+msg.err.Error(), true)
+			m.statusBar.setStatus(severityError, "Error: "+msg.err.Error())
 		} else {
-			m.mode = modeDiff
 			m.diff = newDiffView(m.width, m.height-m.chromeHeight())
 			m.diff.branchName = msg.branchName
 			m.diff.parentBranch = msg.parentBranch
+			m.diff.scope = m.scope
+			m.diff.parts = msg.parts
 			m.diff.setFiles(msg.files)
-			m.statusBar.setMessage("", false)
-			if len(msg.files) > 0 {
-				cmds = append(cmds, m.loadDiffFile(msg.parentBranch, msg.branchName, msg.files[0].path))
+			m.diff.heads = diffHeads(m.diff, branchHeads(m.branches))
+			if cached, ok := m.diffs.views[diffCacheKey(m.diff)]; ok {
+				m.diff.resume(cached)
 			}
+			m.setMode(modeDiff)
+			m.statusBar.setStatus(severityInfo, "")
 		}
 
+	case syncPreviewMsg:
+		m.running = false
+		m.statusBar.stopSpinner()
+		if msg.err != nil {
+			m.statusBar.setStatus(severityError, "Sync preview failed: "+msg.err.Error())
+			break
+		}
+		m.statusBar.setStatus(severityInfo, "")
+		m.syncPreview = msg.preview
+		m.setMode(modeSyncPreview)
+		m.resizeViewport()
+		m.viewport.SetContent(renderSyncPreview(m.syncPreview, m.width))
+		m.viewport.GotoTop()
+
+	case upstreamCheckMsg:
+		m.running = false
+		m.statusBar.stopSpinner()
+		m.statusBar.setStatus(severityInfo, "")
+		cmds = append(cmds, m.confirmUpstream(msg)...)
+
+	case preflightResultMsg:
+		m.running = false
+		m.statusBar.stopSpinner()
+		m.statusBar.setStatus(severityInfo, "")
+		m.setMode(modePreflight)
+		m.resizeViewport()
+		m.viewport.SetContent(renderPreflight(m.pending.desc, msg.results))
+		m.viewport.GotoTop()
+
 	case diffFileContentMsg:
+		if m.mode != modeDiff || msg.session != m.diff.session {
+			break // loaded for a diff since closed
+		}
 		if msg.err != nil {
-			m.statusBar.setMessage("Error loading diff: "+msg.err.Error(), true)
-		} else {
+			m.statusBar.setStatus(severityError, "Error loading diff: "+msg.err.Error())
+			break
+		}
+		m.diff.remember(msg.file, msg.content)
+		if m.diff.fileCursor < len(m.diff.files) && m.diff.files[m.diff.fileCursor].path == msg.file {
 			m.diff.setDiffContent(msg.content)
 		}
 
 	case actionResultMsg:
 		m.running = false
+		if msg.action != "openpr" {
+			// Even a failed action may have pushed or rewritten some branches.
+			m.branchStates.changed(m.actionTargets)
+		}
+		m.actionGuard.finished(time.Now())
+		m.recordCommand(msg.action, msg.err != nil, time.Now())
 		m.statusBar.stopSpinner()
 		if msg.err != nil {
 			errMsg := msg.err.Error()
-			if strings.Contains(errMsg, "conflict") || strings.Contains(errMsg, "CONFLICT") {
-				m.statusBar.setMessage("Conflict detected — resolve in terminal, then press f to refresh", true)
+			if isConflict(errMsg) && !errors.Is(msg.err, errFixupRebase) {
+				m.statusBar.setStatus(severityError, "Conflict detected — resolve the files, then press "+m.keys.Continue.Help().Key+" to continue")
+				m.openConflicts()
 			} else {
-				m.statusBar.setMessage("Error: "+errMsg, true)
+				m.statusBar.setStatus(severityError, "Error: "+errMsg)
 			}
+			m.cursorTarget = ""
+			m.actionTargets = nil
 			// Reload tree after errors to reflect actual repo state.
 			cmds = append(cmds, m.loadLog())
 		} else {
-			m.statusBar.setSuccessMessage(msg.message)
+			if msg.warning != "" {
+				m.statusBar.setStatus(severityWarning, msg.message+" — "+msg.warning)
+			} else {
+				m.statusBar.setStatus(severitySuccess, msg.message)
+			}
+			if msg.action == "merge" {
+				m.markQueued(m.actionTargets)
+			}
+			if journalActionNames[msg.action] == "submit" {
+				// New PRs can take a few seconds to show up in PR info.
+				cmds = append(cmds, schedulePRRetry(0, m.actionTargets))
+			}
+			m.journalAction(msg.action, time.Now())
+			if m.mode == modeConflict {
+				m.closeConflicts()
+			}
 			// Reload tree after successful actions (except openpr which doesn't change git state).
 			if msg.action != "openpr" {
+				m.prInfoAt = time.Time{}
 				cmds = append(cmds, m.loadLog())
 			}
 		}
 
+	case prTextMsg:
+		if cmd := m.editPRText(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case prEditedMsg:
+		cmds = append(cmds, m.finishPREdit(msg)...)
+
+	case repoLabelsMsg:
+		m.openLabelPicker(msg)
+
+	case prDiscussionMsg:
+		m.showDiscussion(msg)
+
+	case statsMsg:
+		m.showStats(msg)
+
+	case conflictFilesMsg:
+		if m.mode == modeConflict {
+			if msg.err != nil {
+				m.statusBar.setStatus(severityError, "Could not list conflicts: "+msg.err.Error())
+			}
+			m.conflict.root, m.conflict.files, m.conflict.loaded = msg.root, msg.files, true
+			m.refreshConflictView()
+		}
+
+	case editorDoneMsg:
+		if msg.err != nil {
+			m.statusBar.setStatus(severityError, "Editor failed: "+msg.err.Error())
+		}
+		if m.mode == modeConflict {
+			cmds = append(cmds, m.loadConflicts())
+		}
+
+	case splitReadyMsg:
+		m.running = false
+		m.statusBar.stopSpinner()
+		if msg.err != nil {
+			m.statusBar.setStatus(severityError, "Error: "+msg.err.Error())
+			cmds = append(cmds, m.loadLog())
+		} else {
+			cmds = append(cmds, runSplit(msg.branch, msg.cmd))
+		}
+
+	case worktreeShellDoneMsg:
+		if msg.err != nil {
+			m.statusBar.setStatus(severityError, "Shell in "+msg.path+" failed: "+msg.err.Error())
+		} else {
+			m.statusBar.setStatus(severityInfo, "Back from "+msg.path)
+		}
+		cmds = append(cmds, m.loadLog())
+
+	case splitDoneMsg:
+		text, isError := splitResult(msg, m.keys.Continue.Help().Key)
+		if isError {
+			m.statusBar.setStatus(severityError, text)
+		} else {
+			m.statusBar.setStatus(severitySuccess, text)
+		}
+		m.prInfoAt = time.Time{}
+		cmds = append(cmds, m.loadLog())
+
+	case testResultMsg:
+		delete(m.testsRunning, msg.sha)
+		switch {
+		case msg.err != nil:
+			m.statusBar.setStatus(severityError, "Could not run tests on "+msg.branch+": "+msg.err.Error())
+		case msg.passed:
+			m.tests[msg.sha] = testRecord{Passed: true, At: time.Now()}
+			m.statusBar.setStatus(severitySuccess, "Tests passed on "+msg.branch)
+		default:
+			m.tests[msg.sha] = testRecord{Passed: false, At: time.Now()}
+			text := "Tests failed on " + msg.branch
+			if line := lastLine(msg.output); line != "" {
+				text += ": " + line
+			}
+			m.statusBar.setStatus(severityError, text)
+		}
+		if msg.err == nil {
+			// Persisting is best-effort; the in-memory result still shows.
+			_ = m.tests.save(m.gitDir)
+		}
+		applyTestStatus(m.branches, nil, m.tests, m.testsRunning)
+		if m.mode == modeTree && m.ready {
+			m.viewport.SetContent(m.treeContent())
+		}
+
+	case jobDoneMsg:
+		j := m.jobs.finish(msg.id, msg.err)
+		cmds = append(cmds, m.jobs.startQueued()...)
+		if msg.result != nil {
+			updated, cmd := m.Update(msg.result)
+			m = updated.(Model)
+			cmds = append(cmds, cmd)
+		}
+		if j != nil && j.state == jobCancelled {
+			m.statusBar.setStatus(severityInfo, "Cancelled: "+j.label)
+		}
+		if m.mode == modeJobs {
+			m.refreshJobsView()
+		}
+
+	case viewTickMsg:
+		// Keep live views current; the tick stops once they close.
+		switch m.mode {
+		case modeJobs:
+			m.refreshJobsView()
+			cmds = append(cmds, m.viewTick())
+		case modeDebug:
+			m.refreshDebugView()
+			cmds = append(cmds, m.viewTick())
+		}
+
+	case quotaResultMsg:
+		m.debug.loading = false
+		m.debug.quotas, m.debug.quotaErr = msg.quotas, msg.err
+		if m.mode == modeDebug {
+			m.refreshDebugView()
+		}
+
+	case prRetryMsg:
+		cmds = append(cmds, m.retryPRInfo(msg)...)
+
+	case shareResultMsg:
+		if msg.prs == 0 {
+			m.statusBar.setStatus(severityWarning, "No open PRs in this stack — submit it first")
+		} else {
+			what := fmt.Sprintf("%d PRs", msg.prs)
+			if msg.prs == 1 {
+				what = "1 PR"
+			}
+			m.statusBar.setStatus(severitySuccess, "Copied share links for "+what)
+		}
+
 	case prInfoResultMsg:
+		// Branches an action changed while this refresh ran keep their
+		// previous PR until the refresh that follows the action.
+		dropped := dropStalePRInfo(&m.branchStates, msg.mark, m.prInfos, msg.infos)
+		carryQueued(m.prInfos, msg.infos)
+		if stale := keepStalePRInfo(m.prInfos, msg.infos, msg.failed); stale > 0 {
+			m.toasts.push(severityWarning, "PR data stale: could not refresh "+pluralize(stale, "PR"))
+		}
+		if merged := newlyMerged(m.prInfos, msg.infos); len(merged) > 0 {
+			m.toasts.push(severityInfo, mergedNotice(merged, m.keys.Cleanup.Help().Key))
+		}
+		m.journalMerged(msg.infos, time.Now())
+		m.prInfos = msg.infos
+		if len(dropped) == 0 {
+			m.prInfoAt = time.Now()
+		}
 		applyPRInfo(m.branches, msg.infos)
+		if m.filter.active() {
+			// PR filters depend on the states that just arrived.
+			name := ""
+			if b := m.selectedBranch(); b != nil {
+				name = b.Name
+			}
+			m.rebuildEntries(name)
+		} else if m.mode == modeTree && m.ready {
+			m.viewport.SetContent(m.treeContent())
+		}
+
+	case graphiteResultMsg:
+		m.showGraphiteResult(msg)
+
+	case blobResultMsg:
+		if msg.err != nil {
+			m.statusBar.setStatus(severityError, "Could not open "+msg.file+" on GitHub: "+msg.err.Error())
+		} else {
+			m.statusBar.setStatus(severitySuccess, "Opened "+msg.file+" on GitHub")
+		}
+
+	case stagedResultMsg:
+		if m.mode == modeCommit {
+			m.commit.staged, m.commit.stagedErr, m.commit.stagedLoaded = msg.files, msg.err, true
+		}
+
+	case changesResultMsg:
+		applyChanges(m.branches, msg.changes)
 		if m.mode == modeTree && m.ready {
-			m.viewport.SetContent(renderTree(m.displayEntries, m.cursor))
+			m.viewport.SetContent(m.treeContent())
+		} else if m.mode == modeOverlaps {
+			m.refreshOverlapsView()
 		}
 
 	case spinner.TickMsg:
-		if m.running {
+		if m.running && !m.statusBar.reduceMotion {
 			var cmd tea.Cmd
 			m.statusBar.spinner, cmd = m.statusBar.spinner.Update(msg)
 			cmds = append(cmds, cmd)
 		}
 
 	case gitChangeMsg:
+		if m.idle {
+			// Paused: keep draining the watcher; wake reloads.
+			cmds = append(cmds, waitForChange(m.watcher))
+			break
+		}
 		m.debounceSeq++
 		seq := m.debounceSeq
 		cmds = append(cmds,
@@ -568,13 +1665,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			waitForChange(m.watcher),
 		)
 
+	case idleCheckMsg:
+		cmds = append(cmds, m.checkIdle(time.Now()))
+
+	case pollMsg:
+		cmds = append(cmds, m.poll(msg)...)
+
 	case debounceFireMsg:
-		if msg.seq == m.debounceSeq {
+		if msg.seq == m.debounceSeq && !m.idle {
 			cmds = append(cmds, m.loadLog())
 		}
 
+	case statusExpiredMsg:
+		m.statusBar.expire(msg.seq)
+
+	case toastExpiredMsg:
+		m.toasts.expire(msg.id)
+
 	case watcherErrMsg:
-		m.statusBar.setMessage("Watch error: "+msg.err.Error(), true)
+		m.statusBar.setStatus(severityError, "Watch error: "+msg.err.Error())
 		cmds = append(cmds, waitForChange(m.watcher))
 	}
 
@@ -582,6 +1691,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.viewport, vpCmd = m.viewport.Update(msg)
 	cmds = append(cmds, vpCmd)
 
+	m.advanceTutorial()
 	return m, tea.Batch(cmds...)
 }
 
@@ -606,26 +1716,116 @@ func renderLegend(pairs []struct{ key, desc string }, width int) string {
 }
 
 func (m Model) legendView() string {
+	if m.moving != "" {
+		return m.moveLegendView()
+	}
+	if m.tracking != "" {
+		return m.trackLegendView()
+	}
+	pairs := []struct{ key, desc string }{{"↑↓", "navigate"}}
+	for _, s := range actionSpecs {
+		if s.legend != "" {
+			pairs = append(pairs, struct{ key, desc string }{s.binding(&m.keys).Help().Key, s.legend})
+		}
+	}
+	return renderLegend(pairs, m.width)
+}
+
+func (m Model) preflightLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"enter", "submit"},
+		{"esc", "cancel"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
+
+func (m Model) syncPreviewLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"enter", "sync"},
+		{"esc", "cancel"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
+
+func (m Model) cleanupLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"enter", "clean up"},
+		{"esc", "cancel"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
+
+func (m Model) bulkCleanupLegendView() string {
 	pairs := []struct{ key, desc string }{
 		{"↑↓", "navigate"},
-		{"enter", "checkout"},
-		{"m", "trunk"},
-		{"d", "diff"},
-		{"s", "submit"},
-		{"S", "downstack"},
-		{"r", "restack"},
-		{"f", "fetch"},
-		{"y", "sync"},
-		{"o", "open PR"},
-		{"?", "help"},
+		{"space", "toggle"},
+		{"enter", "delete selected"},
+		{"esc", "cancel"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
+
+func (m Model) confirmLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"enter", "run"},
+		{"esc", "cancel"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
+
+func (m Model) splitLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"c", "by commit"},
+		{"h", "by hunk"},
+		{"esc", "cancel"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
+
+func (m Model) jobsLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"↑↓", "navigate"},
+		{"x", "cancel job"},
+		{"J/esc", "close"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
+
+func (m Model) debugLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"D/esc", "close"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
+
+func (m Model) overlapsLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"O/esc", "close"},
 		{"q", "quit"},
 	}
 	return renderLegend(pairs, m.width)
 }
 
 func (m Model) helpLegendView() string {
+	if m.help.searching {
+		return renderLegend([]struct{ key, desc string }{
+			{"enter", "keep search"},
+			{"esc", "clear search"},
+		}, m.width)
+	}
 	pairs := []struct{ key, desc string }{
-		{"?/esc", "close help"},
+		{"↑/↓", "scroll"},
+		{"tab/shift+tab", "next/prev section"},
+		{"/", "search"},
+		{m.keys.Help.Help().Key + "/esc", "close help"},
 		{"q", "quit"},
 	}
 	return renderLegend(pairs, m.width)
@@ -635,6 +1835,7 @@ func (m Model) diffLegendView() string {
 	pairs := []struct{ key, desc string }{
 		{"↑↓", "navigate"},
 		{"tab", "switch panel"},
+		{m.keys.OpenFile.Help().Key, "open on GitHub"},
 		{"esc/d", "close"},
 		{"q", "quit"},
 	}
@@ -650,39 +1851,264 @@ func (m Model) chromeHeight() int {
 		legend = m.diffLegendView()
 	case modeHelp:
 		legend = m.helpLegendView()
+	case modePreflight:
+		legend = m.preflightLegendView()
+	case modeJobs:
+		legend = m.jobsLegendView()
+	case modeDebug:
+		legend = m.debugLegendView()
+	case modeOverlaps:
+		legend = m.overlapsLegendView()
+	case modeComments:
+		legend = m.commentsLegendView()
+	case modeStats:
+		legend = m.statsLegendView()
+	case modeCleanup:
+		legend = m.cleanupLegendView()
+	case modeSyncPreview:
+		legend = m.syncPreviewLegendView()
+	case modeConfirm:
+		legend = m.confirmLegendView()
+	case modeSplit:
+		legend = m.splitLegendView()
+	case modeConflict:
+		legend = m.conflictLegendView()
+	case modeBulkCleanup:
+		legend = m.bulkCleanupLegendView()
+	case modeCommit:
+		legend = m.commitLegendView()
+	case modePicker:
+		legend = m.pickerLegendView()
 	default:
 		legend = m.legendView()
+		if banner := m.headerView(); banner != "" {
+			return lipgloss.Height(banner) + lipgloss.Height(legend) + m.statusLines() + m.tutorialHeight()
+		}
+	}
+	return lipgloss.Height(legend) + m.statusLines() + m.tutorialHeight()
+}
+
+// statusLines is the height of the status bar, which grows when a long
+// message wraps.
+func (m Model) statusLines() int {
+	return lipgloss.Height(m.statusView())
+}
+
+// headerView renders the banners above the tree: the detached HEAD /
+// rebase banner and the active filters.
+func (m Model) headerView() string {
+	var banners []string
+	for _, b := range []string{
+		renderRepoBanner(m.repo, m.width),
+		renderFilterBanner(m.filter, m.width, m.keys.ClearFilters.Help().Key),
+	} {
+		if b != "" {
+			banners = append(banners, b)
+		}
 	}
-	return lipgloss.Height(legend) + 1 // +1 for status bar
+	return strings.Join(banners, "\n")
+}
+
+// tutorialHeight is the height of the tutorial line above every view.
+func (m Model) tutorialHeight() int {
+	if !m.tutorial.active {
+		return 0
+	}
+	return 1
+}
+
+// statusView renders the status bar, naming the error details key when a
+// long error is cut.
+func (m Model) statusView() string {
+	bar := m.statusBar
+	bar.detailsKey = m.keys.ErrorDetails.Help().Key
+	return bar.view()
 }
 
 func (m Model) View() string {
 	if !m.ready {
 		return "Loading..."
 	}
+	if m.tutorial.active {
+		return m.withToasts(lipgloss.JoinVertical(lipgloss.Left, m.tutorialView(), m.view()))
+	}
+	return m.withToasts(m.view())
+}
+
+// view renders the current mode.
+func (m Model) view() string {
 
 	if m.mode == modeDiff {
 		return lipgloss.JoinVertical(
 			lipgloss.Left,
 			m.diff.view(),
 			m.diffLegendView(),
-			m.statusBar.view(),
+			m.statusView(),
 		)
 	}
 
 	if m.mode == modeHelp {
 		return lipgloss.JoinVertical(
 			lipgloss.Left,
-			m.viewport.View(),
+			m.help.view(),
 			m.helpLegendView(),
-			m.statusBar.view(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modePreflight {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.preflightLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeJobs {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.jobsLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeDebug {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.debugLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeCleanup {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.cleanupLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeSyncPreview {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.syncPreviewLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeConfirm {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.withOverlay(m.viewport.View(), confirmOverlay(m.confirm)),
+			m.confirmLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeSplit {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.splitLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeCommit {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.commitView(m.viewport.Height),
+			m.commitLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modePicker {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.withOverlay(m.viewport.View(), m.pickerOverlay()),
+			m.pickerLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeBulkCleanup {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.bulkCleanupLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeConflict {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.conflictLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeOverlaps {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.overlapsLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeComments {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.commentsLegendView(),
+			m.statusView(),
+		)
+	}
+
+	if m.mode == modeStats {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.statsLegendView(),
+			m.statusView(),
+		)
+	}
+
+	main := m.viewport.View()
+	if m.detailVisible() {
+		main = lipgloss.JoinHorizontal(lipgloss.Top, main, m.detailView())
+	}
+	switch {
+	case m.prompt.active():
+		main = m.withOverlay(main, m.prompt.overlay())
+	case m.form.active():
+		main = m.withOverlay(main, m.form.overlay())
+	case m.dialog.active():
+		main = m.withOverlay(main, m.dialog)
+	}
+
+	if banner := m.headerView(); banner != "" {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			banner,
+			main,
+			m.legendView(),
+			m.statusView(),
 		)
 	}
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
-		m.viewport.View(),
+		main,
 		m.legendView(),
-		m.statusBar.view(),
+		m.statusView(),
 	)
 }
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// modeHooks start and stop a view mode's background work. enter runs once
+// the mode is shown and exit as it is left, before the next mode's enter,
+// so a mode can cancel what it started instead of leaving its results to
+// arrive after it has closed. Either may be nil.
+type modeHooks struct {
+	enter func(m *Model) []tea.Cmd
+	exit  func(m *Model) []tea.Cmd
+}
+
+// viewModeHooks lists the hooks of the modes that have any.
+var viewModeHooks = map[viewMode]modeHooks{
+	modeDiff:     {enter: (*Model).enterDiff, exit: (*Model).exitDiff},
+	modeConflict: {enter: (*Model).enterConflicts, exit: (*Model).exitConflicts},
+	modeComments: {enter: (*Model).enterComments},
+	modeStats:    {enter: (*Model).enterStats},
+}
+
+// setMode switches the view to mode, running the exit hook of the mode
+// left and the enter hook of mode. Switching to the mode already shown
+// runs neither. Commands the hooks return are queued on m.modeCmds and run
+// after the current update, so callers needn't thread them through.
+// Leaving one view for another records it for switchView.
+func (m *Model) setMode(mode viewMode) {
+	if mode == m.mode {
+		return
+	}
+	if switchableViews[m.mode] && switchableViews[mode] {
+		m.prevView = m.mode
+		m.viewScrolls[m.mode] = m.viewport.YOffset
+	}
+	if exit := viewModeHooks[m.mode].exit; exit != nil {
+		m.modeCmds = append(m.modeCmds, exit(m)...)
+	}
+	m.mode = mode
+	if enter := viewModeHooks[mode].enter; enter != nil {
+		m.modeCmds = append(m.modeCmds, enter(m)...)
+	}
+}
+
+// takeModeCmds returns the commands queued by mode hooks since the last
+// call.
+func (m *Model) takeModeCmds() []tea.Cmd {
+	cmds := m.modeCmds
+	m.modeCmds = nil
+	return cmds
+}
+
+// enterDiff starts a diff session: file loads run under its context and
+// are stamped with its number, and the file under the cursor is loaded,
+// unless it already was.
+func (m *Model) enterDiff() []tea.Cmd {
+	m.diffSession++
+	m.diff.session = m.diffSession
+	m.diff.ctx, m.diff.cancel = context.WithCancel(context.Background())
+	if len(m.diff.files) == 0 || m.diff.fileLoaded() {
+		return nil
+	}
+	return []tea.Cmd{m.loadDiffFileAt(m.diff.fileCursor)}
+}
+
+// exitDiff cancels the session's file loads still running. Loads that
+// finish anyway are dropped by their session number. The diff itself is
+// kept, so switchView can return to it as it was, and cached for when
+// the same branch's diff is opened again.
+func (m *Model) exitDiff() []tea.Cmd {
+	if m.diff.cancel != nil {
+		m.diff.cancel()
+	}
+	m.diffs.put(m.diff)
+	return nil
+}
+
+// enterConflicts starts listing the conflicted files.
+func (m *Model) enterConflicts() []tea.Cmd {
+	m.conflict = conflictView{}
+	return []tea.Cmd{m.loadConflicts()}
+}
+
+// exitConflicts discards the conflict list.
+func (m *Model) exitConflicts() []tea.Cmd {
+	m.conflict = conflictView{}
+	return nil
+}
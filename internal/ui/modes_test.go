@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func openTestDiff(m Model) Model {
+	updated, _ := m.Update(diffDataMsg{
+		branchName:   "feature-top",
+		parentBranch: "main",
+		files:        []diffFileEntry{{path: "model.go", summary: "5 +++--"}},
+	})
+	return updated.(Model)
+}
+
+func TestModeHooks_ClosingDiffCancelsItsLoads(t *testing.T) {
+	m := openTestDiff(loadedDiffModel("│ ◉  feature-top\n◯─┘  main"))
+	ctx := m.diff.ctx
+	if ctx == nil || ctx.Err() != nil {
+		t.Fatal("entering the diff should start a live session context")
+	}
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || ctx.Err() == nil {
+		t.Errorf("mode = %d, ctx err = %v, want the tree and the session cancelled", m.mode, ctx.Err())
+	}
+}
+
+func TestModeHooks_DropsDiffContentFromClosedSession(t *testing.T) {
+	m := openTestDiff(loadedDiffModel("│ ◉  feature-top\n◯─┘  main"))
+	old := m.diff.session
+	m = sendSpecialKey(m, tea.KeyEscape)
+
+	updated, _ := m.Update(diffFileContentMsg{session: old, file: "model.go", err: errors.New("context canceled")})
+	m = updated.(Model)
+	if m.statusBar.severity == severityError {
+		t.Errorf("a load for a closed diff should be dropped, status = %q", m.statusBar.message)
+	}
+
+	m = openTestDiff(m)
+	if m.diff.session == old {
+		t.Fatal("reopening the diff should start a new session")
+	}
+	updated, _ = m.Update(diffFileContentMsg{session: old, file: "model.go", content: "+stale"})
+	m = updated.(Model)
+	if m.diff.content != "" {
+		t.Errorf("content = %q, want the old session's load ignored", m.diff.content)
+	}
+	updated, _ = m.Update(diffFileContentMsg{session: m.diff.session, file: "model.go", content: "+fresh"})
+	m = updated.(Model)
+	if m.diff.content != "+fresh" {
+		t.Errorf("content = %q, want the current session's load shown", m.diff.content)
+	}
+}
+
+func TestSetMode_SameModeRunsNoHooks(t *testing.T) {
+	m := openTestDiff(loadedDiffModel("│ ◉  feature-top\n◯─┘  main"))
+	m.takeModeCmds()
+	session := m.diff.session
+	m.setMode(modeDiff)
+	if m.diff.session != session || len(m.modeCmds) != 0 {
+		t.Errorf("session = %d, queued = %d, want the diff left alone", m.diff.session, len(m.modeCmds))
+	}
+}
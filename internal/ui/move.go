@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var movingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+
+const (
+	// movingLabel is appended to the branch being moved.
+	movingLabel = "  ⇡ moving"
+	// moveTargetLabel is appended to the cursor row while picking a parent.
+	moveTargetLabel = "  ← onto"
+)
+
+// beginMove starts picking a new parent for name with the cursor.
+func (m *Model) beginMove(name string) {
+	m.moving = name
+	m.viewport.SetContent(m.treeContent())
+	m.statusBar.setStatus(severityInfo, "Moving "+name+" — pick its new parent and press enter")
+}
+
+// cancelMove leaves move selection, returning the cursor to the branch
+// that was being moved.
+func (m *Model) cancelMove() {
+	name := m.moving
+	m.moving = ""
+	m.preserveCursor(name)
+	m.viewport.SetContent(m.treeContent())
+	m.ensureCursorVisible()
+	m.statusBar.setStatus(severityInfo, "Move cancelled")
+}
+
+// moveRefusal explains why name can't be moved onto target, or returns ""
+// if it can.
+func moveRefusal(branches []*gt.Branch, name, target string) string {
+	if parent, _ := gt.FindParent(branches, name); parent == target {
+		return name + " is already on " + target
+	}
+	if target == name {
+		return "Cannot move " + name + " onto itself"
+	}
+	var above []*gt.Branch
+	if b := gt.FindBranch(branches, name); b != nil {
+		collectDescendants(b, &above)
+	}
+	for _, b := range above {
+		if b.Name == target {
+			return "Cannot move " + name + " onto " + target + ", which is stacked on it"
+		}
+	}
+	return ""
+}
+
+// finishMove moves the branch being moved onto the branch at the cursor.
+// The cursor follows the moved branch.
+func (m *Model) finishMove() []tea.Cmd {
+	target := m.selectedBranch()
+	if target == nil {
+		return nil
+	}
+	name := m.moving
+	if reason := moveRefusal(m.branches, name, target.Name); reason != "" {
+		m.statusBar.setStatus(severityWarning, reason)
+		return nil
+	}
+	m.moving = ""
+	m.cursorTarget = name
+	m.actionTargets = []string{name}
+	if b := gt.FindBranch(m.branches, name); b != nil {
+		var above []*gt.Branch
+		collectDescendants(b, &above)
+		m.actionTargets = append(m.actionTargets, branchNames(above)...)
+	}
+	m.viewport.SetContent(m.treeContent())
+	onto := target.Name
+	return m.startAction("move", "Moved "+name+" onto "+onto, "Moving "+name+" onto "+onto+"...", "", func(ctx context.Context, client *gt.Client) error {
+		return client.Move(ctx, name, onto)
+	})
+}
+
+func (m Model) moveLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"↑↓", "pick parent"},
+		{"enter", "move"},
+		{m.keys.Finder.Help().Key, "find parent"},
+		{"esc", "cancel"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestMove_PicksParentWithCursor(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	m.cursor = 0 // feature-top
+	m = sendKey(m, 'M')
+	if m.moving != "feature-top" {
+		t.Fatalf("moving = %q", m.moving)
+	}
+	m = sendSpecialKey(m, tea.KeyDown)
+	m = sendSpecialKey(m, tea.KeyDown)
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"feature-top 2/2" + movingLabel, "main" + moveTargetLabel, "pick parent"} {
+		if !containsString(view, want) {
+			t.Errorf("view should contain %q:\n%s", want, view)
+		}
+	}
+
+	// Other tree keys are ignored while picking.
+	m = sendKey(m, 's')
+	if m.running || len(*calls) != 0 {
+		t.Fatal("submit should not run while moving")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	runBatch(cmd)
+	want := []string{"move", "--no-interactive", "--onto", "main", "--branch", "feature-top"}
+	if len(*calls) != 1 || !slices.Equal((*calls)[0].args, want) {
+		t.Errorf("calls = %+v, want gt %v", *calls, want)
+	}
+	if m.moving != "" || m.cursorTarget != "feature-top" {
+		t.Errorf("moving = %q, cursorTarget = %q", m.moving, m.cursorTarget)
+	}
+}
+
+func TestMove_PicksParentByName(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◯  feature-top\n│ ◉  feature-mid\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	m.cursor = 1 // feature-mid
+	m = sendKey(m, 'M')
+	m = sendKey(m, '/')
+	if m.mode != modePicker {
+		t.Fatalf("/ should open the picker while moving, mode = %d", m.mode)
+	}
+	// Only main can take feature-mid: it is already on feature-base and
+	// feature-top is stacked on it.
+	if len(m.picker.items) != 1 || m.picker.items[0].name != "main" {
+		t.Errorf("items = %+v, want only main", m.picker.items)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	runBatch(cmd)
+	want := []string{"move", "--no-interactive", "--onto", "main", "--branch", "feature-mid"}
+	if len(*calls) != 1 || !slices.Equal((*calls)[0].args, want) {
+		t.Errorf("calls = %+v, want gt %v", *calls, want)
+	}
+}
+
+func TestMove_Refusals(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.cursor = 2 // main
+	m = sendKey(m, 'M')
+	if m.moving != "" || m.statusBar.message != "Cannot move trunk branch" {
+		t.Errorf("moving = %q, message = %q", m.moving, m.statusBar.message)
+	}
+
+	for _, tc := range []struct {
+		branch, target, want string
+	}{
+		{"feature-top", "feature-base", "feature-top is already on feature-base"},
+		{"feature-top", "feature-top", "Cannot move feature-top onto itself"},
+		{"feature-base", "feature-top", "Cannot move feature-base onto feature-top, which is stacked on it"},
+		{"feature-top", "main", ""},
+	} {
+		if got := moveRefusal(m.branches, tc.branch, tc.target); got != tc.want {
+			t.Errorf("moveRefusal(%s, %s) = %q, want %q", tc.branch, tc.target, got, tc.want)
+		}
+	}
+
+	// A refused target keeps picking; esc returns the cursor.
+	m.cursor = 1 // feature-base
+	m = sendKey(m, 'M')
+	m = sendSpecialKey(m, tea.KeyUp)
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.moving != "feature-base" || m.running {
+		t.Errorf("refused move should keep picking: moving = %q, running = %v", m.moving, m.running)
+	}
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.moving != "" || m.cursor != 1 {
+		t.Errorf("esc should cancel and return the cursor: moving = %q, cursor = %d", m.moving, m.cursor)
+	}
+}
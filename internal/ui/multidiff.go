@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var (
+	markStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+	diffOverlapStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+)
+
+// markLabel is appended to branches marked for a combined diff.
+const markLabel = " ✚"
+
+// diffPart is one branch's contribution to a combined diff: its changes
+// relative to its own parent.
+type diffPart struct {
+	parent string
+	branch string
+}
+
+// markedParts returns a diff part for each marked branch, ordered from the
+// trunk side of the stack upwards so the combined diff reads in stack order.
+// Marked names that are no longer in the tree, or have no parent, are skipped.
+func markedParts(branches []*gt.Branch, marked map[string]bool) []diffPart {
+	entries := flattenForDisplay(branches)
+	var parts []diffPart
+	for i := len(entries) - 1; i >= 0; i-- {
+		name := entries[i].branch.Name
+		if !marked[name] {
+			continue
+		}
+		if parent, ok := gt.FindParent(branches, name); ok {
+			parts = append(parts, diffPart{parent: parent, branch: name})
+		}
+	}
+	return parts
+}
+
+// combinedTitle returns the branch and base labels for a combined diff
+// header, e.g. "a + b" vs "main". Bases are the parents of marked branches
+// that are not themselves marked, i.e. where the marked range meets the
+// rest of the stack.
+func combinedTitle(parts []diffPart) (names, bases string) {
+	inSet := make(map[string]bool, len(parts))
+	var branchNames []string
+	for _, p := range parts {
+		inSet[p.branch] = true
+		branchNames = append(branchNames, p.branch)
+	}
+	var baseNames []string
+	for _, p := range parts {
+		if !inSet[p.parent] && !slices.Contains(baseNames, p.parent) {
+			baseNames = append(baseNames, p.parent)
+		}
+	}
+	return strings.Join(branchNames, " + "), strings.Join(baseNames, ", ")
+}
+
+// pruneMarks drops marks for branches no longer in the tree.
+func pruneMarks(marked map[string]bool, branches []*gt.Branch) {
+	present := make(map[string]bool)
+	for _, e := range flattenForDisplay(branches) {
+		present[e.branch.Name] = true
+	}
+	for name := range marked {
+		if !present[name] {
+			delete(marked, name)
+		}
+	}
+}
+
+// mergeDiffFiles merges per-part file lists into one list sorted by path,
+// recording which branches touch each file.
+func mergeDiffFiles(parts []diffPart, perPart [][]diffFileEntry) []diffFileEntry {
+	index := make(map[string]int)
+	var files []diffFileEntry
+	for i, entries := range perPart {
+		for _, f := range entries {
+			idx, ok := index[f.path]
+			if !ok {
+				idx = len(files)
+				index[f.path] = idx
+				files = append(files, diffFileEntry{path: f.path, summary: f.summary})
+			}
+			files[idx].branches = append(files[idx].branches, parts[i].branch)
+		}
+	}
+	slices.SortFunc(files, func(a, b diffFileEntry) int { return strings.Compare(a.path, b.path) })
+	return files
+}
+
+// loadCombinedDiffData fetches the union of the changed files of several
+// branches, each diffed against its own parent.
+func (m Model) loadCombinedDiffData(parts []diffPart) tea.Cmd {
+	client := m.gtClient
+	ignore := m.ignore
+	var paths []string
+	if m.scope != "" {
+		paths = []string{m.scope}
+	}
+	names, bases := combinedTitle(parts)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		perPart := make([][]diffFileEntry, len(parts))
+		for i, p := range parts {
+			statOutput, err := client.DiffStat(ctx, p.parent, p.branch, paths...)
+			if err != nil {
+				return diffDataMsg{branchName: names, err: err}
+			}
+			for _, f := range parseDiffStat(statOutput) {
+				if !ignore.ignored(f.path) {
+					perPart[i] = append(perPart[i], f)
+				}
+			}
+		}
+		return diffDataMsg{branchName: names, parentBranch: bases, files: mergeDiffFiles(parts, perPart), parts: parts}
+	}
+}
+
+// loadCombinedDiffFile fetches a file's diff from every marked branch that
+// touches it, each under a header naming the branch.
+func (m Model) loadCombinedDiffFile(parts []diffPart, file diffFileEntry) tea.Cmd {
+	client := m.gtClient
+	session, parentCtx := m.diff.session, m.diff.context()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(parentCtx, 10*time.Second)
+		defer cancel()
+		var sections []string
+		for _, p := range parts {
+			if !slices.Contains(file.branches, p.branch) {
+				continue
+			}
+			content, err := client.DiffFile(ctx, p.parent, p.branch, file.path)
+			if err != nil {
+				return diffFileContentMsg{session: session, file: file.path, err: err}
+			}
+			header := diffHeaderStyle.Render("── " + p.branch + " (vs " + p.parent + ")")
+			sections = append(sections, header+"\n"+strings.TrimRight(content, "\n"))
+		}
+		return diffFileContentMsg{session: session, file: file.path, content: strings.Join(sections, "\n\n")}
+	}
+}
+
+// loadDiffFileAt loads the diff for the file at index i of the open diff,
+// combined across branches when several are being diffed.
+func (m Model) loadDiffFileAt(i int) tea.Cmd {
+	file := m.diff.files[i]
+	if len(m.diff.parts) > 0 {
+		return m.loadCombinedDiffFile(m.diff.parts, file)
+	}
+	return m.loadDiffFile(m.diff.parentBranch, m.diff.branchName, file.path)
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+const multiDiffLog = "│ ◉  feature-top\n│ ◯  feature-base\n│ │ ◯  side\n◯─┘  main"
+
+func TestMarkedParts_StackOrder(t *testing.T) {
+	branches, err := gt.ParseLogShort(multiDiffLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	marked := map[string]bool{"feature-top": true, "feature-base": true, "gone": true}
+	got := markedParts(branches, marked)
+	want := []diffPart{{parent: "main", branch: "feature-base"}, {parent: "feature-base", branch: "feature-top"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("markedParts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCombinedTitle(t *testing.T) {
+	names, bases := combinedTitle([]diffPart{
+		{parent: "main", branch: "feature-base"},
+		{parent: "feature-base", branch: "feature-top"},
+		{parent: "main", branch: "side"},
+	})
+	if names != "feature-base + feature-top + side" {
+		t.Errorf("names = %q", names)
+	}
+	if bases != "main" {
+		t.Errorf("bases = %q, want main", bases)
+	}
+}
+
+func TestMergeDiffFiles_RecordsOverlap(t *testing.T) {
+	parts := []diffPart{{parent: "main", branch: "a"}, {parent: "a", branch: "b"}}
+	files := mergeDiffFiles(parts, [][]diffFileEntry{
+		{{path: "z.go"}, {path: "shared.go"}},
+		{{path: "shared.go"}, {path: "b.go"}},
+	})
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.path)
+	}
+	if !reflect.DeepEqual(paths, []string{"b.go", "shared.go", "z.go"}) {
+		t.Errorf("paths = %v", paths)
+	}
+	if !reflect.DeepEqual(files[1].branches, []string{"a", "b"}) {
+		t.Errorf("shared.go branches = %v, want [a b]", files[1].branches)
+	}
+}
+
+func TestMarkKey_TogglesAndRenders(t *testing.T) {
+	m := loadedModel(multiDiffLog)
+	m.cursor = 0 // feature-top
+	m = sendSpecialKey(m, tea.KeySpace)
+	if !m.marked["feature-top"] {
+		t.Fatal("feature-top should be marked")
+	}
+	if !strings.Contains(ansi.Strip(m.viewport.View()), "feature-top 2/3"+markLabel) {
+		t.Error("marked branch should show the mark in the tree")
+	}
+
+	m = sendSpecialKey(m, tea.KeySpace)
+	if m.marked["feature-top"] {
+		t.Error("second space should unmark")
+	}
+}
+
+func TestMarkKey_TrunkGuarded(t *testing.T) {
+	m := loadedModel(multiDiffLog)
+	m.cursor = len(m.displayEntries) - 1 // main
+	m = sendSpecialKey(m, tea.KeySpace)
+	if len(m.marked) != 0 {
+		t.Error("trunk should not be markable")
+	}
+	if !strings.Contains(m.statusBar.message, "trunk") {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+func TestEscape_ClearsMarks(t *testing.T) {
+	m := loadedModel(multiDiffLog)
+	m.marked["feature-top"] = true
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if len(m.marked) != 0 {
+		t.Errorf("marks = %v, want none", m.marked)
+	}
+}
+
+func TestReload_PrunesMarks(t *testing.T) {
+	m := loadedModel(multiDiffLog)
+	m.marked["side"] = true
+	m.marked["feature-top"] = true
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n◯─┘  main"})
+	m = updated.(Model)
+	if m.marked["side"] || !m.marked["feature-top"] {
+		t.Errorf("marks = %v, want only feature-top", m.marked)
+	}
+}
+
+func TestCombinedDiff_LoadsUnionAndSections(t *testing.T) {
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		switch {
+		case args[1] == "--stat" && args[2] == "main...feature-base":
+			return " shared.go | 2 +-\n base.go | 1 +\n", nil
+		case args[1] == "--stat" && args[2] == "feature-base...feature-top":
+			return " shared.go | 3 ++-\n", nil
+		case args[1] == "--color=always":
+			return "diff of " + args[2] + "\n", nil
+		}
+		return "", nil
+	}}
+	m := loadedModel(multiDiffLog)
+	m.gtClient = gt.New(mock)
+	m.marked["feature-top"] = true
+	m.marked["feature-base"] = true
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'d'}}))
+	m = updated.(Model)
+	var data diffDataMsg
+	for _, msg := range batchMsgs(cmd) {
+		if d, ok := msg.(diffDataMsg); ok {
+			data = d
+		}
+	}
+	if data.err != nil || len(data.files) != 2 {
+		t.Fatalf("diffDataMsg = %+v", data)
+	}
+
+	updated, cmd = m.Update(data)
+	m = updated.(Model)
+	if m.mode != modeDiff {
+		t.Fatal("expected diff mode")
+	}
+	if !strings.Contains(ansi.Strip(m.diff.view()), "feature-base + feature-top (vs main)") {
+		t.Error("header should name the marked branches and the stack base")
+	}
+	if m.diff.files[1].path != "shared.go" || len(m.diff.files[1].branches) != 2 {
+		t.Errorf("shared.go entry = %+v", m.diff.files[1])
+	}
+
+	// First file is base.go, touched only by feature-base.
+	var content diffFileContentMsg
+	for _, msg := range batchMsgs(cmd) {
+		if c, ok := msg.(diffFileContentMsg); ok {
+			content = c
+		}
+	}
+	if strings.Contains(content.content, "feature-top") || !strings.Contains(content.content, "main...feature-base") {
+		t.Errorf("base.go content = %q", content.content)
+	}
+
+	_, cmd = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'j'}}))
+	for _, msg := range batchMsgs(cmd) {
+		if c, ok := msg.(diffFileContentMsg); ok {
+			content = c
+		}
+	}
+	stripped := ansi.Strip(content.content)
+	if content.file != "shared.go" {
+		t.Fatalf("loaded %q, want shared.go", content.file)
+	}
+	if !strings.Contains(stripped, "── feature-base (vs main)") || !strings.Contains(stripped, "── feature-top (vs feature-base)") {
+		t.Errorf("shared.go content = %q", stripped)
+	}
+}
+
+// batchMsgs runs cmd and returns the messages it produces, running each
+// command of a batch.
+func batchMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, c := range batch {
+		if c != nil {
+			msgs = append(msgs, c())
+		}
+	}
+	return msgs
+}
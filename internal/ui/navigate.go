@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// stackDestination returns the branch `gt <dir>` checks out from current,
+// where dir is "up", "down", "top" or "bottom", or a reason it can't move.
+// Like gt with --no-interactive, it won't choose between several children.
+func stackDestination(branches []*gt.Branch, current, dir string) (dest, refusal string) {
+	b := gt.FindBranch(branches, current)
+	if b == nil {
+		return "", "The current branch isn't in the tree"
+	}
+	switch dir {
+	case "down":
+		if b.Parent == "" {
+			return "", current + " is trunk"
+		}
+		return b.Parent, ""
+	case "bottom":
+		if b.Parent == "" {
+			return "", current + " is trunk"
+		}
+		for {
+			parent := gt.FindBranch(branches, b.Parent)
+			if parent == nil || parent.Parent == "" {
+				return b.Name, ""
+			}
+			b = parent
+		}
+	default:
+		if len(b.Children) == 0 {
+			if dir == "top" {
+				return b.Name, ""
+			}
+			return "", current + " is at the top of its stack"
+		}
+		for len(b.Children) == 1 {
+			b = b.Children[0]
+			if dir == "up" {
+				return b.Name, ""
+			}
+		}
+		if len(b.Children) > 1 {
+			return "", fmt.Sprintf("%s has %d branches stacked on it — pick one with the cursor", b.Name, len(b.Children))
+		}
+		return b.Name, ""
+	}
+}
+
+// startStackStep checks out the branch dir ("up", "down", "top" or
+// "bottom") of the current stack. The cursor follows the checkout.
+func (m *Model) startStackStep(dir string) []tea.Cmd {
+	step := map[string]func(*gt.Client, context.Context) error{
+		"up":     (*gt.Client).Up,
+		"down":   (*gt.Client).Down,
+		"top":    (*gt.Client).Top,
+		"bottom": (*gt.Client).Bottom,
+	}[dir]
+	dest, refusal := stackDestination(m.branches, currentBranchName(*m), dir)
+	if refusal != "" {
+		m.statusBar.setStatus(severityWarning, refusal)
+		return nil
+	}
+	if dest == currentBranchName(*m) {
+		m.statusBar.setStatus(severityInfo, "Already on "+dest)
+		return nil
+	}
+	if m.inOtherWorktree(dest) {
+		return nil
+	}
+	m.cursorTarget = dest
+	return m.startAction("checkout", "Checked out "+dest, "Checking out "+dest+"...", "", func(ctx context.Context, client *gt.Client) error {
+		return step(client, ctx)
+	})
+}
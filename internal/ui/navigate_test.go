@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestStackDestination(t *testing.T) {
+	branches, _ := gt.ParseLogShort("│ ◯  top\n│ ◉  mid\n│ ◯  base\n◯─┘  main")
+	tests := []struct {
+		current, dir string
+		want         string
+		refused      bool
+	}{
+		{"mid", "up", "top", false},
+		{"mid", "down", "base", false},
+		{"mid", "top", "top", false},
+		{"mid", "bottom", "base", false},
+		{"top", "up", "", true},
+		{"top", "top", "top", false},
+		{"main", "down", "", true},
+		{"main", "bottom", "", true},
+		{"main", "up", "base", false},
+	}
+	for _, tt := range tests {
+		dest, refusal := stackDestination(branches, tt.current, tt.dir)
+		if dest != tt.want || (refusal != "") != tt.refused {
+			t.Errorf("%s from %s = %q, %q; want %q, refused %v", tt.dir, tt.current, dest, refusal, tt.want, tt.refused)
+		}
+	}
+}
+
+func TestStackDestination_RefusesFork(t *testing.T) {
+	branches, _ := gt.ParseLogShort("◯    b\n│ ◯  a\n◯─┘  main")
+	if _, refusal := stackDestination(branches, "main", "up"); refusal == "" {
+		t.Error("up from a branch with two children should be refused")
+	}
+}
+
+func TestStackStepKeys_RunGtNavigation(t *testing.T) {
+	for _, tt := range []struct {
+		key    rune
+		args   []string
+		cursor string
+	}{
+		{']', []string{"up", "--no-interactive"}, "top"},
+		{'[', []string{"down", "--no-interactive"}, "base"},
+		{'}', []string{"top", "--no-interactive"}, "top"},
+		{'{', []string{"bottom", "--no-interactive"}, "base"},
+	} {
+		mock, calls := recordingMock()
+		m := New(gt.New(mock), "")
+		m = sendWindowSize(m, 80, 24)
+		updated, _ := m.Update(logResultMsg{output: "│ ◯  top\n│ ◉  mid\n│ ◯  base\n◯─┘  main"})
+		m = updated.(Model)
+
+		*calls = nil
+		updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{tt.key}}))
+		m = updated.(Model)
+		if !m.running || m.cursorTarget != tt.cursor {
+			t.Fatalf("%c: running = %v, cursorTarget = %q", tt.key, m.running, m.cursorTarget)
+		}
+		runBatch(cmd)
+		if len(*calls) == 0 || !reflect.DeepEqual((*calls)[0], callRecord{name: "gt", args: tt.args}) {
+			t.Errorf("%c: calls = %v, want gt %v", tt.key, *calls, tt.args)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var orphanStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+
+// orphanLabel returns a styled flag for a branch whose recorded parent is
+// broken, or empty string if it isn't.
+func orphanLabel(b *gt.Branch) string {
+	if b.Orphan == "" {
+		return ""
+	}
+	return " " + orphanStyle.Render("⚠ orphaned")
+}
+
+// orphanLabelPlain returns an unstyled orphan flag for use in reverse-video labels.
+func orphanLabelPlain(b *gt.Branch) string {
+	if b.Orphan == "" {
+		return ""
+	}
+	return " ⚠ orphaned"
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// orphanModel has feature-top recorded on a deleted parent.
+// Entries: 0 = feature-top, 1 = feature-base, 2 = main.
+func orphanModel() (Model, *[]callRecord) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 120, 24)
+	updated, _ := m.Update(logResultMsg{
+		output:  "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main",
+		orphans: map[string]string{"feature-top": "parent old-base no longer exists"},
+	})
+	return updated.(Model), calls
+}
+
+func TestOrphan_FlaggedInTreeAndDetail(t *testing.T) {
+	m, _ := orphanModel()
+	lines := strings.Split(m.treeContent(), "\n")
+	if !strings.Contains(lines[0], "orphaned") || strings.Contains(lines[1], "orphaned") {
+		t.Errorf("only feature-top should be flagged:\n%s", m.treeContent())
+	}
+	if !containsString(m.detailView(), "old-base no longer") {
+		t.Errorf("detail panel should explain the orphan:\n%s", m.detailView())
+	}
+}
+
+func TestOrphan_TrackKeyRetracks(t *testing.T) {
+	m, calls := orphanModel()
+	m = sendKey(m, 'T')
+	if m.tracking != "feature-top" {
+		t.Fatalf("tracking = %q, want feature-top", m.tracking)
+	}
+
+	// Its own row can't be the parent.
+	updated, _ := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	if m.tracking == "" || !strings.Contains(m.statusBar.message, "on itself") {
+		t.Fatalf("tracking = %q, message = %q", m.tracking, m.statusBar.message)
+	}
+
+	m = sendSpecialKey(m, tea.KeyDown) // feature-base
+	*calls = nil
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	want := callRecord{name: "gt", args: []string{"track", "feature-top", "--parent", "feature-base", "--no-interactive"}}
+	if len(*calls) == 0 || !reflect.DeepEqual((*calls)[0], want) {
+		t.Errorf("calls = %v, want %v first", *calls, want)
+	}
+}
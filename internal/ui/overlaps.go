@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var overlapNameStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2"))
+
+// maxOverlapFiles is how many shared paths are listed per overlap before
+// the rest are summarized as a count.
+const maxOverlapFiles = 3
+
+// overlap records files two branches of the same stack both change.
+type overlap struct {
+	other string
+	files []string
+}
+
+// stackOverlaps is the overlap analysis of one stack: every branch stacked
+// on a single child of trunk.
+type stackOverlaps struct {
+	base     string   // the stack's bottom branch
+	branches []string // in stack order, bottom first
+	loaded   map[string]bool
+	overlaps map[string][]overlap
+}
+
+// findOverlaps compares the changed-file lists of every pair of branches
+// within each stack, using the change info already loaded for the tree.
+// Branches whose changes have not loaded yet are listed but not compared.
+func findOverlaps(roots []*gt.Branch) []stackOverlaps {
+	var stacks []stackOverlaps
+	for _, root := range roots {
+		for _, base := range root.Children {
+			var members []*gt.Branch
+			collectStack(base, &members)
+			slices.SortFunc(members, func(a, b *gt.Branch) int { return b.Order - a.Order })
+
+			s := stackOverlaps{base: base.Name, loaded: make(map[string]bool), overlaps: make(map[string][]overlap)}
+			for _, b := range members {
+				s.branches = append(s.branches, b.Name)
+				s.loaded[b.Name] = b.Changes.Loaded
+			}
+			for i, a := range members {
+				for _, b := range members[i+1:] {
+					if !a.Changes.Loaded || !b.Changes.Loaded {
+						continue
+					}
+					shared := sharedPaths(a.Changes.Paths, b.Changes.Paths)
+					if len(shared) == 0 {
+						continue
+					}
+					s.overlaps[a.Name] = append(s.overlaps[a.Name], overlap{other: b.Name, files: shared})
+					s.overlaps[b.Name] = append(s.overlaps[b.Name], overlap{other: a.Name, files: shared})
+				}
+			}
+			stacks = append(stacks, s)
+		}
+	}
+	return stacks
+}
+
+func collectStack(b *gt.Branch, out *[]*gt.Branch) {
+	*out = append(*out, b)
+	for _, child := range b.Children {
+		collectStack(child, out)
+	}
+}
+
+// sharedPaths returns the paths present in both lists, sorted.
+func sharedPaths(a, b []string) []string {
+	inA := make(map[string]bool, len(a))
+	for _, p := range a {
+		inA[p] = true
+	}
+	var shared []string
+	for _, p := range b {
+		if inA[p] {
+			shared = append(shared, p)
+		}
+	}
+	slices.Sort(shared)
+	return slices.Compact(shared)
+}
+
+// formatOverlapFiles lists up to maxOverlapFiles paths, then a count.
+func formatOverlapFiles(files []string) string {
+	if len(files) <= maxOverlapFiles {
+		return strings.Join(files, ", ")
+	}
+	return strings.Join(files[:maxOverlapFiles], ", ") + fmt.Sprintf(", +%d more", len(files)-maxOverlapFiles)
+}
+
+// renderOverlaps renders the overlap analysis: for each stack, each branch
+// with the other branches it shares changed files with.
+func renderOverlaps(stacks []stackOverlaps) string {
+	var sb strings.Builder
+	sb.WriteString(helpTitleStyle.Render("Overlaps"))
+	sb.WriteString("\n\n")
+	sb.WriteString(helpDescStyle.Render("Branches in the same stack that change the same files are likely to conflict on restack."))
+	sb.WriteString("\n")
+	if len(stacks) == 0 {
+		sb.WriteString("\n" + helpDescStyle.Render("No stacks."))
+		return sb.String()
+	}
+	for _, s := range stacks {
+		sb.WriteString("\n" + helpSectionStyle.Render("--- "+s.base+" ---") + "\n")
+		width := 0
+		for _, name := range s.branches {
+			width = max(width, ansi.StringWidth(name)+2)
+		}
+		for _, name := range s.branches {
+			sb.WriteString("  " + overlapNameStyle.Render(padToWidth(name, width)))
+			switch overlaps := s.overlaps[name]; {
+			case !s.loaded[name]:
+				sb.WriteString(helpDescStyle.Render("loading..."))
+			case len(overlaps) == 0:
+				sb.WriteString(helpDescStyle.Render("no overlaps"))
+			default:
+				for i, o := range overlaps {
+					if i > 0 {
+						sb.WriteString("\n  " + strings.Repeat(" ", width))
+					}
+					sb.WriteString(diffOverlapStyle.Render("overlaps with "+o.other) +
+						helpDescStyle.Render(" ("+formatOverlapFiles(o.files)+")"))
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// refreshOverlapsView re-renders the overlap analysis into the viewport.
+func (m *Model) refreshOverlapsView() {
+	m.viewport.SetContent(renderOverlaps(findOverlaps(m.branches)))
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func overlapTree(t *testing.T) []*gt.Branch {
+	t.Helper()
+	top := &gt.Branch{Name: "feature-top", Order: 0}
+	base := &gt.Branch{Name: "feature-base", Order: 1, Children: []*gt.Branch{top}}
+	side := &gt.Branch{Name: "side", Order: 2}
+	branches := []*gt.Branch{{Name: "main", Order: 3, Children: []*gt.Branch{base, side}}}
+	applyChanges(branches, map[string]gt.ChangeInfo{
+		"feature-base": {Loaded: true, Paths: []string{"api.go", "model.go", "util.go"}},
+		"feature-top":  {Loaded: true, Paths: []string{"model.go", "util.go", "view.go"}},
+		"side":         {Loaded: true, Paths: []string{"model.go"}},
+	})
+	return branches
+}
+
+func TestFindOverlaps_WithinStackOnly(t *testing.T) {
+	stacks := findOverlaps(overlapTree(t))
+	if len(stacks) != 2 {
+		t.Fatalf("got %d stacks, want 2", len(stacks))
+	}
+
+	var chain stackOverlaps
+	for _, s := range stacks {
+		if s.base == "feature-base" {
+			chain = s
+		}
+	}
+	if !reflect.DeepEqual(chain.branches, []string{"feature-base", "feature-top"}) {
+		t.Errorf("branches = %v, want bottom first", chain.branches)
+	}
+	want := []overlap{{other: "feature-top", files: []string{"model.go", "util.go"}}}
+	if !reflect.DeepEqual(chain.overlaps["feature-base"], want) {
+		t.Errorf("feature-base overlaps = %+v, want %+v", chain.overlaps["feature-base"], want)
+	}
+	// side touches model.go too, but is in a different stack.
+	for _, o := range chain.overlaps["feature-top"] {
+		if o.other == "side" {
+			t.Error("branches in different stacks should not be compared")
+		}
+	}
+}
+
+func TestFindOverlaps_SkipsUnloaded(t *testing.T) {
+	branches := overlapTree(t)
+	applyChanges(branches, map[string]gt.ChangeInfo{"feature-top": {}})
+	for _, s := range findOverlaps(branches) {
+		if len(s.overlaps) != 0 {
+			t.Errorf("stack %s: overlaps = %v, want none while changes load", s.base, s.overlaps)
+		}
+	}
+	if out := ansi.Strip(renderOverlaps(findOverlaps(branches))); !strings.Contains(out, "loading...") {
+		t.Error("unloaded branch should render as loading")
+	}
+}
+
+func TestFormatOverlapFiles(t *testing.T) {
+	if got := formatOverlapFiles([]string{"a", "b"}); got != "a, b" {
+		t.Errorf("got %q", got)
+	}
+	if got := formatOverlapFiles([]string{"a", "b", "c", "d", "e"}); got != "a, b, c, +2 more" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRenderOverlaps(t *testing.T) {
+	out := ansi.Strip(renderOverlaps(findOverlaps(overlapTree(t))))
+	for _, want := range []string{
+		"--- feature-base ---",
+		"feature-top   overlaps with feature-base (model.go, util.go)",
+		"--- side ---",
+		"side  no overlaps",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestOverlapsKey_OpensAndCloses(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m = sendKey(m, 'O')
+	if m.mode != modeOverlaps {
+		t.Fatalf("mode = %v, want modeOverlaps", m.mode)
+	}
+	if !strings.Contains(ansi.Strip(m.View()), "Overlaps") {
+		t.Error("view should show the overlaps title")
+	}
+
+	updated, _ := m.Update(changesResultMsg{changes: map[string]gt.ChangeInfo{
+		"feature-base": {Loaded: true, Paths: []string{"x.go"}},
+		"feature-top":  {Loaded: true, Paths: []string{"x.go"}},
+	}})
+	m = updated.(Model)
+	if !strings.Contains(ansi.Strip(m.viewport.View()), "overlaps with feature-top (x.go)") {
+		t.Error("view should refresh when changed files arrive")
+	}
+
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree {
+		t.Errorf("mode = %v, want modeTree after esc", m.mode)
+	}
+}
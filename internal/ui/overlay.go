@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	overlayTitleStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	overlayBorderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	overlayFooterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// Overlay sizing: boxes grow to fit their content between the minimum and
+// maximum width, but always leave overlayMargin cells of the screen showing
+// around them.
+const (
+	overlayMinWidth = 36
+	overlayMaxWidth = 90
+	overlayMargin   = 2
+)
+
+// overlayChrome is the width and height the border and padding add to a
+// box's content.
+const (
+	overlayChromeWidth  = 4
+	overlayChromeHeight = 2
+)
+
+// overlay is a modal box drawn centered over the screen: confirmations,
+// prompts, pickers and error details. While one is open it traps focus,
+// so keys it doesn't use never reach the view behind it.
+type overlay struct {
+	title  string
+	body   string // rendered content, cut to fit the screen
+	footer string // key hints, always shown
+	width  int    // content width wanted, 0 to fit the body
+}
+
+// active reports whether o is open, for overlays kept on the model.
+func (o overlay) active() bool {
+	return o.title != "" || o.body != ""
+}
+
+// overlayKey classifies a key for a dialog: enter submits it, esc cancels
+// it, and anything else is the dialog's own to handle or ignore.
+type overlayKey int
+
+const (
+	overlayOther overlayKey = iota
+	overlaySubmit
+	overlayCancel
+)
+
+func classifyOverlayKey(msg tea.KeyMsg) overlayKey {
+	switch msg.Type {
+	case tea.KeyEnter:
+		return overlaySubmit
+	case tea.KeyEscape:
+		return overlayCancel
+	}
+	return overlayOther
+}
+
+// contentWidth returns the width of the box's content on a screen width
+// cells wide.
+func (o overlay) contentWidth(width int) int {
+	want := o.width
+	if want == 0 {
+		want = max(lipgloss.Width(o.title), lipgloss.Width(o.body), lipgloss.Width(o.footer))
+	}
+	want = min(max(want, overlayMinWidth), overlayMaxWidth)
+	return max(min(want, width-2*overlayMargin-overlayChromeWidth), 1)
+}
+
+// render draws the box to fit a screen width by height cells. A body too
+// tall for it is cut, ending in "…".
+func (o overlay) render(width, height int) string {
+	inner := o.contentWidth(width)
+	wrap := lipgloss.NewStyle().Width(inner)
+
+	var top, bottom []string
+	if o.title != "" {
+		top = append(top, overlayTitleStyle.Render(truncateToWidth(o.title, inner)), "")
+	}
+	if o.footer != "" {
+		bottom = append(bottom, "", overlayFooterStyle.Render(wrap.Render(o.footer)))
+	}
+	body := strings.Split(wrap.Render(o.body), "\n")
+	room := height - 2*overlayMargin - overlayChromeHeight - len(top) - len(bottom)
+	if room < 1 {
+		room = 1
+	}
+	if len(body) > room {
+		body = append(body[:room-1], overlayFooterStyle.Render("…"))
+	}
+
+	lines := append(append(top, body...), bottom...)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(overlayBorderStyle.GetForeground()).
+		Padding(0, 1).
+		Width(inner + 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// placeOverlay draws box centered over background, a screen width by
+// height cells, keeping the background visible around it.
+func placeOverlay(background, box string, width, height int) string {
+	x := max((width-lipgloss.Width(box))/2, 0)
+	y := max((height-lipgloss.Height(box))/2, 0)
+	return placeBox(background, box, x, y, height)
+}
+
+// placeBox draws box over background with its top-left corner at column
+// x of row y, padding background to height rows first. Rows of box below
+// the last are cut.
+func placeBox(background, box string, x, y, height int) string {
+	lines := strings.Split(background, "\n")
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	boxLines := strings.Split(box, "\n")
+	boxWidth := lipgloss.Width(box)
+	for i, boxLine := range boxLines {
+		row := y + i
+		if row >= len(lines) {
+			break
+		}
+		line := lines[row]
+		left := ansi.Truncate(line, x, "")
+		left += strings.Repeat(" ", x-ansi.StringWidth(left))
+		right := ansi.TruncateLeft(line, x+boxWidth, "")
+		lines[row] = left + "\x1b[0m" + boxLine + right
+	}
+	return strings.Join(lines, "\n")
+}
+
+// withOverlay draws o over background, the main area of the screen.
+func (m Model) withOverlay(background string, o overlay) string {
+	height := m.viewport.Height
+	return placeOverlay(background, o.render(m.width, height), m.width, height)
+}
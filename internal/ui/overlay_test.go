@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestOverlay_ContentWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		o     overlay
+		width int
+		want  int
+	}{
+		{"short body gets the minimum", overlay{body: "hi"}, 120, overlayMinWidth},
+		{"fits the body", overlay{body: strings.Repeat("x", 50)}, 120, 50},
+		{"capped at the maximum", overlay{body: strings.Repeat("x", 200)}, 200, overlayMaxWidth},
+		{"leaves a margin on narrow screens", overlay{body: "hi"}, 30, 30 - 2*overlayMargin - overlayChromeWidth},
+		{"explicit width", overlay{body: "hi", width: 60}, 120, 60},
+	}
+	for _, tt := range tests {
+		if got := tt.o.contentWidth(tt.width); got != tt.want {
+			t.Errorf("%s: contentWidth = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOverlay_RenderCutsTallBody(t *testing.T) {
+	body := strings.Repeat("line\n", 30) + "last"
+	box := ansi.Strip(overlay{title: "Title", body: body, footer: "esc closes"}.render(80, 20))
+	lines := strings.Split(box, "\n")
+	if len(lines) != 20-2*overlayMargin {
+		t.Errorf("box is %d lines, want %d:\n%s", len(lines), 20-2*overlayMargin, box)
+	}
+	if strings.Contains(box, "last") || !strings.Contains(box, "…") || !strings.Contains(box, "esc closes") {
+		t.Errorf("tall body should be cut with … and keep the footer:\n%s", box)
+	}
+}
+
+func TestPlaceOverlay_KeepsBackgroundAround(t *testing.T) {
+	background := strings.Join([]string{"aaaaaaaaaa", "bbbbbbbbbb", "cccccccccc"}, "\n")
+	got := ansi.Strip(placeOverlay(background, "XX", 10, 3))
+	want := "aaaaaaaaaa\nbbbbXXbbbb\ncccccccccc"
+	if got != want {
+		t.Errorf("placeOverlay =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestErrorDetails_ShowsLastErrorAndTrapsKeys(t *testing.T) {
+	m := loadedModel("│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main")
+	m = sendKey(m, 'E')
+	if m.dialog.active() || m.statusBar.message != "No errors yet" {
+		t.Fatalf("E with no error: dialog = %+v, message = %q", m.dialog, m.statusBar.message)
+	}
+
+	m.statusBar.setStatus(severityError, "Error: push rejected by the remote")
+	m.statusBar.setStatus(severitySuccess, "Fetched")
+	m = sendKey(m, 'E')
+	if !m.dialog.active() || !strings.Contains(m.View(), "push rejected by the remote") {
+		t.Fatalf("E should show the last error even once replaced:\n%s", m.View())
+	}
+	cursor := m.cursor
+	m = sendKey(m, 'j')
+	if !m.dialog.active() || m.cursor != cursor {
+		t.Errorf("keys should not reach the tree behind the dialog, cursor = %d, want %d", m.cursor, cursor)
+	}
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.dialog.active() {
+		t.Errorf("esc should close the dialog")
+	}
+}
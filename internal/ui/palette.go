@@ -0,0 +1,31 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openPalette lists the actions that apply to the selected branch and the
+// repo's state by description, and runs the one picked as its key does.
+func (m *Model) openPalette() {
+	specs := make(map[string]*actionSpec)
+	var items []pickerItem
+	for i := range actionSpecs {
+		s := &actionSpecs[i]
+		if s.desc == "" || s.noPalette || s.execute == nil || !s.applies(*m) {
+			continue
+		}
+		specs[s.desc] = s
+		items = append(items, pickerItem{name: s.desc})
+	}
+	m.openPicker(picker{
+		title: "Run an action",
+		noun:  "actions",
+		items: items,
+		pick: func(m *Model, item pickerItem) []tea.Cmd {
+			return m.runSpec(specs[item.name])
+		},
+		preview: func(m Model, item pickerItem) string {
+			return "key " + specs[item.name].binding(&m.keys).Help().Key
+		},
+	})
+}
@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	pickerMatchStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("3"))
+	pickerCountStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	pickerPreviewStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// pickerHeaderHeight is the number of lines above the list in the overlay:
+// the title, a blank line, the query and the match count.
+const pickerHeaderHeight = 4
+
+// pickerItem is an entry listed in a picker.
+type pickerItem struct {
+	name    string
+	current bool // marked ◉ rather than ◯, e.g. the checked-out branch
+	checked bool // ticked in a checklist
+}
+
+// pickerMatch is an item matching the query, with the positions of the
+// matched runes in its name.
+type pickerMatch struct {
+	item      pickerItem
+	positions []int
+	score     int
+}
+
+// picker is a fuzzy-filtered list for choosing one of several items, shown
+// as an overlay in modePicker: typing filters, ↑/↓ move, enter calls pick
+// with the selected item and esc closes it. The finder, move targets and
+// other "pick one of N" choices are pickers. A picker with check set is a
+// checklist instead: tab ticks or unticks the selected item and enter
+// calls check with every ticked item.
+type picker struct {
+	title   string
+	noun    string // what the items are, plural, for the match count
+	input   textinput.Model
+	items   []pickerItem
+	matches []pickerMatch
+	cursor  int
+	ordered bool                                         // items are listed in a meaningful order, kept among equal matches
+	width   int                                          // widest row, so the overlay doesn't resize as it filters
+	pick    func(m *Model, item pickerItem) []tea.Cmd    // acts on the chosen item; the picker is already closed
+	preview func(m Model, item pickerItem) string        // describes the selected item under the list, nil for none
+	check   func(m *Model, items []pickerItem) []tea.Cmd // acts on the ticked items of a checklist; the picker is already closed
+}
+
+// fuzzyMatch reports whether the runes of query appear in order in name,
+// ignoring case. The score favors runes matched back to back and at the
+// start of a word, so "ab" ranks "auth-bug" above "tab".
+func fuzzyMatch(query, name string) (positions []int, score int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return nil, 0, true
+	}
+	n := []rune(strings.ToLower(name))
+	qi := 0
+	for i := 0; i < len(n) && qi < len(q); i++ {
+		if n[i] != q[qi] {
+			continue
+		}
+		score++
+		if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+			score += 3
+		}
+		if i == 0 || strings.ContainsRune("-_/.", n[i-1]) {
+			score += 2
+		}
+		positions = append(positions, i)
+		qi++
+	}
+	if qi < len(q) {
+		return nil, 0, false
+	}
+	return positions, score, true
+}
+
+// refilter matches the items against the query, best first, and moves the
+// cursor back to the top.
+func (f *picker) refilter() {
+	query := strings.TrimSpace(f.input.Value())
+	f.matches = f.matches[:0]
+	for _, item := range f.items {
+		if positions, score, ok := fuzzyMatch(query, item.name); ok {
+			f.matches = append(f.matches, pickerMatch{item: item, positions: positions, score: score})
+		}
+	}
+	sort.SliceStable(f.matches, func(i, j int) bool {
+		a, b := f.matches[i], f.matches[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		return !f.ordered && len(a.item.name) < len(b.item.name)
+	})
+	f.cursor = 0
+}
+
+// toggle ticks the item under the cursor, or unticks it if ticked.
+func (f *picker) toggle() {
+	match := f.selected()
+	if match == nil {
+		return
+	}
+	match.item.checked = !match.item.checked
+	for i := range f.items {
+		if f.items[i].name == match.item.name {
+			f.items[i].checked = match.item.checked
+		}
+	}
+}
+
+// checked returns the ticked items, in the order they are listed.
+func (f picker) checked() []pickerItem {
+	var items []pickerItem
+	for _, item := range f.items {
+		if item.checked {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// marker returns the mark shown before item: a checkbox in a checklist,
+// otherwise ◉ for the current item and ◯ for the rest.
+func (f picker) marker(item pickerItem) string {
+	switch {
+	case f.check != nil && item.checked:
+		return "[x] "
+	case f.check != nil:
+		return "[ ] "
+	case item.current:
+		return "◉ "
+	}
+	return "◯ "
+}
+
+// selected returns the match under the cursor, or nil if nothing matches.
+func (f picker) selected() *pickerMatch {
+	if f.cursor < len(f.matches) {
+		return &f.matches[f.cursor]
+	}
+	return nil
+}
+
+// openPicker shows p over the tree.
+func (m *Model) openPicker(p picker) {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "type to filter"
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+	p.input = input
+	p.width = lipgloss.Width(p.title)
+	for _, item := range p.items {
+		p.width = max(p.width, lipgloss.Width(p.marker(item)+item.name))
+	}
+	p.refilter()
+	m.picker = p
+	m.setMode(modePicker)
+	m.resizeViewport()
+}
+
+// closePicker returns to the tree, discarding the picker.
+func (m *Model) closePicker() {
+	m.picker = picker{}
+	m.setMode(modeTree)
+	m.resizeViewport()
+	m.viewport.SetContent(m.treeContent())
+}
+
+// updatePicker handles a key in the picker: arrows move, enter picks the
+// selected item, esc closes it and anything else edits the query. In a
+// checklist tab ticks the selected item and enter acts on the ticked ones.
+func (m *Model) updatePicker(msg tea.KeyMsg) []tea.Cmd {
+	switch classifyOverlayKey(msg) {
+	case overlayCancel:
+		m.closePicker()
+		return nil
+	case overlaySubmit:
+		if m.picker.check != nil {
+			check, items := m.picker.check, m.picker.checked()
+			m.closePicker()
+			return check(m, items)
+		}
+		match := m.picker.selected()
+		if match == nil {
+			m.statusBar.setStatus(severityWarning, "Nothing matches "+m.picker.input.Value())
+			return nil
+		}
+		pick := m.picker.pick
+		m.closePicker()
+		return pick(m, match.item)
+	}
+	switch msg.Type {
+	case tea.KeyTab:
+		if m.picker.check != nil {
+			m.picker.toggle()
+		}
+		return nil
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.picker.cursor > 0 {
+			m.picker.cursor--
+		}
+		return nil
+	case tea.KeyDown, tea.KeyCtrlN:
+		if m.picker.cursor < len(m.picker.matches)-1 {
+			m.picker.cursor++
+		}
+		return nil
+	}
+	var cmd tea.Cmd
+	m.picker.input, cmd = m.picker.input.Update(msg)
+	m.picker.refilter()
+	return []tea.Cmd{cmd}
+}
+
+// highlightMatch renders name with the runes at positions emphasized.
+func highlightMatch(name string, positions []int) string {
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+	var sb strings.Builder
+	for i, r := range []rune(name) {
+		if matched[i] {
+			sb.WriteString(pickerMatchStyle.Render(string(r)))
+		} else {
+			sb.WriteString(branchStyle.Render(string(r)))
+		}
+	}
+	return sb.String()
+}
+
+// pickerOverlay renders the picker over the tree, scrolled to keep the
+// cursor in view, with the selected item's preview under the list.
+func (m Model) pickerOverlay() overlay {
+	f := m.picker
+	var sb strings.Builder
+	sb.WriteString(promptLabelStyle.Render("/ ") + f.input.View() + "\n")
+	sb.WriteString(pickerCountStyle.Render(fmt.Sprintf("%d of %d %s", len(f.matches), len(f.items), f.noun)))
+
+	var preview string
+	if match := f.selected(); match != nil && f.preview != nil {
+		preview = f.preview(m, match.item)
+	}
+	rows := m.viewport.Height - 2*overlayMargin - overlayChromeHeight - pickerHeaderHeight
+	if preview != "" {
+		rows -= lipgloss.Height(preview) + 1
+	}
+	rows = max(rows, 1)
+	start := max(f.cursor-rows+1, 0)
+	style := cursorStyle(m.lowBandwidth)
+	for i := start; i < len(f.matches) && i < start+rows; i++ {
+		match := f.matches[i]
+		marker := f.marker(match.item)
+		sb.WriteString("\n")
+		if i == f.cursor {
+			sb.WriteString(style.Render(marker + match.item.name))
+		} else {
+			sb.WriteString(branchStyle.Render(marker) + highlightMatch(match.item.name, match.positions))
+		}
+	}
+	if preview != "" {
+		sb.WriteString("\n\n" + pickerPreviewStyle.Render(preview))
+	}
+	return overlay{title: f.title, body: sb.String(), width: f.width}
+}
+
+func (m Model) pickerLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"enter", "pick"},
+		{"↑/↓", "move"},
+		{"esc", "cancel"},
+	}
+	if m.picker.check != nil {
+		pairs = []struct{ key, desc string }{
+			{"tab", "toggle"},
+			{"enter", "apply"},
+			{"↑/↓", "move"},
+			{"esc", "cancel"},
+		}
+	}
+	return renderLegend(pairs, m.width)
+}
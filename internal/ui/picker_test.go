@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query, name string
+		ok          bool
+	}{
+		{"", "anything", true},
+		{"fb", "feature-base", true},
+		{"FB", "feature-base", true},
+		{"bf", "feature-base", false},
+		{"zz", "feature-base", false},
+	}
+	for _, tt := range tests {
+		if _, _, ok := fuzzyMatch(tt.query, tt.name); ok != tt.ok {
+			t.Errorf("fuzzyMatch(%q, %q) ok = %v, want %v", tt.query, tt.name, ok, tt.ok)
+		}
+	}
+	positions, _, _ := fuzzyMatch("ab", "auth-bug")
+	if len(positions) != 2 || positions[0] != 0 || positions[1] != 5 {
+		t.Errorf("positions = %v, want [0 5]", positions)
+	}
+	_, word, _ := fuzzyMatch("ab", "auth-bug")
+	_, inner, _ := fuzzyMatch("ab", "tab")
+	if word <= inner {
+		t.Errorf("word-start match scored %d, not above %d", word, inner)
+	}
+}
+
+func TestPicker_PicksSelectedAndPreviewsIt(t *testing.T) {
+	m := loadedModel("◉  main")
+	var picked string
+	m.openPicker(picker{
+		title: "Pick a color",
+		noun:  "colors",
+		items: []pickerItem{{name: "red"}, {name: "green"}, {name: "blue"}},
+		pick: func(m *Model, item pickerItem) []tea.Cmd {
+			picked = item.name
+			return nil
+		},
+		preview: func(m Model, item pickerItem) string { return "preview of " + item.name },
+	})
+	m = sendKey(m, 'r')
+	m = sendSpecialKey(m, tea.KeyDown)
+	view := m.View()
+	if !strings.Contains(view, "2 of 3 colors") || !strings.Contains(view, "preview of "+m.picker.selected().item.name) {
+		t.Errorf("view should show the count and the selected item's preview:\n%s", view)
+	}
+	want := m.picker.selected().item.name
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if picked != want || m.mode != modeTree || m.picker.pick != nil {
+		t.Errorf("picked = %q, mode = %d, want %q picked and the picker closed", picked, m.mode, want)
+	}
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pinsFile is where pinned branch names are persisted, relative to the git dir.
+const pinsFile = "grit/pins.json"
+
+var pinStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+
+// pinMarker prefixes pinned rows shown at the top of the tree.
+const pinMarker = "★ "
+
+// loadPins reads pinned branch names from gitDir. A missing or unreadable
+// file means no pins.
+func loadPins(gitDir string) []string {
+	if gitDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, pinsFile))
+	if err != nil {
+		return nil
+	}
+	var pins []string
+	if err := json.Unmarshal(data, &pins); err != nil {
+		return nil
+	}
+	return pins
+}
+
+// savePins writes pinned branch names to gitDir.
+func savePins(gitDir string, pins []string) error {
+	if gitDir == "" {
+		return nil
+	}
+	path := filepath.Join(gitDir, pinsFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// togglePin adds name to pins, or removes it if already pinned. It reports
+// whether name is pinned afterwards.
+func togglePin(pins []string, name string) ([]string, bool) {
+	if i := slices.Index(pins, name); i >= 0 {
+		return slices.Delete(slices.Clone(pins), i, i+1), false
+	}
+	return append(slices.Clone(pins), name), true
+}
+
+// withPins prepends a pinned copy of each pinned branch to the flattened
+// tree, in pin order. Pins for branches that no longer exist are skipped.
+// The branch also keeps its normal place in the tree.
+func withPins(entries []displayEntry, pins []string) []displayEntry {
+	if len(pins) == 0 {
+		return entries
+	}
+	var pinned []displayEntry
+	for _, name := range pins {
+		for _, e := range entries {
+			if e.branch.Name == name {
+				pinned = append(pinned, displayEntry{branch: e.branch, pinned: true})
+				break
+			}
+		}
+	}
+	return append(pinned, entries...)
+}
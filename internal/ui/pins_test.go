@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestPins_SaveLoadRoundTrip(t *testing.T) {
+	gitDir := t.TempDir()
+	if got := loadPins(gitDir); got != nil {
+		t.Errorf("loadPins() = %v, want nil with no file", got)
+	}
+	if err := savePins(gitDir, []string{"feature-a", "feature-b"}); err != nil {
+		t.Fatalf("savePins: %v", err)
+	}
+	if got := loadPins(gitDir); !reflect.DeepEqual(got, []string{"feature-a", "feature-b"}) {
+		t.Errorf("loadPins() = %v", got)
+	}
+}
+
+func TestTogglePin(t *testing.T) {
+	pins, pinned := togglePin(nil, "a")
+	if !pinned || !reflect.DeepEqual(pins, []string{"a"}) {
+		t.Fatalf("pin a: %v %v", pins, pinned)
+	}
+	pins, _ = togglePin(pins, "b")
+	original := pins
+	pins, pinned = togglePin(pins, "a")
+	if pinned || !reflect.DeepEqual(pins, []string{"b"}) {
+		t.Errorf("unpin a: %v %v", pins, pinned)
+	}
+	if !reflect.DeepEqual(original, []string{"a", "b"}) {
+		t.Errorf("togglePin modified its input: %v", original)
+	}
+}
+
+func TestWithPins(t *testing.T) {
+	entries := []displayEntry{
+		{branch: &gt.Branch{Name: "feature-top"}, depth: 1},
+		{branch: &gt.Branch{Name: "feature-base"}, depth: 1},
+		{branch: &gt.Branch{Name: "main"}},
+	}
+
+	got := withPins(entries, []string{"feature-base", "gone"})
+	if len(got) != 4 {
+		t.Fatalf("got %d entries, want 4", len(got))
+	}
+	if !got[0].pinned || got[0].branch.Name != "feature-base" || got[0].depth != 0 {
+		t.Errorf("first entry = %+v, want pinned feature-base", got[0])
+	}
+	if got[2].pinned || got[2].branch.Name != "feature-base" {
+		t.Error("pinned branch should keep its place in the tree")
+	}
+
+	if got := withPins(entries, nil); len(got) != 3 {
+		t.Errorf("no pins: got %d entries, want 3", len(got))
+	}
+}
+
+func TestRenderTree_PinnedRow(t *testing.T) {
+	b := &gt.Branch{Name: "feature-base"}
+	entries := []displayEntry{{branch: b, pinned: true}, {branch: b, depth: 1}}
+	lines := strings.Split(ansi.Strip(renderTree(entries, -1)), "\n")
+	if lines[0] != "★ ◯ feature-base" {
+		t.Errorf("pinned row = %q", lines[0])
+	}
+	if lines[1] != "│ ◯ feature-base" {
+		t.Errorf("tree row = %q", lines[1])
+	}
+}
+
+func TestPinKey_TogglesAndPersists(t *testing.T) {
+	gitDir := t.TempDir()
+	m := New(gt.New(simpleMock("", nil)), gitDir)
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	// Move to feature-base and pin it.
+	for m.selectedBranch().Name != "feature-base" {
+		m = sendKey(m, 'j')
+	}
+	m = sendKey(m, 'P')
+	if m.statusBar.message != "Pinned feature-base" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+	if !m.displayEntries[0].pinned || m.cursor != 0 {
+		t.Errorf("pinned entry should be first and selected, cursor=%d", m.cursor)
+	}
+	if got := loadPins(gitDir); !reflect.DeepEqual(got, []string{"feature-base"}) {
+		t.Errorf("persisted pins = %v", got)
+	}
+
+	// Pins survive a reload.
+	updated, _ = m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	if !m.displayEntries[0].pinned {
+		t.Error("pin should survive a reload")
+	}
+
+	m = sendKey(m, 'P')
+	if m.statusBar.message != "Unpinned feature-base" || m.displayEntries[0].pinned {
+		t.Errorf("unpin failed: %q", m.statusBar.message)
+	}
+}
+
+func TestPinKey_Trunk(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	for m.selectedBranch().Name != "main" {
+		m = sendKey(m, 'j')
+	}
+	m = sendKey(m, 'P')
+	if m.statusBar.message != "Cannot pin trunk branch" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
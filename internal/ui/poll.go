@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pollMsg fires when a PR poll is due. seq is the poll loop it belongs to,
+// so a poll scheduled before grit went idle doesn't run beside the loop
+// restarted on waking.
+type pollMsg struct {
+	seq int
+}
+
+// schedulePoll schedules the next PR poll, or returns nil when polling is
+// disabled.
+func (m Model) schedulePoll() tea.Cmd {
+	if m.pollInterval <= 0 {
+		return nil
+	}
+	seq := m.pollSeq
+	return tea.Tick(m.pollInterval, func(time.Time) tea.Msg { return pollMsg{seq: seq} })
+}
+
+// poll refreshes PR state and CI checks without reloading the tree; the
+// results update the branches in place. A refresh still queued or running
+// is left to finish instead. Polling stops while idle, and wake restarts it.
+func (m *Model) poll(msg pollMsg) []tea.Cmd {
+	if msg.seq != m.pollSeq || m.idle {
+		return nil
+	}
+	cmds := []tea.Cmd{m.schedulePoll()}
+	if m.loaded && !m.jobs.pending(prInfoJobLabel) {
+		cmds = append(cmds, m.loadPRInfo())
+	}
+	return cmds
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elliotb/grit/internal/config"
+)
+
+// prRefreshes counts the PR info refreshes submitted to m's jobs.
+func prRefreshes(m Model) int {
+	n := 0
+	for _, j := range m.jobs.jobs {
+		if j.label == prInfoJobLabel {
+			n++
+		}
+	}
+	return n
+}
+
+// finishJobs marks every job done, as if its results had arrived.
+func finishJobs(m Model) {
+	for _, j := range m.jobs.jobs {
+		j.state = jobDone
+	}
+}
+
+func TestPoll_Disabled(t *testing.T) {
+	m := idleModel(config.Config{})
+	if m.schedulePoll() != nil {
+		t.Error("no polls should be scheduled without an interval")
+	}
+}
+
+func TestPoll_RefreshesPRInfo(t *testing.T) {
+	m := idleModel(config.Config{PollInterval: 60})
+	finishJobs(m)
+	before := prRefreshes(m)
+
+	updated, cmd := m.Update(pollMsg{seq: m.pollSeq})
+	m = updated.(Model)
+	if prRefreshes(m) != before+1 {
+		t.Errorf("refreshes = %d, want one more than %d", prRefreshes(m), before)
+	}
+	if cmd == nil {
+		t.Error("the next poll should be scheduled")
+	}
+}
+
+func TestPoll_LeavesRunningRefresh(t *testing.T) {
+	m := idleModel(config.Config{PollInterval: 60})
+	if !m.jobs.pending(prInfoJobLabel) {
+		t.Fatal("loading the tree should have started a PR info refresh")
+	}
+	before := prRefreshes(m)
+
+	updated, cmd := m.Update(pollMsg{seq: m.pollSeq})
+	m = updated.(Model)
+	if prRefreshes(m) != before {
+		t.Error("a poll should not start a refresh beside one still running")
+	}
+	if cmd == nil {
+		t.Error("the next poll should still be scheduled")
+	}
+}
+
+func TestPoll_StopsWhileIdleAndRestartsOnWake(t *testing.T) {
+	m := idleModel(config.Config{PollInterval: 60, IdleTimeout: 5})
+	finishJobs(m)
+	m.lastActivity = time.Now().Add(-10 * time.Minute)
+	updated, _ := m.Update(idleCheckMsg{})
+	m = updated.(Model)
+
+	seq := m.pollSeq
+	updated, cmd := m.Update(pollMsg{seq: seq})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("polling should stop while idle")
+	}
+
+	m = sendKey(m, 'j')
+	if m.pollSeq == seq {
+		t.Fatal("waking should start a new poll loop")
+	}
+	before := prRefreshes(m)
+	updated, cmd = m.Update(pollMsg{seq: seq})
+	m = updated.(Model)
+	if cmd != nil || prRefreshes(m) != before {
+		t.Error("a poll from before going idle should be dropped")
+	}
+}
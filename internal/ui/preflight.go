@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// pendingSubmit is a submit waiting on pre-flight confirmation.
+type pendingSubmit struct {
+	action       string
+	desc         string // e.g. "Submit stack (feature-a)", shown on the checklist
+	successMsg   string
+	spinnerLabel string
+	targets      []*gt.Branch
+	submit       clientAction
+}
+
+// startSubmit first checks the remote copies of p's branches with open
+// PRs for commits the submit would force-push over, then continues with
+// preflightSubmit.
+func (m *Model) startSubmit(p pendingSubmit) []tea.Cmd {
+	m.actionTargets = branchNames(p.targets)
+	if pushed := pushedBranches(p.targets); len(pushed) > 0 {
+		m.running = true
+		spinnerCmd := m.statusBar.startSpinner("Checking remote branches...")
+		return []tea.Cmd{spinnerCmd, m.checkUpstream(p, pushed)}
+	}
+	return m.preflightSubmit(p)
+}
+
+// preflightSubmit runs the pre-flight checks for p if enabled, deferring
+// the submit until the user confirms; otherwise it submits immediately.
+func (m *Model) preflightSubmit(p pendingSubmit) []tea.Cmd {
+	if !m.preflight.Enabled {
+		return m.runSubmit(p)
+	}
+	m.running = true
+	m.pending = p
+	spinnerCmd := m.statusBar.startSpinner("Running pre-flight checks...")
+	return []tea.Cmd{spinnerCmd, m.runPreflight(m.preflight, p.targets)}
+}
+
+// runSubmit starts the submit action for p, once its commands are
+// confirmed if its confirmPolicy asks.
+func (m *Model) runSubmit(p pendingSubmit) []tea.Cmd {
+	return m.confirmAction(p.action, pendingAction{
+		desc:     p.spinnerLabel,
+		commands: previewCommands(p.submit),
+		run: func(m *Model) []tea.Cmd {
+			m.running = true
+			spinnerCmd := m.statusBar.startSpinner(p.spinnerLabel)
+			return []tea.Cmd{spinnerCmd, m.submitAction(p.desc, p.action, p.successMsg, p.targets, p.submit)}
+		},
+	})
+}
+
+// checkResult is one line of the pre-flight checklist.
+type checkResult struct {
+	name   string
+	ok     bool
+	detail string // why the check failed, or extra context
+}
+
+// preflightResultMsg carries the outcome of the pre-flight checks.
+type preflightResultMsg struct {
+	results []checkResult
+}
+
+// wipPattern matches commit subjects that shouldn't be submitted.
+var wipPattern = regexp.MustCompile(`(?i)^(fixup!|squash!|amend!|wip\b)`)
+
+var (
+	checkPassStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	checkFailStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+)
+
+// runPreflight runs the configured checks against the branches about to be
+// submitted.
+func (m Model) runPreflight(cfg config.Preflight, targets []*gt.Branch) tea.Cmd {
+	client := m.gtClient
+	parents := branchParents(m.branches)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		return preflightResultMsg{results: preflightChecks(ctx, client, cfg, targets, parents)}
+	}
+}
+
+// preflightChecks runs each check in order. A check that can't be evaluated
+// (e.g. git fails) is reported as failed with the error.
+func preflightChecks(ctx context.Context, client *gt.Client, cfg config.Preflight, targets []*gt.Branch, parents map[string]string) []checkResult {
+	var results []checkResult
+
+	clean := checkResult{name: "Working tree clean", ok: true}
+	if status, err := client.StatusPorcelain(ctx); err != nil {
+		clean.ok, clean.detail = false, err.Error()
+	} else if status = strings.TrimSpace(status); status != "" {
+		clean.ok = false
+		clean.detail = pluralize(strings.Count(status, "\n")+1, "uncommitted change")
+	}
+	results = append(results, clean)
+
+	restacked := checkResult{name: "Branches restacked", ok: true}
+	var needRestack []string
+	for _, b := range targets {
+		if strings.Contains(b.Annotation, "restack") {
+			needRestack = append(needRestack, b.Name)
+		}
+	}
+	if len(needRestack) > 0 {
+		restacked.ok = false
+		restacked.detail = "needs restack: " + strings.Join(needRestack, ", ")
+	}
+	results = append(results, restacked)
+
+	var pattern *regexp.Regexp
+	valid := checkResult{name: "Commit messages valid", ok: true}
+	if cfg.CommitPattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(cfg.CommitPattern); err != nil {
+			valid.ok, valid.detail = false, "bad commitPattern: "+err.Error()
+		}
+	}
+	noWIP := checkResult{name: "No WIP/fixup commits", ok: true}
+	for _, b := range targets {
+		subjects, err := client.CommitSubjects(ctx, parents[b.Name], b.Name)
+		if err != nil {
+			noWIP.ok, noWIP.detail = false, b.Name+": "+err.Error()
+			continue
+		}
+		for _, subj := range subjects {
+			if noWIP.ok && wipPattern.MatchString(subj) {
+				noWIP.ok, noWIP.detail = false, b.Name+": "+subj
+			}
+			if valid.ok && pattern != nil && !pattern.MatchString(subj) {
+				valid.ok, valid.detail = false, b.Name+": "+subj
+			}
+		}
+	}
+	if cfg.CommitPattern != "" {
+		results = append(results, valid)
+	}
+	results = append(results, noWIP)
+
+	if cfg.TestCommand != "" {
+		tests := checkResult{name: "Tests pass (" + cfg.TestCommand + ")", ok: true}
+		if _, err := client.RunShell(ctx, cfg.TestCommand); err != nil {
+			tests.ok, tests.detail = false, lastLine(err.Error())
+		}
+		results = append(results, tests)
+	}
+
+	return results
+}
+
+// pluralize formats a count with a noun, adding "s" when n != 1.
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// lastLine returns the last non-empty line of s, which for failing test
+// commands is usually the most useful summary.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// preflightPassed reports whether every check passed.
+func preflightPassed(results []checkResult) bool {
+	for _, r := range results {
+		if !r.ok {
+			return false
+		}
+	}
+	return true
+}
+
+// renderPreflight renders the checklist shown before confirming a submit.
+func renderPreflight(label string, results []checkResult) string {
+	var sb strings.Builder
+	sb.WriteString(helpTitleStyle.Render("Pre-flight: " + label))
+	sb.WriteString("\n\n")
+	for _, r := range results {
+		if r.ok {
+			sb.WriteString(checkPassStyle.Render("✓ " + r.name))
+		} else {
+			sb.WriteString(checkFailStyle.Render("✗ " + r.name))
+			if r.detail != "" {
+				sb.WriteString(helpSectionStyle.Render(" — " + r.detail))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	if preflightPassed(results) {
+		sb.WriteString(helpDescStyle.Render("All checks passed. Press enter to submit, esc to cancel."))
+	} else {
+		sb.WriteString(helpDescStyle.Render("Some checks failed. Press enter to submit anyway, esc to cancel."))
+	}
+	return sb.String()
+}
@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// preflightMock answers the git commands used by the pre-flight checks.
+func preflightMock(status string, subjects map[string]string, testErr error) *mockExecutor {
+	return &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		switch {
+		case name == "sh":
+			return "", testErr
+		case name == "git" && args[0] == "status":
+			return status, nil
+		case name == "git" && args[0] == "log":
+			return subjects[args[2]], nil
+		}
+		return "", nil
+	}}
+}
+
+func checkByName(t *testing.T, results []checkResult, name string) checkResult {
+	t.Helper()
+	for _, r := range results {
+		if r.name == name {
+			return r
+		}
+	}
+	t.Fatalf("no check named %q in %+v", name, results)
+	return checkResult{}
+}
+
+func TestPreflightChecks_AllPass(t *testing.T) {
+	client := gt.New(preflightMock("", map[string]string{"main..a": "feat: add a\n"}, nil))
+	targets := []*gt.Branch{{Name: "a"}}
+	cfg := config.Preflight{Enabled: true, CommitPattern: "^feat: ", TestCommand: "make test"}
+
+	results := preflightChecks(context.Background(), client, cfg, targets, map[string]string{"a": "main"})
+
+	if !preflightPassed(results) {
+		t.Errorf("expected all checks to pass, got %+v", results)
+	}
+	if len(results) != 5 {
+		t.Errorf("got %d checks, want 5 (clean, restack, commit pattern, wip, tests)", len(results))
+	}
+}
+
+func TestPreflightChecks_Failures(t *testing.T) {
+	client := gt.New(preflightMock(" M a.go\n?? b.go\n", map[string]string{
+		"main..a": "feat: ok\n",
+		"a..b":    "fixup! feat: ok\nrandom message\n",
+	}, errors.New("FAIL\nexit status 1")))
+	targets := []*gt.Branch{{Name: "a"}, {Name: "b", Annotation: "needs restack"}}
+	cfg := config.Preflight{Enabled: true, CommitPattern: "^feat: ", TestCommand: "make test"}
+
+	results := preflightChecks(context.Background(), client, cfg, targets, map[string]string{"a": "main", "b": "a"})
+
+	if r := checkByName(t, results, "Working tree clean"); r.ok || r.detail != "2 uncommitted changes" {
+		t.Errorf("clean = %+v", r)
+	}
+	if r := checkByName(t, results, "Branches restacked"); r.ok || r.detail != "needs restack: b" {
+		t.Errorf("restacked = %+v", r)
+	}
+	if r := checkByName(t, results, "No WIP/fixup commits"); r.ok || r.detail != "b: fixup! feat: ok" {
+		t.Errorf("wip = %+v", r)
+	}
+	if r := checkByName(t, results, "Commit messages valid"); r.ok || r.detail != "b: fixup! feat: ok" {
+		t.Errorf("valid = %+v", r)
+	}
+	if r := checkByName(t, results, "Tests pass (make test)"); r.ok || r.detail != "exit status 1" {
+		t.Errorf("tests = %+v", r)
+	}
+}
+
+func TestPreflightChecks_OptionalChecksOmitted(t *testing.T) {
+	client := gt.New(preflightMock("", nil, nil))
+	results := preflightChecks(context.Background(), client, config.Preflight{Enabled: true}, nil, nil)
+	if len(results) != 3 {
+		t.Errorf("got %d checks, want 3 without pattern or test command", len(results))
+	}
+}
+
+func TestRenderPreflight(t *testing.T) {
+	got := ansi.Strip(renderPreflight("Submit stack (a)", []checkResult{
+		{name: "Working tree clean", ok: true},
+		{name: "No WIP/fixup commits", detail: "a: WIP"},
+	}))
+	for _, want := range []string{"Pre-flight: Submit stack (a)", "✓ Working tree clean", "✗ No WIP/fixup commits — a: WIP", "submit anyway"} {
+		if !containsString(got, want) {
+			t.Errorf("should contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func preflightModel(mock *mockExecutor) Model {
+	m := NewWithConfig(gt.New(mock), "", config.Config{Preflight: config.Preflight{Enabled: true}})
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	return updated.(Model)
+}
+
+func TestPreflight_SubmitShowsChecklistThenConfirms(t *testing.T) {
+	mock, calls := recordingMock()
+	m := preflightModel(mock)
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'s'}}))
+	m = updated.(Model)
+	if m.statusBar.spinnerLabel != "Running pre-flight checks..." {
+		t.Errorf("spinnerLabel = %q", m.statusBar.spinnerLabel)
+	}
+	runBatch(cmd)
+	for _, c := range *calls {
+		if c.name == "gt" {
+			t.Fatalf("submit should not run before confirmation, got %v", c.args)
+		}
+	}
+
+	updated, _ = m.Update(preflightResultMsg{results: []checkResult{{name: "Working tree clean", ok: true}}})
+	m = updated.(Model)
+	if m.mode != modePreflight {
+		t.Fatal("expected pre-flight mode")
+	}
+	if !containsString(m.View(), "Pre-flight: Submit stack (feature-top)") {
+		t.Errorf("view should show checklist, got:\n%s", m.View())
+	}
+
+	*calls = nil
+	updated, cmd = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	if m.mode != modeTree || !m.running {
+		t.Fatalf("enter should return to the tree and start submitting (mode=%v running=%v)", m.mode, m.running)
+	}
+	runBatch(cmd)
+	if len(*calls) == 0 || (*calls)[0].args[0] != "stack" {
+		t.Errorf("calls = %v, want gt stack submit", *calls)
+	}
+}
+
+func TestPreflight_EscCancels(t *testing.T) {
+	mock, calls := recordingMock()
+	m := preflightModel(mock)
+	m = sendKey(m, 's')
+	updated, _ := m.Update(preflightResultMsg{results: []checkResult{{name: "x", ok: false}}})
+	m = updated.(Model)
+
+	*calls = nil
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEscape}))
+	m = updated.(Model)
+	runBatch(cmd)
+
+	if m.mode != modeTree {
+		t.Error("esc should return to the tree")
+	}
+	if m.running || len(*calls) != 0 {
+		t.Errorf("cancel should not submit (running=%v, calls=%v)", m.running, *calls)
+	}
+	if m.statusBar.message != "Submit cancelled" {
+		t.Errorf("status = %q", m.statusBar.message)
+	}
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/config"
+)
+
+// themeRoles are the colors a theme can override, named by role, with the
+// ANSI color each role uses by default.
+var themeRoles = []struct {
+	name  string
+	color lipgloss.Color
+}{
+	{"error", "1"},
+	{"success", "2"},
+	{"warning", "3"},
+	{"highlight", "5"},
+	{"accent", "6"},
+	{"text", "7"},
+	{"muted", "8"},
+}
+
+// themedStyles are recolored by a theme according to the role of their
+// default foreground color.
+var themedStyles = []*lipgloss.Style{
+	&confirmCommandStyle, &confirmWarningStyle,
+	&detailTitleStyle, &detailLabelStyle, &detailValueStyle, &detailBorderStyle,
+	&diffHeaderStyle, &diffFileStyle, &diffBorderStyle, &diffPanelHeaderStyle, &diffPanelFocusedStyle,
+	&unsubmittedStyle,
+	&emptyTitleStyle, &emptyTextStyle,
+	&helpTitleStyle, &helpKeyStyle, &helpDescStyle, &helpSectionStyle,
+	&jobRunningStyle, &jobQueuedStyle, &jobDoneStyle, &jobFailedStyle, &jobCancelledStyle,
+	&legendKeyStyle, &legendDescStyle,
+	&markStyle, &diffOverlapStyle,
+	&movingStyle,
+	&overlapNameStyle,
+	&pinStyle, &untrackedStyle, &orphanStyle, &groupHeaderStyle,
+	&checkPassStyle, &checkFailStyle,
+	&promptLabelStyle, &promptErrorStyle,
+	&formLabelStyle, &formFocusStyle, &formChoiceStyle,
+	&bannerStyle, &filterBannerStyle,
+	&commitTitleStyle, &commitStagedStyle,
+	&pickerMatchStyle, &pickerCountStyle, &pickerPreviewStyle,
+	&overlayTitleStyle, &overlayBorderStyle, &overlayFooterStyle,
+	&statusWorkingStyle, &statusErrorStyle, &statusWarningStyle, &statusSuccessStyle, &statusInfoStyle,
+	&currentBranchStyle, &branchStyle, &connectorStyle, &annotationStyle,
+	&prOpenStyle, &prDraftStyle, &prMergedStyle, &prClosedStyle, &prQueuedStyle, &ciPassingStyle, &ciFailingStyle, &ciPendingStyle, &conflictBadgeStyle, &threadsBadgeStyle, &labelStyle,
+	&changesStyle, &outOfScopeStyle,
+	&testPassedStyle, &testFailedStyle, &testRunningStyle,
+	&tutorialStyle,
+}
+
+// themeDefaults records each themed style's default color, so applying a
+// theme always starts from the defaults.
+var themeDefaults = defaultStyleColors()
+
+func defaultStyleColors() map[*lipgloss.Style]lipgloss.Color {
+	colors := make(map[*lipgloss.Style]lipgloss.Color, len(themedStyles))
+	for _, s := range themedStyles {
+		if c, ok := s.GetForeground().(lipgloss.Color); ok {
+			colors[s] = c
+		}
+	}
+	return colors
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether c is an ANSI color number or a hex color.
+func validColor(c string) bool {
+	if n, err := strconv.Atoi(c); err == nil {
+		return n >= 0 && n <= 255
+	}
+	return hexColor.MatchString(c)
+}
+
+// checkTheme reports unknown roles and malformed colors in theme.
+func checkTheme(theme map[string]string) error {
+	known := make(map[string]bool, len(themeRoles))
+	for _, r := range themeRoles {
+		known[r.name] = true
+	}
+	roles := make([]string, 0, len(theme))
+	for role := range theme {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		if !known[role] {
+			return fmt.Errorf("unknown theme role %q", role)
+		}
+		if !validColor(theme[role]) {
+			return fmt.Errorf("theme %s: invalid color %q", role, theme[role])
+		}
+	}
+	return nil
+}
+
+// applyTheme recolors the themed styles, mapping each role's default color
+// to the theme's color for that role. Roles the theme doesn't set keep
+// their default.
+func applyTheme(theme map[string]string) error {
+	if err := checkTheme(theme); err != nil {
+		return err
+	}
+	remap := make(map[lipgloss.Color]lipgloss.Color, len(themeRoles))
+	for _, r := range themeRoles {
+		remap[r.color] = r.color
+		if c, ok := theme[r.name]; ok {
+			remap[r.color] = lipgloss.Color(c)
+		}
+	}
+	for s, def := range themeDefaults {
+		if c, ok := remap[def]; ok {
+			*s = s.Foreground(c)
+		}
+	}
+	return nil
+}
+
+// ApplyProfile checks cfg's keys and templates and applies its theme. The
+// theme is global, like the lipgloss color profile, so call this once
+// before creating the model.
+func ApplyProfile(cfg config.Config) error {
+	keys := defaultKeyMap()
+	if err := keys.rebind(cfg.Keys); err != nil {
+		return err
+	}
+	if err := checkTemplates(cfg.Templates, keys); err != nil {
+		return err
+	}
+	return applyTheme(cfg.Theme)
+}
+
+// ExportProfile returns the complete keymap and theme in effect for cfg,
+// for sharing as a profile file.
+func ExportProfile(cfg config.Config) (config.Profile, error) {
+	keys := defaultKeyMap()
+	if err := keys.rebind(cfg.Keys); err != nil {
+		return config.Profile{}, err
+	}
+	if err := checkTheme(cfg.Theme); err != nil {
+		return config.Profile{}, err
+	}
+	theme := make(map[string]string, len(themeRoles))
+	for _, r := range themeRoles {
+		theme[r.name] = string(r.color)
+		if c, ok := cfg.Theme[r.name]; ok {
+			theme[r.name] = c
+		}
+	}
+	return config.Profile{Keys: keys.export(), Theme: theme}, nil
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestRebind(t *testing.T) {
+	k := defaultKeyMap()
+	if err := k.rebind(map[string][]string{"stackSubmit": {"Z", "ctrl+s"}, "up": {"up", "ctrl+k"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := k.StackSubmit.Help().Key; got != "Z/ctrl+s" {
+		t.Errorf("help key = %q, want Z/ctrl+s", got)
+	}
+	if got := k.StackSubmit.Help().Desc; got != "submit stack" {
+		t.Errorf("help desc = %q, should be kept", got)
+	}
+	if got := k.Up.Help().Key; got != "↑/ctrl+k" {
+		t.Errorf("up help key = %q, want ↑/ctrl+k", got)
+	}
+	if !containsString(ansi.Strip(renderHelp(k, nil)), "Z/ctrl+s") {
+		t.Error("help should show the new keys")
+	}
+}
+
+func TestRebind_Errors(t *testing.T) {
+	k := defaultKeyMap()
+	if err := k.rebind(map[string][]string{"launch": {"L"}}); err == nil {
+		t.Error("unknown action should be an error")
+	}
+	if err := k.rebind(map[string][]string{"restack": {}}); err == nil {
+		t.Error("empty key list should be an error")
+	}
+}
+
+func TestRebind_Conflicts(t *testing.T) {
+	k := defaultKeyMap()
+	err := k.rebind(map[string][]string{"sync": {"r"}})
+	if err == nil || !strings.Contains(err.Error(), `"r"`) {
+		t.Errorf("err = %v, want sync clashing with r's default action", err)
+	}
+	k = defaultKeyMap()
+	if err := k.rebind(map[string][]string{"openFile": {"ctrl+n"}, "openPR": {"ctrl+n"}}); err != nil {
+		t.Errorf("actions sharing a key by default may share another: %v", err)
+	}
+}
+
+func TestRebind_ModelReportsConflict(t *testing.T) {
+	m := NewWithConfig(gt.New(simpleMock("", nil)), "", config.Config{Keys: map[string][]string{"sync": {"r"}}})
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n◯─┘  main"})
+	m = updated.(Model)
+
+	if items := m.toasts.items; len(items) != 1 || items[0].severity != severityError || !strings.Contains(items[0].message, `"r"`) {
+		t.Errorf("toasts = %v, want the key conflict reported", items)
+	}
+	if got := m.keys.Sync.Keys(); len(got) != 1 || got[0] == "r" {
+		t.Errorf("sync keys = %v, want the defaults kept", got)
+	}
+}
+
+func TestRebind_ModelUsesKeys(t *testing.T) {
+	mock, calls := recordingMock()
+	m := NewWithConfig(gt.New(mock), "", config.Config{Keys: map[string][]string{"fetch": {"Z"}}})
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n◯─┘  main"})
+	m = updated.(Model)
+
+	m = sendKey(m, 'f')
+	if m.running {
+		t.Error("f should no longer fetch")
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'Z'}}))
+	m = updated.(Model)
+	if !m.running {
+		t.Fatal("Z should fetch")
+	}
+	runBatch(cmd)
+	if len(*calls) != 1 || (*calls)[0].args[0] != "repo" {
+		t.Errorf("calls = %v", *calls)
+	}
+}
+
+func TestExportProfile(t *testing.T) {
+	p, err := ExportProfile(config.Config{
+		Keys:  map[string][]string{"sync": {"ctrl+n"}},
+		Theme: map[string]string{"accent": "#5f87ff"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Keys["sync"]; len(got) != 1 || got[0] != "ctrl+n" {
+		t.Errorf("sync keys = %v", got)
+	}
+	if got := p.Keys["quit"]; len(got) != 2 {
+		t.Errorf("defaults should be exported, quit = %v", got)
+	}
+	if p.Theme["accent"] != "#5f87ff" || p.Theme["error"] != "1" {
+		t.Errorf("theme = %v", p.Theme)
+	}
+
+	if _, err := ExportProfile(config.Config{Theme: map[string]string{"background": "0"}}); err == nil {
+		t.Error("unknown theme role should be an error")
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	defer applyTheme(nil)
+
+	if err := applyTheme(map[string]string{"error": "#ff0000", "muted": "240"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := statusErrorStyle.GetForeground(); got != lipgloss.Color("#ff0000") {
+		t.Errorf("error color = %v", got)
+	}
+	if got := testFailedStyle.GetForeground(); got != lipgloss.Color("#ff0000") {
+		t.Errorf("error color = %v", got)
+	}
+	if got := connectorStyle.GetForeground(); got != lipgloss.Color("240") {
+		t.Errorf("muted color = %v", got)
+	}
+	if got := currentBranchStyle.GetForeground(); got != lipgloss.Color("2") {
+		t.Errorf("unset role should keep its default, got %v", got)
+	}
+
+	// Applying again starts from the defaults.
+	if err := applyTheme(map[string]string{"success": "10"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := statusErrorStyle.GetForeground(); got != lipgloss.Color("1") {
+		t.Errorf("error color should be reset, got %v", got)
+	}
+}
+
+func TestApplyTheme_Invalid(t *testing.T) {
+	defer applyTheme(nil)
+	for _, theme := range []map[string]string{
+		{"accent": "blue"},
+		{"accent": "256"},
+		{"accent": "#12"},
+		{"background": "1"},
+	} {
+		if err := applyTheme(theme); err == nil {
+			t.Errorf("applyTheme(%v) should fail", theme)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// promptKind identifies what a submitted prompt value is used for.
+type promptKind int
+
+const (
+	promptNone      promptKind = iota
+	promptCreate               // new branch stacked on prompt.base
+	promptInsert               // new branch between prompt.base and prompt.child
+	promptRename               // new name for prompt.base
+	promptFilter               // branch name filter text
+	promptReviewers            // reviewers to request on prompt.base's PR
+	promptComment              // comment to post on prompt.base's PR
+)
+
+// promptPlaceholders are shown in an empty prompt of each kind.
+var promptPlaceholders = map[promptKind]string{
+	promptCreate:    "branch name",
+	promptInsert:    "branch name",
+	promptRename:    "new branch name",
+	promptFilter:    "part of a branch name, empty clears",
+	promptReviewers: "user, org/team",
+	promptComment:   "e.g. rebased, PTAL",
+}
+
+// promptHistoryMax is how many submitted values each kind of prompt
+// remembers for recall.
+const promptHistoryMax = 50
+
+var (
+	promptLabelStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	promptErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// prompt is a single-line text input shown in an overlay over the tree.
+// While active it captures all key input except ctrl+c. Up and down recall
+// the values submitted to earlier prompts of the same kind.
+type prompt struct {
+	kind     promptKind
+	label    string
+	input    textinput.Model
+	base     string // promptCreate, promptInsert: branch to stack on; promptRename: branch to rename; promptReviewers, promptComment: branch whose PR gets them
+	child    string // promptInsert: branch moved onto the new one
+	prefix   string // promptCreate: prefilled name prefix
+	number   int    // promptReviewers, promptComment: PR number
+	validate func(value string) string
+	complete func(value string) []string // whole values that tab completes value to, nil for none
+	err      string                      // why the value can't be submitted, "" if it can
+	history  []string                    // earlier values, oldest first
+	recalled int                         // index into history being shown, len(history) for the draft
+	draft    string                      // value typed before recalling history
+}
+
+func newPrompt(kind promptKind, label, value string) prompt {
+	ti := textinput.New()
+	ti.Prompt = ""
+	ti.Placeholder = promptPlaceholders[kind]
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	ti.SetValue(value)
+	ti.CursorEnd()
+	ti.Focus()
+	return prompt{kind: kind, label: label, input: ti}
+}
+
+// showPrompt opens p with the values submitted to earlier prompts of its
+// kind to recall.
+func (m *Model) showPrompt(p prompt) {
+	p.history = m.promptHistory[p.kind]
+	p.recalled = len(p.history)
+	m.prompt = p
+}
+
+// rememberPrompt adds the value submitted to p to its kind's history,
+// moving it to the end if it was already there.
+func (m *Model) rememberPrompt(p prompt) {
+	value := p.value()
+	if value == "" {
+		return
+	}
+	if m.promptHistory == nil {
+		m.promptHistory = make(map[promptKind][]string)
+	}
+	history := slices.DeleteFunc(slices.Clone(m.promptHistory[p.kind]), func(v string) bool { return v == value })
+	history = append(history, value)
+	if len(history) > promptHistoryMax {
+		history = history[len(history)-promptHistoryMax:]
+	}
+	m.promptHistory[p.kind] = history
+}
+
+func (p prompt) active() bool {
+	return p.kind != promptNone
+}
+
+func (p prompt) value() string {
+	return strings.TrimSpace(p.input.Value())
+}
+
+// check validates the current value, returning false if it can't be
+// submitted.
+func (p *prompt) check() bool {
+	p.err = ""
+	if p.validate != nil {
+		p.err = p.validate(p.value())
+	}
+	return p.err == ""
+}
+
+// update feeds a key to the input. It reports whether the user submitted
+// (enter) a valid value or cancelled (esc) the prompt. An invalid value
+// keeps the prompt open with the reason shown.
+func (p *prompt) update(msg tea.KeyMsg) (submitted, cancelled bool, cmd tea.Cmd) {
+	switch classifyOverlayKey(msg) {
+	case overlaySubmit:
+		return p.check(), false, nil
+	case overlayCancel:
+		return false, true, nil
+	}
+	switch msg.Type {
+	case tea.KeyUp:
+		if p.recalled > 0 {
+			if p.recalled == len(p.history) {
+				p.draft = p.input.Value()
+			}
+			p.recalled--
+			p.recall(p.history[p.recalled])
+		}
+		return false, false, nil
+	case tea.KeyDown:
+		if p.recalled < len(p.history) {
+			p.recalled++
+			if p.recalled == len(p.history) {
+				p.recall(p.draft)
+			} else {
+				p.recall(p.history[p.recalled])
+			}
+		}
+		return false, false, nil
+	}
+	p.input, cmd = p.input.Update(msg)
+	if p.complete != nil {
+		p.input.SetSuggestions(p.complete(p.input.Value()))
+	}
+	// Complain while typing only about values that are there, not about
+	// the empty input the prompt opens with.
+	if p.value() != "" || p.err != "" {
+		p.check()
+	}
+	return false, false, cmd
+}
+
+// recall replaces the input with value.
+func (p *prompt) recall(value string) {
+	p.input.SetValue(value)
+	p.input.CursorEnd()
+	p.check()
+	if value == "" {
+		p.err = ""
+	}
+}
+
+// overlay renders the prompt as a dialog titled with its label, with the
+// reason the value can't be submitted under the input.
+func (p prompt) overlay() overlay {
+	body := p.input.View()
+	if p.err != "" {
+		body += "\n" + promptErrorStyle.Render(p.err)
+	}
+	footer := "Press enter to confirm, esc to cancel."
+	if p.complete != nil {
+		footer += " tab completes."
+	}
+	if len(p.history) > 0 {
+		footer += " ↑/↓ recall earlier entries."
+	}
+	return overlay{title: p.label, body: body, footer: footer}
+}
+
+// branchNameError returns why git would refuse name as a branch name, or
+// "" if it wouldn't. It follows git check-ref-format.
+func branchNameError(name string) string {
+	switch {
+	case name == "":
+		return "Branch name cannot be empty"
+	case strings.ContainsAny(name, " ~^:?*[\\\t"):
+		return "Branch name cannot contain spaces or any of ~ ^ : ? * [ \\"
+	case strings.Contains(name, ".."), strings.Contains(name, "@{"), strings.Contains(name, "//"):
+		return "Branch name cannot contain .., @{ or //"
+	case strings.HasPrefix(name, "-"), strings.HasPrefix(name, "/"), strings.HasPrefix(name, "."):
+		return "Branch name cannot start with -, / or ."
+	case strings.HasSuffix(name, "/"), strings.HasSuffix(name, "."), strings.HasSuffix(name, ".lock"):
+		return "Branch name cannot end with /, . or .lock"
+	case name == "@" || strings.Contains(name, "/."):
+		return "Branch name is not a valid git ref"
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return "Branch name cannot contain control characters"
+		}
+	}
+	return ""
+}
+
+// newBranchNameError is branchNameError for a branch about to be created,
+// which also must not exist yet.
+func (m Model) newBranchNameError(name string) string {
+	if err := branchNameError(name); err != "" {
+		return err
+	}
+	if gt.FindBranch(m.branches, name) != nil || slices.Contains(m.untracked, name) {
+		return "A branch named " + name + " already exists"
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func typeText(m Model, text string) Model {
+	for _, r := range text {
+		m = sendKey(m, r)
+	}
+	return m
+}
+
+func TestBranchNameError(t *testing.T) {
+	valid := []string{"feature", "eb/auth-fix", "v1.2", "a_b"}
+	for _, name := range valid {
+		if err := branchNameError(name); err != "" {
+			t.Errorf("branchNameError(%q) = %q, want valid", name, err)
+		}
+	}
+	invalid := []string{"", "has space", "a..b", "a~1", "what?", "x@{y", "-dash", "trailing/", "dot.", "name.lock", "a//b", "a/.hidden", "@"}
+	for _, name := range invalid {
+		if branchNameError(name) == "" {
+			t.Errorf("branchNameError(%q) = valid, want an error", name)
+		}
+	}
+}
+
+func TestPrompt_InvalidNameKeepsPromptOpen(t *testing.T) {
+	m := loadedModel("◉  main")
+	m = sendKey(m, 'c')
+	m = typeText(m, "bad..name")
+	if m.prompt.err == "" {
+		t.Fatal("expected the error to show while typing")
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if !m.prompt.active() || m.running {
+		t.Error("invalid name should keep the prompt open and not start an action")
+	}
+	if !strings.Contains(m.View(), m.prompt.err) {
+		t.Error("expected the error in the prompt overlay")
+	}
+}
+
+func TestPrompt_ExistingBranchRefused(t *testing.T) {
+	m := loadedModel("│ ◯  feature\n◉─┘  main")
+	m = sendKey(m, 'c')
+	m = typeText(m, "feature")
+	if m.prompt.err != "A branch named feature already exists" {
+		t.Errorf("err = %q, want the branch reported as existing", m.prompt.err)
+	}
+}
+
+func TestPrompt_RenameAllowsSameName(t *testing.T) {
+	m := loadedModel("│ ◉  feature\n◯─┘  main")
+	m = sendKey(m, 'R')
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.prompt.active() || m.running {
+		t.Error("submitting the unchanged name should close the prompt without renaming")
+	}
+}
+
+func TestPrompt_HistoryRecall(t *testing.T) {
+	m := loadedModel("◉  main")
+	for _, text := range []string{"auth", "billing", "auth"} {
+		m = sendSpecialKey(m, tea.KeyCtrlF)
+		m = sendSpecialKey(m, tea.KeyCtrlU)
+		m = typeText(m, text)
+		m = sendSpecialKey(m, tea.KeyEnter)
+	}
+	if got := m.promptHistory[promptFilter]; len(got) != 2 || got[0] != "billing" || got[1] != "auth" {
+		t.Fatalf("history = %v, want [billing auth]", got)
+	}
+
+	m = sendSpecialKey(m, tea.KeyCtrlF)
+	m = sendSpecialKey(m, tea.KeyCtrlU)
+	m = typeText(m, "dra")
+	m = sendSpecialKey(m, tea.KeyUp)
+	if got := m.prompt.input.Value(); got != "auth" {
+		t.Errorf("up = %q, want the latest entry", got)
+	}
+	m = sendSpecialKey(m, tea.KeyUp)
+	m = sendSpecialKey(m, tea.KeyUp)
+	if got := m.prompt.input.Value(); got != "billing" {
+		t.Errorf("up past the oldest = %q, want it kept", got)
+	}
+	m = sendSpecialKey(m, tea.KeyDown)
+	m = sendSpecialKey(m, tea.KeyDown)
+	if got := m.prompt.input.Value(); got != "dra" {
+		t.Errorf("down past the newest = %q, want the draft back", got)
+	}
+}
+
+func TestPrompt_HistoryIsPerKind(t *testing.T) {
+	m := loadedModel("◉  main")
+	m = sendSpecialKey(m, tea.KeyCtrlF)
+	m = typeText(m, "auth")
+	m = sendSpecialKey(m, tea.KeyEnter)
+
+	m = sendKey(m, 'c')
+	m = sendSpecialKey(m, tea.KeyUp)
+	if got := m.prompt.input.Value(); got != "" {
+		t.Errorf("create prompt recalled %q from the filter history", got)
+	}
+}
+
+func TestPrompt_Placeholder(t *testing.T) {
+	m := loadedModel("◉  main")
+	m = sendKey(m, 'c')
+	if !strings.Contains(m.View(), "branch name") {
+		t.Error("expected the placeholder in an empty prompt")
+	}
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// prRetryDelays are the waits before each extra PR info refresh after a
+// submit, for PRs GitHub hadn't reported yet when the first refresh ran.
+var prRetryDelays = []time.Duration{3 * time.Second, 8 * time.Second, 20 * time.Second}
+
+// prRetryMsg fires when a post-submit PR info retry is due.
+type prRetryMsg struct {
+	attempt  int      // index into prRetryDelays
+	branches []string // submitted branches
+}
+
+// schedulePRRetry schedules retry attempt for branches, or returns nil
+// once the retries are used up.
+func schedulePRRetry(attempt int, branches []string) tea.Cmd {
+	if attempt >= len(prRetryDelays) || len(branches) == 0 {
+		return nil
+	}
+	return tea.Tick(prRetryDelays[attempt], func(time.Time) tea.Msg {
+		return prRetryMsg{attempt: attempt, branches: branches}
+	})
+}
+
+// missingPRs returns the branches with no PR number in infos.
+func missingPRs(infos map[string]gt.PRInfo, branches []string) []string {
+	var missing []string
+	for _, name := range branches {
+		if infos[name].Number == 0 {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// retryPRInfo refreshes PR info again if any submitted branch still has no
+// PR number, and schedules the next retry for those.
+func (m *Model) retryPRInfo(msg prRetryMsg) []tea.Cmd {
+	missing := missingPRs(m.prInfos, msg.branches)
+	if len(missing) == 0 {
+		return nil
+	}
+	m.prInfoAt = time.Time{}
+	return []tea.Cmd{m.loadPRInfo(), schedulePRRetry(msg.attempt+1, missing)}
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestSchedulePRRetry_StopsAfterLastAttempt(t *testing.T) {
+	if cmd := schedulePRRetry(len(prRetryDelays), []string{"a"}); cmd != nil {
+		t.Error("no retry should be scheduled after the last attempt")
+	}
+	if cmd := schedulePRRetry(0, nil); cmd != nil {
+		t.Error("no retry should be scheduled without branches")
+	}
+}
+
+func TestSubmitResult_SchedulesPRRetry(t *testing.T) {
+	saved := prRetryDelays
+	prRetryDelays = []time.Duration{time.Millisecond}
+	defer func() { prRetryDelays = saved }()
+
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.actionTargets = []string{"feature-base", "feature-top"}
+	_, cmd := m.Update(actionResultMsg{action: "submit", message: "Submitted"})
+
+	var retry *prRetryMsg
+	for _, msg := range batchMsgs(cmd) {
+		if r, ok := msg.(prRetryMsg); ok {
+			retry = &r
+		}
+	}
+	if retry == nil || !reflect.DeepEqual(retry.branches, []string{"feature-base", "feature-top"}) {
+		t.Errorf("retry = %+v, want one for both submitted branches", retry)
+	}
+}
+
+func TestRetryPRInfo(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.prInfos = map[string]gt.PRInfo{"feature-base": {Number: 7, State: "OPEN"}}
+	m.prInfoAt = time.Now()
+
+	cmds := m.retryPRInfo(prRetryMsg{branches: []string{"feature-base", "feature-top"}})
+	if len(cmds) == 0 || !m.prInfoAt.IsZero() {
+		t.Errorf("feature-top has no PR yet: cmds = %d, prInfoAt = %v", len(cmds), m.prInfoAt)
+	}
+
+	m.prInfos["feature-top"] = gt.PRInfo{Number: 8, State: "OPEN"}
+	m.prInfoAt = time.Now()
+	if cmds := m.retryPRInfo(prRetryMsg{branches: []string{"feature-base", "feature-top"}}); cmds != nil {
+		t.Errorf("every PR is known, cmds = %v", cmds)
+	}
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// repoState captures HEAD and in-progress operation state, gathered
+// alongside each tree load.
+type repoState struct {
+	head         gt.HeadState
+	rebasing     bool
+	rebaseBranch string // branch being rebased, "" if unknown
+}
+
+var bannerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true)
+
+// detectRebase reports whether a rebase is in progress in gitDir and, if so,
+// which branch is being rebased. git keeps its state in rebase-merge/ (merge
+// backend) or rebase-apply/ (apply backend), each with a head-name file.
+func detectRebase(gitDir string) (bool, string) {
+	if gitDir == "" {
+		return false, ""
+	}
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		path := filepath.Join(gitDir, dir)
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue
+		}
+		headName, err := os.ReadFile(filepath.Join(path, "head-name"))
+		if err != nil {
+			return true, ""
+		}
+		return true, strings.TrimPrefix(strings.TrimSpace(string(headName)), "refs/heads/")
+	}
+	return false, ""
+}
+
+// hasBanner reports whether the state is unusual enough to show a banner.
+func (s repoState) hasBanner() bool {
+	return s.head.Detached || s.rebasing
+}
+
+// renderRepoBanner renders the detached HEAD / rebase banner shown above the
+// tree, including the keys for the actions that get the user out of it.
+// Returns "" when HEAD is on a branch and no rebase is running.
+func renderRepoBanner(s repoState, width int) string {
+	if !s.hasBanner() {
+		return ""
+	}
+
+	var lines []string
+	if s.rebasing {
+		line := "Rebase in progress"
+		if s.rebaseBranch != "" {
+			line += " (" + s.rebaseBranch + ")"
+		}
+		lines = append(lines, bannerStyle.Render("⚠ "+line)+"  "+
+			legendKeyStyle.Render("C")+" "+legendDescStyle.Render("continue rebase")+"  "+
+			legendKeyStyle.Render("A")+" "+legendDescStyle.Render("abort"))
+	}
+	if s.head.Detached {
+		line := "Detached HEAD at " + s.head.SHA
+		if s.head.Nearest != "" {
+			line += " (in " + s.head.Nearest + ")"
+		}
+		text := bannerStyle.Render("⚠ " + line)
+		// During a rebase HEAD is detached by design; checking out a branch
+		// would abandon the rebase, so only offer continue.
+		if s.head.Nearest != "" && !s.rebasing {
+			text += "  " + legendKeyStyle.Render("n") + " " + legendDescStyle.Render("checkout "+s.head.Nearest)
+		}
+		lines = append(lines, text)
+	}
+
+	style := lipgloss.NewStyle().Width(width).Padding(0, 1)
+	return style.Render(strings.Join(lines, "\n"))
+}
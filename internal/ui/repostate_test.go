@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestDetectRebase_None(t *testing.T) {
+	dir := t.TempDir()
+	if rebasing, _ := detectRebase(dir); rebasing {
+		t.Error("expected no rebase in empty git dir")
+	}
+}
+
+func TestDetectRebase_EmptyGitDir(t *testing.T) {
+	if rebasing, _ := detectRebase(""); rebasing {
+		t.Error("expected no rebase for empty gitDir")
+	}
+}
+
+func TestDetectRebase_MergeBackend(t *testing.T) {
+	dir := t.TempDir()
+	rebaseDir := filepath.Join(dir, "rebase-merge")
+	if err := os.Mkdir(rebaseDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rebaseDir, "head-name"), []byte("refs/heads/feature-a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rebasing, branch := detectRebase(dir)
+	if !rebasing {
+		t.Fatal("expected rebase in progress")
+	}
+	if branch != "feature-a" {
+		t.Errorf("branch = %q, want %q", branch, "feature-a")
+	}
+}
+
+func TestDetectRebase_ApplyBackendWithoutHeadName(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "rebase-apply"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	rebasing, branch := detectRebase(dir)
+	if !rebasing {
+		t.Fatal("expected rebase in progress")
+	}
+	if branch != "" {
+		t.Errorf("branch = %q, want empty", branch)
+	}
+}
+
+func TestRenderRepoBanner_Normal(t *testing.T) {
+	if got := renderRepoBanner(repoState{head: gt.HeadState{Branch: "main"}}, 80); got != "" {
+		t.Errorf("expected no banner, got %q", got)
+	}
+}
+
+func TestRenderRepoBanner_Detached(t *testing.T) {
+	s := repoState{head: gt.HeadState{Detached: true, SHA: "abc1234", Nearest: "feature-a"}}
+	got := ansi.Strip(renderRepoBanner(s, 100))
+	for _, want := range []string{"Detached HEAD at abc1234", "in feature-a", "checkout feature-a"} {
+		if !containsString(got, want) {
+			t.Errorf("banner should contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderRepoBanner_Rebasing(t *testing.T) {
+	s := repoState{
+		head:         gt.HeadState{Detached: true, SHA: "abc1234", Nearest: "feature-a"},
+		rebasing:     true,
+		rebaseBranch: "feature-b",
+	}
+	got := ansi.Strip(renderRepoBanner(s, 100))
+	if !containsString(got, "Rebase in progress (feature-b)") {
+		t.Errorf("banner should mention rebase, got:\n%s", got)
+	}
+	if !containsString(got, "continue rebase") {
+		t.Errorf("banner should offer continue, got:\n%s", got)
+	}
+	if containsString(got, "checkout feature-a") {
+		t.Errorf("banner should not offer checkout mid-rebase, got:\n%s", got)
+	}
+}
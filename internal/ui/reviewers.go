@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// reviewersFile is where recently requested reviewers are persisted,
+// relative to the git dir.
+const reviewersFile = "grit/reviewers.json"
+
+// reviewersMax is how many recent reviewers are kept for completion.
+const reviewersMax = 30
+
+// loadReviewers reads the recently requested reviewers from gitDir, oldest
+// first. A missing or unreadable file means none.
+func loadReviewers(gitDir string) []string {
+	if gitDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, reviewersFile))
+	if err != nil {
+		return nil
+	}
+	var reviewers []string
+	if err := json.Unmarshal(data, &reviewers); err != nil {
+		return nil
+	}
+	return reviewers
+}
+
+// saveReviewers writes the recently requested reviewers to gitDir.
+func saveReviewers(gitDir string, reviewers []string) error {
+	if gitDir == "" {
+		return nil
+	}
+	path := filepath.Join(gitDir, reviewersFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(reviewers, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// rememberReviewers moves requested to the end of the recent reviewers,
+// dropping the oldest past reviewersMax, and saves them.
+func (m *Model) rememberReviewers(requested []string) {
+	if len(requested) == 0 {
+		return
+	}
+	recent := slices.DeleteFunc(slices.Clone(m.reviewers), func(r string) bool { return slices.Contains(requested, r) })
+	recent = append(recent, requested...)
+	if len(recent) > reviewersMax {
+		recent = recent[len(recent)-reviewersMax:]
+	}
+	m.reviewers = recent
+	_ = saveReviewers(m.gitDir, m.reviewers)
+}
+
+// reviewerCompletions returns the completions of the last reviewer in
+// value from recent, most recent first, each as the whole value with
+// that reviewer filled in. Reviewers already listed aren't offered again.
+func reviewerCompletions(value string, recent []string) []string {
+	i := strings.LastIndexAny(value, ", ") + 1
+	head, last := value[:i], value[i:]
+	if last == "" {
+		return nil
+	}
+	listed := splitReviewers(head)
+	var completions []string
+	for _, r := range slices.Backward(recent) {
+		if strings.HasPrefix(strings.ToLower(r), strings.ToLower(last)) && !slices.Contains(listed, r) {
+			completions = append(completions, head+r)
+		}
+	}
+	return completions
+}
+
+// startRequestReview asks whom to request review from on the selected
+// branch's open PR, completing names from recently requested reviewers.
+func (m *Model) startRequestReview() {
+	branch := m.selectedBranch()
+	switch {
+	case branch == nil:
+		return
+	case !prOpen(branch.PR):
+		m.statusBar.setStatus(severityWarning, "Cannot request review: "+branch.Name+" has no open PR")
+		return
+	}
+	p := newPrompt(promptReviewers, fmt.Sprintf("Request review on #%d (%s)", branch.PR.Number, branch.Name), "")
+	p.base = branch.Name
+	p.number = branch.PR.Number
+	p.validate = func(value string) string {
+		if len(splitReviewers(value)) == 0 {
+			return "Enter at least one reviewer"
+		}
+		return ""
+	}
+	recent := m.reviewers
+	p.complete = func(value string) []string { return reviewerCompletions(value, recent) }
+	p.input.ShowSuggestions = true
+	m.showPrompt(p)
+}
+
+// requestReview runs `gh pr edit --add-reviewer` on name's PR.
+func (m *Model) requestReview(name string, number int, reviewers []string) []tea.Cmd {
+	m.rememberReviewers(reviewers)
+	m.actionTargets = []string{name}
+	who := strings.Join(reviewers, ", ")
+	return m.startAction("request-review", fmt.Sprintf("Requested review on #%d from %s", number, who), fmt.Sprintf("Requesting review on #%d...", number), "", func(ctx context.Context, client *gt.Client) error {
+		return client.RequestReviewers(ctx, name, reviewers)
+	})
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestReviewerCompletions(t *testing.T) {
+	recent := []string{"alex", "org/api", "alice"}
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{"al", []string{"alice", "alex"}},
+		{"AL", []string{"alice", "alex"}},
+		{"alice, al", []string{"alice, alex"}},
+		{"bob org/", []string{"bob org/api"}},
+		{"alice, ", nil},
+		{"zed", nil},
+	}
+	for _, tt := range tests {
+		if got := reviewerCompletions(tt.value, recent); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("reviewerCompletions(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRememberReviewers_MostRecentLast(t *testing.T) {
+	m := Model{reviewers: []string{"alice", "bob"}}
+	m.rememberReviewers([]string{"alice", "carol"})
+	if want := []string{"bob", "alice", "carol"}; !slices.Equal(m.reviewers, want) {
+		t.Errorf("reviewers = %v, want %v", m.reviewers, want)
+	}
+}
+
+func TestRequestReviewKey_CompletesAndRequests(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature\n◯─┘  main"})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{"feature": {Number: 7, State: "OPEN"}}})
+	m = updated.(Model)
+	m.reviewers = []string{"alice"}
+	m.cursor = 0
+
+	m = sendKey(m, 'V')
+	if !m.prompt.active() {
+		t.Fatal("V should ask for reviewers")
+	}
+	if view := m.View(); !strings.Contains(view, "Request review on #7 (feature)") || !strings.Contains(view, "tab completes") {
+		t.Errorf("prompt should name the PR and offer completion:\n%s", view)
+	}
+	m = typeText(m, "bob, al")
+	m = sendSpecialKey(m, tea.KeyTab)
+	if got := m.prompt.input.Value(); got != "bob, alice" {
+		t.Fatalf("value = %q, want alice completed", got)
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if len(*calls) != 1 || (*calls)[0].name != "gh" || !slices.Equal((*calls)[0].args, []string{"pr", "edit", "feature", "--add-reviewer", "bob,alice"}) {
+		t.Fatalf("calls = %v", *calls)
+	}
+	if want := []string{"bob", "alice"}; !slices.Equal(m.reviewers, want) {
+		t.Errorf("reviewers = %v, want %v", m.reviewers, want)
+	}
+}
+
+func TestRequestReviewKey_NeedsOpenPR(t *testing.T) {
+	m := loadedModel("│ ◉  feature\n◯─┘  main")
+	m.cursor = 0
+	m = sendKey(m, 'V')
+	if m.prompt.active() || m.statusBar.message != "Cannot request review: feature has no open PR" {
+		t.Errorf("prompt active = %v, message = %q", m.prompt.active(), m.statusBar.message)
+	}
+}
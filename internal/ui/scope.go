@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// changesResultMsg carries changed-file counts for all non-trunk branches.
+type changesResultMsg struct {
+	changes map[string]gt.ChangeInfo
+}
+
+// normalizeScope cleans a path scope to a repo-relative directory with no
+// leading "./" or trailing slash. Returns "" for the repo root.
+func normalizeScope(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	p = path.Clean(filepath.ToSlash(p))
+	p = strings.TrimPrefix(p, "./")
+	p = strings.Trim(p, "/")
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// inScope reports whether a repo-relative file path lies inside scope.
+// An empty scope matches everything.
+func inScope(file, scope string) bool {
+	if scope == "" {
+		return true
+	}
+	return file == scope || strings.HasPrefix(file, scope+"/")
+}
+
+// countChanges builds a ChangeInfo from a branch's changed files.
+func countChanges(files []string, scope string) gt.ChangeInfo {
+	info := gt.ChangeInfo{Loaded: true, Files: len(files)}
+	for _, f := range files {
+		if inScope(f, scope) {
+			info.InScope++
+		}
+	}
+	return info
+}
+
+// branchParents returns every non-root branch mapped to its parent's name:
+// the Parent gt recorded, as diffs use, or the branch below it in the tree.
+func branchParents(branches []*gt.Branch) map[string]string {
+	parents := make(map[string]string)
+	var walk func(b *gt.Branch)
+	walk = func(b *gt.Branch) {
+		for _, child := range b.Children {
+			parents[child.Name] = b.Name
+			if child.Parent != "" {
+				parents[child.Name] = child.Parent
+			}
+			walk(child)
+		}
+	}
+	for _, root := range branches {
+		walk(root)
+	}
+	return parents
+}
+
+// loadChanges fetches the changed-file list of every non-trunk branch
+// against its parent, counts the files inside the path scope and resolves
+// their code owners, skipping ignored files. It runs as a background job.
+func (m Model) loadChanges() tea.Cmd {
+	job := m.changesJob()
+	if job == nil {
+		return nil
+	}
+	return m.jobs.submit("Count changed files", true, job)
+}
+
+// changesJob returns the job body for loadChanges, or nil if there are no
+// non-trunk branches.
+func (m Model) changesJob() jobFunc {
+	parents := branchParents(m.branches)
+	if len(parents) == 0 {
+		return nil
+	}
+
+	client := m.gtClient
+	scope := m.scope
+	ignore := m.ignore
+	owners := m.codeowners
+	return func(jobCtx context.Context) (tea.Msg, error) {
+		changes := make(map[string]gt.ChangeInfo)
+		for name, parent := range parents {
+			ctx, cancel := context.WithTimeout(jobCtx, 5*time.Second)
+			files, err := client.DiffNameOnly(ctx, parent, name)
+			cancel()
+			if jobCtx.Err() != nil {
+				return nil, jobCtx.Err()
+			}
+			if err != nil {
+				continue
+			}
+			files = ignore.filterIgnored(files)
+			info := countChanges(files, scope)
+			info.Owners = owners.ownersFor(files)
+			info.Paths = files
+			changes[name] = info
+		}
+		return changesResultMsg{changes: changes}, nil
+	}
+}
+
+// applyChanges walks the branch tree and sets change info from the map.
+func applyChanges(branches []*gt.Branch, changes map[string]gt.ChangeInfo) {
+	var walk func(b *gt.Branch)
+	walk = func(b *gt.Branch) {
+		if info, ok := changes[b.Name]; ok {
+			b.Changes = info
+		}
+		for _, child := range b.Children {
+			walk(child)
+		}
+	}
+	for _, root := range branches {
+		walk(root)
+	}
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestNormalizeScope(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{".", ""},
+		{"./", ""},
+		{"services/api", "services/api"},
+		{"./services/api/", "services/api"},
+		{"services//api", "services/api"},
+		{" services/api ", "services/api"},
+	}
+	for _, tt := range tests {
+		if got := normalizeScope(tt.input); got != tt.want {
+			t.Errorf("normalizeScope(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestInScope(t *testing.T) {
+	tests := []struct {
+		file, scope string
+		want        bool
+	}{
+		{"anything.go", "", true},
+		{"services/api/main.go", "services/api", true},
+		{"services/api", "services/api", true},
+		{"services/api-gateway/main.go", "services/api", false},
+		{"web/main.go", "services/api", false},
+	}
+	for _, tt := range tests {
+		if got := inScope(tt.file, tt.scope); got != tt.want {
+			t.Errorf("inScope(%q, %q) = %v, want %v", tt.file, tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestCountChanges(t *testing.T) {
+	files := []string{"services/api/a.go", "services/api/b.go", "web/c.ts"}
+
+	got := countChanges(files, "services/api")
+	want := gt.ChangeInfo{Loaded: true, Files: 3, InScope: 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	got = countChanges(files, "")
+	if got.InScope != 3 {
+		t.Errorf("unscoped InScope = %d, want 3", got.InScope)
+	}
+}
+
+func TestChangesLabelPlain(t *testing.T) {
+	tests := []struct {
+		info gt.ChangeInfo
+		want string
+	}{
+		{gt.ChangeInfo{}, ""},
+		{gt.ChangeInfo{Loaded: true, Files: 1, InScope: 1}, " 1 file"},
+		{gt.ChangeInfo{Loaded: true, Files: 5, InScope: 2}, " 2 files"},
+		{gt.ChangeInfo{Loaded: true, Files: 4, InScope: 0}, " outside scope"},
+	}
+	for _, tt := range tests {
+		if got := changesLabelPlain(tt.info); got != tt.want {
+			t.Errorf("changesLabelPlain(%+v) = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
+
+func TestLoadChanges(t *testing.T) {
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if args[0] == "diff" && args[2] == "feature-base...feature-top" {
+			return "web/x.ts\n", nil
+		}
+		return "services/api/a.go\nweb/y.ts\n", nil
+	}}
+	m := NewWithConfig(gt.New(mock), "", config.Config{Path: "services/api/"})
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	msg := runJob(m.changesJob()).(changesResultMsg)
+	if got := msg.changes["feature-base"]; got.InScope != 1 || got.Files != 2 {
+		t.Errorf("feature-base = %+v, want 1 of 2 in scope", got)
+	}
+	if got := msg.changes["feature-top"]; got.InScope != 0 || got.Files != 1 {
+		t.Errorf("feature-top = %+v, want 0 of 1 in scope", got)
+	}
+
+	updated, _ = m.Update(msg)
+	m = updated.(Model)
+	view := ansi.Strip(m.View())
+	if !containsString(view, "feature-base 1/2 1 file") {
+		t.Errorf("view should badge feature-base, got:\n%s", view)
+	}
+	if !containsString(view, "feature-top 2/2 outside scope") {
+		t.Errorf("view should flag feature-top, got:\n%s", view)
+	}
+	if !containsString(view, "scope: services/api") {
+		t.Errorf("status bar should show scope, got:\n%s", view)
+	}
+}
+
+func TestLoadChanges_RecordedParent(t *testing.T) {
+	var ranges []string
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if args[0] == "diff" {
+			ranges = append(ranges, args[2])
+		}
+		return "", nil
+	}}
+	m := NewWithConfig(gt.New(mock), "", config.Config{})
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	gt.FindBranch(m.branches, "feature-top").Parent = "main"
+
+	runJob(m.changesJob())
+	if !slices.Contains(ranges, "main...feature-top") {
+		t.Errorf("diffed %v, want feature-top against its recorded parent main", ranges)
+	}
+}
+
+func TestLoadDiffData_Scoped(t *testing.T) {
+	var gotArgs []string
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		gotArgs = args
+		return " services/api/a.go | 2 +-\n", nil
+	}}
+	m := NewWithConfig(gt.New(mock), "", config.Config{Path: "services/api"})
+
+	msg := m.loadDiffData("main", "feature-a")().(diffDataMsg)
+	want := []string{"diff", "--stat", "main...feature-a", "--", "services/api"}
+	if len(gotArgs) != len(want) {
+		t.Fatalf("args = %v, want %v", gotArgs, want)
+	}
+	for i := range want {
+		if gotArgs[i] != want[i] {
+			t.Errorf("arg[%d] = %q, want %q", i, gotArgs[i], want[i])
+		}
+	}
+	if len(msg.files) != 1 {
+		t.Errorf("files = %v, want 1 entry", msg.files)
+	}
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// shareResultMsg is sent when a stack's share text has been copied.
+type shareResultMsg struct {
+	prs int // PRs listed; 0 means nothing was copied
+}
+
+// sharedPRs returns the branches in stack with a PR still up for review,
+// in review order (trunk side first).
+func sharedPRs(stack []*gt.Branch) []*gt.Branch {
+	var shared []*gt.Branch
+	for _, b := range stack {
+		state := strings.ToUpper(b.PR.State)
+		if b.PR.Number != 0 && state != "MERGED" && state != "CLOSED" {
+			shared = append(shared, b)
+		}
+	}
+	return shared
+}
+
+// shareText formats the PRs of stack for a review request: a Graphite link
+// to the top PR, whose page shows the whole stack, then each PR in review
+// order. Without a GitHub repo there are no links, just the PR numbers.
+func shareText(stack []*gt.Branch, repo string) string {
+	shared := sharedPRs(stack)
+	var sb strings.Builder
+	if repo != "" && len(shared) > 0 {
+		sb.WriteString("Stack: " + gt.GraphitePRURL(repo, shared[len(shared)-1].PR.Number) + "\n")
+	}
+	for i, b := range shared {
+		fmt.Fprintf(&sb, "%d/%d #%d %s", i+1, len(shared), b.PR.Number, b.Name)
+		if repo != "" {
+			fmt.Fprintf(&sb, " — https://github.com/%s/pull/%d", repo, b.PR.Number)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// shareStack copies the share text for the stack containing name. The
+// remote lookup runs in the command.
+func (m Model) shareStack(name string) tea.Cmd {
+	stack := stackBranches(m.branches, name, true)
+	client, copyText := m.gtClient, m.copyText
+	return func() tea.Msg {
+		if len(sharedPRs(stack)) == 0 {
+			return shareResultMsg{}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		repo, _ := client.GitHubRepo(ctx)
+		copyText(shareText(stack, repo))
+		return shareResultMsg{prs: len(sharedPRs(stack))}
+	}
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func shareStackFixture() []*gt.Branch {
+	return []*gt.Branch{
+		{Name: "base", PR: gt.PRInfo{Number: 10, State: "MERGED"}},
+		{Name: "api", PR: gt.PRInfo{Number: 12, State: "OPEN"}},
+		{Name: "ui", PR: gt.PRInfo{Number: 14, State: "DRAFT"}},
+		{Name: "wip"},
+	}
+}
+
+func TestShareText_WithRepo(t *testing.T) {
+	got := shareText(shareStackFixture(), "acme/shop")
+	want := "Stack: https://app.graphite.dev/github/pr/acme/shop/14\n" +
+		"1/2 #12 api — https://github.com/acme/shop/pull/12\n" +
+		"2/2 #14 ui — https://github.com/acme/shop/pull/14\n"
+	if got != want {
+		t.Errorf("shareText() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestShareText_WithoutRepo(t *testing.T) {
+	if got, want := shareText(shareStackFixture(), ""), "1/2 #12 api\n2/2 #14 ui\n"; got != want {
+		t.Errorf("shareText() = %q, want %q", got, want)
+	}
+}
+
+func TestShareKey_CopiesStack(t *testing.T) {
+	var copied string
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if name == "git" && args[0] == "remote" {
+			return "git@github.com:acme/shop.git\n", nil
+		}
+		return "", nil
+	}}
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	m.copyText = func(s string) { copied = s }
+	m.displayEntries[0].branch.PR = gt.PRInfo{Number: 8, State: "OPEN"}
+	m.displayEntries[1].branch.PR = gt.PRInfo{Number: 7, State: "OPEN"}
+
+	m.cursor = 1 // the whole stack is shared from any branch in it
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'L'}}))
+	m = updated.(Model)
+	for _, msg := range batchMsgs(cmd) {
+		updated, _ = m.Update(msg)
+		m = updated.(Model)
+	}
+	want := "Stack: https://app.graphite.dev/github/pr/acme/shop/8\n" +
+		"1/2 #7 feature-base — https://github.com/acme/shop/pull/7\n" +
+		"2/2 #8 feature-top — https://github.com/acme/shop/pull/8\n"
+	if copied != want {
+		t.Errorf("copied\n%s\nwant\n%s", copied, want)
+	}
+	if m.statusBar.message != "Copied share links for 2 PRs" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
+
+func TestShareKey_NoPRs(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.copyText = func(string) { t.Error("nothing should be copied") }
+	_, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'L'}}))
+	for _, msg := range batchMsgs(cmd) {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	if m.statusBar.message != "No open PRs in this stack — submit it first" {
+		t.Errorf("message = %q", m.statusBar.message)
+	}
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// splitChoice is one way of splitting a branch offered in modeSplit.
+type splitChoice struct {
+	key  string
+	mode gt.SplitMode
+	desc string
+}
+
+var splitChoices = []splitChoice{
+	{"c", gt.SplitByCommit, "By commit — pick the commits that start each new branch"},
+	{"h", gt.SplitByHunk, "By hunk — stage the hunks for each new branch in turn"},
+}
+
+// splitReadyMsg is sent once the branch to split is checked out.
+type splitReadyMsg struct {
+	branch string
+	cmd    *exec.Cmd
+	err    error
+}
+
+// splitDoneMsg is sent when gt split exits and grit has the terminal back.
+type splitDoneMsg struct {
+	branch string
+	err    error
+}
+
+// renderSplit renders the split mode choice for branch.
+func renderSplit(branch string) string {
+	var sb strings.Builder
+	sb.WriteString(helpTitleStyle.Render("Split " + branch))
+	sb.WriteString("\n\n")
+	for _, c := range splitChoices {
+		sb.WriteString(helpKeyStyle.Render(c.key))
+		sb.WriteString(helpDescStyle.Render(c.desc))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(helpDescStyle.Render("gt split takes over the terminal until it finishes. Press esc to cancel."))
+	return sb.String()
+}
+
+// startSplit checks out branch if needed, then hands the terminal to
+// `gt split` in mode.
+func (m *Model) startSplit(branch string, mode gt.SplitMode) []tea.Cmd {
+	cmd, ok := m.gtClient.SplitCommand(mode)
+	if !ok {
+		m.statusBar.setStatus(severityWarning, "Splitting needs gt attached to a terminal, which isn't available here")
+		return nil
+	}
+	if currentBranchName(*m) == branch {
+		return []tea.Cmd{runSplit(branch, cmd)}
+	}
+	if m.inOtherWorktree(branch) {
+		return nil
+	}
+	m.running = true
+	spinnerCmd := m.statusBar.startSpinner("Checking out " + branch + "...")
+	client := m.gtClient
+	return []tea.Cmd{spinnerCmd, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		return splitReadyMsg{branch: branch, cmd: cmd, err: client.Checkout(ctx, branch)}
+	}}
+}
+
+// runSplit runs cmd attached to the terminal, suspending the UI.
+func runSplit(branch string, cmd *exec.Cmd) tea.Cmd {
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return splitDoneMsg{branch: branch, err: err}
+	})
+}
+
+// splitResult describes how a split ended. gt split stops midway on a
+// rebase conflict, leaving gt continue (continueKey) to finish it.
+func splitResult(msg splitDoneMsg, continueKey string) (text string, isError bool) {
+	if msg.err == nil {
+		return "Split " + msg.branch, false
+	}
+	return "gt split stopped (" + msg.err.Error() + ") — if it hit a conflict, resolve it and press " + continueKey + " to continue", true
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// interactiveMock is a recording mock that can also run interactive
+// commands, like the real executor.
+type interactiveMock struct {
+	*mockExecutor
+	commands [][]string
+}
+
+func (m *interactiveMock) Command(name string, args ...string) *exec.Cmd {
+	m.commands = append(m.commands, append([]string{name}, args...))
+	return exec.Command("true")
+}
+
+func TestSplit_ChecksOutThenRunsGtSplit(t *testing.T) {
+	mock, calls := recordingMock()
+	ie := &interactiveMock{mockExecutor: mock}
+	m := New(gt.New(ie), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	m.cursor = 1 // feature-base; feature-top is checked out
+	m = sendKey(m, 'B')
+	if m.mode != modeSplit || !containsString(m.View(), "By hunk") {
+		t.Fatalf("mode = %d, view:\n%s", m.mode, m.View())
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	m = updated.(Model)
+	if m.mode != modeTree || !m.running {
+		t.Fatalf("mode = %d, running = %v; want the checkout running", m.mode, m.running)
+	}
+	if want := [][]string{{"gt", "split", "--by-hunk"}}; !slices.EqualFunc(ie.commands, want, slices.Equal) {
+		t.Errorf("commands = %v, want %v", ie.commands, want)
+	}
+
+	var ready splitReadyMsg
+	for _, msg := range batchMsgs(cmd) {
+		if r, ok := msg.(splitReadyMsg); ok {
+			ready = r
+		}
+	}
+	if ready.branch != "feature-base" || ready.err != nil {
+		t.Fatalf("ready = %+v", ready)
+	}
+	if len(*calls) != 1 || !slices.Equal((*calls)[0].args, []string{"checkout", "feature-base", "--no-interactive"}) {
+		t.Errorf("calls = %+v, want a checkout of feature-base", *calls)
+	}
+	updated, cmd = m.Update(ready)
+	m = updated.(Model)
+	if m.running || cmd == nil {
+		t.Error("the split should run once the branch is checked out")
+	}
+}
+
+func TestSplit_CancelAndGuards(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.cursor = 2 // main
+	m = sendKey(m, 'B')
+	if m.mode != modeTree || m.statusBar.message != "Cannot split trunk branch" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+
+	m.cursor = 0
+	m = sendKey(m, 'B')
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || m.split != "" {
+		t.Errorf("esc should cancel: mode = %d, split = %q", m.mode, m.split)
+	}
+
+	// The mock executor can't hand over the terminal.
+	m = sendKey(m, 'B')
+	m = sendKey(m, 'c')
+	if m.mode != modeTree || m.running || !strings.Contains(m.statusBar.message, "isn't available") {
+		t.Errorf("mode = %d, running = %v, message = %q", m.mode, m.running, m.statusBar.message)
+	}
+}
+
+func TestSplitResult(t *testing.T) {
+	if text, isError := splitResult(splitDoneMsg{branch: "feature"}, "C"); isError || text != "Split feature" {
+		t.Errorf("success = %q, %v", text, isError)
+	}
+	text, isError := splitResult(splitDoneMsg{branch: "feature", err: errors.New("exit status 1")}, "C")
+	if !isError || !strings.Contains(text, "exit status 1") || !strings.Contains(text, "press C to continue") {
+		t.Errorf("failure = %q, %v", text, isError)
+	}
+}
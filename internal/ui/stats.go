@@ -0,0 +1,324 @@
+package ui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/journal"
+)
+
+var (
+	statsStalledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	statsMergedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+)
+
+// statsView is the cycle-time report shown in modeStats.
+type statsView struct {
+	loaded   bool
+	err      error
+	cycles   map[string]journal.Cycle
+	mergedAt map[string]time.Time // merge times of merged PRs, from GitHub
+}
+
+// statsMsg carries what the stats view loads: the journal's cycles and
+// GitHub's merge times.
+type statsMsg struct {
+	cycles   map[string]journal.Cycle
+	mergedAt map[string]time.Time
+	err      error
+}
+
+// branchCycle is one branch's row in the stats view.
+type branchCycle struct {
+	branch *gt.Branch
+	cycle  journal.Cycle
+	merged time.Time // zero if not merged or the time is unknown
+}
+
+// isMerged reports whether the branch's PR merged.
+func (b branchCycle) isMerged() bool {
+	return strings.EqualFold(b.branch.PR.State, "MERGED") || !b.merged.IsZero()
+}
+
+// stackCycle is one stack's rows, bottom first.
+type stackCycle struct {
+	base     string
+	branches []branchCycle
+}
+
+// firstSubmit returns the stack's earliest submit, or the zero time.
+func (s stackCycle) firstSubmit() time.Time {
+	var first time.Time
+	for _, b := range s.branches {
+		if t := b.cycle.FirstSubmit; !t.IsZero() && (first.IsZero() || t.Before(first)) {
+			first = t
+		}
+	}
+	return first
+}
+
+// merged reports whether every branch in the stack merged, and when the
+// last one did (zero if unknown).
+func (s stackCycle) merged() (time.Time, bool) {
+	var last time.Time
+	for _, b := range s.branches {
+		if !b.isMerged() {
+			return time.Time{}, false
+		}
+		if b.merged.After(last) {
+			last = b.merged
+		}
+	}
+	return last, true
+}
+
+// counts totals the stack's submits and restacks.
+func (s stackCycle) counts() (submits, restacks int) {
+	for _, b := range s.branches {
+		submits += b.cycle.Submits
+		restacks += b.cycle.Restacks
+	}
+	return submits, restacks
+}
+
+// rank orders stacks in the view: those in review first, then ones never
+// submitted, then merged ones.
+func (s stackCycle) rank() int {
+	if _, ok := s.merged(); ok {
+		return 2
+	}
+	if s.firstSubmit().IsZero() {
+		return 1
+	}
+	return 0
+}
+
+// stackCycles builds the rows of every stack in roots. Merge times come
+// from GitHub where known, else from when grit first saw the merge. Stacks
+// in review come first, longest in review first, so the ones stalling
+// lead the list; merged stacks come last, most recent first.
+func stackCycles(roots []*gt.Branch, cycles map[string]journal.Cycle, mergedAt map[string]time.Time) []stackCycle {
+	var stacks []stackCycle
+	for _, root := range roots {
+		for _, base := range root.Children {
+			var members []*gt.Branch
+			collectStack(base, &members)
+			slices.SortFunc(members, func(a, b *gt.Branch) int { return b.Order - a.Order })
+			s := stackCycle{base: base.Name}
+			for _, b := range members {
+				row := branchCycle{branch: b, cycle: cycles[b.Name], merged: mergedAt[b.Name]}
+				if row.merged.IsZero() && strings.EqualFold(b.PR.State, "MERGED") {
+					row.merged = row.cycle.Merged
+				}
+				s.branches = append(s.branches, row)
+			}
+			stacks = append(stacks, s)
+		}
+	}
+	slices.SortStableFunc(stacks, func(a, b stackCycle) int {
+		if c := cmp.Compare(a.rank(), b.rank()); c != 0 {
+			return c
+		}
+		switch a.rank() {
+		case 0:
+			return a.firstSubmit().Compare(b.firstSubmit())
+		case 2:
+			at, _ := a.merged()
+			bt, _ := b.merged()
+			return bt.Compare(at)
+		}
+		return 0
+	})
+	return stacks
+}
+
+// stallReasons lists what is holding up b's open PR, if anything.
+func stallReasons(b *gt.Branch) []string {
+	var reasons []string
+	if strings.EqualFold(b.PR.State, "DRAFT") {
+		reasons = append(reasons, "draft")
+	}
+	if strings.Contains(b.Annotation, "restack") {
+		reasons = append(reasons, "needs restack")
+	}
+	if b.PR.Conflicts {
+		reasons = append(reasons, "conflicts")
+	}
+	if b.PR.CI == gt.CIFailing {
+		reasons = append(reasons, "CI failing")
+	}
+	if b.PR.Unresolved > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d unresolved", b.PR.Unresolved))
+	}
+	return reasons
+}
+
+// openStats shows the stats view, loaded afresh.
+func (m *Model) openStats() {
+	m.stats = statsView{}
+	m.setMode(modeStats)
+	m.resizeViewport()
+	m.refreshStatsView()
+	m.viewport.GotoTop()
+}
+
+// enterStats loads the journal and the merge times of merged PRs in the
+// tree. Merge time lookups are best-effort. Stats loaded before stay shown
+// until they arrive.
+func (m *Model) enterStats() []tea.Cmd {
+	client, gitDir := m.gtClient, m.gitDir
+	var merged []string
+	for name, info := range m.prInfos {
+		if strings.EqualFold(info.State, "MERGED") {
+			merged = append(merged, name)
+		}
+	}
+	slices.Sort(merged)
+	return []tea.Cmd{func() tea.Msg {
+		var events []journal.Event
+		if gitDir != "" {
+			var err error
+			if events, err = journal.Load(gitDir); err != nil {
+				return statsMsg{err: err}
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		mergedAt := make(map[string]time.Time)
+		for _, name := range merged {
+			if t, err := client.PRMergedAt(ctx, name); err == nil && !t.IsZero() {
+				mergedAt[name] = t
+			}
+		}
+		return statsMsg{cycles: journal.Cycles(events), mergedAt: mergedAt}
+	}}
+}
+
+// showStats fills in the loaded stats, unless the view has closed since.
+func (m *Model) showStats(msg statsMsg) {
+	if m.mode != modeStats {
+		return
+	}
+	m.stats = statsView{loaded: true, err: msg.err, cycles: msg.cycles, mergedAt: msg.mergedAt}
+	m.refreshStatsView()
+}
+
+func (m *Model) refreshStatsView() {
+	m.viewport.SetContent(renderStats(m.stats, m.branches, time.Now()))
+}
+
+// renderStats renders each stack's time in review and restack count, then
+// a row per branch with what is holding up its PR.
+func renderStats(v statsView, roots []*gt.Branch, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(helpTitleStyle.Render("Stack cycle times"))
+	sb.WriteString("\n\n")
+	switch {
+	case !v.loaded:
+		sb.WriteString(helpDescStyle.Render("Loading stats..."))
+		return sb.String()
+	case v.err != nil:
+		sb.WriteString(promptErrorStyle.Render("Could not load the journal: " + v.err.Error()))
+		return sb.String()
+	}
+	stacks := stackCycles(roots, v.cycles, v.mergedAt)
+	if len(stacks) == 0 {
+		sb.WriteString(helpDescStyle.Render("No stacks."))
+		return sb.String()
+	}
+
+	nameWidth := 0
+	for _, s := range stacks {
+		for _, b := range s.branches {
+			nameWidth = max(nameWidth, len(b.branch.Name))
+		}
+	}
+	for i, s := range stacks {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(helpTitleStyle.Render(s.base) + "  " + stackSummary(s, now) + "\n")
+		for _, b := range s.branches {
+			pr := ""
+			if b.branch.PR.Number > 0 {
+				pr = fmt.Sprintf("#%d", b.branch.PR.Number)
+			}
+			sb.WriteString(fmt.Sprintf("  %-*s  %-6s %s\n", nameWidth, b.branch.Name, pr, branchSummary(b, now)))
+		}
+	}
+	sb.WriteString("\n")
+	sb.WriteString(helpDescStyle.Render("Submits and restacks count those run in grit; merge times come from GitHub."))
+	return sb.String()
+}
+
+// stackSummary describes a stack's cycle: how long it has been in review,
+// or took to merge, and how often it was submitted and restacked.
+func stackSummary(s stackCycle, now time.Time) string {
+	first := s.firstSubmit()
+	var parts []string
+	last, merged := s.merged()
+	switch {
+	case merged && !first.IsZero() && !last.IsZero():
+		parts = append(parts, statsMergedStyle.Render("merged in "+formatDays(last.Sub(first))))
+	case merged:
+		parts = append(parts, statsMergedStyle.Render("merged"))
+	case first.IsZero():
+		parts = append(parts, helpDescStyle.Render("not submitted"))
+	default:
+		parts = append(parts, statsStalledStyle.Render("in review "+formatDays(now.Sub(first))))
+	}
+	submits, restacks := s.counts()
+	parts = append(parts, pluralize(submits, "submit"), pluralize(restacks, "restack"))
+	return strings.Join(parts, " · ")
+}
+
+// branchSummary describes one branch's cycle and, while its PR is open,
+// what is holding it up.
+func branchSummary(b branchCycle, now time.Time) string {
+	first := b.cycle.FirstSubmit
+	var parts []string
+	switch {
+	case b.isMerged() && !first.IsZero() && !b.merged.IsZero():
+		parts = append(parts, statsMergedStyle.Render("merged after "+formatDays(b.merged.Sub(first))))
+	case b.isMerged():
+		parts = append(parts, statsMergedStyle.Render("merged"))
+	case first.IsZero():
+		parts = append(parts, helpDescStyle.Render("not submitted"))
+	default:
+		parts = append(parts, "in review "+formatDays(now.Sub(first)))
+	}
+	if b.cycle.Restacks > 0 {
+		parts = append(parts, pluralize(b.cycle.Restacks, "restack"))
+	}
+	if !b.isMerged() {
+		if reasons := stallReasons(b.branch); len(reasons) > 0 {
+			parts = append(parts, statsStalledStyle.Render(strings.Join(reasons, ", ")))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatDays renders a span in whole days, or hours under a day.
+func formatDays(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", max(int(d.Hours()), 0))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+func (m Model) statsLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"↑/↓", "scroll"},
+		{m.keys.Stats.Help().Key + "/esc", "close"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/journal"
+)
+
+// statsTree is two stacks: a-1 (merged) under a-2, and b-1 alone.
+func statsTree() []*gt.Branch {
+	a2 := &gt.Branch{Name: "a-2", Order: 0, PR: gt.PRInfo{Number: 2, State: "OPEN", Conflicts: true, Unresolved: 1}}
+	a1 := &gt.Branch{Name: "a-1", Order: 1, PR: gt.PRInfo{Number: 1, State: "MERGED"}, Children: []*gt.Branch{a2}}
+	b1 := &gt.Branch{Name: "b-1", Order: 2}
+	return []*gt.Branch{{Name: "main", Order: 3, Children: []*gt.Branch{a1, b1}}}
+}
+
+func TestRenderStats(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	v := statsView{
+		loaded: true,
+		cycles: map[string]journal.Cycle{
+			"a-1": {FirstSubmit: now.Add(-6 * 24 * time.Hour), Submits: 2, Restacks: 1},
+			"a-2": {FirstSubmit: now.Add(-5 * 24 * time.Hour), Submits: 1, Restacks: 3},
+		},
+		mergedAt: map[string]time.Time{"a-1": now.Add(-4 * 24 * time.Hour)},
+	}
+	out := ansi.Strip(renderStats(v, statsTree(), now))
+	for _, want := range []string{
+		"a-1  in review 6d · 3 submits · 4 restacks",
+		"  a-1  #1     merged after 2d · 1 restack",
+		"  a-2  #2     in review 5d · 3 restacks · conflicts, 1 unresolved",
+		"b-1  not submitted · 0 submits · 0 restacks",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "b-1  not submitted") < strings.Index(out, "a-1  in review") {
+		t.Errorf("stacks in review should come first:\n%s", out)
+	}
+}
+
+func TestStackCycles_Order(t *testing.T) {
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	merged := &gt.Branch{Name: "merged", Order: 0, PR: gt.PRInfo{State: "MERGED"}}
+	recent := &gt.Branch{Name: "recent", Order: 1}
+	fresh := &gt.Branch{Name: "fresh", Order: 2}
+	stalled := &gt.Branch{Name: "stalled", Order: 3}
+	roots := []*gt.Branch{{Name: "main", Children: []*gt.Branch{merged, recent, fresh, stalled}}}
+	cycles := map[string]journal.Cycle{
+		"merged":  {FirstSubmit: now.Add(-30 * 24 * time.Hour), Merged: now.Add(-20 * 24 * time.Hour)},
+		"recent":  {FirstSubmit: now.Add(-time.Hour)},
+		"stalled": {FirstSubmit: now.Add(-9 * 24 * time.Hour)},
+	}
+	var got []string
+	for _, s := range stackCycles(roots, cycles, nil) {
+		got = append(got, s.base)
+	}
+	want := "stalled recent fresh merged"
+	if strings.Join(got, " ") != want {
+		t.Errorf("order = %v, want %s", got, want)
+	}
+	if at, ok := stackCycles(roots, cycles, nil)[3].merged(); !ok || !at.Equal(cycles["merged"].Merged) {
+		t.Errorf("merged stack should fall back to the journal's merge time, got %v %v", at, ok)
+	}
+}
+
+func TestStatsKey_LoadsJournalAndMergeTimes(t *testing.T) {
+	gitDir := t.TempDir()
+	submitted := time.Now().Add(-3 * 24 * time.Hour).UTC().Truncate(time.Second)
+	if err := journal.Append(gitDir, []journal.Event{
+		{Time: submitted, Kind: journal.Seen, Branch: "feature-base"},
+		{Time: submitted, Kind: journal.Seen, Branch: "feature-top"},
+		{Time: submitted, Kind: journal.Action, Branch: "feature-base", Action: "submit"},
+		{Time: submitted, Kind: journal.Action, Branch: "feature-top", Action: "submit"},
+		{Time: submitted.Add(time.Hour), Kind: journal.Action, Branch: "feature-top", Action: "restack"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var mergedLookups []string
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if name == "gh" && len(args) == 5 && args[4] == "mergedAt" {
+			mergedLookups = append(mergedLookups, args[2])
+			return `{"mergedAt":"` + submitted.Add(24*time.Hour).Format(time.RFC3339) + `"}`, nil
+		}
+		return "", nil
+	}}
+	m := New(gt.New(mock), gitDir)
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	updated, _ = m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{
+		"feature-base": {Number: 1, State: "MERGED"},
+		"feature-top":  {Number: 2, State: "OPEN"},
+	}})
+	m = updated.(Model)
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'%'}}))
+	m = updated.(Model)
+	if m.mode != modeStats || !strings.Contains(m.viewport.View(), "Loading stats") {
+		t.Fatalf("%% should open the stats view, mode = %d", m.mode)
+	}
+	msg, ok := cmd().(statsMsg)
+	if !ok {
+		t.Fatal("the stats view should load the journal")
+	}
+	if strings.Join(mergedLookups, " ") != "feature-base" {
+		t.Errorf("merge times looked up for %v, want only the merged PR", mergedLookups)
+	}
+	updated, _ = m.Update(msg)
+	m = updated.(Model)
+	view := ansi.Strip(m.viewport.View())
+	for _, want := range []string{"in review 3d · 2 submits · 1 restack", "merged after 1d", "feature-top   #2     in review 3d · 1 restack"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("missing %q in:\n%s", want, view)
+		}
+	}
+
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || !m.stats.loaded {
+		t.Errorf("esc should close the stats view and keep the stats to switch back to, mode = %d", m.mode)
+	}
+}
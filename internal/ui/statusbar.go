@@ -2,28 +2,81 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// lowBandwidthSpinner is a two-frame spinner ticking twice a second, used
+// in low-bandwidth mode instead of the default ~12fps animation.
+var lowBandwidthSpinner = spinner.Spinner{Frames: []string{"·", "•"}, FPS: time.Second / 2}
+
+// lowBandwidthTick is the live view refresh interval in low-bandwidth mode.
+const lowBandwidthTick = 5 * time.Second
+
+// severity ranks a status message, picking its style and how long it stays.
+type severity int
+
+const (
+	severityInfo severity = iota
+	severitySuccess
+	severityWarning // the user's request can't be done, or data may be off
+	severityError   // a command failed
 )
 
+// statusTTL is how long a message of each severity is shown before the bar
+// falls back to the refresh time. Info messages often describe the mode
+// grit is in ("Moving x — pick its new parent"), so they stay until
+// replaced.
+var statusTTL = map[severity]time.Duration{
+	severitySuccess: 4 * time.Second,
+	severityWarning: 8 * time.Second,
+	severityError:   15 * time.Second,
+}
+
+// statusMaxLines caps how many lines a long message wraps to. Beyond
+// that it is cut, and errors point at the error details dialog.
+const statusMaxLines = 3
+
+// statusExpiredMsg clears the message numbered seq, unless it has been
+// replaced since.
+type statusExpiredMsg struct{ seq int }
+
 type statusBar struct {
 	width        int
 	message      string
-	isError      bool
-	isSuccess    bool
+	severity     severity
+	seq          int // bumped by every setStatus
+	scheduled    int // seq whose expiry has been scheduled
 	lastRefresh  time.Time
 	spinner      spinner.Model
 	spinning     bool
 	spinnerLabel string
+	scope        string // path scope shown next to the refresh time, "" for none
+	reduceMotion bool   // show static "working…" text instead of animating
+	lastError    string // most recent error message, kept after it expires
+	lastErrorAt  time.Time
+	detailsKey   string // key that opens the error details, named when an error is cut
 }
 
+var (
+	statusWorkingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	statusErrorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	statusWarningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	statusSuccessStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	statusInfoStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
 func newStatusBar() statusBar {
 	s := spinner.New()
 	s.Spinner = spinner.MiniDot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	s.Style = statusWorkingStyle
 	return statusBar{spinner: s}
 }
 
@@ -31,16 +84,49 @@ func (s *statusBar) setSize(width int) {
 	s.width = width
 }
 
-func (s *statusBar) setMessage(msg string, isError bool) {
-	s.message = msg
-	s.isError = isError
-	s.isSuccess = false
+// setStatus shows msg at sev, scrubbed of credentials: messages often
+// embed command errors or output. An empty msg clears the message.
+func (s *statusBar) setStatus(sev severity, msg string) {
+	s.message = gt.Redact(msg)
+	s.severity = sev
+	s.seq++
+	if sev == severityError && s.message != "" {
+		s.lastError, s.lastErrorAt = s.message, time.Now()
+	}
 }
 
-func (s *statusBar) setSuccessMessage(msg string) {
-	s.message = msg
-	s.isError = false
-	s.isSuccess = true
+// errorDetails returns a dialog with the whole of the last error, which the
+// status bar may have cut to its width or already expired.
+func (s statusBar) errorDetails() overlay {
+	return overlay{
+		title:  "Last error, at " + s.lastErrorAt.Format("15:04:05"),
+		body:   statusErrorStyle.Render(s.lastError),
+		footer: "Press esc or enter to close.",
+	}
+}
+
+// scheduleExpiry returns a command that expires the current message once
+// its severity's TTL has passed, or nil if it doesn't expire or its expiry
+// is already scheduled.
+func (s *statusBar) scheduleExpiry() tea.Cmd {
+	if s.scheduled == s.seq {
+		return nil
+	}
+	s.scheduled = s.seq
+	ttl := statusTTL[s.severity]
+	if s.message == "" || ttl <= 0 {
+		return nil
+	}
+	seq := s.seq
+	return tea.Tick(ttl, func(time.Time) tea.Msg { return statusExpiredMsg{seq: seq} })
+}
+
+// expire clears the message numbered seq if it is still shown.
+func (s *statusBar) expire(seq int) {
+	if seq == s.seq {
+		s.message = ""
+		s.severity = severityInfo
+	}
 }
 
 func (s *statusBar) setRefreshTime(t time.Time) {
@@ -48,10 +134,14 @@ func (s *statusBar) setRefreshTime(t time.Time) {
 }
 
 // startSpinner begins the spinner animation with the given label.
-// Returns a tea.Cmd that must be sent to start the spinner ticks.
+// Returns a tea.Cmd that must be sent to start the spinner ticks, or nil in
+// reduce-motion mode, where the spinner is static.
 func (s *statusBar) startSpinner(label string) tea.Cmd {
 	s.spinning = true
 	s.spinnerLabel = label
+	if s.reduceMotion {
+		return nil
+	}
 	return s.spinner.Tick
 }
 
@@ -62,25 +152,25 @@ func (s *statusBar) stopSpinner() {
 }
 
 func (s statusBar) view() string {
-	style := lipgloss.NewStyle().
-		Width(s.width).
-		Padding(0, 1)
-
-	if s.spinning {
-		style = style.Foreground(lipgloss.Color("6"))
-		return style.Render(s.spinner.View() + " " + s.spinnerLabel)
+	style := statusInfoStyle
+	switch {
+	case s.spinning:
+		style = statusWorkingStyle
+	case s.message == "":
+	case s.severity == severityError:
+		style = statusErrorStyle
+	case s.severity == severityWarning:
+		style = statusWarningStyle
+	case s.severity == severitySuccess:
+		style = statusSuccessStyle
 	}
+	style = style.Width(s.width).Padding(0, 1)
 
-	if s.isError {
-		style = style.
-			Foreground(lipgloss.Color("1")).
-			Bold(true)
-	} else if s.isSuccess {
-		style = style.
-			Foreground(lipgloss.Color("2"))
-	} else {
-		style = style.
-			Foreground(lipgloss.Color("8"))
+	if s.spinning {
+		if s.reduceMotion {
+			return style.Render(s.fit("working… " + s.spinnerLabel))
+		}
+		return style.Render(s.fit(s.spinner.View() + " " + s.spinnerLabel))
 	}
 
 	text := s.message
@@ -90,6 +180,32 @@ func (s statusBar) view() string {
 	if text == "" {
 		text = "grit"
 	}
+	if s.message == "" && s.scope != "" {
+		text += " · scope: " + s.scope
+	}
+
+	return style.Render(s.fit(text))
+}
 
-	return style.Render(text)
+// fit wraps text to the bar's width, up to statusMaxLines lines, so long
+// messages such as conflict errors stay readable on narrow terminals. Text
+// that still doesn't fit is cut with an ellipsis and, for errors, a hint
+// naming the error details key.
+func (s statusBar) fit(text string) string {
+	width := s.width - 2 // padding
+	if width < 1 {
+		return text
+	}
+	lines := strings.Split(ansi.Wrap(strings.TrimSpace(text), width, ""), "\n")
+	if len(lines) <= statusMaxLines {
+		return strings.Join(lines, "\n")
+	}
+	lines = lines[:statusMaxLines]
+	tail := "…"
+	if s.severity == severityError && s.detailsKey != "" && !s.spinning {
+		tail = "… (" + s.detailsKey + ": full error)"
+	}
+	last := strings.TrimRight(lines[statusMaxLines-1], " ")
+	lines[statusMaxLines-1] = ansi.Truncate(last, max(width-ansi.StringWidth(tail), 0), "") + tail
+	return strings.Join(lines, "\n")
 }
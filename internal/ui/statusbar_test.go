@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestStatusBar_SpinnerAnimates(t *testing.T) {
+	s := newStatusBar()
+	s.setSize(80)
+	if cmd := s.startSpinner("Fetching..."); cmd == nil {
+		t.Error("startSpinner should return a tick cmd")
+	}
+	if got := ansi.Strip(s.view()); containsString(got, "working…") {
+		t.Errorf("animated spinner should not show static text, got %q", got)
+	}
+}
+
+func TestStatusBar_ReduceMotion(t *testing.T) {
+	s := newStatusBar()
+	s.setSize(80)
+	s.reduceMotion = true
+
+	if cmd := s.startSpinner("Fetching..."); cmd != nil {
+		t.Error("reduce-motion spinner should not tick")
+	}
+	if got := ansi.Strip(s.view()); !containsString(got, "working… Fetching...") {
+		t.Errorf("view = %q, want static working text", got)
+	}
+}
+
+func TestReduceMotion_FromConfig(t *testing.T) {
+	m := NewWithConfig(gt.New(simpleMock("", nil)), "", config.Config{ReduceMotion: true})
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+
+	m = sendKey(m, 'f')
+	if !m.running {
+		t.Fatal("fetch should be running")
+	}
+	if !containsString(m.View(), "working… Fetching...") {
+		t.Error("view should show static working text")
+	}
+}
+
+func TestLowBandwidth_FromConfig(t *testing.T) {
+	m := NewWithConfig(gt.New(simpleMock("", nil)), "", config.Config{LowBandwidth: true})
+	if !m.lowBandwidth || !m.treeOptions().plainCursor {
+		t.Error("low-bandwidth config should enable the plain cursor")
+	}
+	if m.statusBar.spinner.Spinner.FPS != lowBandwidthSpinner.FPS {
+		t.Errorf("spinner FPS = %v, want %v", m.statusBar.spinner.Spinner.FPS, lowBandwidthSpinner.FPS)
+	}
+
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	if !containsString(m.viewport.View(), plainSelectedStyle.Render("◉ feature-top")) {
+		t.Error("tree should render the cursor without reverse video")
+	}
+}
+
+func TestStatusBar_ExpiryBySeverity(t *testing.T) {
+	saved := statusTTL
+	statusTTL = map[severity]time.Duration{severityWarning: time.Millisecond}
+	defer func() { statusTTL = saved }()
+
+	s := newStatusBar()
+	s.setStatus(severityInfo, "Moving feature-top")
+	if cmd := s.scheduleExpiry(); cmd != nil {
+		t.Error("info messages should not expire")
+	}
+	s.setStatus(severityWarning, "PR data stale")
+	cmd := s.scheduleExpiry()
+	if cmd == nil {
+		t.Fatal("warnings should expire")
+	}
+	if s.scheduleExpiry() != nil {
+		t.Error("expiry should be scheduled once per message")
+	}
+	msg := cmd().(statusExpiredMsg)
+
+	s.setStatus(severityWarning, "Newer warning")
+	s.expire(msg.seq)
+	if s.message != "Newer warning" {
+		t.Errorf("message = %q, a stale expiry should not clear a newer message", s.message)
+	}
+	s.expire(s.seq)
+	if s.message != "" || s.severity != severityInfo {
+		t.Errorf("message = %q, severity = %d, want cleared", s.message, s.severity)
+	}
+}
+
+func TestStatusBar_SeverityStyles(t *testing.T) {
+	s := newStatusBar()
+	s.setSize(40)
+	for sev, style := range map[severity]lipgloss.Style{
+		severityInfo:    statusInfoStyle,
+		severitySuccess: statusSuccessStyle,
+		severityWarning: statusWarningStyle,
+		severityError:   statusErrorStyle,
+	} {
+		s.setStatus(sev, "msg")
+		if got, want := s.view(), style.Width(40).Padding(0, 1).Render("msg"); got != want {
+			t.Errorf("severity %d: view = %q, want %q", sev, got, want)
+		}
+	}
+}
+
+func TestPRInfo_FailedLookupKeepsStaleInfo(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	updated, _ := m.Update(prInfoResultMsg{infos: map[string]gt.PRInfo{"feature-top": {Number: 7, State: "OPEN"}}})
+	m = updated.(Model)
+
+	updated, _ = m.Update(prInfoResultMsg{
+		infos:  map[string]gt.PRInfo{"feature-top": {}, "feature-base": {}},
+		failed: []string{"feature-top", "feature-base"},
+	})
+	m = updated.(Model)
+	if m.prInfos["feature-top"].Number != 7 {
+		t.Errorf("feature-top PR = %+v, want the previous PR kept", m.prInfos["feature-top"])
+	}
+	if got := toastMessages(m); len(got) != 1 || got[0] != "PR data stale: could not refresh 1 PR" || m.toasts.items[0].severity != severityWarning {
+		t.Errorf("toasts = %q", got)
+	}
+}
+
+func TestStatusBar_WrapsLongMessages(t *testing.T) {
+	s := newStatusBar()
+	s.setSize(40)
+	s.setStatus(severityError, "Restack failed: could not apply 1a2b3c4 in feature-b")
+	got := ansi.Strip(s.view())
+	if lipgloss.Height(got) != 2 || !containsString(got, "in feature-b") {
+		t.Errorf("view = %q, want the whole error on two lines", got)
+	}
+}
+
+func TestStatusBar_CutsMessagesPastMaxLines(t *testing.T) {
+	s := newStatusBar()
+	s.setSize(40)
+	s.detailsKey = "E"
+	long := "CONFLICT (content): Merge conflict in api/handlers/users.go, api/handlers/teams.go, api/handlers/orgs.go and web/src/components/UserList.tsx; fix conflicts and then run gt continue"
+	s.setStatus(severityError, long)
+	got := ansi.Strip(s.view())
+	if lipgloss.Height(got) != statusMaxLines {
+		t.Errorf("height = %d, want %d:\n%s", lipgloss.Height(got), statusMaxLines, got)
+	}
+	if !containsString(got, "… (E: full error)") {
+		t.Errorf("a cut error should point at the details key:\n%s", got)
+	}
+	if s.lastError != long {
+		t.Error("the whole error should be kept for the details dialog")
+	}
+
+	s.setStatus(severityWarning, long)
+	if got := ansi.Strip(s.view()); containsString(got, "full error") || !containsString(got, "…") {
+		t.Errorf("a cut warning should just end in an ellipsis:\n%s", got)
+	}
+}
+
+func TestLongError_GrowsStatusBarWithinScreen(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	treeHeight := m.viewport.Height
+	updated, _ := m.Update(actionResultMsg{action: "submit", err: errors.New("! [remote rejected] feature-top -> feature-top (protected branch hook declined): GH006 Protected branch update failed for refs/heads/feature-top")})
+	m = updated.(Model)
+	view := ansi.Strip(m.View())
+	if !containsString(view, "refs/heads/feature-top") {
+		t.Errorf("the whole error should be visible:\n%s", view)
+	}
+	if m.viewport.Height >= treeHeight {
+		t.Errorf("viewport height = %d, want it to shrink below %d for the wrapped error", m.viewport.Height, treeHeight)
+	}
+	if h := lipgloss.Height(m.View()); h != 24 {
+		t.Errorf("view height = %d, want the screen's 24", h)
+	}
+
+	m.statusBar.expire(m.statusBar.seq)
+	updated, _ = m.Update(statusExpiredMsg{seq: m.statusBar.seq})
+	m = updated.(Model)
+	if m.viewport.Height != treeHeight {
+		t.Errorf("viewport height = %d, want %d once the error clears", m.viewport.Height, treeHeight)
+	}
+}
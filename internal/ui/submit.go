@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// stackBranches returns the non-trunk branches a submit of name affects:
+// its ancestors below trunk and itself, plus all descendants when
+// includeUpstack is set (stack submit) rather than just downstack.
+func stackBranches(branches []*gt.Branch, name string, includeUpstack bool) []*gt.Branch {
+	var result []*gt.Branch
+	var walk func(b *gt.Branch, path []*gt.Branch) bool
+	walk = func(b *gt.Branch, path []*gt.Branch) bool {
+		if b.Name == name {
+			result = append(result, path...)
+			result = append(result, b)
+			if includeUpstack {
+				collectDescendants(b, &result)
+			}
+			return true
+		}
+		for _, child := range b.Children {
+			if walk(child, append(path, b)) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, root := range branches {
+		// The root itself (trunk) is never submitted.
+		for _, child := range root.Children {
+			if walk(child, nil) {
+				return result
+			}
+		}
+	}
+	return result
+}
+
+// collectDescendants appends every descendant of b to out, depth-first.
+func collectDescendants(b *gt.Branch, out *[]*gt.Branch) {
+	for _, child := range b.Children {
+		*out = append(*out, child)
+		collectDescendants(child, out)
+	}
+}
+
+// branchNames returns the names of bs.
+func branchNames(bs []*gt.Branch) []string {
+	var names []string
+	for _, b := range bs {
+		names = append(names, b.Name)
+	}
+	return names
+}
+
+// submitAction runs a submit and then, for each submitted branch whose
+// changes have code owners, checks the PR's reviewers and reports owner
+// teams that were not requested. Reviewer lookups are best-effort. The
+// submit is tracked as a foreground job under label.
+func (m Model) submitAction(label, action, successMsg string, targets []*gt.Branch, submit clientAction) tea.Cmd {
+	client := m.gtClient
+	owners := make(map[string][]string)
+	for _, b := range targets {
+		if len(b.Changes.Owners) > 0 {
+			owners[b.Name] = b.Changes.Owners
+		}
+	}
+
+	return m.jobs.submit(label, false, func(jobCtx context.Context) (tea.Msg, error) {
+		ctx, cancel := context.WithTimeout(jobCtx, 60*time.Second)
+		defer cancel()
+		if err := submit(ctx, client); err != nil {
+			return actionResultMsg{action: action, err: err, message: successMsg}, err
+		}
+		return actionResultMsg{action: action, message: successMsg, warning: ownerWarning(ctx, client, owners)}, nil
+	})
+}
+
+// ownerWarning builds a warning listing owner teams missing from each
+// branch's PR reviewers, or "" if every owner team was requested.
+func ownerWarning(ctx context.Context, client *gt.Client, owners map[string][]string) string {
+	names := make([]string, 0, len(owners))
+	for name := range owners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		reviewers, err := client.PRReviewers(ctx, name)
+		if err != nil {
+			continue
+		}
+		if missing := missingOwnerTeams(owners[name], reviewers); len(missing) > 0 {
+			parts = append(parts, name+": "+strings.Join(missing, ", "))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "owner review not requested — " + strings.Join(parts, "; ")
+}
+
+// submitScopes are the scope choices of the submit options form, in the
+// order of gt.SubmitScope.
+var submitScopes = []string{"branch", "downstack", "stack"}
+
+// Fields of the submit options form.
+const (
+	submitFieldScope = iota
+	submitFieldDraft
+	submitFieldUpdateOnly
+	submitFieldReviewers
+)
+
+// openSubmitForm asks how to submit branch: which branches, whether new
+// PRs are drafts, whether to skip branches without PRs, and whom to
+// request reviews from.
+func (m *Model) openSubmitForm(branch *gt.Branch) {
+	name := branch.Name
+	m.form = newForm("Submit "+name, []formField{
+		submitFieldScope:      selectField("Submit", submitScopes, int(gt.SubmitStack)),
+		submitFieldDraft:      checkboxField("Open new PRs as drafts", false),
+		submitFieldUpdateOnly: checkboxField("Only update existing PRs", false),
+		submitFieldReviewers:  textField("Reviewers", "", "user, org/team"),
+	}, func(m *Model, f form) []tea.Cmd {
+		opts := gt.SubmitOptions{
+			Scope:      gt.SubmitScope(f.fields[submitFieldScope].choice),
+			Draft:      f.fields[submitFieldDraft].checked,
+			UpdateOnly: f.fields[submitFieldUpdateOnly].checked,
+			Reviewers:  splitReviewers(f.fields[submitFieldReviewers].text()),
+		}
+		m.rememberReviewers(opts.Reviewers)
+		return m.startSubmit(submitWithOptions(m.branches, name, opts))
+	})
+}
+
+// submitWithOptions builds the submit of name that opts describes.
+func submitWithOptions(branches []*gt.Branch, name string, opts gt.SubmitOptions) pendingSubmit {
+	p := pendingSubmit{
+		submit: func(ctx context.Context, client *gt.Client) error {
+			return client.Submit(ctx, name, opts)
+		},
+	}
+	switch opts.Scope {
+	case gt.SubmitStack:
+		p.action, p.desc, p.successMsg = "submit", "Submit stack ("+name+")", "Stack submitted"
+		p.targets = stackBranches(branches, name, true)
+	case gt.SubmitDownstack:
+		p.action, p.desc, p.successMsg = "downstack-submit", "Submit downstack ("+name+")", "Downstack submitted"
+		p.targets = stackBranches(branches, name, false)
+	default:
+		p.action, p.desc, p.successMsg = "branch-submit", "Submit branch ("+name+")", "Submitted "+name
+		if b := gt.FindBranch(branches, name); b != nil {
+			p.targets = []*gt.Branch{b}
+		}
+	}
+	if opts.Draft {
+		p.desc += " as draft"
+	}
+	p.spinnerLabel = strings.Replace(p.desc, "Submit", "Submitting", 1) + "..."
+	return p
+}
+
+// splitReviewers splits a comma or space separated reviewer list.
+func splitReviewers(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func submitTestTree() []*gt.Branch {
+	// main → a → b → c, and main → x
+	return []*gt.Branch{{
+		Name: "main",
+		Children: []*gt.Branch{
+			{Name: "a", Children: []*gt.Branch{
+				{Name: "b", Children: []*gt.Branch{
+					{Name: "c"},
+				}},
+			}},
+			{Name: "x"},
+		},
+	}}
+}
+
+func TestStackBranches(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstack  bool
+		wantList string
+	}{
+		{"b", true, "a,b,c"},
+		{"b", false, "a,b"},
+		{"a", false, "a"},
+		{"x", true, "x"},
+		{"main", true, ""},
+		{"missing", true, ""},
+	}
+	for _, tt := range tests {
+		got := joinNames(branchNames(stackBranches(submitTestTree(), tt.name, tt.upstack)))
+		if got != tt.wantList {
+			t.Errorf("stackBranches(%q, %v) = %q, want %q", tt.name, tt.upstack, got, tt.wantList)
+		}
+	}
+}
+
+func joinNames(names []string) string {
+	out := ""
+	for i, n := range names {
+		if i > 0 {
+			out += ","
+		}
+		out += n
+	}
+	return out
+}
+
+func TestSubmitAction_WarnsAboutMissingOwnerTeams(t *testing.T) {
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		if name == "gh" {
+			return `{"reviewRequests":[{"login":"alice"}],"reviews":[]}`, nil
+		}
+		return "", nil
+	}}
+	m := New(gt.New(mock), "")
+	targets := []*gt.Branch{
+		{Name: "a", Changes: gt.ChangeInfo{Loaded: true, Owners: []string{"@alice", "@org/team-api"}}},
+		{Name: "b"},
+	}
+
+	msg := runJobCmd(m.submitAction("Submit", "submit", "Stack submitted", targets, func(ctx context.Context, client *gt.Client) error {
+		return nil
+	})).(actionResultMsg)
+
+	if msg.err != nil {
+		t.Fatalf("unexpected error: %v", msg.err)
+	}
+	if msg.warning != "owner review not requested — a: @org/team-api" {
+		t.Errorf("warning = %q", msg.warning)
+	}
+
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if m.statusBar.severity != severityWarning || !containsString(m.statusBar.message, "@org/team-api") {
+		t.Errorf("status = %q (severity=%d), want warning", m.statusBar.message, m.statusBar.severity)
+	}
+}
+
+func TestSubmitAction_NoWarningWhenCovered(t *testing.T) {
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		return `{"reviewRequests":[{"slug":"team-api"}]}`, nil
+	}}
+	m := New(gt.New(mock), "")
+	targets := []*gt.Branch{{Name: "a", Changes: gt.ChangeInfo{Owners: []string{"@org/team-api"}}}}
+
+	msg := runJobCmd(m.submitAction("Submit", "submit", "ok", targets, func(ctx context.Context, client *gt.Client) error { return nil })).(actionResultMsg)
+	if msg.warning != "" {
+		t.Errorf("warning = %q, want none", msg.warning)
+	}
+}
+
+func TestSubmitAction_ErrorSkipsOwnerCheck(t *testing.T) {
+	called := false
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		called = true
+		return "", nil
+	}}
+	m := New(gt.New(mock), "")
+	targets := []*gt.Branch{{Name: "a", Changes: gt.ChangeInfo{Owners: []string{"@org/team-api"}}}}
+
+	msg := runJobCmd(m.submitAction("Submit", "submit", "ok", targets, func(ctx context.Context, client *gt.Client) error {
+		return errors.New("submit failed")
+	})).(actionResultMsg)
+	if msg.err == nil {
+		t.Fatal("expected error")
+	}
+	if called {
+		t.Error("reviewers should not be fetched after a failed submit")
+	}
+}
@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// syncPreview is what `gt sync` is expected to do, worked out from the
+// freshly fetched remote trunk before running it.
+type syncPreview struct {
+	trunk      string
+	incoming   int // commits the remote trunk has that the local one lacks
+	before     []*gt.Branch
+	after      []*gt.Branch
+	deleted    map[string]bool     // merged and closed branches the sync deletes
+	reparented map[string]string   // surviving branch -> its new parent
+	conflicts  map[string][]string // branch -> files predicted to conflict
+}
+
+// syncPreviewMsg carries the preview, or why it couldn't be built.
+type syncPreviewMsg struct {
+	preview syncPreview
+	err     error
+}
+
+// startSyncPreview fetches trunk and works out the preview in the
+// background.
+func (m *Model) startSyncPreview() []tea.Cmd {
+	m.running = true
+	spinnerCmd := m.statusBar.startSpinner("Fetching trunk for the sync preview...")
+	client := m.gtClient
+	branches := m.branches
+	return []tea.Cmd{spinnerCmd, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		p, err := loadSyncPreview(ctx, client, branches)
+		return syncPreviewMsg{preview: p, err: err}
+	}}
+}
+
+// loadSyncPreview fetches the remote trunk and simulates the sync on
+// branches: branches it already contains, or whose PRs merged or were
+// closed (`gt sync -f` deletes both), are deleted, their children move down, and the rest are test-merged onto it
+// to predict restack conflicts. Conflict prediction is skipped where git
+// can't do it.
+func loadSyncPreview(ctx context.Context, client *gt.Client, branches []*gt.Branch) (syncPreview, error) {
+	trunk := branches[0].Name
+	remote := remoteRef(trunk)
+	if err := client.FetchTrunk(ctx, trunk); err != nil {
+		return syncPreview{}, err
+	}
+	incoming, err := client.CommitsBetween(ctx, trunk, remote)
+	if err != nil {
+		return syncPreview{}, err
+	}
+	merged, err := client.MergedInto(ctx, remote)
+	if err != nil {
+		return syncPreview{}, err
+	}
+	p := syncPreview{
+		trunk:      trunk,
+		incoming:   incoming,
+		before:     branches,
+		deleted:    make(map[string]bool),
+		reparented: make(map[string]string),
+		conflicts:  make(map[string][]string),
+	}
+	var stack []*gt.Branch
+	for _, root := range branches {
+		collectDescendants(root, &stack)
+	}
+	for _, b := range stack {
+		if merged[b.Name] || strings.EqualFold(b.PR.State, "MERGED") || strings.EqualFold(b.PR.State, "CLOSED") {
+			p.deleted[b.Name] = true
+		}
+	}
+	p.after = gt.SimulateSync(branches, p.deleted)
+	for _, b := range stack {
+		if p.deleted[b.Name] {
+			continue
+		}
+		if after := gt.FindBranch(p.after, b.Name); after != nil && after.Parent != b.Parent {
+			p.reparented[b.Name] = after.Parent
+		}
+		if incoming == 0 {
+			continue
+		}
+		if files, err := client.MergeConflicts(ctx, remote, b.Name); err == nil && len(files) > 0 {
+			p.conflicts[b.Name] = files
+		}
+	}
+	return p, nil
+}
+
+// startSync runs `gt sync`.
+func (m *Model) startSync() []tea.Cmd {
+	return m.startAction("sync", "Synced", "Syncing...", "", func(ctx context.Context, client *gt.Client) error {
+		return client.Sync(ctx)
+	})
+}
+
+// renderSyncPreview renders the tree now and after the sync side by side,
+// followed by a summary of the fallout.
+func renderSyncPreview(p syncPreview, width int) string {
+	var sb strings.Builder
+	title := p.trunk + " is up to date with " + remoteRef(p.trunk)
+	if p.incoming > 0 {
+		title = fmt.Sprintf("%s is %s ahead of %s", remoteRef(p.trunk), pluralize(p.incoming, "commit"), p.trunk)
+	}
+	sb.WriteString(helpTitleStyle.Render("Sync preview: " + title))
+	sb.WriteString("\n\n")
+
+	paneWidth := max((width-3)/2, 10)
+	left := []string{helpTitleStyle.Render("Now")}
+	for _, e := range flattenForDisplay(p.before) {
+		row := syncPreviewIndent(e) + e.branch.Name
+		if p.deleted[e.branch.Name] {
+			row = syncPreviewIndent(e) + prMergedStyle.Render(e.branch.Name+"  ✗ deleted")
+		}
+		left = append(left, row)
+	}
+	right := []string{helpTitleStyle.Render("After sync")}
+	for _, e := range flattenForDisplay(p.after) {
+		row := syncPreviewIndent(e) + e.branch.Name
+		if parent, ok := p.reparented[e.branch.Name]; ok {
+			row += annotationStyle.Render("  → onto " + parent)
+		}
+		if files := p.conflicts[e.branch.Name]; len(files) > 0 {
+			row += testFailedStyle.Render("  ⚠ conflicts")
+		}
+		right = append(right, row)
+	}
+	for i := 0; i < max(len(left), len(right)); i++ {
+		var l, r string
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		sb.WriteString(padToWidth(truncateToWidth(l, paneWidth), paneWidth))
+		sb.WriteString(connectorStyle.Render(" │ "))
+		sb.WriteString(truncateToWidth(r, paneWidth))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	for _, line := range syncPreviewSummary(p) {
+		sb.WriteString(helpDescStyle.Render(truncateToWidth(line, width)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(helpDescStyle.Render("Press enter to sync (gt sync -f), esc to cancel."))
+	return sb.String()
+}
+
+// syncPreviewIndent is the connector prefix for e in a preview pane.
+func syncPreviewIndent(e displayEntry) string {
+	return connectorStyle.Render(strings.Repeat("│ ", e.depth))
+}
+
+// syncPreviewSummary lists what the sync changes, in tree order.
+func syncPreviewSummary(p syncPreview) []string {
+	var deleted, moved, conflicted []string
+	for _, e := range flattenForDisplay(p.before) {
+		name := e.branch.Name
+		if p.deleted[name] {
+			deleted = append(deleted, name)
+		}
+		if parent, ok := p.reparented[name]; ok {
+			moved = append(moved, name+" onto "+parent)
+		}
+		if files := p.conflicts[name]; len(files) > 0 {
+			conflicted = append(conflicted, name+" ("+strings.Join(files, ", ")+")")
+		}
+	}
+	var lines []string
+	if len(deleted) > 0 {
+		lines = append(lines, "Deletes merged or closed: "+strings.Join(deleted, ", "))
+	}
+	if len(moved) > 0 {
+		lines = append(lines, "Restacks: "+strings.Join(moved, ", "))
+	}
+	if len(conflicted) > 0 {
+		lines = append(lines, "Likely conflicts: "+strings.Join(conflicted, ", "))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No branches are deleted or moved.")
+	}
+	return lines
+}
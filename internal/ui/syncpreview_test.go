@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// syncPreviewMock answers the sync preview's git commands: origin/main is
+// 3 commits ahead, feature-base is merged into it and feature-top
+// conflicts when test-merged.
+func syncPreviewMock() (*mockExecutor, *[]callRecord) {
+	var calls []callRecord
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		calls = append(calls, callRecord{name: name, args: args})
+		switch {
+		case name == "git" && args[0] == "rev-list":
+			return "3\n", nil
+		case name == "git" && args[0] == "branch":
+			return "main\nfeature-base\n", nil
+		case name == "git" && args[0] == "merge-tree" && args[len(args)-1] == "feature-top":
+			return "4b825dc\napi/handler.go\n", context.DeadlineExceeded
+		case name == "git" && args[0] == "merge-tree":
+			return "4b825dc\n", nil
+		}
+		return "", nil
+	}}
+	return mock, &calls
+}
+
+func TestLoadSyncPreview(t *testing.T) {
+	mock, _ := syncPreviewMock()
+	branches, _ := gt.ParseLogShort("│ ◯  feature-top\n│ ◯  feature-base\n│ ◯  other\n◯─┘  main")
+	p, err := loadSyncPreview(context.Background(), gt.New(mock), branches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.incoming != 3 || !p.deleted["feature-base"] || len(p.deleted) != 1 {
+		t.Errorf("incoming = %d, deleted = %v", p.incoming, p.deleted)
+	}
+	if p.reparented["feature-top"] != "other" || len(p.reparented) != 1 {
+		t.Errorf("reparented = %v", p.reparented)
+	}
+	if got := strings.Join(p.conflicts["feature-top"], ","); got != "api/handler.go" || len(p.conflicts) != 1 {
+		t.Errorf("conflicts = %v", p.conflicts)
+	}
+
+	summary := strings.Join(syncPreviewSummary(p), "\n")
+	for _, want := range []string{"Deletes merged or closed: feature-base", "Restacks: feature-top onto other", "Likely conflicts: feature-top (api/handler.go)"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary should contain %q:\n%s", want, summary)
+		}
+	}
+}
+
+func TestLoadSyncPreview_DeletesClosedPRs(t *testing.T) {
+	mock, _ := syncPreviewMock()
+	branches, _ := gt.ParseLogShort("│ ◯  feature-top\n│ ◯  feature-base\n│ ◯  other\n◯─┘  main")
+	gt.FindBranch(branches, "other").PR = gt.PRInfo{Number: 7, State: "CLOSED"}
+	p, err := loadSyncPreview(context.Background(), gt.New(mock), branches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.deleted["other"] || !p.deleted["feature-base"] || len(p.deleted) != 2 {
+		t.Errorf("deleted = %v, want the closed PR's branch deleted too", p.deleted)
+	}
+	if p.reparented["feature-top"] != "main" {
+		t.Errorf("reparented = %v, want feature-top onto main", p.reparented)
+	}
+}
+
+func TestSyncPreviewKey_ShowsPreviewThenSyncs(t *testing.T) {
+	mock, calls := syncPreviewMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 100, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	*calls = nil
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'g'}}))
+	m = updated.(Model)
+	batch, _ := cmd().(tea.BatchMsg)
+	for _, c := range batch {
+		if c == nil {
+			continue
+		}
+		if p, ok := c().(syncPreviewMsg); ok {
+			updated, _ = m.Update(p)
+			m = updated.(Model)
+		}
+	}
+	if m.mode != modeSyncPreview {
+		t.Fatalf("mode = %d, want modeSyncPreview", m.mode)
+	}
+	if len(*calls) == 0 || strings.Join((*calls)[0].args, " ") != "fetch origin main" {
+		t.Errorf("the preview should fetch trunk first: %v", *calls)
+	}
+	view := m.View()
+	for _, want := range []string{"origin/main is 3 commits ahead of main", "Now", "After sync", "feature-base  ✗ deleted", "→ onto main"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("preview should show %q:\n%s", want, view)
+		}
+	}
+
+	*calls = nil
+	updated, cmd = m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if m.mode != modeTree || len(*calls) != 1 || strings.Join((*calls)[0].args, " ") != "sync -f --no-interactive" {
+		t.Errorf("enter should sync, mode = %d, calls = %v", m.mode, *calls)
+	}
+}
+
+func TestSyncPreview_Cancel(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	updated, _ := m.Update(syncPreviewMsg{preview: syncPreview{trunk: "main", before: m.branches, after: m.branches}})
+	m = updated.(Model)
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || m.statusBar.message != "Sync cancelled" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/config"
+)
+
+// branchTemplate is a configured quick-create action (config templates).
+type branchTemplate struct {
+	binding key.Binding
+	prefix  string
+	onTrunk bool // stack on trunk rather than the selected branch
+}
+
+// newTemplates builds the quick-create actions from config.
+func newTemplates(ts []config.Template) []branchTemplate {
+	templates := make([]branchTemplate, 0, len(ts))
+	for _, t := range ts {
+		onTrunk := t.Base == "trunk"
+		desc := "New " + t.Prefix + "… branch on selected branch"
+		if onTrunk {
+			desc = "New " + t.Prefix + "… branch on trunk"
+		}
+		templates = append(templates, branchTemplate{
+			binding: key.NewBinding(key.WithKeys(t.Key), key.WithHelp(keyHelp([]string{t.Key}), desc)),
+			prefix:  t.Prefix,
+			onTrunk: onTrunk,
+		})
+	}
+	return templates
+}
+
+// checkTemplates reports templates missing a key or prefix, with an
+// unknown base, or whose key is already bound in keys or by another
+// template.
+func checkTemplates(ts []config.Template, keys keyMap) error {
+	for i, t := range ts {
+		switch {
+		case t.Key == "":
+			return fmt.Errorf("template %d: no key", i+1)
+		case t.Prefix == "":
+			return fmt.Errorf("template %q: no prefix", t.Key)
+		case t.Base != "" && t.Base != "selected" && t.Base != "trunk":
+			return fmt.Errorf("template %q: base must be \"selected\" or \"trunk\", got %q", t.Key, t.Base)
+		}
+	}
+	return keys.checkConflicts(ts)
+}
+
+// matchTemplate returns the template bound to msg, if any.
+func (m Model) matchTemplate(msg tea.KeyMsg) (branchTemplate, bool) {
+	for _, t := range m.templates {
+		if key.Matches(msg, t.binding) {
+			return t, true
+		}
+	}
+	return branchTemplate{}, false
+}
+
+// startTemplate opens the new-branch prompt for t with its prefix filled
+// in, so only the rest of the name needs typing.
+func (m *Model) startTemplate(t branchTemplate) {
+	base := ""
+	if t.onTrunk {
+		if len(m.branches) > 0 {
+			base = m.branches[0].Name
+		}
+	} else if b := m.selectedBranch(); b != nil {
+		base = b.Name
+	}
+	if base == "" {
+		return
+	}
+	m.openCreatePrompt(base, t.prefix)
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var testTemplates = []config.Template{
+	{Key: "1", Prefix: "feat/"},
+	{Key: "2", Prefix: "fix/", Base: "trunk"},
+}
+
+func templateModel(mock *mockExecutor) Model {
+	m := NewWithConfig(gt.New(mock), "", config.Config{Templates: testTemplates})
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	return updated.(Model)
+}
+
+func TestTemplate_StacksOnSelected(t *testing.T) {
+	mock, calls := recordingMock()
+	m := templateModel(mock)
+	m.cursor = 1 // feature-base
+
+	m = sendKey(m, '1')
+	if !m.prompt.active() {
+		t.Fatal("1 should open the prompt")
+	}
+	if m.prompt.input.Value() != "feat/" {
+		t.Errorf("prompt value = %q, want the prefix", m.prompt.input.Value())
+	}
+	if !containsString(m.View(), "New branch on feature-base") {
+		t.Errorf("view should show the base, got:\n%s", m.View())
+	}
+
+	for _, r := range "login" {
+		m = sendKey(m, r)
+	}
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+
+	if m.cursorTarget != "feat/login" {
+		t.Errorf("cursorTarget = %q, want feat/login", m.cursorTarget)
+	}
+	if len(*calls) != 2 {
+		t.Fatalf("calls = %v, want checkout then create", *calls)
+	}
+	if got := (*calls)[0].args; got[0] != "checkout" || got[1] != "feature-base" {
+		t.Errorf("first call = %v, want checkout feature-base", got)
+	}
+	if got := (*calls)[1].args; got[0] != "create" || got[1] != "feat/login" {
+		t.Errorf("second call = %v, want create feat/login", got)
+	}
+}
+
+func TestTemplate_StacksOnTrunk(t *testing.T) {
+	mock, _ := recordingMock()
+	m := templateModel(mock)
+	m.cursor = 0 // feature-top
+
+	m = sendKey(m, '2')
+	if m.prompt.base != "main" {
+		t.Errorf("base = %q, want main", m.prompt.base)
+	}
+	if m.prompt.input.Value() != "fix/" {
+		t.Errorf("prompt value = %q", m.prompt.input.Value())
+	}
+}
+
+func TestTemplate_CurrentBaseSkipsCheckout(t *testing.T) {
+	mock, calls := recordingMock()
+	m := templateModel(mock)
+	m.cursor = 0 // feature-top is checked out
+
+	m = sendKey(m, '1')
+	m = sendKey(m, 'x')
+	_, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	runBatch(cmd)
+	if len(*calls) != 1 || (*calls)[0].args[0] != "create" {
+		t.Errorf("calls = %v, want only create", *calls)
+	}
+}
+
+func TestTemplate_EmptySlug(t *testing.T) {
+	mock, calls := recordingMock()
+	m := templateModel(mock)
+	m = sendKey(m, '1')
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.running || len(*calls) != 0 {
+		t.Error("prefix alone should not create a branch")
+	}
+	if m.prompt.err == "" {
+		t.Error("expected the prompt to show why")
+	}
+}
+
+func TestCheckTemplates(t *testing.T) {
+	keys := defaultKeyMap()
+	if err := checkTemplates(testTemplates, keys); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, bad := range [][]config.Template{
+		{{Prefix: "feat/"}},
+		{{Key: "1"}},
+		{{Key: "1", Prefix: "feat/", Base: "main"}},
+		{{Key: "F", Prefix: "feat/"}},
+		{{Key: "1", Prefix: "feat/"}, {Key: "1", Prefix: "fix/"}},
+	} {
+		if err := checkTemplates(bad, keys); err == nil {
+			t.Errorf("checkTemplates(%+v) should fail", bad)
+		}
+	}
+	if err := keys.rebind(map[string][]string{"fold": {"ctrl+v"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkTemplates([]config.Template{{Key: "F", Prefix: "feat/"}}, keys); err != nil {
+		t.Errorf("F is free once fold is rebound: %v", err)
+	}
+}
+
+func TestTemplate_ConflictReportedAtStartup(t *testing.T) {
+	m := NewWithConfig(gt.New(simpleMock("", nil)), "", config.Config{Templates: []config.Template{{Key: "F", Prefix: "feat/"}}})
+	if len(m.templates) != 0 {
+		t.Error("a template shadowing fold should not be installed")
+	}
+	if items := m.toasts.items; len(items) != 1 || !strings.Contains(items[0].message, `"fold"`) {
+		t.Errorf("toasts = %v, want the template conflict reported", items)
+	}
+}
+
+func TestRenderHelp_ListsTemplates(t *testing.T) {
+	help := ansi.Strip(renderHelp(defaultKeyMap(), newTemplates(testTemplates)))
+	for _, want := range []string{"Templates", "New feat/… branch on selected branch", "New fix/… branch on trunk"} {
+		if !containsString(help, want) {
+			t.Errorf("help should contain %q", want)
+		}
+	}
+}
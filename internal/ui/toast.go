@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// Toasts are stacked in the top-right corner, newest at the bottom, each
+// dismissing itself after toastTTL. Past toastMax the oldest is dropped.
+const (
+	toastTTL      = 6 * time.Second
+	toastMax      = 3
+	toastMaxWidth = 50
+)
+
+// toastExpiredMsg dismisses the toast numbered id.
+type toastExpiredMsg struct{ id int }
+
+// toast is a transient notification about something that happened in the
+// background, e.g. a PR merged remotely.
+type toast struct {
+	id       int
+	severity severity
+	message  string
+}
+
+// toasts are the notifications shown over the screen. Unlike the status
+// bar, which the next action overwrites, each stays for its whole TTL.
+type toasts struct {
+	items     []toast
+	seq       int // id of the newest toast
+	scheduled int // id of the newest toast whose expiry has been scheduled
+}
+
+// push shows msg at sev, scrubbed of credentials like status messages.
+func (t *toasts) push(sev severity, msg string) {
+	t.seq++
+	t.items = append(t.items, toast{id: t.seq, severity: sev, message: gt.Redact(msg)})
+	if len(t.items) > toastMax {
+		t.items = t.items[len(t.items)-toastMax:]
+	}
+}
+
+// scheduleExpiry returns commands that expire the toasts pushed since the
+// last call.
+func (t *toasts) scheduleExpiry() []tea.Cmd {
+	var cmds []tea.Cmd
+	for _, item := range t.items {
+		if item.id <= t.scheduled {
+			continue
+		}
+		id := item.id
+		cmds = append(cmds, tea.Tick(toastTTL, func(time.Time) tea.Msg { return toastExpiredMsg{id: id} }))
+	}
+	t.scheduled = t.seq
+	return cmds
+}
+
+// expire removes the toast numbered id, if it is still shown.
+func (t *toasts) expire(id int) {
+	for i, item := range t.items {
+		if item.id == id {
+			t.items = append(t.items[:i:i], t.items[i+1:]...)
+			return
+		}
+	}
+}
+
+// render draws each toast as a box no wider than width.
+func (t toasts) render(width int) []string {
+	inner := max(min(toastMaxWidth, width-2*overlayMargin-overlayChromeWidth), 1)
+	boxes := make([]string, 0, len(t.items))
+	for _, item := range t.items {
+		style := statusInfoStyle
+		switch item.severity {
+		case severityError:
+			style = statusErrorStyle
+		case severityWarning:
+			style = statusWarningStyle
+		case severitySuccess:
+			style = statusSuccessStyle
+		}
+		text := lipgloss.NewStyle().Width(min(lipgloss.Width(item.message), inner)).Render(item.message)
+		boxes = append(boxes, lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(style.GetForeground()).
+			Padding(0, 1).
+			Render(style.Render(text)))
+	}
+	return boxes
+}
+
+// withToasts draws the toasts stacked in the top-right corner of screen.
+func (m Model) withToasts(screen string) string {
+	if len(m.toasts.items) == 0 || m.width == 0 {
+		return screen
+	}
+	height := strings.Count(screen, "\n") + 1
+	y := 0
+	for _, box := range m.toasts.render(m.width) {
+		x := max(m.width-lipgloss.Width(box)-overlayMargin, 0)
+		screen = placeBox(screen, box, x, y, height)
+		y += lipgloss.Height(box)
+	}
+	return screen
+}
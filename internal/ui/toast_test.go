@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// toastMessages returns the messages of the toasts m shows, oldest first.
+func toastMessages(m Model) []string {
+	var messages []string
+	for _, item := range m.toasts.items {
+		messages = append(messages, item.message)
+	}
+	return messages
+}
+
+func TestToasts_StackAndDropOldest(t *testing.T) {
+	var ts toasts
+	for _, msg := range []string{"one", "two", "three", "four"} {
+		ts.push(severityInfo, msg)
+	}
+	if len(ts.items) != toastMax || ts.items[0].message != "two" {
+		t.Errorf("items = %+v, want the newest %d", ts.items, toastMax)
+	}
+	if cmds := ts.scheduleExpiry(); len(cmds) != toastMax {
+		t.Errorf("scheduled %d expiries, want one per shown toast", len(cmds))
+	}
+	if cmds := ts.scheduleExpiry(); len(cmds) != 0 {
+		t.Errorf("scheduled %d expiries again, want none", len(cmds))
+	}
+	ts.expire(ts.items[1].id)
+	if len(ts.items) != 2 || ts.items[0].message != "two" || ts.items[1].message != "four" {
+		t.Errorf("items = %+v, want three expired", ts.items)
+	}
+}
+
+func TestToasts_ExpireMsgDismisses(t *testing.T) {
+	m := loadedModel("◉  main")
+	m.toasts.push(severityWarning, "background refresh failed")
+	updated, cmd := m.Update(nil)
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected the toast's expiry to be scheduled")
+	}
+	updated, _ = m.Update(toastExpiredMsg{id: m.toasts.items[0].id})
+	m = updated.(Model)
+	if len(m.toasts.items) != 0 {
+		t.Errorf("toasts = %q, want the expired one gone", toastMessages(m))
+	}
+}
+
+func TestToasts_DrawnTopRightOverView(t *testing.T) {
+	m := loadedModel("◉  main")
+	m.toasts.push(severityInfo, "PR #142 merged remotely")
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	if !strings.Contains(lines[1], "PR #142 merged remotely") {
+		t.Fatalf("second row should hold the toast:\n%s", strings.Join(lines, "\n"))
+	}
+	if !strings.HasSuffix(strings.TrimRight(lines[1], " "), "│") || strings.Index(lines[1], "PR #142") < m.width/3 {
+		t.Errorf("toast should be at the right edge: %q", lines[1])
+	}
+	if len(lines) != m.height {
+		t.Errorf("view has %d rows, want the toast drawn over the %d-row screen", len(lines), m.height)
+	}
+}
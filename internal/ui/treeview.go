@@ -15,22 +15,59 @@ var (
 	branchStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
 	connectorStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 	selectedBranchStyle = lipgloss.NewStyle().Bold(true).Reverse(true)
-	annotationStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
-	prOpenStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
-	prDraftStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
-	prMergedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
-	prClosedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	// plainSelectedStyle marks the cursor without reverse video, which
+	// forces a repaint of the whole highlighted span on every move.
+	plainSelectedStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+	annotationStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	prOpenStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	prDraftStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	prMergedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+	prClosedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	prQueuedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	ciPassingStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	ciFailingStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	ciPendingStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	conflictBadgeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	threadsBadgeStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	changesStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	outOfScopeStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Faint(true)
+	testPassedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	testFailedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	testRunningStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 )
 
 // displayEntry represents a branch with its visual depth for flat rendering.
 type displayEntry struct {
-	branch *gt.Branch
-	depth  int
+	branch    *gt.Branch
+	depth     int
+	pinned    bool // pinned copy shown above the tree
+	untracked bool // branch Graphite doesn't track, shown below the tree
+
+	// A group header stands for the stacks in group rather than a branch;
+	// its branch is empty. size counts the branches in the group and
+	// hasCurrent is set if one of them is checked out.
+	group      string
+	size       int
+	hasCurrent bool
+}
+
+// treeOptions adjusts how renderTreeWith draws the tree.
+type treeOptions struct {
+	plainCursor bool            // underline the cursor row instead of reverse video
+	marked      map[string]bool // branches marked for a combined diff
+	moving      string          // branch being moved; the cursor picks its new parent
+	tracking    string          // untracked branch being tracked; the cursor picks its parent
+	collapsed   map[string]bool // groups showing only their header
 }
 
 // renderTree converts display entries into a styled flat display with │ connectors.
 // The entry at the cursor index is highlighted with reverse video.
 func renderTree(entries []displayEntry, cursor int) string {
+	return renderTreeWith(entries, cursor, treeOptions{})
+}
+
+// renderTreeWith is renderTree with rendering options.
+func renderTreeWith(entries []displayEntry, cursor int, opts treeOptions) string {
 	if len(entries) == 0 {
 		return "(no stacks)"
 	}
@@ -40,14 +77,47 @@ func renderTree(entries []displayEntry, cursor int) string {
 		if i > 0 {
 			sb.WriteString("\n")
 		}
-		if e.depth > 0 {
+		if e.group != "" {
+			sb.WriteString(connectorStyle.Render(strings.Repeat("│ ", e.depth)))
+			label := groupHeaderLabel(e, opts.collapsed[e.group])
+			if i == cursor {
+				sb.WriteString(cursorStyle(opts.plainCursor).Render(label))
+			} else {
+				sb.WriteString(groupHeaderStyle.Render(label))
+			}
+			continue
+		}
+		if e.pinned {
+			sb.WriteString(pinStyle.Render(pinMarker))
+		} else if e.untracked {
+			sb.WriteString(untrackedStyle.Render(untrackedMarker))
+		} else if e.depth > 0 {
 			sb.WriteString(connectorStyle.Render(strings.Repeat("│ ", e.depth)))
 		}
 		if i == cursor {
-			sb.WriteString(selectedBranchLabel(e.branch))
+			sb.WriteString(selectedBranchLabel(e.branch, cursorStyle(opts.plainCursor)))
+		} else if e.untracked {
+			sb.WriteString(untrackedStyle.Render(e.branch.Name) + orphanLabel(e.branch))
 		} else {
 			sb.WriteString(branchLabel(e.branch))
 		}
+		if opts.marked[e.branch.Name] {
+			sb.WriteString(markStyle.Render(markLabel))
+		}
+		if opts.moving != "" && !e.pinned {
+			if e.branch.Name == opts.moving {
+				sb.WriteString(movingStyle.Render(movingLabel))
+			} else if i == cursor {
+				sb.WriteString(movingStyle.Render(moveTargetLabel))
+			}
+		}
+		if opts.tracking != "" {
+			if e.branch.Name == opts.tracking {
+				sb.WriteString(movingStyle.Render(trackingLabel))
+			} else if i == cursor && !e.untracked {
+				sb.WriteString(movingStyle.Render(trackTargetLabel))
+			}
+		}
 	}
 	return sb.String()
 }
@@ -66,7 +136,7 @@ func flattenForDisplay(branches []*gt.Branch) []displayEntry {
 // collectAll recursively collects all branches from the tree into a flat list.
 func collectAll(branches []*gt.Branch, entries *[]displayEntry) {
 	for _, b := range branches {
-		*entries = append(*entries, displayEntry{b, b.Depth})
+		*entries = append(*entries, displayEntry{branch: b, depth: b.Depth})
 		collectAll(b.Children, entries)
 	}
 }
@@ -85,11 +155,14 @@ func prLabel(pr gt.PRInfo) string {
 		return ""
 	}
 	numStr := fmt.Sprintf("#%d", pr.Number)
+	if pr.Queued && prOpen(pr) {
+		return " " + prQueuedStyle.Render(numStr+" queued") + ciLabel(pr.CI) + conflictLabel(pr.Conflicts) + threadsLabel(pr.Unresolved)
+	}
 	switch strings.ToUpper(pr.State) {
 	case "OPEN":
-		return " " + prOpenStyle.Render(numStr+" open")
+		return " " + prOpenStyle.Render(numStr+" open") + ciLabel(pr.CI) + conflictLabel(pr.Conflicts) + threadsLabel(pr.Unresolved)
 	case "DRAFT":
-		return " " + prDraftStyle.Render(numStr+" draft")
+		return " " + prDraftStyle.Render(numStr+" draft") + ciLabel(pr.CI) + conflictLabel(pr.Conflicts) + threadsLabel(pr.Unresolved)
 	case "MERGED":
 		return " " + prMergedStyle.Render(numStr+" merged")
 	case "CLOSED":
@@ -105,11 +178,14 @@ func prLabelPlain(pr gt.PRInfo) string {
 		return ""
 	}
 	numStr := fmt.Sprintf("#%d", pr.Number)
+	if pr.Queued && prOpen(pr) {
+		return " " + numStr + " queued" + ciLabelPlain(pr.CI) + conflictLabelPlain(pr.Conflicts) + threadsLabelPlain(pr.Unresolved)
+	}
 	switch strings.ToUpper(pr.State) {
 	case "OPEN":
-		return " " + numStr + " open"
+		return " " + numStr + " open" + ciLabelPlain(pr.CI) + conflictLabelPlain(pr.Conflicts) + threadsLabelPlain(pr.Unresolved)
 	case "DRAFT":
-		return " " + numStr + " draft"
+		return " " + numStr + " draft" + ciLabelPlain(pr.CI) + conflictLabelPlain(pr.Conflicts) + threadsLabelPlain(pr.Unresolved)
 	case "MERGED":
 		return " " + numStr + " merged"
 	case "CLOSED":
@@ -119,16 +195,164 @@ func prLabelPlain(pr gt.PRInfo) string {
 	}
 }
 
+// ciLabelPlain returns the CI indicator shown after an open PR: ✓ passing,
+// ✗ failing, ● running, or empty string when there are no checks.
+func ciLabelPlain(status gt.CIStatus) string {
+	switch status {
+	case gt.CIPassing:
+		return " ✓"
+	case gt.CIFailing:
+		return " ✗"
+	case gt.CIPending:
+		return " ●"
+	default:
+		return ""
+	}
+}
+
+// ciLabel returns a styled CI indicator, or empty string if none.
+func ciLabel(status gt.CIStatus) string {
+	plain := ciLabelPlain(status)
+	switch status {
+	case gt.CIPassing:
+		return " " + ciPassingStyle.Render(plain[1:])
+	case gt.CIFailing:
+		return " " + ciFailingStyle.Render(plain[1:])
+	case gt.CIPending:
+		return " " + ciPendingStyle.Render(plain[1:])
+	default:
+		return ""
+	}
+}
+
+// conflictLabelPlain returns the badge shown after an open PR that GitHub
+// can't merge cleanly into its base, or empty string if it can.
+func conflictLabelPlain(conflicts bool) string {
+	if !conflicts {
+		return ""
+	}
+	return " conflicts"
+}
+
+// conflictLabel returns a styled conflicts badge, or empty string if none.
+func conflictLabel(conflicts bool) string {
+	if !conflicts {
+		return ""
+	}
+	return " " + conflictBadgeStyle.Render("conflicts")
+}
+
+// threadsLabelPlain returns the badge counting an open PR's unresolved
+// review threads, e.g. " 2 unresolved", or empty string if there are none.
+func threadsLabelPlain(unresolved int) string {
+	if unresolved == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %d unresolved", unresolved)
+}
+
+// threadsLabel returns a styled unresolved threads badge, or empty string
+// if none.
+func threadsLabel(unresolved int) string {
+	if unresolved == 0 {
+		return ""
+	}
+	return " " + threadsBadgeStyle.Render(threadsLabelPlain(unresolved)[1:])
+}
+
+// stackLabelPlain returns a branch's review-order position in its stack,
+// e.g. " 2/3", or empty string for trunk and single-branch stacks.
+func stackLabelPlain(pos gt.StackPosition) string {
+	if pos.Size < 2 {
+		return ""
+	}
+	return fmt.Sprintf(" %d/%d", pos.Index, pos.Size)
+}
+
+// stackLabel returns a styled review-order position, or empty string if none.
+func stackLabel(pos gt.StackPosition) string {
+	if plain := stackLabelPlain(pos); plain != "" {
+		return " " + changesStyle.Render(plain[1:])
+	}
+	return ""
+}
+
+// changesLabelPlain returns an unstyled changed-file badge, e.g. "3 files",
+// or "outside scope" when the branch changes files only outside the path
+// scope. Returns "" until change info has loaded.
+func changesLabelPlain(c gt.ChangeInfo) string {
+	switch {
+	case !c.Loaded:
+		return ""
+	case c.InScope == 0 && c.Files > 0:
+		return " outside scope"
+	case c.InScope == 1:
+		return " 1 file"
+	default:
+		return fmt.Sprintf(" %d files", c.InScope)
+	}
+}
+
+// changesLabel returns a styled changed-file badge, or empty string if none.
+func changesLabel(c gt.ChangeInfo) string {
+	plain := changesLabelPlain(c)
+	if plain == "" {
+		return ""
+	}
+	if c.InScope == 0 && c.Files > 0 {
+		return " " + outOfScopeStyle.Render(plain[1:])
+	}
+	return " " + changesStyle.Render(plain[1:])
+}
+
+// testLabelPlain returns an unstyled test badge for the branch's current
+// head, or "" if the test command has not been run on it.
+func testLabelPlain(status gt.TestStatus) string {
+	switch status {
+	case gt.TestRunning:
+		return " … testing"
+	case gt.TestPassed:
+		return " ✓ tests"
+	case gt.TestFailed:
+		return " ✗ tests"
+	default:
+		return ""
+	}
+}
+
+// testLabel returns a styled test badge, or empty string if none.
+func testLabel(status gt.TestStatus) string {
+	plain := testLabelPlain(status)
+	switch status {
+	case gt.TestRunning:
+		return " " + testRunningStyle.Render(plain[1:])
+	case gt.TestPassed:
+		return " " + testPassedStyle.Render(plain[1:])
+	case gt.TestFailed:
+		return " " + testFailedStyle.Render(plain[1:])
+	default:
+		return ""
+	}
+}
+
 // branchLabel returns a styled label for a branch.
 func branchLabel(b *gt.Branch) string {
 	if b.IsCurrent {
-		return currentBranchStyle.Render("◉ "+b.Name) + annotationLabel(b) + prLabel(b.PR)
+		return currentBranchStyle.Render("◉ "+b.Name) + annotationLabel(b) + orphanLabel(b) + worktreeLabel(b) + prLabel(b.PR) + stackLabel(b.Stack) + labelsLabel(b.PR.Labels) + unsubmittedLabel(b) + changesLabel(b.Changes) + testLabel(b.Tests)
+	}
+	return branchStyle.Render("◯ "+b.Name) + annotationLabel(b) + orphanLabel(b) + worktreeLabel(b) + prLabel(b.PR) + stackLabel(b.Stack) + labelsLabel(b.PR.Labels) + unsubmittedLabel(b) + changesLabel(b.Changes) + testLabel(b.Tests)
+}
+
+// cursorStyle returns the style for the highlighted row of a list.
+func cursorStyle(plain bool) lipgloss.Style {
+	if plain {
+		return plainSelectedStyle
 	}
-	return branchStyle.Render("◯ "+b.Name) + annotationLabel(b) + prLabel(b.PR)
+	return selectedBranchStyle
 }
 
 // selectedBranchLabel returns a highlighted label for the cursor-selected branch.
-func selectedBranchLabel(b *gt.Branch) string {
+func selectedBranchLabel(b *gt.Branch, style lipgloss.Style) string {
 	marker := "◯ "
 	if b.IsCurrent {
 		marker = "◉ "
@@ -137,6 +361,13 @@ func selectedBranchLabel(b *gt.Branch) string {
 	if b.Annotation != "" {
 		label += " (" + b.Annotation + ")"
 	}
+	label += orphanLabelPlain(b)
+	label += worktreeLabelPlain(b)
 	label += prLabelPlain(b.PR)
-	return selectedBranchStyle.Render(label)
+	label += stackLabelPlain(b.Stack)
+	label += labelsLabelPlain(b.PR.Labels)
+	label += unsubmittedLabelPlain(b)
+	label += changesLabelPlain(b.Changes)
+	label += testLabelPlain(b.Tests)
+	return style.Render(label)
 }
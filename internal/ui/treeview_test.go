@@ -253,6 +253,24 @@ func TestRenderTree_CursorOutOfRange(t *testing.T) {
 	}
 }
 
+func TestRenderTreeWith_PlainCursor(t *testing.T) {
+	entries := []displayEntry{
+		{branch: &gt.Branch{Name: "main"}, depth: 0},
+		{branch: &gt.Branch{Name: "feature-a"}, depth: 1},
+	}
+
+	result := renderTreeWith(entries, 1, treeOptions{plainCursor: true})
+	if !strings.Contains(result, plainSelectedStyle.Render("◯ feature-a")) {
+		t.Errorf("cursor row should use the plain style, got %q", result)
+	}
+	if cursorStyle(true).GetReverse() {
+		t.Error("plain cursor style must not use reverse video")
+	}
+	if !cursorStyle(false).GetReverse() {
+		t.Error("default cursor style should use reverse video")
+	}
+}
+
 func TestRenderTree_WithAnnotation(t *testing.T) {
 	entries := []displayEntry{
 		{branch: &gt.Branch{Name: "main"}, depth: 0},
@@ -342,10 +360,20 @@ func TestPrLabel_States(t *testing.T) {
 		{gt.PRInfo{Number: 99, State: "DRAFT"}, "#99 draft"},
 		{gt.PRInfo{Number: 10, State: "MERGED"}, "#10 merged"},
 		{gt.PRInfo{Number: 5, State: "CLOSED"}, "#5 closed"},
+		{gt.PRInfo{Number: 42, State: "OPEN", CI: gt.CIPassing}, "#42 open ✓"},
+		{gt.PRInfo{Number: 42, State: "OPEN", CI: gt.CIFailing}, "#42 open ✗"},
+		{gt.PRInfo{Number: 99, State: "DRAFT", CI: gt.CIPending}, "#99 draft ●"},
+		{gt.PRInfo{Number: 10, State: "MERGED", CI: gt.CIFailing}, "#10 merged"},
+		{gt.PRInfo{Number: 42, State: "OPEN", CI: gt.CIFailing, Conflicts: true}, "#42 open ✗ conflicts"},
+		{gt.PRInfo{Number: 99, State: "DRAFT", Conflicts: true}, "#99 draft conflicts"},
+		{gt.PRInfo{Number: 10, State: "MERGED", Conflicts: true}, "#10 merged"},
 		{gt.PRInfo{}, ""},
 	}
 
 	for _, tt := range tests {
+		if plain := strings.TrimSpace(prLabelPlain(tt.pr)); plain != tt.want {
+			t.Errorf("prLabelPlain(%v) = %q, want %q", tt.pr, plain, tt.want)
+		}
 		got := ansi.Strip(prLabel(tt.pr))
 		got = strings.TrimSpace(got)
 		if got != tt.want {
@@ -545,3 +573,13 @@ func TestFlattenForDisplay_BranchingStackDepths(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderTree_ShowsReviewOrder(t *testing.T) {
+	branches, _ := gt.ParseLogShort("◯    solo\n│ ◉  top\n│ ◯  base\n◯─┘  main")
+	branches[0].Children[0].PR = gt.PRInfo{Number: 7, State: "OPEN"}
+	got := ansi.Strip(renderTree(flattenForDisplay(branches), -1))
+	want := "◯ solo\n│ ◉ top 2/2\n│ ◯ base #7 open 1/2\n◯ main"
+	if got != want {
+		t.Errorf("renderTree() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var tutorialStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+
+// tutorialMark is the model state when a tutorial step began, which the
+// step's completion check compares against.
+type tutorialMark struct {
+	cursor  int
+	current string
+}
+
+// tutorialStep is one guided instruction of --tutorial.
+type tutorialStep struct {
+	text func(k keyMap) string
+	done func(m Model, start tutorialMark) bool
+}
+
+// tutorialSteps walk through navigating, checking out, diffing and
+// submitting. The last step has no completion check.
+var tutorialSteps = []tutorialStep{
+	{
+		text: func(k keyMap) string {
+			return fmt.Sprintf("Move the cursor with %s and %s.", k.Down.Help().Key, k.Up.Help().Key)
+		},
+		done: func(m Model, start tutorialMark) bool { return m.cursor != start.cursor },
+	},
+	{
+		text: func(k keyMap) string {
+			return fmt.Sprintf("Press %s to check out the branch under the cursor.", k.Checkout.Help().Key)
+		},
+		done: func(m Model, start tutorialMark) bool {
+			return !m.running && currentBranchName(m) != start.current
+		},
+	},
+	{
+		text: func(k keyMap) string {
+			return fmt.Sprintf("Press %s on a branch to see its diff against its parent.", k.Diff.Help().Key)
+		},
+		done: func(m Model, start tutorialMark) bool { return m.mode == modeDiff },
+	},
+	{
+		text: func(k keyMap) string {
+			return fmt.Sprintf("%s switches panels, %s/%s scroll. Press esc to close the diff.", k.Tab.Help().Key, k.Down.Help().Key, k.Up.Help().Key)
+		},
+		done: func(m Model, start tutorialMark) bool { return m.mode == modeTree },
+	},
+	{
+		text: func(k keyMap) string {
+			return fmt.Sprintf("Press %s on a branch to submit its stack. grit shows the gt command first.", k.StackSubmit.Help().Key)
+		},
+		done: func(m Model, start tutorialMark) bool { return m.mode == modeConfirm },
+	},
+	{
+		text: func(k keyMap) string {
+			return "The sandbox has no remote, so press esc to cancel the submit."
+		},
+		done: func(m Model, start tutorialMark) bool { return m.mode == modeTree },
+	},
+	{
+		text: func(k keyMap) string {
+			return fmt.Sprintf("Done! Press %s for every key, %s to quit. The sandbox is deleted on exit.", k.Help.Help().Key, k.Quit.Help().Key)
+		},
+	},
+}
+
+// tutorial tracks progress through tutorialSteps.
+type tutorial struct {
+	active bool
+	begun  bool // start has been marked for the first step
+	step   int
+	start  tutorialMark
+}
+
+// WithTutorial returns m with the guided tutorial shown above every view.
+// Actions ask for confirmation of their commands, so each step shows
+// what gt would run.
+func (m Model) WithTutorial() Model {
+	m.tutorial = tutorial{active: true}
+	m.confirmCommands = true
+	return m
+}
+
+// markTutorial snapshots the state a step's check compares against.
+func (m Model) markTutorial() tutorialMark {
+	return tutorialMark{cursor: m.cursor, current: currentBranchName(m)}
+}
+
+// advanceTutorial moves to the next step once the current one is done.
+// Nothing counts until the tree has loaded.
+func (m *Model) advanceTutorial() {
+	if !m.tutorial.active || !m.loaded || m.tutorial.step >= len(tutorialSteps)-1 {
+		return
+	}
+	if !m.tutorial.begun {
+		m.tutorial.begun = true
+		m.tutorial.start = m.markTutorial()
+		return
+	}
+	if done := tutorialSteps[m.tutorial.step].done; done != nil && done(*m, m.tutorial.start) {
+		m.tutorial.step++
+		m.tutorial.start = m.markTutorial()
+	}
+}
+
+// tutorialView renders the current instruction, or "" without a tutorial.
+func (m Model) tutorialView() string {
+	if !m.tutorial.active {
+		return ""
+	}
+	text := fmt.Sprintf("Tutorial %d/%d · %s", m.tutorial.step+1, len(tutorialSteps), tutorialSteps[m.tutorial.step].text(m.keys))
+	return lipgloss.NewStyle().Width(m.width).Padding(0, 1).Render(tutorialStyle.Render(truncateToWidth(text, m.width-2)))
+}
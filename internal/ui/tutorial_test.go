@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func tutorialModel() Model {
+	m := newTestModel("", nil).WithTutorial()
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{output: "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main"})
+	return updated.(Model)
+}
+
+func TestTutorial_Steps(t *testing.T) {
+	m := tutorialModel()
+	if !strings.Contains(m.tutorialView(), "Tutorial 1/") {
+		t.Fatalf("view = %q", m.tutorialView())
+	}
+
+	m = sendKey(m, 'j')
+	if m.tutorial.step != 1 {
+		t.Fatalf("moving the cursor should finish step 1, step = %d", m.tutorial.step)
+	}
+
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.mode != modeConfirm {
+		t.Fatalf("checkout should ask for confirmation in the tutorial, mode = %d", m.mode)
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	updated, _ := m.Update(actionResultMsg{action: "checkout", message: "Checked out feature-base"})
+	m = updated.(Model)
+	updated, _ = m.Update(logResultMsg{output: "│ ◯  feature-top\n│ ◉  feature-base\n◯─┘  main"})
+	m = updated.(Model)
+	if m.tutorial.step != 2 {
+		t.Fatalf("checking out should finish step 2, step = %d", m.tutorial.step)
+	}
+
+	m = sendKey(m, 'd')
+	updated, _ = m.Update(diffDataMsg{
+		branchName:   "feature-base",
+		parentBranch: "main",
+		files:        []diffFileEntry{{path: "model.go", summary: "5 +++--"}},
+	})
+	m = updated.(Model)
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.tutorial.step != 4 {
+		t.Fatalf("opening and closing the diff should finish steps 3 and 4, step = %d", m.tutorial.step)
+	}
+
+	m = sendKey(m, 's')
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.running {
+		t.Error("cancelled submit should not run")
+	}
+	if m.tutorial.step != len(tutorialSteps)-1 {
+		t.Fatalf("step = %d, want the last step", m.tutorial.step)
+	}
+	if !strings.Contains(m.tutorialView(), "Done!") {
+		t.Errorf("view = %q", m.tutorialView())
+	}
+}
+
+func TestTutorial_Inactive(t *testing.T) {
+	m := loadedModel("│ ◉  feature-top\n◯─┘  main")
+	if m.tutorialView() != "" || m.tutorialHeight() != 0 {
+		t.Error("no tutorial line without --tutorial")
+	}
+	if strings.Contains(m.View(), "Tutorial") {
+		t.Error("view should not mention the tutorial")
+	}
+
+	m = tutorialModel()
+	if m.tutorialHeight() != 1 {
+		t.Errorf("tutorialHeight = %d, want 1", m.tutorialHeight())
+	}
+	if !strings.Contains(m.View(), "Tutorial 1/") {
+		t.Error("view should show the tutorial line")
+	}
+}
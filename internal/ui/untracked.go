@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var untrackedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+const (
+	// untrackedMarker prefixes rows for branches Graphite doesn't track,
+	// shown below the tree.
+	untrackedMarker = "? "
+	// trackingLabel is appended to the branch being tracked.
+	trackingLabel = "  ⇣ tracking"
+	// trackTargetLabel is appended to the cursor row while picking a parent.
+	trackTargetLabel = "  ← parent"
+)
+
+// withUntracked appends an entry for each untracked branch after the tree,
+// flagging those orphans names.
+func withUntracked(entries []displayEntry, untracked []string, orphans map[string]string) []displayEntry {
+	for _, name := range untracked {
+		entries = append(entries, displayEntry{branch: &gt.Branch{Name: name, Orphan: orphans[name]}, untracked: true})
+	}
+	return entries
+}
+
+// buildEntries flattens the tree for display, with pins above it and
+// untracked branches below, dropping the branches the filter hides and
+// gathering grouped stacks under their headers.
+func (m Model) buildEntries() []displayEntry {
+	entries := withUntracked(withPins(flattenForDisplay(m.branches), m.pins), m.untracked, m.orphans)
+	return withGroups(withFilter(entries, m.branches, m.filter), m.branches, m.groups, m.collapsed)
+}
+
+// needsTracking reports whether msg is a branch action that only works on
+// branches Graphite tracks.
+func (m Model) needsTracking(msg tea.KeyMsg) bool {
+	s := m.specFor(msg)
+	return s != nil && s.tracked
+}
+
+// selectedUntracked returns the untracked branch at the cursor, or "".
+func (m Model) selectedUntracked() string {
+	if m.cursor >= 0 && m.cursor < len(m.displayEntries) && m.displayEntries[m.cursor].untracked {
+		return m.displayEntries[m.cursor].branch.Name
+	}
+	return ""
+}
+
+// beginTrack starts picking a parent for the untracked branch name with
+// the cursor.
+func (m *Model) beginTrack(name string) {
+	m.tracking = name
+	m.viewport.SetContent(m.treeContent())
+	m.statusBar.setStatus(severityInfo, "Tracking "+name+" — pick its parent and press enter")
+}
+
+// cancelTrack leaves parent selection, returning the cursor to the
+// untracked branch.
+func (m *Model) cancelTrack() {
+	name := m.tracking
+	m.tracking = ""
+	m.preserveCursor(name)
+	m.viewport.SetContent(m.treeContent())
+	m.ensureCursorVisible()
+	m.statusBar.setStatus(severityInfo, "Track cancelled")
+}
+
+// finishTrack tracks the branch being tracked on top of the branch at the
+// cursor. The cursor follows the newly tracked branch into the tree.
+func (m *Model) finishTrack() []tea.Cmd {
+	target := m.selectedBranch()
+	if target == nil {
+		return nil
+	}
+	if m.selectedUntracked() != "" {
+		m.statusBar.setStatus(severityWarning, "Pick a tracked branch as the parent")
+		return nil
+	}
+	name, parent := m.tracking, target.Name
+	if reason := trackRefusal(m.branches, name, parent); reason != "" {
+		m.statusBar.setStatus(severityWarning, reason)
+		return nil
+	}
+	m.tracking = ""
+	m.cursorTarget = name
+	m.viewport.SetContent(m.treeContent())
+	return m.startAction("track", "Tracked "+name+" on "+parent, "Tracking "+name+"...", "", func(ctx context.Context, client *gt.Client) error {
+		return client.Track(ctx, name, parent)
+	})
+}
+
+// startUntrack asks to confirm untracking b and the branches stacked on
+// it, which stay in git and move to the untracked list.
+func (m *Model) startUntrack(b *gt.Branch) []tea.Cmd {
+	name := b.Name
+	var above []*gt.Branch
+	collectDescendants(b, &above)
+	warning := "Graphite stops tracking " + name + "; the git branch is kept."
+	if len(above) > 0 {
+		warning = fmt.Sprintf("Graphite stops tracking %s and the %d branches stacked on it (%s); the git branches are kept.",
+			name, len(above), strings.Join(branchNames(above), ", "))
+	}
+	m.cursorTarget = name
+	return m.startAction("untrack", "Untracked "+name, "Untracking "+name+"...", warning, func(ctx context.Context, client *gt.Client) error {
+		return client.Untrack(ctx, name)
+	})
+}
+
+// trackRefusal explains why name can't be tracked on parent, or returns ""
+// if it can. Only an orphaned branch that is still in the tree can be
+// refused, by picking itself or a branch stacked on it.
+func trackRefusal(branches []*gt.Branch, name, parent string) string {
+	if parent == name {
+		return "Cannot track " + name + " on itself"
+	}
+	var above []*gt.Branch
+	if b := gt.FindBranch(branches, name); b != nil {
+		collectDescendants(b, &above)
+	}
+	for _, b := range above {
+		if b.Name == parent {
+			return "Cannot track " + name + " on " + parent + ", which is stacked on it"
+		}
+	}
+	return ""
+}
+
+func (m Model) trackLegendView() string {
+	pairs := []struct{ key, desc string }{
+		{"↑↓", "pick parent"},
+		{"enter", "track"},
+		{"esc", "cancel"},
+		{"q", "quit"},
+	}
+	return renderLegend(pairs, m.width)
+}
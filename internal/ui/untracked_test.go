@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// untrackedModel has feature-a tracked on main and hotfix only in git.
+// Entries: 0 = feature-a, 1 = main, 2 = hotfix.
+func untrackedModel(t *testing.T) (Model, *[]callRecord) {
+	t.Helper()
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{
+		output: "│ ◉  feature-a\n◯─┘  main",
+		heads:  map[string]string{"main": "a", "feature-a": "b", "hotfix": "c"},
+	})
+	m = updated.(Model)
+	if len(m.displayEntries) != 3 || !m.displayEntries[2].untracked {
+		t.Fatalf("entries = %v, want hotfix listed untracked last", m.displayEntries)
+	}
+	return m, calls
+}
+
+func TestUntracked_ListedBelowTree(t *testing.T) {
+	m, _ := untrackedModel(t)
+	lines := strings.Split(m.treeContent(), "\n")
+	if last := lines[len(lines)-1]; !strings.Contains(last, untrackedMarker+"hotfix") {
+		t.Errorf("last line = %q, want the untracked hotfix", last)
+	}
+}
+
+func TestHasStacks_IgnoresUntracked(t *testing.T) {
+	entries := withUntracked(flattenForDisplay([]*gt.Branch{{Name: "main"}}), []string{"wip"}, nil)
+	if hasStacks(entries) {
+		t.Error("an untracked branch is not a stack")
+	}
+}
+
+func TestTrackKey_PicksParentAndTracks(t *testing.T) {
+	m, calls := untrackedModel(t)
+	m.cursor = 2
+	m = sendKey(m, 'T')
+	if m.tracking != "hotfix" {
+		t.Fatalf("tracking = %q, want hotfix", m.tracking)
+	}
+
+	// An untracked branch can't be the parent.
+	updated, _ := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	if m.tracking != "hotfix" || !strings.Contains(m.statusBar.message, "tracked branch") {
+		t.Fatalf("tracking = %q, message = %q", m.tracking, m.statusBar.message)
+	}
+
+	m = sendSpecialKey(m, tea.KeyUp) // main
+	if !strings.Contains(m.treeContent(), trackTargetLabel) {
+		t.Error("cursor row should be labelled as the parent")
+	}
+	*calls = nil
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	if m.tracking != "" || !m.running {
+		t.Fatalf("tracking = %q, running = %v after enter", m.tracking, m.running)
+	}
+	runBatch(cmd)
+	want := callRecord{name: "gt", args: []string{"track", "hotfix", "--parent", "main", "--no-interactive"}}
+	if len(*calls) == 0 || !reflect.DeepEqual((*calls)[0], want) {
+		t.Errorf("calls = %v, want %v first", *calls, want)
+	}
+}
+
+func TestTrackKey_EscCancels(t *testing.T) {
+	m, _ := untrackedModel(t)
+	m.cursor = 2
+	m = sendKey(m, 'T')
+	m = sendSpecialKey(m, tea.KeyUp)
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.tracking != "" || m.cursor != 2 || m.statusBar.message != "Track cancelled" {
+		t.Errorf("tracking = %q, cursor = %d, message = %q", m.tracking, m.cursor, m.statusBar.message)
+	}
+}
+
+func TestTrackKey_UntracksTrackedBranch(t *testing.T) {
+	m, calls := untrackedModel(t)
+	m = sendKey(m, 'T') // feature-a
+	if m.mode != modeConfirm || !containsString(m.View(), "gt untrack feature-a --force") {
+		t.Fatalf("untrack should confirm first, mode = %d:\n%s", m.mode, m.View())
+	}
+	*calls = nil
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runBatch(cmd)
+	m = updated.(Model)
+	want := callRecord{name: "gt", args: []string{"untrack", "feature-a", "--force", "--no-interactive"}}
+	if len(*calls) == 0 || !reflect.DeepEqual((*calls)[0], want) {
+		t.Errorf("calls = %v, want %v first", *calls, want)
+	}
+}
+
+func TestTrackKey_TrunkCannotBeUntracked(t *testing.T) {
+	m, _ := untrackedModel(t)
+	m.cursor = 1 // main
+	m = sendKey(m, 'T')
+	if m.mode != modeTree || m.statusBar.message != "Cannot untrack trunk branch" {
+		t.Errorf("mode = %d, message = %q", m.mode, m.statusBar.message)
+	}
+}
+
+func TestUntracked_BranchActionsNeedTracking(t *testing.T) {
+	m, _ := untrackedModel(t)
+	m.cursor = 2
+	m = sendKey(m, 'M')
+	if m.moving != "" || !strings.Contains(m.statusBar.message, "press T to track it") {
+		t.Errorf("moving = %q, message = %q", m.moving, m.statusBar.message)
+	}
+}
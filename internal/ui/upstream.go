@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// upstreamCommitsShown caps how many remote-only commits are listed per
+// branch in the warning.
+const upstreamCommitsShown = 5
+
+// upstreamChanges are commits on a branch's remote copy that the local
+// branch lacks, e.g. a collaborator's push to the PR.
+type upstreamChanges struct {
+	branch  string
+	commits []string // "<sha> <author>: <subject>", newest first
+	stat    string   // diffstat summary line of the remote-only changes
+}
+
+// upstreamCheckMsg carries the remote-only commits found before a submit.
+type upstreamCheckMsg struct {
+	pending  pendingSubmit
+	diverged []upstreamChanges
+}
+
+// checkUpstream fetches the remote copy of each branch in names and looks
+// for commits the local branch lacks, which submitting would force-push
+// over. Branches that can't be fetched (e.g. deleted on the remote) are
+// skipped: there is nothing there to lose.
+func (m Model) checkUpstream(p pendingSubmit, names []string) tea.Cmd {
+	client := m.gtClient
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		var diverged []upstreamChanges
+		for _, name := range names {
+			if err := client.FetchBranch(ctx, name); err != nil {
+				continue
+			}
+			commits, err := client.CommitSummaries(ctx, name, remoteRef(name))
+			if err != nil || len(commits) == 0 {
+				continue
+			}
+			c := upstreamChanges{branch: name, commits: commits}
+			if stat, err := client.DiffStat(ctx, name, remoteRef(name)); err == nil && strings.TrimSpace(stat) != "" {
+				c.stat = lastLine(stat)
+			}
+			diverged = append(diverged, c)
+		}
+		return upstreamCheckMsg{pending: p, diverged: diverged}
+	}
+}
+
+// pushedBranches returns the names of targets with open PRs, the branches
+// others may have pushed to.
+func pushedBranches(targets []*gt.Branch) []string {
+	var names []string
+	for _, b := range targets {
+		if prOpen(b.PR) {
+			names = append(names, b.Name)
+		}
+	}
+	return names
+}
+
+// confirmUpstream continues the checked submit, first asking for
+// confirmation when the remote has commits the submit would overwrite.
+func (m *Model) confirmUpstream(msg upstreamCheckMsg) []tea.Cmd {
+	p := msg.pending
+	if len(msg.diverged) == 0 {
+		return m.preflightSubmit(p)
+	}
+	var names []string
+	for _, c := range msg.diverged {
+		names = append(names, c.branch)
+	}
+	m.askConfirm(pendingAction{
+		desc:     p.spinnerLabel,
+		warning:  "Someone else pushed to " + strings.Join(names, ", ") + ". Submitting force-pushes over their commits.",
+		detail:   renderUpstreamChanges(msg.diverged),
+		commands: previewCommands(p.submit),
+		run:      func(m *Model) []tea.Cmd { return m.preflightSubmit(p) },
+	})
+	return nil
+}
+
+// renderUpstreamChanges lists each branch's remote-only commits and the
+// size of their changes.
+func renderUpstreamChanges(diverged []upstreamChanges) string {
+	var sb strings.Builder
+	for i, c := range diverged {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(helpTitleStyle.Render(fmt.Sprintf("%s has %s not in %s", remoteRef(c.branch), pluralize(len(c.commits), "commit"), c.branch)))
+		for j, commit := range c.commits {
+			if j == upstreamCommitsShown {
+				sb.WriteString("\n" + helpDescStyle.Render(fmt.Sprintf("  … and %d more", len(c.commits)-j)))
+				break
+			}
+			sb.WriteString("\n  " + commit)
+		}
+		if c.stat != "" {
+			sb.WriteString("\n" + helpDescStyle.Render("  "+c.stat))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// finishUpstreamCheck runs the remote branch check that cmd, a submit's
+// batch, started and feeds its result back to m.
+func finishUpstreamCheck(t *testing.T, m Model, cmd tea.Cmd) (Model, tea.Cmd) {
+	t.Helper()
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("expected a batch")
+	}
+	for _, c := range batch {
+		if c == nil {
+			continue
+		}
+		if msg, ok := c().(upstreamCheckMsg); ok {
+			updated, next := m.Update(msg)
+			return updated.(Model), next
+		}
+	}
+	t.Fatal("no remote branch check in the batch")
+	return m, nil
+}
+
+// upstreamMock answers the remote branch check with remote-only commits
+// per branch, recording every call.
+func upstreamMock(remote map[string]string) (*mockExecutor, *[]callRecord) {
+	var calls []callRecord
+	return &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		calls = append(calls, callRecord{name: name, args: args})
+		switch {
+		case name == "git" && args[0] == "log":
+			return remote[args[len(args)-1]], nil
+		case name == "git" && args[0] == "diff":
+			return " a.go | 3 ++-\n 1 file changed, 2 insertions(+), 1 deletion(-)\n", nil
+		}
+		return "", nil
+	}}, &calls
+}
+
+func TestSubmit_ChecksRemoteOnlyForOpenPRs(t *testing.T) {
+	mock, calls := recordingMock()
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.gtClient = gt.New(mock)
+	m.cursor = 0 // feature-top
+	gt.FindBranch(m.branches, "feature-base").PR = gt.PRInfo{Number: 1, State: "OPEN"}
+	gt.FindBranch(m.branches, "feature-top").PR = gt.PRInfo{Number: 2, State: "MERGED"}
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'s'}}))
+	m = updated.(Model)
+	if !m.running {
+		t.Fatal("the check should run")
+	}
+	m, cmd = finishUpstreamCheck(t, m, cmd)
+	want := []callRecord{
+		{name: "git", args: []string{"fetch", "origin", "feature-base"}},
+		{name: "git", args: []string{"log", "--format=%h %an: %s", "feature-base..origin/feature-base"}},
+	}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Errorf("calls = %v, want %v", *calls, want)
+	}
+	if m.mode != modeTree || !m.running {
+		t.Fatalf("nothing diverged, so the submit should start: mode = %v running = %v", m.mode, m.running)
+	}
+	runBatch(cmd)
+	if last := (*calls)[len(*calls)-1]; last.name != "gt" || !slices.Contains(last.args, "submit") {
+		t.Errorf("last call = %v, want the submit", last)
+	}
+}
+
+func TestSubmit_WarnsAboutRemoteOnlyCommits(t *testing.T) {
+	mock, calls := upstreamMock(map[string]string{
+		"feature-top..origin/feature-top": "abc1234 alice: address review\ndef5678 alice: fix typo\n",
+	})
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.gtClient = gt.New(mock)
+	m.cursor = 0 // feature-top
+	gt.FindBranch(m.branches, "feature-top").PR = gt.PRInfo{Number: 2, State: "OPEN"}
+
+	_, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'s'}}))
+	m, _ = finishUpstreamCheck(t, m, cmd)
+	if m.mode != modeConfirm || m.running {
+		t.Fatalf("mode = %v running = %v, want a confirmation", m.mode, m.running)
+	}
+	view := ansi.Strip(m.View())
+	for _, want := range []string{
+		"Someone else pushed to feature-top",
+		"origin/feature-top has 2 commits not in feature-top",
+		"abc1234 alice: address review",
+		"1 file changed, 2 insertions(+), 1 deletion(-)",
+		"$ gt stack submit",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	*calls = nil
+	m = sendSpecialKey(m, tea.KeyEscape)
+	if m.mode != modeTree || m.running || len(*calls) != 0 {
+		t.Errorf("esc should cancel: mode = %v running = %v calls = %v", m.mode, m.running, *calls)
+	}
+}
+
+func TestSubmit_ConfirmedOverRemoteOnlyCommits(t *testing.T) {
+	mock, calls := upstreamMock(map[string]string{
+		"feature-top..origin/feature-top": "abc1234 alice: address review\n",
+	})
+	m := loadedModel("│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main")
+	m.gtClient = gt.New(mock)
+	m.cursor = 0 // feature-top
+	gt.FindBranch(m.branches, "feature-top").PR = gt.PRInfo{Number: 2, State: "OPEN"}
+
+	_, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'s'}}))
+	m, _ = finishUpstreamCheck(t, m, cmd)
+	*calls = nil
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	if !m.running {
+		t.Fatal("enter should start the submit")
+	}
+	runBatch(cmd)
+	want := callRecord{name: "gt", args: []string{"stack", "submit", "--no-interactive", "--branch", "feature-top"}}
+	if len(*calls) != 1 || !reflect.DeepEqual((*calls)[0], want) {
+		t.Errorf("calls = %v, want %v", *calls, want)
+	}
+}
+
+func TestRenderUpstreamChanges_CapsCommits(t *testing.T) {
+	var commits []string
+	for i := range 8 {
+		commits = append(commits, "c"+string(rune('0'+i))+" bob: change")
+	}
+	out := ansi.Strip(renderUpstreamChanges([]upstreamChanges{{branch: "a", commits: commits}}))
+	if !strings.Contains(out, "c4 bob") || strings.Contains(out, "c5 bob") || !strings.Contains(out, "… and 3 more") {
+		t.Errorf("out = %q", out)
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/elliotb/grit/internal/hooks"
 )
 
 // gitChangeMsg is sent when the file watcher detects a change in .git.
@@ -49,6 +51,14 @@ func createWatcher(gitDir string) (*fsnotify.Watcher, error) {
 		}
 	}
 
+	// The sentinel `grit hooks` has git hooks write, for filesystems where
+	// the refs/ events above don't arrive. It's a file of its own so grit's
+	// writes to the rest of .git/grit don't trigger reloads.
+	sentinel := filepath.Join(gitDir, hooks.SentinelFile)
+	if _, err := os.Stat(sentinel); err == nil {
+		_ = watcher.Add(sentinel)
+	}
+
 	if len(watcher.WatchList()) == 0 {
 		watcher.Close()
 		return nil, fmt.Errorf("no watchable paths found in %s", gitDir)
@@ -57,6 +67,16 @@ func createWatcher(gitDir string) (*fsnotify.Watcher, error) {
 	return watcher, nil
 }
 
+// CanWatch reports whether gitDir can be watched the way grit watches it,
+// for `grit doctor`.
+func CanWatch(gitDir string) error {
+	watcher, err := createWatcher(gitDir)
+	if err != nil {
+		return err
+	}
+	return watcher.Close()
+}
+
 // waitForChange returns a tea.Cmd that blocks until the watcher fires
 // an event or error, then sends the appropriate message.
 func waitForChange(watcher *fsnotify.Watcher) tea.Cmd {
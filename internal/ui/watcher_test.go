@@ -8,8 +8,10 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
 
 	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/hooks"
 )
 
 // setupFakeGitDir creates a minimal .git directory structure for watcher tests.
@@ -155,6 +157,37 @@ func TestCreateWatcher_ValidGitDir(t *testing.T) {
 	}
 }
 
+func TestCreateWatcher_HookSentinel(t *testing.T) {
+	dir := t.TempDir() // only the sentinel, as if refs/ couldn't be watched
+	if _, err := hooks.Install(filepath.Join(dir, "hooks"), dir); err != nil {
+		t.Fatal(err)
+	}
+	watcher, err := createWatcher(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer watcher.Close()
+
+	if err := os.WriteFile(filepath.Join(dir, hooks.SentinelFile), []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-watcherMsgs(watcher):
+		if _, ok := msg.(gitChangeMsg); !ok {
+			t.Errorf("msg = %T, want gitChangeMsg", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("writing the sentinel should fire the watcher")
+	}
+}
+
+// watcherMsgs runs waitForChange in the background.
+func watcherMsgs(w *fsnotify.Watcher) <-chan tea.Msg {
+	ch := make(chan tea.Msg, 1)
+	go func() { ch <- waitForChange(w)() }()
+	return ch
+}
+
 func TestCreateWatcher_EmptyDir_NoWatchablePaths(t *testing.T) {
 	dir := t.TempDir() // No HEAD or refs/heads
 	_, err := createWatcher(dir)
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+var worktreeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+
+// Choices offered when checking out a branch another worktree has.
+const (
+	worktreeCopyCd = "copy cd command"
+	worktreeShell  = "open a shell there"
+)
+
+// worktreeShellDoneMsg is sent when the shell opened in a worktree exits.
+type worktreeShellDoneMsg struct {
+	path string
+	err  error
+}
+
+// worktreeLabel returns a styled flag for a branch checked out in another
+// worktree, or empty string if it isn't.
+func worktreeLabel(b *gt.Branch) string {
+	if b.Worktree == "" {
+		return ""
+	}
+	return " " + worktreeStyle.Render(worktreeLabelPlain(b)[1:])
+}
+
+// worktreeLabelPlain returns an unstyled worktree flag for use in
+// reverse-video labels.
+func worktreeLabelPlain(b *gt.Branch) string {
+	if b.Worktree == "" {
+		return ""
+	}
+	return " ⌂ in worktree"
+}
+
+// inOtherWorktree reports whether another worktree has name checked out,
+// where git won't check it out here, and if so offers to go there
+// instead. Every action that checks a branch out asks it first.
+func (m *Model) inOtherWorktree(name string) bool {
+	path := m.worktrees[name]
+	if path == "" {
+		return false
+	}
+	m.offerWorktree(name, path)
+	return true
+}
+
+// offerWorktree explains that name can't be checked out here because the
+// worktree at path has it, and offers to go there instead: copy a cd
+// command, or open a shell in it.
+func (m *Model) offerWorktree(name, path string) {
+	cd := gt.FormatCommand("cd", path)
+	m.openPicker(picker{
+		title:   name + " is checked out in " + path,
+		noun:    "choices",
+		items:   []pickerItem{{name: worktreeCopyCd}, {name: worktreeShell}},
+		ordered: true,
+		pick: func(m *Model, item pickerItem) []tea.Cmd {
+			if item.name == worktreeShell {
+				return []tea.Cmd{openShell(path)}
+			}
+			m.statusBar.setStatus(severitySuccess, yankDescription(cd, "cd command"))
+			return []tea.Cmd{m.yank(cd)}
+		},
+		preview: func(_ Model, item pickerItem) string {
+			if item.name == worktreeShell {
+				return helpDescStyle.Render("Suspends grit until the shell exits")
+			}
+			return helpDescStyle.Render(cd)
+		},
+	})
+}
+
+// shellCommand returns the user's $SHELL, or sh, started in dir.
+func shellCommand(getenv func(string) string, dir string) *exec.Cmd {
+	shell := getenv("SHELL")
+	if shell == "" {
+		shell = "sh"
+	}
+	cmd := exec.Command(shell)
+	cmd.Dir = dir
+	return cmd
+}
+
+// openShell runs a shell in path attached to the terminal, suspending the
+// UI until it exits.
+func openShell(path string) tea.Cmd {
+	return tea.ExecProcess(shellCommand(os.Getenv, path), func(err error) tea.Msg {
+		return worktreeShellDoneMsg{path: path, err: err}
+	})
+}
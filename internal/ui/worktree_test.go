@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+func TestLoadLog_FindsBranchesInOtherWorktrees(t *testing.T) {
+	mock := &mockExecutor{fn: func(ctx context.Context, name string, args ...string) (string, error) {
+		switch {
+		case name == "gt":
+			return "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main", nil
+		case args[0] == "symbolic-ref":
+			return "feature-top\n", nil
+		case args[0] == "worktree":
+			return "worktree /src/app\nbranch refs/heads/feature-top\n\nworktree /src/app-review\nbranch refs/heads/feature-base\n", nil
+		}
+		return "", errors.New("unexpected")
+	}}
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(m.loadLog()())
+	m = updated.(Model)
+
+	if m.worktrees["feature-base"] != "/src/app-review" || m.worktrees["feature-top"] != "" {
+		t.Errorf("worktrees = %v, want only feature-base elsewhere", m.worktrees)
+	}
+	if view := ansi.Strip(m.viewport.View()); !strings.Contains(view, "feature-base ⌂ in worktree") {
+		t.Errorf("tree should flag feature-base:\n%s", view)
+	}
+}
+
+func TestCheckout_OffersOtherWorktree(t *testing.T) {
+	mock, calls := recordingMock()
+	m := New(gt.New(mock), "")
+	m = sendWindowSize(m, 80, 24)
+	updated, _ := m.Update(logResultMsg{
+		output:    "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main",
+		worktrees: map[string]string{"feature-base": "/src/app review"},
+	})
+	m = updated.(Model)
+	var copied string
+	m.copyText = func(s string) { copied = s }
+	m.cursor = 1
+
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if m.running || len(*calls) != 0 {
+		t.Fatalf("checkout should not run, calls = %v", *calls)
+	}
+	if m.mode != modePicker || m.picker.title != "feature-base is checked out in /src/app review" {
+		t.Fatalf("mode = %d, picker title = %q", m.mode, m.picker.title)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+	m = updated.(Model)
+	runBatch(cmd)
+	if copied != "cd '/src/app review'" {
+		t.Errorf("copied %q, want the cd command", copied)
+	}
+}
+
+func TestCheckingOutActions_OfferOtherWorktree(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(m *Model) []tea.Cmd
+	}{
+		{"create", func(m *Model) []tea.Cmd { return m.startCreate("feature-base", "new") }},
+		{"insert", func(m *Model) []tea.Cmd { return m.startInsert("feature-base", "feature-top", "new") }},
+		{"rename", func(m *Model) []tea.Cmd { return m.startRename("feature-base", "renamed") }},
+		{"pop", func(m *Model) []tea.Cmd { m.cursor = 0; return actionHandlers["pop"](m) }},
+		{"merge queue", func(m *Model) []tea.Cmd { m.cursor = 1; return m.startMerge() }},
+		{"split", func(m *Model) []tea.Cmd { return m.startSplit("feature-base", gt.SplitByCommit) }},
+		{"stack step", func(m *Model) []tea.Cmd { return m.startStackStep("down") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, calls := recordingMock()
+			m := New(gt.New(&interactiveMock{mockExecutor: mock}), "")
+			m = sendWindowSize(m, 80, 24)
+			updated, _ := m.Update(logResultMsg{
+				output:    "│ ◉  feature-top\n│ ◯  feature-base\n◯─┘  main",
+				worktrees: map[string]string{"feature-base": "/src/app-review"},
+			})
+			m = updated.(Model)
+			for _, b := range []string{"feature-top", "feature-base"} {
+				gt.FindBranch(m.branches, b).PR = gt.PRInfo{Number: 1, State: "OPEN"}
+			}
+
+			runBatch(tea.Batch(tt.run(&m)...))
+			if m.running || len(*calls) != 0 {
+				t.Errorf("nothing should run, calls = %v", *calls)
+			}
+			if m.mode != modePicker || m.picker.title != "feature-base is checked out in /src/app-review" {
+				t.Errorf("mode = %d, picker title = %q, want the worktree offer", m.mode, m.picker.title)
+			}
+		})
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	cmd := shellCommand(func(string) string { return "" }, "/src/app-review")
+	if cmd.Args[0] != "sh" || cmd.Dir != "/src/app-review" {
+		t.Errorf("args = %v, dir = %q, want sh in the worktree", cmd.Args, cmd.Dir)
+	}
+	cmd = shellCommand(func(string) string { return "/bin/zsh" }, "/x")
+	if cmd.Args[0] != "/bin/zsh" {
+		t.Errorf("args = %v, want $SHELL", cmd.Args)
+	}
+}
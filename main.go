@@ -1,22 +1,338 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 
+	"github.com/elliotb/grit/internal/check"
+	"github.com/elliotb/grit/internal/config"
+	"github.com/elliotb/grit/internal/digest"
+	"github.com/elliotb/grit/internal/doctor"
 	"github.com/elliotb/grit/internal/gt"
+	"github.com/elliotb/grit/internal/hooks"
+	"github.com/elliotb/grit/internal/journal"
+	"github.com/elliotb/grit/internal/record"
 	"github.com/elliotb/grit/internal/ui"
 )
 
 func main() {
-	gtClient := gt.NewDefault()
-	model := ui.New(gtClient, ".git")
+	pathFlag := flag.String("path", "", "limit diffs and changed-file badges to a repo subdirectory (e.g. services/api)")
+	lowBandwidthFlag := flag.Bool("low-bandwidth", false, "minimize redraws for slow SSH sessions (no colors or reverse video, slower spinner)")
+	inlineFlag := flag.Bool("inline", false, "run without the alt screen in a fixed-height region at the bottom of the terminal")
+	profileFlag := flag.String("profile", "", "import keybindings and theme from a profile file")
+	exportFlag := flag.String("export-profile", "", "write the active keybindings and theme to a profile file and exit")
+	tutorialFlag := flag.Bool("tutorial", false, "learn grit in a temporary sandbox repo with a guided walkthrough")
+	recordFlag := flag.String("record", "", "record frames, messages and commands to a file for grit replay (for bug reports)")
+	demoFlag := flag.Bool("demo", false, "show a simulated repo instead of running git and gt (for demos and screenshots)")
+	flag.Parse()
 
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	switch flag.Arg(0) {
+	case "digest":
+		os.Exit(runDigest(flag.Args()[1:]))
+	case "replay":
+		os.Exit(runReplay(flag.Args()[1:]))
+	case "doctor":
+		os.Exit(runDoctor())
+	case "check":
+		os.Exit(runCheck())
+	case "hooks":
+		os.Exit(runHooks(flag.Args()[1:]))
+	case "corpus":
+		os.Exit(runCorpus(flag.Args()[1:]))
+	}
+
+	gtClient, gitDir := gt.NewDefault(), ".git"
+	if *demoFlag {
+		gtClient, gitDir = gt.NewDemo(), ""
+	}
+	sandbox := ""
+	if *tutorialFlag && !*demoFlag {
+		dir, err := newSandbox(gtClient)
+		if err == nil {
+			err = os.Chdir(dir)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sandbox = dir
+	}
+
+	cfg, err := config.LoadForRepo(config.UserPath(), config.RepoFileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	if *pathFlag != "" {
+		cfg.Path = *pathFlag
+	}
+	if *lowBandwidthFlag {
+		cfg.LowBandwidth = true
+	}
+	if *inlineFlag {
+		cfg.Inline = true
+	}
+	if *profileFlag != "" {
+		cfg.Profile = *profileFlag
+	}
+	if cfg.Profile != "" {
+		profile, err := config.LoadProfile(cfg.Profile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading profile: %v\n", err)
+			os.Exit(1)
+		}
+		cfg = cfg.WithProfile(profile)
+	}
+	if *exportFlag != "" {
+		profile, err := ui.ExportProfile(cfg)
+		if err == nil {
+			err = config.WriteProfile(*exportFlag, profile)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting profile: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote profile to %s\n", *exportFlag)
+		return
+	}
+	if err := ui.ApplyProfile(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in config: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.LowBandwidth {
+		// Plain text only: every color escape is bytes on the wire.
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	var recorder *record.Recorder
+	if *recordFlag != "" {
+		recorder, err = record.Create(*recordFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting recording: %v\n", err)
+			os.Exit(1)
+		}
+		gtClient = gt.New(recorder.Executor(gtClient.Executor()))
+	}
+
+	model := ui.NewWithConfig(gtClient, gitDir, cfg)
+	if *tutorialFlag {
+		model = model.WithTutorial()
+	}
+	var program tea.Model = model
+	if recorder != nil {
+		program = recorder.Model(model)
+	}
+
+	var opts []tea.ProgramOption
+	if !cfg.Inline {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(program, opts...)
+	_, err = p.Run()
+	if recorder != nil {
+		if closeErr := recorder.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "Error writing recording: %v\n", closeErr)
+		}
+	}
+	if sandbox != "" {
+		os.RemoveAll(sandbox)
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// runDigest prints a Markdown summary of recent stack activity:
+// `grit digest [--days N]`.
+func runDigest(args []string) int {
+	flags := flag.NewFlagSet("digest", flag.ExitOnError)
+	days := flags.Int("days", 7, "number of days to summarize")
+	flags.Parse(args)
+
+	events, err := journal.Load(".git")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading journal: %v\n", err)
+		return 1
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	out, err := digest.Build(ctx, gt.NewDefault(), events, time.Now(), *days)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Print(out)
+	return 0
+}
+
+// runDoctor checks grit's dependencies and setup and prints fixes:
+// `grit doctor`. It exits non-zero if any check fails.
+func runDoctor() int {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	env := doctor.DefaultEnv(gt.NewDefault(), ui.CanWatch, func() error {
+		cfg, err := config.LoadForRepo(config.UserPath(), config.RepoFileName)
+		if err != nil {
+			return err
+		}
+		if cfg.Profile != "" {
+			profile, err := config.LoadProfile(cfg.Profile)
+			if err != nil {
+				return fmt.Errorf("profile: %w", err)
+			}
+			cfg = cfg.WithProfile(profile)
+		}
+		return ui.ApplyProfile(cfg)
+	})
+	if doctor.Write(os.Stdout, doctor.Run(ctx, env)) {
+		return 1
+	}
+	return 0
+}
+
+// runCheck prints a JSON report on the checked-out stack: `grit check`. It
+// exits 1 if any branch needs a restack, has no PR, an unsubmitted head or
+// failing required checks, and 2 if the stack couldn't be checked.
+func runCheck() int {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	report, err := check.Run(ctx, gt.NewDefault())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if err := check.Write(os.Stdout, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if !report.OK {
+		return 1
+	}
+	return 0
+}
+
+// runHooks installs or removes the git hooks that tell grit to refresh:
+// `grit hooks install|uninstall`.
+func runHooks(args []string) int {
+	if len(args) != 1 || args[0] != "install" && args[0] != "uninstall" {
+		fmt.Fprintln(os.Stderr, "Usage: grit hooks install|uninstall")
+		return 2
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	hooksDir, gitDir, err := gt.NewDefault().HookPaths(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	var results []hooks.Result
+	if args[0] == "install" {
+		results, err = hooks.Install(hooksDir, gitDir)
+	} else {
+		results, err = hooks.Uninstall(hooksDir)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	hooks.Write(os.Stdout, results)
+	return 0
+}
+
+// runCorpus records the current repo's `gt log short` output, anonymized
+// and tagged with the gt version, into a file to attach to a parser bug
+// report: `grit corpus [--dir D] <name>`.
+func runCorpus(args []string) int {
+	flags := flag.NewFlagSet("corpus", flag.ExitOnError)
+	dir := flags.String("dir", ".", "directory to write to")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: grit corpus [--dir D] <name>")
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client := gt.NewDefault()
+	version, err := client.GtVersion(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	output, err := client.LogShort(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	anonymized, err := gt.AnonymizeLogShort(output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	filename, content := gt.CorpusFile(version, flags.Arg(0), anonymized)
+	path := filepath.Join(*dir, filename)
+	if _, err := os.Stat(path); err == nil {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists\n", path)
+		return 1
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Wrote %s\nCheck it, then attach it to your issue.\n", path)
+	return 0
+}
+
+// runReplay plays back a session recorded with --record:
+// `grit replay [--speed N] [--log] <file>`.
+func runReplay(args []string) int {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	speed := flags.Float64("speed", 1, "playback speed multiplier")
+	logOnly := flags.Bool("log", false, "print the recorded messages and commands instead of playing frames")
+	flags.Parse(args)
+	if flags.NArg() != 1 || *speed <= 0 {
+		fmt.Fprintln(os.Stderr, "Usage: grit replay [--speed N] [--log] <file>")
+		return 2
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+	events, err := record.Load(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading recording: %v\n", err)
+		return 1
+	}
+
+	if *logOnly {
+		err = record.WriteLog(events, os.Stdout)
+	} else {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		err = record.Replay(ctx, events, os.Stdout, *speed, 2*time.Second)
+		fmt.Println()
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/elliotb/grit/internal/gt"
+)
+
+// sandboxScript builds the --tutorial repo: trunk plus a two-branch stack
+// and a one-branch stack, each branch adding a file.
+const sandboxScript = `set -e
+git init -q -b main
+git config user.name "grit tutorial"
+git config user.email "tutorial@grit.invalid"
+printf '# Sandbox\n\nA throwaway repo for the grit tutorial.\n' > README.md
+git add README.md
+git commit -qm "Initial commit"
+gt repo init --trunk main --no-interactive >/dev/null
+printf 'package api\n\n// Client talks to the API.\ntype Client struct{}\n' > client.go
+gt create api-client -a -m "Add API client" --no-interactive >/dev/null
+printf 'package api\n\n// Get fetches a resource.\nfunc (c *Client) Get(path string) error { return nil }\n' > get.go
+gt create api-get -a -m "Add Client.Get" --no-interactive >/dev/null
+gt checkout main --no-interactive >/dev/null
+printf '# Usage\n\nRun the tool.\n' > USAGE.md
+gt create docs-usage -a -m "Document usage" --no-interactive >/dev/null
+gt checkout main --no-interactive >/dev/null
+`
+
+// newSandbox creates a temporary repo with a fake stack for --tutorial and
+// returns its path. The caller removes it.
+func newSandbox(client *gt.Client) (string, error) {
+	dir, err := os.MkdirTemp("", "grit-tutorial-")
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := client.RunShellIn(ctx, dir, sandboxScript); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("creating tutorial sandbox (is gt installed?): %w", err)
+	}
+	return dir, nil
+}